@@ -414,6 +414,8 @@ export class GoAuthApiStack extends cdk.Stack {
         "MYSQL_PORT": `${dbPort}`,
         "MYSQL_USER": dbUser,
         "MYSQL_DATABASE": dbName,
+        // The ALB forwards client addresses in X-Forwarded-For.
+        "SERVER_TRUSTED_PROXIES": apPublicSubnets.map((subnet) => subnet.ipv4CidrBlock).join(","),
       },
       secrets: {
         'MYSQL_PASSWORD': ecs.Secret.fromSsmParameter(
@@ -0,0 +1,31 @@
+package adapter
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.AccessPolicySource = (*AccessPolicyTable)(nil)
+
+// AccessPolicyTable holds the access policy in force. It may be replaced at
+// any time, such as when its file changes.
+type AccessPolicyTable struct {
+	policy atomic.Pointer[entity.AccessPolicy]
+}
+
+func NewAccessPolicyTable(policy *entity.AccessPolicy) *AccessPolicyTable {
+	t := &AccessPolicyTable{}
+	t.Set(policy)
+	return t
+}
+
+func (t *AccessPolicyTable) Set(policy *entity.AccessPolicy) {
+	t.policy.Store(policy)
+}
+
+func (t *AccessPolicyTable) Get(ctx context.Context) (*entity.AccessPolicy, error) {
+	return t.policy.Load(), nil
+}
@@ -0,0 +1,59 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha1"
+	"io"
+
+	"github.com/mkaiho/go-auth-api/adapter/pwned"
+	"github.com/mkaiho/go-auth-api/adapter/storage"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.BreachedPasswordChecker = (*BreachedPasswordChecker)(nil)
+
+type BreachedPasswordChecker struct {
+	corpus   *pwned.Corpus
+	minCount int
+}
+
+// NewBreachedPasswordChecker reports passwords seen at least minCount
+// times in the corpus as breached.
+func NewBreachedPasswordChecker(corpus *pwned.Corpus, minCount int) *BreachedPasswordChecker {
+	if minCount < 1 {
+		minCount = 1
+	}
+	return &BreachedPasswordChecker{
+		corpus:   corpus,
+		minCount: minCount,
+	}
+}
+
+// NewBreachedPasswordCheckerFromStorage reads the whole corpus into memory,
+// so it suits trimmed down lists rather than the full download.
+func NewBreachedPasswordCheckerFromStorage(
+	ctx context.Context,
+	client storage.Client,
+	path string,
+	minCount int,
+) (*BreachedPasswordChecker, error) {
+	r, err := client.Get(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewBreachedPasswordChecker(pwned.NewCorpusFromBytes(b), minCount), nil
+}
+
+func (c *BreachedPasswordChecker) IsBreached(ctx context.Context, password entity.Password) (bool, error) {
+	count, err := c.corpus.Lookup(sha1.Sum([]byte(password.String())))
+	if err != nil {
+		return false, err
+	}
+	return count >= c.minCount, nil
+}
@@ -0,0 +1,101 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/mkaiho/go-auth-api/adapter/storage"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ storage.Client = (*memoryStorageClient)(nil)
+
+var errStorageNotFound = errors.New("not found")
+
+type memoryStorageClient struct {
+	objects map[string][]byte
+}
+
+func (c *memoryStorageClient) Get(ctx context.Context, path string) (io.ReadCloser, error) {
+	b, ok := c.objects[path]
+	if !ok {
+		return nil, errStorageNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (c *memoryStorageClient) Save(ctx context.Context, path string, mime storage.MimeType, body []byte) error {
+	c.objects[path] = body
+	return nil
+}
+
+func (c *memoryStorageClient) Remove(ctx context.Context, path string) error {
+	delete(c.objects, path)
+	return nil
+}
+
+func TestNewBreachedPasswordCheckerFromStorage(t *testing.T) {
+	ctx := context.Background()
+	client := &memoryStorageClient{
+		objects: map[string][]byte{
+			"pwned/passwords.txt": []byte(fmt.Sprintf(
+				"%X:3\n",
+				sha1.Sum([]byte("hunter2")),
+			)),
+		},
+	}
+	tests := []struct {
+		name     string
+		path     string
+		minCount int
+		want     map[string]bool
+		wantErr  error
+	}{
+		{
+			name:     "return checker reporting passwords in stored corpus",
+			path:     "pwned/passwords.txt",
+			minCount: 1,
+			want: map[string]bool{
+				"hunter2":   true,
+				"not-pwned": false,
+			},
+		},
+		{
+			name:     "return checker ignoring passwords seen less than min count",
+			path:     "pwned/passwords.txt",
+			minCount: 4,
+			want: map[string]bool{
+				"hunter2": false,
+			},
+		},
+		{
+			name:     "return error when corpus is not stored",
+			path:     "pwned/missing.txt",
+			minCount: 1,
+			wantErr:  errStorageNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewBreachedPasswordCheckerFromStorage(ctx, client, tt.path, tt.minCount)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			for password, want := range tt.want {
+				got, err := c.IsBreached(ctx, entity.Password(password))
+				assert.NoError(t, err)
+				assert.Equal(t, want, got, password)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 
 var _ Cryptor = (*AESCryptor)(nil)
 
+// AESCryptor uses unauthenticated CFB mode. It is kept to read data written
+// before Keyring existed and should not be used for new data.
 type AESCryptor struct {
 	key       string
 	blockSize int
@@ -41,6 +43,9 @@ func (c *AESCryptor) Encrypt(ctx context.Context, plaintext []byte) ([]byte, err
 }
 
 func (c *AESCryptor) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < c.blockSize {
+		return nil, ErrInvalidCiphertext
+	}
 	iv := []byte(ciphertext[:c.blockSize])
 	text := []byte(ciphertext[c.blockSize:])
 
@@ -55,3 +60,54 @@ func (c *AESCryptor) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, er
 
 	return plaintext, nil
 }
+
+var _ AEADCryptor = (*AESGCMCryptor)(nil)
+
+// AESGCMCryptor encrypts with a single AES-GCM key and prefixes the nonce to
+// the ciphertext. It suits one-off keys such as envelope data keys; use
+// Keyring for keys that have to be rotated.
+type AESGCMCryptor struct {
+	aead cipher.AEAD
+}
+
+func NewAESGCMCryptor(key []byte) (*AESGCMCryptor, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCMCryptor{
+		aead: aead,
+	}, nil
+}
+
+func (c *AESGCMCryptor) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return c.Seal(ctx, plaintext, nil)
+}
+
+func (c *AESGCMCryptor) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return c.Open(ctx, ciphertext, nil)
+}
+
+func (c *AESGCMCryptor) Seal(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plaintext, associatedData), nil
+}
+
+func (c *AESGCMCryptor) Open(ctx context.Context, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	if len(ciphertext) < c.aead.NonceSize()+c.aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := ciphertext[:c.aead.NonceSize()], ciphertext[c.aead.NonceSize():]
+	plaintext, err := c.aead.Open(nil, nonce, sealed, associatedData)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
@@ -71,6 +71,15 @@ func TestAESCryptor_Decrypt(t *testing.T) {
 			want:    []byte("hello world"),
 			wantErr: false,
 		},
+		{
+			name: "return error when ciphertext is shorter than block size",
+			args: args{
+				ctx:        context.Background(),
+				ciphertext: []byte("short"),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -87,3 +96,78 @@ func TestAESCryptor_Decrypt(t *testing.T) {
 		})
 	}
 }
+
+func TestAESGCMCryptor_Seal_Open(t *testing.T) {
+	key := []byte("abcdefghijklmnopqrstuvwxyz012345")
+	type args struct {
+		sealAD []byte
+		openAD []byte
+		tamper func(ciphertext []byte) []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr error
+	}{
+		{
+			name: "return plaintext",
+			args: args{
+				sealAD: []byte("keys/signing.pem"),
+				openAD: []byte("keys/signing.pem"),
+			},
+			want: []byte("hello world"),
+		},
+		{
+			name: "return error when associated data differs",
+			args: args{
+				sealAD: []byte("keys/signing.pem"),
+				openAD: []byte("keys/other.pem"),
+			},
+			wantErr: ErrInvalidCiphertext,
+		},
+		{
+			name: "return error when ciphertext is modified",
+			args: args{
+				tamper: func(ciphertext []byte) []byte {
+					ciphertext[len(ciphertext)-1] ^= 0xff
+					return ciphertext
+				},
+			},
+			wantErr: ErrInvalidCiphertext,
+		},
+		{
+			name: "return error when ciphertext is truncated",
+			args: args{
+				tamper: func(ciphertext []byte) []byte {
+					return ciphertext[:8]
+				},
+			},
+			wantErr: ErrInvalidCiphertext,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			c, err := NewAESGCMCryptor(key)
+			if !assert.NoError(t, err) {
+				return
+			}
+			ciphertext, err := c.Seal(ctx, []byte("hello world"), tt.args.sealAD)
+			if !assert.NoError(t, err) {
+				return
+			}
+			if tt.args.tamper != nil {
+				ciphertext = tt.args.tamper(ciphertext)
+			}
+			got, err := c.Open(ctx, ciphertext, tt.args.openAD)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
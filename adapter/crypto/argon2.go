@@ -0,0 +1,150 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"strconv"
+
+	"golang.org/x/crypto/argon2"
+)
+
+var _ PasswordHashGenerator = (*Argon2idHashGenerator)(nil)
+
+const argon2idID = "argon2id"
+
+type Argon2idParams struct {
+	// Memory is in KiB.
+	Memory     uint32
+	Time       uint32
+	Threads    uint8
+	SaltLength uint32
+	KeyLength  uint32
+	// MaxMemory, MaxTime and MaxThreads bound the parameters of stored
+	// hashes, which are refused above them, so that a planted hash can not
+	// make each login exhaust memory or CPU.
+	MaxMemory  uint32
+	MaxTime    uint32
+	MaxThreads uint8
+}
+
+// DefaultArgon2idParams follows the second recommended option of RFC 9106.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:     64 * 1024,
+	Time:       3,
+	Threads:    4,
+	SaltLength: 16,
+	KeyLength:  32,
+	MaxMemory:  256 * 1024,
+	MaxTime:    10,
+	MaxThreads: 16,
+}
+
+type Argon2idHashGenerator struct {
+	params Argon2idParams
+}
+
+func NewArgon2idHashGenerator(params Argon2idParams) (*Argon2idHashGenerator, error) {
+	switch {
+	case params.Time < 1:
+		return nil, errors.New("argon2id time must be at least 1")
+	case params.Threads < 1:
+		return nil, errors.New("argon2id threads must be at least 1")
+	case params.Memory < 8*uint32(params.Threads):
+		return nil, errors.New("argon2id memory must be at least 8 KiB per thread")
+	case params.SaltLength < 8:
+		return nil, errors.New("argon2id salt length must be at least 8 bytes")
+	case params.KeyLength < 16:
+		return nil, errors.New("argon2id key length must be at least 16 bytes")
+	case params.MaxMemory < params.Memory || params.MaxTime < params.Time || params.MaxThreads < params.Threads:
+		return nil, errors.New("argon2id maximum memory, time and threads must not be below their parameters")
+	}
+	return &Argon2idHashGenerator{
+		params: params,
+	}, nil
+}
+
+func (g *Argon2idHashGenerator) Algorithm() HashAlgorithm {
+	return HashAlgorithmArgon2id
+}
+
+func (g *Argon2idHashGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
+	salt := make([]byte, g.params.SaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	p := phcString{
+		id:      argon2idID,
+		version: argon2.Version,
+		params: map[string]string{
+			"m": strconv.FormatUint(uint64(g.params.Memory), 10),
+			"t": strconv.FormatUint(uint64(g.params.Time), 10),
+			"p": strconv.FormatUint(uint64(g.params.Threads), 10),
+		},
+		salt: salt,
+		hash: argon2.IDKey(value, salt, g.params.Time, g.params.Memory, g.params.Threads, g.params.KeyLength),
+	}
+	return p.encode("m", "t", "p"), nil
+}
+
+func (g *Argon2idHashGenerator) Compare(ctx context.Context, hashed []byte, value []byte) error {
+	p, err := g.parse(hashed)
+	if err != nil {
+		return err
+	}
+	m, _ := p.param("m")
+	t, _ := p.param("t")
+	threads, _ := p.param("p")
+	key := argon2.IDKey(value, p.salt, uint32(t), uint32(m), uint8(threads), uint32(len(p.hash)))
+	if subtle.ConstantTimeCompare(key, p.hash) != 1 {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+func (g *Argon2idHashGenerator) Identify(hashed []byte) bool {
+	return bytes.HasPrefix(hashed, []byte("$"+argon2idID+"$"))
+}
+
+func (g *Argon2idHashGenerator) NeedsRehash(hashed []byte) bool {
+	p, err := g.parse(hashed)
+	if err != nil {
+		return true
+	}
+	m, _ := p.param("m")
+	t, _ := p.param("t")
+	threads, _ := p.param("p")
+	return m < uint64(g.params.Memory) ||
+		t < uint64(g.params.Time) ||
+		threads < uint64(g.params.Threads) ||
+		len(p.salt) < int(g.params.SaltLength) ||
+		len(p.hash) < int(g.params.KeyLength)
+}
+
+func (g *Argon2idHashGenerator) parse(hashed []byte) (*phcString, error) {
+	p, err := parsePHCString(hashed)
+	if err != nil {
+		return nil, err
+	}
+	if p.id != argon2idID || p.version != argon2.Version {
+		return nil, ErrUnsupportedHash
+	}
+	for _, k := range []string{"m", "t", "p"} {
+		if v, ok := p.param(k); !ok || v < 1 {
+			return nil, ErrUnsupportedHash
+		}
+	}
+	m, _ := p.param("m")
+	t, _ := p.param("t")
+	threads, _ := p.param("p")
+	if m > uint64(g.params.MaxMemory) || t > uint64(g.params.MaxTime) || threads > uint64(g.params.MaxThreads) {
+		return nil, ErrUnsupportedHash
+	}
+	if len(p.hash) == 0 {
+		return nil, ErrUnsupportedHash
+	}
+	return p, nil
+}
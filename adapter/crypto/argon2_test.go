@@ -0,0 +1,158 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testArgon2idParams = Argon2idParams{
+	Memory:     64,
+	Time:       1,
+	Threads:    1,
+	SaltLength: 16,
+	KeyLength:  32,
+	MaxMemory:  128,
+	MaxTime:    2,
+	MaxThreads: 2,
+}
+
+func TestArgon2idHashGenerator_Generate(t *testing.T) {
+	g, err := NewArgon2idHashGenerator(testArgon2idParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	got, err := g.Generate(ctx, []byte("hello world"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=64,t=1,p=1\$[A-Za-z0-9+/]{22}\$[A-Za-z0-9+/]{43}$`, string(got))
+	assert.True(t, g.Identify(got))
+	assert.False(t, g.NeedsRehash(got))
+	assert.NoError(t, g.Compare(ctx, got, []byte("hello world")))
+	assert.ErrorIs(t, g.Compare(ctx, got, []byte("hello world!")), ErrHashMismatch)
+}
+
+func TestArgon2idHashGenerator_Compare(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		hashed []byte
+		value  []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "return no error",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"),
+				value:  []byte("hello world"),
+			},
+		},
+		{
+			name: "return error when compared values do not match",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"),
+				value:  []byte("hello world!"),
+			},
+			wantErr: ErrHashMismatch,
+		},
+		{
+			name: "return error when hash is not argon2id",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$2a$10$6TJH9Dhk9tHbR57kbC1ZaOC4gJEQ1lLO.kI5gdeEwgB7REWGxayoC"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+		{
+			name: "return error when version is not supported",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+		{
+			name: "return error when memory is above maximum",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$argon2id$v=19$m=4194304,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+		{
+			name: "return error when time is above maximum",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$argon2id$v=19$m=64,t=1000000,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+		{
+			name: "return error when threads are above maximum",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$argon2id$v=19$m=64,t=1,p=255$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Argon2idHashGenerator{
+				params: testArgon2idParams,
+			}
+			err := g.Compare(tt.args.ctx, tt.args.hashed, tt.args.value)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestArgon2idHashGenerator_NeedsRehash(t *testing.T) {
+	tests := []struct {
+		name   string
+		hashed []byte
+		want   bool
+	}{
+		{
+			name:   "return false when parameters are current",
+			hashed: []byte("$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"),
+			want:   false,
+		},
+		{
+			name:   "return true when memory is lower than configured",
+			hashed: []byte("$argon2id$v=19$m=32,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"),
+			want:   true,
+		},
+		{
+			name:   "return true when hash is not argon2id",
+			hashed: []byte("$2a$10$6TJH9Dhk9tHbR57kbC1ZaOC4gJEQ1lLO.kI5gdeEwgB7REWGxayoC"),
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &Argon2idHashGenerator{
+				params: testArgon2idParams,
+			}
+			assert.Equal(t, tt.want, g.NeedsRehash(tt.hashed))
+		})
+	}
+}
@@ -1,12 +1,13 @@
 package crypto
 
 import (
+	"bytes"
 	"context"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
-var _ HashGenerator = (*BcryptoHashGenerator)(nil)
+var _ PasswordHashGenerator = (*BcryptoHashGenerator)(nil)
 
 type BcryptoHashGenerator struct {
 	cost int
@@ -18,6 +19,19 @@ func NewBcryptoHashGenerator() *BcryptoHashGenerator {
 	}
 }
 
+func NewBcryptoHashGeneratorWithCost(cost int) (*BcryptoHashGenerator, error) {
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, bcrypt.InvalidCostError(cost)
+	}
+	return &BcryptoHashGenerator{
+		cost: cost,
+	}, nil
+}
+
+func (g *BcryptoHashGenerator) Algorithm() HashAlgorithm {
+	return HashAlgorithmBcrypt
+}
+
 func (g *BcryptoHashGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
 	hashed, err := bcrypt.GenerateFromPassword([]byte(value), g.cost)
 	if err != nil {
@@ -33,3 +47,17 @@ func (g *BcryptoHashGenerator) Compare(ctx context.Context, hashed []byte, value
 	}
 	return nil
 }
+
+// Identify accepts every "$2?$" variant. The $2y$ hashes written by PHP are
+// verified the same way as $2a$ and $2b$.
+func (g *BcryptoHashGenerator) Identify(hashed []byte) bool {
+	return len(hashed) > 4 && bytes.HasPrefix(hashed, []byte("$2")) && hashed[3] == '$'
+}
+
+func (g *BcryptoHashGenerator) NeedsRehash(hashed []byte) bool {
+	cost, err := bcrypt.Cost(hashed)
+	if err != nil {
+		return true
+	}
+	return cost < g.cost
+}
@@ -93,3 +93,39 @@ func TestBcryptoHashGenerator_Compare(t *testing.T) {
 		})
 	}
 }
+
+func TestBcryptoHashGenerator_NeedsRehash(t *testing.T) {
+	tests := []struct {
+		name   string
+		cost   int
+		hashed []byte
+		want   bool
+	}{
+		{
+			name:   "return false when cost is current",
+			cost:   10,
+			hashed: []byte("$2a$10$6TJH9Dhk9tHbR57kbC1ZaOC4gJEQ1lLO.kI5gdeEwgB7REWGxayoC"),
+			want:   false,
+		},
+		{
+			name:   "return true when cost is lower than configured",
+			cost:   12,
+			hashed: []byte("$2a$10$6TJH9Dhk9tHbR57kbC1ZaOC4gJEQ1lLO.kI5gdeEwgB7REWGxayoC"),
+			want:   true,
+		},
+		{
+			name:   "return true when hash is not bcrypt",
+			cost:   10,
+			hashed: []byte("$scrypt$ln=4,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &BcryptoHashGenerator{
+				cost: tt.cost,
+			}
+			assert.Equal(t, tt.want, g.NeedsRehash(tt.hashed))
+		})
+	}
+}
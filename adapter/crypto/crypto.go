@@ -2,9 +2,20 @@ package crypto
 
 import (
 	"context"
+	"errors"
 )
 
+var ErrInvalidCiphertext = errors.New("invalid ciphertext")
+
 type Cryptor interface {
 	Encrypt(ctx context.Context, plaintext []byte) ([]byte, error)
 	Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error)
 }
+
+// AEADCryptor authenticates associatedData along with the ciphertext, so a
+// ciphertext only decrypts in the context it was encrypted for.
+type AEADCryptor interface {
+	Cryptor
+	Seal(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error)
+	Open(ctx context.Context, ciphertext []byte, associatedData []byte) ([]byte, error)
+}
@@ -2,9 +2,71 @@ package crypto
 
 import (
 	"context"
+	"errors"
+	"strings"
+)
+
+var (
+	ErrHashMismatch    = errors.New("hashed value does not match")
+	ErrUnsupportedHash = errors.New("unsupported hash format")
 )
 
 type HashGenerator interface {
 	Generate(ctx context.Context, value []byte) ([]byte, error)
-	Compare(ctx context.Context, value []byte, hashed []byte) error
+	Compare(ctx context.Context, hashed []byte, value []byte) error
+}
+
+type HashAlgorithm int
+
+const (
+	HashAlgorithmUnsupported HashAlgorithm = iota
+	HashAlgorithmBcrypt
+	HashAlgorithmArgon2id
+	HashAlgorithmScrypt
+	HashAlgorithmPBKDF2
+	HashAlgorithmPHPass
+	HashAlgorithmSaltedSHA256
+)
+
+func (a HashAlgorithm) String() string {
+	return [...]string{
+		"unsupported",
+		"bcrypt",
+		"argon2id",
+		"scrypt",
+		"pbkdf2",
+		"phpass",
+		"salted_sha256",
+	}[a]
+}
+
+func ParseHashAlgorithm(v string) HashAlgorithm {
+	s := strings.ToLower(strings.ReplaceAll(v, "-", "_"))
+	switch s {
+	default:
+		return HashAlgorithmUnsupported
+	case "bcrypt":
+		return HashAlgorithmBcrypt
+	case "argon2id":
+		return HashAlgorithmArgon2id
+	case "scrypt":
+		return HashAlgorithmScrypt
+	case "pbkdf2", "pbkdf2_sha256", "pbkdf2_sha1":
+		return HashAlgorithmPBKDF2
+	case "phpass":
+		return HashAlgorithmPHPass
+	case "salted_sha256":
+		return HashAlgorithmSaltedSHA256
+	}
+}
+
+// PasswordHashGenerator is a HashGenerator that recognizes the hashes it
+// produces, so that several algorithms can be verified side by side.
+type PasswordHashGenerator interface {
+	HashGenerator
+	Algorithm() HashAlgorithm
+	Identify(hashed []byte) bool
+	// NeedsRehash reports whether hashed was produced with weaker
+	// parameters than the generator is configured with.
+	NeedsRehash(hashed []byte) bool
 }
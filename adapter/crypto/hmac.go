@@ -0,0 +1,38 @@
+package crypto
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+)
+
+var _ MACGenerator = (*HMACGenerator)(nil)
+
+type HMACGenerator struct {
+	key []byte
+}
+
+func NewHMACGenerator(key string) *HMACGenerator {
+	return &HMACGenerator{
+		key: []byte(key),
+	}
+}
+
+func (g *HMACGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
+	h := hmac.New(sha256.New, g.key)
+	if _, err := h.Write(value); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+func (g *HMACGenerator) Verify(ctx context.Context, value []byte, mac []byte) error {
+	expected, err := g.Generate(ctx, value)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal(expected, mac) {
+		return ErrMACMismatch
+	}
+	return nil
+}
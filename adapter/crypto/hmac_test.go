@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHMACGenerator_Generate(t *testing.T) {
+	type args struct {
+		ctx   context.Context
+		value []byte
+	}
+	tests := []struct {
+		name    string
+		key     string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "return HMAC-SHA256 of value",
+			key:  "key",
+			args: args{
+				ctx:   context.Background(),
+				value: []byte("The quick brown fox jumps over the lazy dog"),
+			},
+			want:    "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewHMACGenerator(tt.key)
+			got, err := g.Generate(tt.args.ctx, tt.args.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HMACGenerator.Generate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, hex.EncodeToString(got), "HMACGenerator.Generate() = %x, want %v", got, tt.want)
+		})
+	}
+}
+
+func TestHMACGenerator_Verify(t *testing.T) {
+	mac, _ := hex.DecodeString("f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8")
+	type args struct {
+		ctx   context.Context
+		value []byte
+		mac   []byte
+	}
+	tests := []struct {
+		name      string
+		key       string
+		args      args
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return no error",
+			key:  "key",
+			args: args{
+				ctx:   context.Background(),
+				value: []byte("The quick brown fox jumps over the lazy dog"),
+				mac:   mac,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "return error when value is tampered",
+			key:  "key",
+			args: args{
+				ctx:   context.Background(),
+				value: []byte("The quick brown fox jumps over the lazy cat"),
+				mac:   mac,
+			},
+			assertion: func(tt assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(tt, err, ErrMACMismatch)
+			},
+		},
+		{
+			name: "return error when key differs",
+			key:  "other",
+			args: args{
+				ctx:   context.Background(),
+				value: []byte("The quick brown fox jumps over the lazy dog"),
+				mac:   mac,
+			},
+			assertion: func(tt assert.TestingT, err error, i ...interface{}) bool {
+				return assert.ErrorIs(tt, err, ErrMACMismatch)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewHMACGenerator(tt.key)
+			tt.assertion(t, g.Verify(tt.args.ctx, tt.args.value, tt.args.mac))
+		})
+	}
+}
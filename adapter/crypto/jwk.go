@@ -0,0 +1,230 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var ErrInvalidJWK = errors.New("invalid JWK")
+
+// JWK is a JSON Web Key (RFC 7517) for the key types SignatureAlgorithm
+// supports. Private members are empty for public keys.
+type JWK struct {
+	KeyType string `json:"kty"`
+	KeyID   string `json:"kid,omitempty"`
+	Use     string `json:"use,omitempty"`
+	Alg     string `json:"alg,omitempty"`
+	Curve   string `json:"crv,omitempty"`
+	X       string `json:"x,omitempty"`
+	Y       string `json:"y,omitempty"`
+	N       string `json:"n,omitempty"`
+	E       string `json:"e,omitempty"`
+	D       string `json:"d,omitempty"`
+	P       string `json:"p,omitempty"`
+	Q       string `json:"q,omitempty"`
+	DP      string `json:"dp,omitempty"`
+	DQ      string `json:"dq,omitempty"`
+	QI      string `json:"qi,omitempty"`
+}
+
+func NewPublicJWK(pub stdcrypto.PublicKey) (*JWK, error) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return &JWK{
+			KeyType: "RSA",
+			N:       encodeJWKInt(k.N),
+			E:       encodeJWKInt(big.NewInt(int64(k.E))),
+		}, nil
+	case *ecdsa.PublicKey:
+		crv, err := jwkCurveName(k.Curve)
+		if err != nil {
+			return nil, err
+		}
+		size := curveByteSize(k.Curve)
+		return &JWK{
+			KeyType: "EC",
+			Curve:   crv,
+			X:       encodeJWKBytes(k.X.FillBytes(make([]byte, size))),
+			Y:       encodeJWKBytes(k.Y.FillBytes(make([]byte, size))),
+		}, nil
+	case ed25519.PublicKey:
+		return &JWK{
+			KeyType: "OKP",
+			Curve:   "Ed25519",
+			X:       encodeJWKBytes(k),
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, pub)
+	}
+}
+
+func NewPrivateJWK(key stdcrypto.Signer) (*JWK, error) {
+	jwk, err := NewPublicJWK(key.Public())
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		if len(k.Primes) != 2 {
+			return nil, fmt.Errorf("%w: multi-prime RSA keys are not supported", ErrInvalidJWK)
+		}
+		k.Precompute()
+		jwk.D = encodeJWKInt(k.D)
+		jwk.P = encodeJWKInt(k.Primes[0])
+		jwk.Q = encodeJWKInt(k.Primes[1])
+		jwk.DP = encodeJWKInt(k.Precomputed.Dp)
+		jwk.DQ = encodeJWKInt(k.Precomputed.Dq)
+		jwk.QI = encodeJWKInt(k.Precomputed.Qinv)
+	case *ecdsa.PrivateKey:
+		jwk.D = encodeJWKBytes(k.D.FillBytes(make([]byte, curveByteSize(k.Curve))))
+	case ed25519.PrivateKey:
+		jwk.D = encodeJWKBytes(k.Seed())
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, key)
+	}
+	return jwk, nil
+}
+
+func (j *JWK) PublicKey() (stdcrypto.PublicKey, error) {
+	switch j.KeyType {
+	case "RSA":
+		n, err := decodeJWKInt(j.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(j.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("%w: exponent too large", ErrInvalidJWK)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, err := jwkCurve(j.Curve)
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodeJWKInt(j.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(j.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, fmt.Errorf("%w: point is not on curve", ErrInvalidJWK)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if j.Curve != "Ed25519" {
+			return nil, fmt.Errorf("%w: unsupported curve %s", ErrInvalidJWK, j.Curve)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid x", ErrInvalidJWK)
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported kty %s", ErrInvalidJWK, j.KeyType)
+	}
+}
+
+func (j *JWK) PrivateKey() (stdcrypto.Signer, error) {
+	if len(j.D) == 0 {
+		return nil, fmt.Errorf("%w: not a private key", ErrInvalidJWK)
+	}
+	pub, err := j.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		var values [3]*big.Int
+		for i, v := range []string{j.D, j.P, j.Q} {
+			values[i], err = decodeJWKInt(v)
+			if err != nil {
+				return nil, err
+			}
+		}
+		key := &rsa.PrivateKey{
+			PublicKey: *k,
+			D:         values[0],
+			Primes:    []*big.Int{values[1], values[2]},
+		}
+		if err := key.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidJWK, err)
+		}
+		key.Precompute()
+		return key, nil
+	case *ecdsa.PublicKey:
+		d, err := decodeJWKInt(j.D)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PrivateKey{PublicKey: *k, D: d}
+		x, y := k.Curve.ScalarBaseMult(d.FillBytes(make([]byte, curveByteSize(k.Curve))))
+		if x.Cmp(k.X) != 0 || y.Cmp(k.Y) != 0 {
+			return nil, fmt.Errorf("%w: d does not match public key", ErrInvalidJWK)
+		}
+		return key, nil
+	case ed25519.PublicKey:
+		seed, err := base64.RawURLEncoding.DecodeString(j.D)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("%w: invalid d", ErrInvalidJWK)
+		}
+		key := ed25519.NewKeyFromSeed(seed)
+		if !k.Equal(key.Public()) {
+			return nil, fmt.Errorf("%w: d does not match public key", ErrInvalidJWK)
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, pub)
+	}
+}
+
+func jwkCurveName(curve elliptic.Curve) (string, error) {
+	switch curve {
+	case elliptic.P256():
+		return "P-256", nil
+	case elliptic.P384():
+		return "P-384", nil
+	default:
+		return "", fmt.Errorf("%w: curve %s", ErrUnsupportedKeyType, curve.Params().Name)
+	}
+}
+
+func jwkCurve(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported curve %s", ErrInvalidJWK, name)
+	}
+}
+
+func encodeJWKInt(v *big.Int) string {
+	return encodeJWKBytes(v.Bytes())
+}
+
+func encodeJWKBytes(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeJWKInt(v string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(v)
+	if err != nil || len(b) == 0 {
+		return nil, fmt.Errorf("%w: invalid integer", ErrInvalidJWK)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
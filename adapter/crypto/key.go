@@ -0,0 +1,183 @@
+package crypto
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	defaultRSAKeyBits     = 2048
+	ecPrivateKeyBlockType = "EC PRIVATE KEY"
+)
+
+var ErrInvalidPrivateKeyFormat = errors.New("invalid private key format")
+
+type PrivateKeyFormat int
+
+const (
+	PrivateKeyFormatUnsupported PrivateKeyFormat = iota
+	PrivateKeyFormatDer
+	PrivateKeyFormatPem
+	PrivateKeyFormatJWK
+)
+
+func (f PrivateKeyFormat) String() string {
+	return [...]string{
+		"unsupported",
+		"der",
+		"pem",
+		"jwk",
+	}[f]
+}
+
+func ParsePrivateKeyFormat(v string) PrivateKeyFormat {
+	s := strings.ToLower(strings.ReplaceAll(v, "_", ""))
+	switch s {
+	default:
+		return PrivateKeyFormatUnsupported
+	case "der":
+		return PrivateKeyFormatDer
+	case "pem":
+		return PrivateKeyFormatPem
+	case "jwk":
+		return PrivateKeyFormatJWK
+	}
+}
+
+var _ KeyManager = (*keyManager)(nil)
+
+// KeyManager handles signing keys of every type a SignatureAlgorithm can
+// use. DER and PEM are written as PKCS#8; PKCS#1 RSA and SEC 1 EC keys are
+// also read.
+type KeyManager interface {
+	GenerateKey(algorithm SignatureAlgorithm) (stdcrypto.Signer, error)
+	ReadFile(filename string, format PrivateKeyFormat) (stdcrypto.Signer, error)
+	ReadBytes(b []byte, format PrivateKeyFormat) (stdcrypto.Signer, error)
+	ConvertFormat(privateKey stdcrypto.Signer, format PrivateKeyFormat) ([]byte, error)
+}
+
+func NewKeyManager() *keyManager {
+	return &keyManager{}
+}
+
+type keyManager struct{}
+
+func (m *keyManager) GenerateKey(algorithm SignatureAlgorithm) (stdcrypto.Signer, error) {
+	switch algorithm {
+	case SignatureAlgorithmRS256, SignatureAlgorithmPS256:
+		return rsa.GenerateKey(rand.Reader, defaultRSAKeyBits)
+	case SignatureAlgorithmES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case SignatureAlgorithmES384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case SignatureAlgorithmEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported signature algorithm: %s", algorithm)
+	}
+}
+
+func (m *keyManager) ReadFile(filename string, format PrivateKeyFormat) (stdcrypto.Signer, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ReadBytes(b, format)
+}
+
+func (m *keyManager) ReadBytes(b []byte, format PrivateKeyFormat) (stdcrypto.Signer, error) {
+	switch format {
+	case PrivateKeyFormatDer:
+		return parseDerPrivateKey(b)
+	case PrivateKeyFormatPem:
+		block, _ := pem.Decode(b)
+		if block == nil {
+			return nil, ErrInvalidPrivateKeyFormat
+		}
+		switch block.Type {
+		case RSAPrivateKeyBlockTypePKCS1.String():
+			return x509.ParsePKCS1PrivateKey(block.Bytes)
+		case ecPrivateKeyBlockType:
+			return x509.ParseECPrivateKey(block.Bytes)
+		case RSAPrivateKeyBlockTypePKCS8.String():
+			return parsePKCS8PrivateKey(block.Bytes)
+		default:
+			return nil, ErrInvalidRSAPrivateKeyBlockType
+		}
+	case PrivateKeyFormatJWK:
+		var jwk JWK
+		if err := json.Unmarshal(b, &jwk); err != nil {
+			return nil, err
+		}
+		return jwk.PrivateKey()
+	default:
+		return nil, ErrInvalidPrivateKeyFormat
+	}
+}
+
+func (m *keyManager) ConvertFormat(privateKey stdcrypto.Signer, format PrivateKeyFormat) ([]byte, error) {
+	switch format {
+	case PrivateKeyFormatDer:
+		return x509.MarshalPKCS8PrivateKey(privateKey)
+	case PrivateKeyFormatPem:
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: RSAPrivateKeyBlockTypePKCS8.String(), Bytes: der}), nil
+	case PrivateKeyFormatJWK:
+		jwk, err := NewPrivateJWK(privateKey)
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(jwk)
+	default:
+		return nil, ErrInvalidPrivateKeyFormat
+	}
+}
+
+func parseDerPrivateKey(der []byte) (stdcrypto.Signer, error) {
+	if key, err := parsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, ErrInvalidPrivateKeyFormat
+}
+
+func parsePKCS8PrivateKey(der []byte) (stdcrypto.Signer, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return k, nil
+	case *ecdsa.PrivateKey:
+		return k, nil
+	case ed25519.PrivateKey:
+		return k, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, key)
+	}
+}
@@ -0,0 +1,171 @@
+package crypto
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_keyManager_ConvertFormat_ReadBytes(t *testing.T) {
+	m := NewKeyManager()
+	algorithms := []SignatureAlgorithm{
+		SignatureAlgorithmRS256,
+		SignatureAlgorithmES256,
+		SignatureAlgorithmES384,
+		SignatureAlgorithmEdDSA,
+	}
+	formats := []PrivateKeyFormat{
+		PrivateKeyFormatDer,
+		PrivateKeyFormatPem,
+		PrivateKeyFormatJWK,
+	}
+	for _, algorithm := range algorithms {
+		key, err := m.GenerateKey(algorithm)
+		if err != nil {
+			panic(err)
+		}
+		for _, format := range formats {
+			t.Run(algorithm.String()+"/"+format.String(), func(t *testing.T) {
+				ctx := context.Background()
+				b, err := m.ConvertFormat(key, format)
+				if !assert.NoError(t, err) {
+					return
+				}
+				got, err := m.ReadBytes(b, format)
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.True(t, key.Public().(interface {
+					Equal(x stdcrypto.PublicKey) bool
+				}).Equal(got.Public()))
+
+				s, err := NewSigner(algorithm, got)
+				if !assert.NoError(t, err) {
+					return
+				}
+				signature, err := s.Sign(ctx, []byte("message"))
+				if !assert.NoError(t, err) {
+					return
+				}
+				assert.NoError(t, VerifySignature(algorithm, key.Public(), []byte("message"), signature))
+			})
+		}
+	}
+}
+
+func Test_keyManager_ReadBytes(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	sec1, err := x509.MarshalECPrivateKey(ecKey)
+	if err != nil {
+		panic(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		panic(err)
+	}
+	publicJWK, err := NewPublicJWK(ecKey.Public())
+	if err != nil {
+		panic(err)
+	}
+	publicJWKBytes, err := json.Marshal(publicJWK)
+	if err != nil {
+		panic(err)
+	}
+
+	type args struct {
+		b      []byte
+		format PrivateKeyFormat
+	}
+	tests := []struct {
+		name      string
+		args      args
+		assertion assert.ErrorAssertionFunc
+	}{
+		{
+			name: "return EC key from PKCS#8 PEM",
+			args: args{
+				b:      pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+				format: PrivateKeyFormatPem,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "return EC key from SEC 1 PEM",
+			args: args{
+				b:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}),
+				format: PrivateKeyFormatPem,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "return EC key from SEC 1 DER",
+			args: args{
+				b:      sec1,
+				format: PrivateKeyFormatDer,
+			},
+			assertion: assert.NoError,
+		},
+		{
+			name: "return error when JWK has no private key",
+			args: args{
+				b:      publicJWKBytes,
+				format: PrivateKeyFormatJWK,
+			},
+			assertion: assert.Error,
+		},
+		{
+			name: "return error when value is invalid",
+			args: args{
+				b:      []byte("invalid"),
+				format: PrivateKeyFormatPem,
+			},
+			assertion: assert.Error,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := NewKeyManager()
+			got, err := m.ReadBytes(tt.args.b, tt.args.format)
+			tt.assertion(t, err)
+			if err == nil {
+				assert.True(t, ecKey.PublicKey.Equal(got.Public()))
+			}
+		})
+	}
+}
+
+func TestJWK_PrivateKey(t *testing.T) {
+	m := NewKeyManager()
+	key, err := m.GenerateKey(SignatureAlgorithmEdDSA)
+	if err != nil {
+		panic(err)
+	}
+	other, err := m.GenerateKey(SignatureAlgorithmEdDSA)
+	if err != nil {
+		panic(err)
+	}
+	jwk, err := NewPrivateJWK(key)
+	if err != nil {
+		panic(err)
+	}
+	otherJWK, err := NewPrivateJWK(other)
+	if err != nil {
+		panic(err)
+	}
+	mismatched := *jwk
+	mismatched.D = otherJWK.D
+
+	_, err = mismatched.PrivateKey()
+	assert.ErrorIs(t, err, ErrInvalidJWK)
+}
@@ -0,0 +1,190 @@
+package crypto
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+var ErrUnknownKey = errors.New("unknown key")
+
+const (
+	keyringVersion   byte = 1
+	keyringKeyLength      = 32
+	maxKeyIDLength        = 255
+)
+
+type AEADAlgorithm int
+
+const (
+	AEADAlgorithmUnsupported AEADAlgorithm = iota
+	AEADAlgorithmAES256GCM
+	AEADAlgorithmXChaCha20Poly1305
+)
+
+func (a AEADAlgorithm) String() string {
+	return [...]string{
+		"unsupported",
+		"aes-256-gcm",
+		"xchacha20-poly1305",
+	}[a]
+}
+
+func ParseAEADAlgorithm(v string) AEADAlgorithm {
+	s := strings.ToLower(strings.ReplaceAll(v, "_", "-"))
+	switch s {
+	default:
+		return AEADAlgorithmUnsupported
+	case "aes-256-gcm":
+		return AEADAlgorithmAES256GCM
+	case "xchacha20-poly1305":
+		return AEADAlgorithmXChaCha20Poly1305
+	}
+}
+
+var _ AEADCryptor = (*Keyring)(nil)
+
+// Keyring encrypts with its current key and decrypts with whichever key a
+// ciphertext names, so keys can be rotated while old data stays readable.
+// Ciphertexts start with a header that is authenticated with the data:
+//
+//	version (1 byte) | algorithm (1 byte) | key ID length (1 byte) | key ID | nonce | sealed data
+type Keyring struct {
+	algorithm    AEADAlgorithm
+	currentKeyID string
+	keys         map[string][]byte
+}
+
+// NewKeyring takes 32 byte keys, which both algorithms use.
+func NewKeyring(algorithm AEADAlgorithm, currentKeyID string, keys map[string][]byte) (*Keyring, error) {
+	if algorithm == AEADAlgorithmUnsupported {
+		return nil, errors.New("unsupported AEAD algorithm")
+	}
+	for id, key := range keys {
+		if len(id) == 0 || len(id) > maxKeyIDLength {
+			return nil, fmt.Errorf("invalid key ID length: %q", id)
+		}
+		if len(key) != keyringKeyLength {
+			return nil, fmt.Errorf("key %s must be %d bytes", id, keyringKeyLength)
+		}
+	}
+	if _, ok := keys[currentKeyID]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, currentKeyID)
+	}
+	return &Keyring{
+		algorithm:    algorithm,
+		currentKeyID: currentKeyID,
+		keys:         keys,
+	}, nil
+}
+
+func (k *Keyring) CurrentKeyID() string {
+	return k.currentKeyID
+}
+
+func (k *Keyring) HasKey(id string) bool {
+	_, ok := k.keys[id]
+	return ok
+}
+
+func (k *Keyring) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	return k.Seal(ctx, plaintext, nil)
+}
+
+func (k *Keyring) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	return k.Open(ctx, ciphertext, nil)
+}
+
+func (k *Keyring) Seal(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error) {
+	aead, err := newAEAD(k.algorithm, k.keys[k.currentKeyID])
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, 0, 3+len(k.currentKeyID)+aead.NonceSize())
+	header = append(header, keyringVersion, byte(k.algorithm), byte(len(k.currentKeyID)))
+	header = append(header, k.currentKeyID...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plaintext, keyringAssociatedData(header, associatedData)), nil
+}
+
+func (k *Keyring) Open(ctx context.Context, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	algorithm, keyID, header, err := parseKeyringHeader(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, keyID)
+	}
+	aead, err := newAEAD(algorithm, key)
+	if err != nil {
+		return nil, err
+	}
+	rest := ciphertext[len(header):]
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, keyringAssociatedData(header, associatedData))
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return plaintext, nil
+}
+
+// CiphertextKeyID returns the ID of the key a Keyring ciphertext was
+// encrypted with.
+func CiphertextKeyID(ciphertext []byte) (string, error) {
+	_, keyID, _, err := parseKeyringHeader(ciphertext)
+	return keyID, err
+}
+
+func parseKeyringHeader(ciphertext []byte) (AEADAlgorithm, string, []byte, error) {
+	if len(ciphertext) < 3 || ciphertext[0] != keyringVersion {
+		return AEADAlgorithmUnsupported, "", nil, ErrInvalidCiphertext
+	}
+	algorithm := AEADAlgorithm(ciphertext[1])
+	if algorithm != AEADAlgorithmAES256GCM && algorithm != AEADAlgorithmXChaCha20Poly1305 {
+		return AEADAlgorithmUnsupported, "", nil, ErrInvalidCiphertext
+	}
+	end := 3 + int(ciphertext[2])
+	if ciphertext[2] == 0 || len(ciphertext) < end {
+		return AEADAlgorithmUnsupported, "", nil, ErrInvalidCiphertext
+	}
+	return algorithm, string(ciphertext[3:end]), ciphertext[:end], nil
+}
+
+// keyringAssociatedData binds the header so that it can not be altered to
+// point the ciphertext at another key or algorithm.
+func keyringAssociatedData(header []byte, associatedData []byte) []byte {
+	ad := make([]byte, 0, len(header)+len(associatedData))
+	ad = append(ad, header...)
+	return append(ad, associatedData...)
+}
+
+func newAEAD(algorithm AEADAlgorithm, key []byte) (cipher.AEAD, error) {
+	switch algorithm {
+	case AEADAlgorithmAES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case AEADAlgorithmXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	default:
+		return nil, errors.New("unsupported AEAD algorithm")
+	}
+}
@@ -0,0 +1,225 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyring_Seal_Open(t *testing.T) {
+	keys := map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	}
+	type args struct {
+		sealAD []byte
+		openAD []byte
+		tamper func(ciphertext []byte) []byte
+	}
+	tests := []struct {
+		name      string
+		algorithm AEADAlgorithm
+		args      args
+		wantErr   error
+	}{
+		{
+			name:      "return plaintext with aes-256-gcm",
+			algorithm: AEADAlgorithmAES256GCM,
+			args: args{
+				sealAD: []byte("user-1"),
+				openAD: []byte("user-1"),
+			},
+		},
+		{
+			name:      "return plaintext with xchacha20-poly1305",
+			algorithm: AEADAlgorithmXChaCha20Poly1305,
+			args: args{
+				sealAD: []byte("user-1"),
+				openAD: []byte("user-1"),
+			},
+		},
+		{
+			name:      "return error when associated data differs",
+			algorithm: AEADAlgorithmAES256GCM,
+			args: args{
+				sealAD: []byte("user-1"),
+				openAD: []byte("user-2"),
+			},
+			wantErr: ErrInvalidCiphertext,
+		},
+		{
+			name:      "return error when header names another key",
+			algorithm: AEADAlgorithmAES256GCM,
+			args: args{
+				tamper: func(ciphertext []byte) []byte {
+					ciphertext[4] = '2'
+					return ciphertext
+				},
+			},
+			wantErr: ErrInvalidCiphertext,
+		},
+		{
+			name:      "return error when sealed data is modified",
+			algorithm: AEADAlgorithmXChaCha20Poly1305,
+			args: args{
+				tamper: func(ciphertext []byte) []byte {
+					ciphertext[len(ciphertext)-1] ^= 0xff
+					return ciphertext
+				},
+			},
+			wantErr: ErrInvalidCiphertext,
+		},
+		{
+			name:      "return error when ciphertext is truncated",
+			algorithm: AEADAlgorithmAES256GCM,
+			args: args{
+				tamper: func(ciphertext []byte) []byte {
+					return ciphertext[:10]
+				},
+			},
+			wantErr: ErrInvalidCiphertext,
+		},
+		{
+			name:      "return error when version is unknown",
+			algorithm: AEADAlgorithmAES256GCM,
+			args: args{
+				tamper: func(ciphertext []byte) []byte {
+					ciphertext[0] = 0
+					return ciphertext
+				},
+			},
+			wantErr: ErrInvalidCiphertext,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			k, err := NewKeyring(tt.algorithm, "k1", keys)
+			if !assert.NoError(t, err) {
+				return
+			}
+			plaintext := []byte("hello world")
+			ciphertext, err := k.Seal(ctx, plaintext, tt.args.sealAD)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.NotContains(t, string(ciphertext), string(plaintext))
+			if tt.args.tamper != nil {
+				ciphertext = tt.args.tamper(ciphertext)
+			}
+			got, err := k.Open(ctx, ciphertext, tt.args.openAD)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, plaintext, got)
+			}
+		})
+	}
+}
+
+func TestKeyring_rotation(t *testing.T) {
+	ctx := context.Background()
+	k1 := bytes.Repeat([]byte{1}, 32)
+	k2 := bytes.Repeat([]byte{2}, 32)
+
+	old, err := NewKeyring(AEADAlgorithmAES256GCM, "k1", map[string][]byte{"k1": k1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	ciphertext, err := old.Encrypt(ctx, []byte("hello world"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rotated, err := NewKeyring(AEADAlgorithmXChaCha20Poly1305, "k2", map[string][]byte{"k1": k1, "k2": k2})
+	if !assert.NoError(t, err) {
+		return
+	}
+	got, err := rotated.Decrypt(ctx, ciphertext)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []byte("hello world"), got)
+	}
+	keyID, err := CiphertextKeyID(ciphertext)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "k1", keyID)
+	}
+
+	renewed, err := rotated.Encrypt(ctx, got)
+	if !assert.NoError(t, err) {
+		return
+	}
+	keyID, err = CiphertextKeyID(renewed)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "k2", keyID)
+	}
+
+	retired, err := NewKeyring(AEADAlgorithmXChaCha20Poly1305, "k2", map[string][]byte{"k2": k2})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = retired.Decrypt(ctx, ciphertext)
+	assert.True(t, errors.Is(err, ErrUnknownKey), "Keyring.Decrypt() error = %v, want %v", err, ErrUnknownKey)
+}
+
+func TestNewKeyring(t *testing.T) {
+	type args struct {
+		algorithm    AEADAlgorithm
+		currentKeyID string
+		keys         map[string][]byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "return keyring",
+			args: args{
+				algorithm:    AEADAlgorithmAES256GCM,
+				currentKeyID: "k1",
+				keys:         map[string][]byte{"k1": make([]byte, 32)},
+			},
+			wantErr: false,
+		},
+		{
+			name: "return error when algorithm is unsupported",
+			args: args{
+				algorithm:    AEADAlgorithmUnsupported,
+				currentKeyID: "k1",
+				keys:         map[string][]byte{"k1": make([]byte, 32)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error when key is not 32 bytes",
+			args: args{
+				algorithm:    AEADAlgorithmAES256GCM,
+				currentKeyID: "k1",
+				keys:         map[string][]byte{"k1": make([]byte, 16)},
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error when current key is missing",
+			args: args{
+				algorithm:    AEADAlgorithmAES256GCM,
+				currentKeyID: "k2",
+				keys:         map[string][]byte{"k1": make([]byte, 32)},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyring(tt.args.algorithm, tt.args.currentKeyID, tt.args.keys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewKeyring() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
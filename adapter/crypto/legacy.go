@@ -0,0 +1,93 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// HashImporter checks imported hashes against the parameter limits of the
+// generators that will verify them, so that hashes logins would refuse are
+// refused on import too.
+type HashImporter struct {
+	argon2id *Argon2idHashGenerator
+	scrypt   *ScryptHashGenerator
+}
+
+// NewHashImporter takes the configured generators. Argon2id and scrypt
+// limits default to those of DefaultArgon2idParams and DefaultScryptParams
+// when their generators are not given.
+func NewHashImporter(generators ...PasswordHashGenerator) *HashImporter {
+	i := &HashImporter{
+		argon2id: &Argon2idHashGenerator{params: DefaultArgon2idParams},
+		scrypt:   &ScryptHashGenerator{params: DefaultScryptParams},
+	}
+	for _, g := range generators {
+		switch g := g.(type) {
+		case *Argon2idHashGenerator:
+			i.argon2id = g
+		case *ScryptHashGenerator:
+			i.scrypt = g
+		}
+	}
+	return i
+}
+
+// Import converts a password hash exported from another system into the
+// self describing form that PasswordHashGenerator implementations
+// identify. When algorithm is HashAlgorithmUnsupported it is detected from
+// the hash. Salted SHA-256 has no recognizable format, so it must be named
+// explicitly, with the digest in hex and the salt given separately.
+func (i *HashImporter) Import(algorithm HashAlgorithm, hashed string, salt string, position SaltPosition) ([]byte, error) {
+	b := []byte(hashed)
+	if algorithm == HashAlgorithmUnsupported {
+		algorithm = i.detectAlgorithm(b)
+	}
+
+	var err error
+	switch algorithm {
+	case HashAlgorithmBcrypt:
+		_, err = bcrypt.Cost(b)
+	case HashAlgorithmArgon2id:
+		_, err = i.argon2id.parse(b)
+	case HashAlgorithmScrypt:
+		_, err = i.scrypt.parse(b)
+	case HashAlgorithmPBKDF2:
+		_, _, _, _, err = parsePBKDF2Hash(b)
+	case HashAlgorithmPHPass:
+		if !(&PHPassHashGenerator{}).Identify(b) {
+			err = ErrUnsupportedHash
+		}
+	case HashAlgorithmSaltedSHA256:
+		var digest []byte
+		digest, err = hex.DecodeString(hashed)
+		if err == nil && len(digest) != 32 {
+			err = ErrUnsupportedHash
+		}
+		b = EncodeSaltedSHA256Hash([]byte(salt), position, digest)
+	default:
+		err = ErrUnsupportedHash
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s hash: %w", algorithm, err)
+	}
+
+	return b, nil
+}
+
+func (i *HashImporter) detectAlgorithm(hashed []byte) HashAlgorithm {
+	for _, g := range []PasswordHashGenerator{
+		&BcryptoHashGenerator{},
+		i.argon2id,
+		i.scrypt,
+		&PBKDF2HashGenerator{},
+		&PHPassHashGenerator{},
+		&SaltedSHA256HashGenerator{},
+	} {
+		if g.Identify(hashed) {
+			return g.Algorithm()
+		}
+	}
+	return HashAlgorithmUnsupported
+}
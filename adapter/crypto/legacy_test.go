@@ -0,0 +1,177 @@
+package crypto
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashImporter_Import(t *testing.T) {
+	type args struct {
+		algorithm HashAlgorithm
+		hashed    string
+		salt      string
+		position  SaltPosition
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "return bcrypt hash as is when algorithm is detected",
+			args: args{
+				hashed: "$2y$10$6TJH9Dhk9tHbR57kbC1ZaOC4gJEQ1lLO.kI5gdeEwgB7REWGxayoC",
+			},
+			want: "$2y$10$6TJH9Dhk9tHbR57kbC1ZaOC4gJEQ1lLO.kI5gdeEwgB7REWGxayoC",
+		},
+		{
+			name: "return argon2id hash as is when algorithm is detected",
+			args: args{
+				hashed: "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo",
+			},
+			want: "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo",
+		},
+		{
+			name: "return argon2id hash as is when algorithm is given",
+			args: args{
+				algorithm: HashAlgorithmArgon2id,
+				hashed:    "$argon2id$v=19$m=128,t=2,p=2$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo",
+			},
+			want: "$argon2id$v=19$m=128,t=2,p=2$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo",
+		},
+		{
+			name: "return error when argon2id memory is above configured maximum",
+			args: args{
+				hashed: "$argon2id$v=19$m=256,t=1,p=1$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo",
+			},
+			wantErr: true,
+		},
+		{
+			name: "return scrypt hash as is when algorithm is detected",
+			args: args{
+				hashed: "$scrypt$ln=4,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA",
+			},
+			want: "$scrypt$ln=4,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA",
+		},
+		{
+			name: "return error when scrypt log N is above configured maximum",
+			args: args{
+				algorithm: HashAlgorithmScrypt,
+				hashed:    "$scrypt$ln=30,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA",
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error when scrypt r is above configured maximum",
+			args: args{
+				hashed: "$scrypt$ln=4,r=1024,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA",
+			},
+			wantErr: true,
+		},
+		{
+			name: "return django hash as is when algorithm is detected",
+			args: args{
+				hashed: "pbkdf2_sha256$1000$somesalt$6vIAkHC3WTrtFDrLB5lDvI5WiTf/uysM1ehQBSEy9iw=",
+			},
+			want: "pbkdf2_sha256$1000$somesalt$6vIAkHC3WTrtFDrLB5lDvI5WiTf/uysM1ehQBSEy9iw=",
+		},
+		{
+			name: "return phpass hash as is when algorithm is given",
+			args: args{
+				algorithm: HashAlgorithmPHPass,
+				hashed:    "$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0",
+			},
+			want: "$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0",
+		},
+		{
+			name: "return encoded salted sha256 hash",
+			args: args{
+				algorithm: HashAlgorithmSaltedSHA256,
+				hashed:    "5e2580b9e83447315a6d251e3b6bb7b9832429972782023b2b84991a23fa55a3",
+				salt:      "somesalt",
+				position:  SaltPositionSuffix,
+			},
+			want: "$sha256$salt=suffix$c29tZXNhbHQ$XiWAueg0RzFabSUeO2u3uYMkKZcnggI7K4SZGiP6VaM",
+		},
+		{
+			name: "return error when salted sha256 digest is not hex",
+			args: args{
+				algorithm: HashAlgorithmSaltedSHA256,
+				hashed:    "not hex",
+				salt:      "somesalt",
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error when hash does not match algorithm",
+			args: args{
+				algorithm: HashAlgorithmBcrypt,
+				hashed:    "$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0",
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error when algorithm can not be detected",
+			args: args{
+				hashed: "5e2580b9e83447315a6d251e3b6bb7b9832429972782023b2b84991a23fa55a3",
+			},
+			wantErr: true,
+		},
+	}
+	argon2id, err := NewArgon2idHashGenerator(testArgon2idParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrypt, err := NewScryptHashGenerator(testScryptParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := NewHashImporter(argon2id, scrypt)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := i.Import(tt.args.algorithm, tt.args.hashed, tt.args.salt, tt.args.position)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HashImporter.Import() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, string(got))
+			}
+		})
+	}
+}
+
+func TestSaltedSHA256HashGenerator_Compare(t *testing.T) {
+	g := NewSaltedSHA256HashGenerator()
+	ctx := context.Background()
+	digest, err := hex.DecodeString("cc5dc68a44970f57bc7432753586934eb91677b301c81a84488e1c50aee6d38c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	prefixed := EncodeSaltedSHA256Hash([]byte("somesalt"), SaltPositionPrefix, digest)
+	assert.NoError(t, g.Compare(ctx, prefixed, []byte("hello world")))
+	assert.ErrorIs(t, g.Compare(ctx, prefixed, []byte("hello world!")), ErrHashMismatch)
+	assert.True(t, g.NeedsRehash(prefixed))
+
+	suffixed := []byte("$sha256$salt=suffix$c29tZXNhbHQ$XiWAueg0RzFabSUeO2u3uYMkKZcnggI7K4SZGiP6VaM")
+	assert.NoError(t, g.Compare(ctx, suffixed, []byte("hello world")))
+
+	generated, err := g.Generate(ctx, []byte("hello world"))
+	if assert.NoError(t, err) {
+		assert.NoError(t, g.Compare(ctx, generated, []byte("hello world")))
+	}
+}
+
+func TestNewHashImporter(t *testing.T) {
+	// Without generators the default limits apply, under which the
+	// recommended argon2id parameters are importable.
+	hashed := "$argon2id$v=19$m=65536,t=3,p=4$c29tZXNhbHRzb21lc2FsdA$cp39uBzfO5SUIIgx0txij7cRPKx5HdrLlC6VN2Ql8Bo"
+	got, err := NewHashImporter().Import(HashAlgorithmUnsupported, hashed, "", SaltPositionPrefix)
+	assert.NoError(t, err)
+	assert.Equal(t, hashed, string(got))
+}
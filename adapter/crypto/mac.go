@@ -0,0 +1,13 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrMACMismatch = errors.New("mac mismatch")
+
+type MACGenerator interface {
+	Generate(ctx context.Context, value []byte) ([]byte, error)
+	Verify(ctx context.Context, value []byte, mac []byte) error
+}
@@ -0,0 +1,119 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var _ PasswordHashGenerator = (*PBKDF2HashGenerator)(nil)
+
+const (
+	pbkdf2SHA256ID = "pbkdf2_sha256"
+	pbkdf2SHA1ID   = "pbkdf2_sha1"
+
+	pbkdf2SaltChars  = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	pbkdf2SaltLength = 22
+)
+
+// DefaultPBKDF2Iterations is the Django 4.2 default.
+const DefaultPBKDF2Iterations = 600000
+
+// PBKDF2HashGenerator handles the Django password format
+// "<algorithm>$<iterations>$<salt>$<base64 hash>" so that users imported
+// from Django keep their passwords.
+type PBKDF2HashGenerator struct {
+	iterations int
+}
+
+func NewPBKDF2HashGenerator(iterations int) (*PBKDF2HashGenerator, error) {
+	if iterations < 1 {
+		return nil, errors.New("pbkdf2 iterations must be at least 1")
+	}
+	return &PBKDF2HashGenerator{
+		iterations: iterations,
+	}, nil
+}
+
+func (g *PBKDF2HashGenerator) Algorithm() HashAlgorithm {
+	return HashAlgorithmPBKDF2
+}
+
+func (g *PBKDF2HashGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
+	salt := make([]byte, pbkdf2SaltLength)
+	max := big.NewInt(int64(len(pbkdf2SaltChars)))
+	for i := range salt {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return nil, err
+		}
+		salt[i] = pbkdf2SaltChars[n.Int64()]
+	}
+	key := pbkdf2.Key(value, salt, g.iterations, sha256.Size, sha256.New)
+	return []byte(strings.Join([]string{
+		pbkdf2SHA256ID,
+		strconv.Itoa(g.iterations),
+		string(salt),
+		base64.StdEncoding.EncodeToString(key),
+	}, "$")), nil
+}
+
+func (g *PBKDF2HashGenerator) Compare(ctx context.Context, hashed []byte, value []byte) error {
+	h, iterations, salt, key, err := parsePBKDF2Hash(hashed)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(pbkdf2.Key(value, salt, iterations, len(key), h), key) != 1 {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+func (g *PBKDF2HashGenerator) Identify(hashed []byte) bool {
+	return bytes.HasPrefix(hashed, []byte(pbkdf2SHA256ID+"$")) ||
+		bytes.HasPrefix(hashed, []byte(pbkdf2SHA1ID+"$"))
+}
+
+func (g *PBKDF2HashGenerator) NeedsRehash(hashed []byte) bool {
+	_, iterations, _, _, err := parsePBKDF2Hash(hashed)
+	if err != nil || bytes.HasPrefix(hashed, []byte(pbkdf2SHA1ID+"$")) {
+		return true
+	}
+	return iterations < g.iterations
+}
+
+func parsePBKDF2Hash(hashed []byte) (func() hash.Hash, int, []byte, []byte, error) {
+	fields := strings.Split(string(hashed), "$")
+	if len(fields) != 4 {
+		return nil, 0, nil, nil, ErrUnsupportedHash
+	}
+	var h func() hash.Hash
+	switch fields[0] {
+	case pbkdf2SHA256ID:
+		h = sha256.New
+	case pbkdf2SHA1ID:
+		h = sha1.New
+	default:
+		return nil, 0, nil, nil, ErrUnsupportedHash
+	}
+	iterations, err := strconv.Atoi(fields[1])
+	if err != nil || iterations < 1 {
+		return nil, 0, nil, nil, ErrUnsupportedHash
+	}
+	key, err := base64.StdEncoding.DecodeString(fields[3])
+	if err != nil || len(key) == 0 {
+		return nil, 0, nil, nil, ErrUnsupportedHash
+	}
+	return h, iterations, []byte(fields[2]), key, nil
+}
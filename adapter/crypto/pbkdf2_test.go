@@ -0,0 +1,86 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPBKDF2HashGenerator_Generate(t *testing.T) {
+	g, err := NewPBKDF2HashGenerator(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	got, err := g.Generate(ctx, []byte("hello world"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Regexp(t, `^pbkdf2_sha256\$1000\$[A-Za-z0-9]{22}\$[A-Za-z0-9+/]{43}=$`, string(got))
+	assert.True(t, g.Identify(got))
+	assert.False(t, g.NeedsRehash(got))
+	assert.NoError(t, g.Compare(ctx, got, []byte("hello world")))
+}
+
+func TestPBKDF2HashGenerator_Compare(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		hashed []byte
+		value  []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "return no error with pbkdf2_sha256",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("pbkdf2_sha256$1000$somesalt$6vIAkHC3WTrtFDrLB5lDvI5WiTf/uysM1ehQBSEy9iw="),
+				value:  []byte("hello world"),
+			},
+		},
+		{
+			name: "return no error with pbkdf2_sha1",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("pbkdf2_sha1$1000$somesalt$67w9j9tlpND3HUmzCvjpuMqwgqM="),
+				value:  []byte("hello world"),
+			},
+		},
+		{
+			name: "return error when compared values do not match",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("pbkdf2_sha256$1000$somesalt$6vIAkHC3WTrtFDrLB5lDvI5WiTf/uysM1ehQBSEy9iw="),
+				value:  []byte("hello world!"),
+			},
+			wantErr: ErrHashMismatch,
+		},
+		{
+			name: "return error when algorithm is not supported",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("pbkdf2_md5$1000$somesalt$6vIAkHC3WTrtFDrLB5lDvI5WiTf/uysM1ehQBSEy9iw="),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PBKDF2HashGenerator{
+				iterations: 1000,
+			}
+			err := g.Compare(tt.args.ctx, tt.args.hashed, tt.args.value)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"strings"
+)
+
+var _ PasswordHashGenerator = (*PHPassHashGenerator)(nil)
+
+const (
+	phpassItoa64     = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	phpassHashLength = 34
+	phpassMinLog2    = 7
+	phpassMaxLog2    = 30
+)
+
+// DefaultPHPassLog2Rounds is what WordPress uses.
+const DefaultPHPassLog2Rounds = 8
+
+// PHPassHashGenerator handles the portable phpass format ("$P$" and the
+// phpBB "$H$" variant) used by WordPress and other PHP applications.
+type PHPassHashGenerator struct {
+	log2Rounds int
+}
+
+func NewPHPassHashGenerator(log2Rounds int) (*PHPassHashGenerator, error) {
+	if log2Rounds < phpassMinLog2 || log2Rounds > phpassMaxLog2 {
+		return nil, errors.New("phpass log2 rounds must be between 7 and 30")
+	}
+	return &PHPassHashGenerator{
+		log2Rounds: log2Rounds,
+	}, nil
+}
+
+func (g *PHPassHashGenerator) Algorithm() HashAlgorithm {
+	return HashAlgorithmPHPass
+}
+
+func (g *PHPassHashGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
+	random := make([]byte, 6)
+	if _, err := io.ReadFull(rand.Reader, random); err != nil {
+		return nil, err
+	}
+	setting := "$P$" + string(phpassItoa64[g.log2Rounds]) + phpassEncode64(random)
+	return phpassCrypt(value, setting)
+}
+
+func (g *PHPassHashGenerator) Compare(ctx context.Context, hashed []byte, value []byte) error {
+	if !g.Identify(hashed) {
+		return ErrUnsupportedHash
+	}
+	computed, err := phpassCrypt(value, string(hashed))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(computed, hashed) != 1 {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+func (g *PHPassHashGenerator) Identify(hashed []byte) bool {
+	return len(hashed) == phpassHashLength &&
+		(bytes.HasPrefix(hashed, []byte("$P$")) || bytes.HasPrefix(hashed, []byte("$H$")))
+}
+
+func (g *PHPassHashGenerator) NeedsRehash(hashed []byte) bool {
+	if !g.Identify(hashed) {
+		return true
+	}
+	return strings.IndexByte(phpassItoa64, hashed[3]) < g.log2Rounds
+}
+
+func phpassCrypt(value []byte, setting string) ([]byte, error) {
+	if len(setting) < 12 {
+		return nil, ErrUnsupportedHash
+	}
+	log2 := strings.IndexByte(phpassItoa64, setting[3])
+	if log2 < phpassMinLog2 || log2 > phpassMaxLog2 {
+		return nil, ErrUnsupportedHash
+	}
+	salt := setting[4:12]
+
+	sum := md5.Sum(append([]byte(salt), value...))
+	for count := 1 << log2; count > 0; count-- {
+		sum = md5.Sum(append(sum[:], value...))
+	}
+
+	return []byte(setting[:12] + phpassEncode64(sum[:])), nil
+}
+
+// phpassEncode64 is the little endian base64 variant of phpass, which
+// differs from both standard base64 and the crypt(3) alphabet order.
+func phpassEncode64(input []byte) string {
+	var b strings.Builder
+	for i := 0; i < len(input); {
+		value := int(input[i])
+		i++
+		b.WriteByte(phpassItoa64[value&0x3f])
+		if i < len(input) {
+			value |= int(input[i]) << 8
+		}
+		b.WriteByte(phpassItoa64[(value>>6)&0x3f])
+		if i >= len(input) {
+			break
+		}
+		i++
+		if i < len(input) {
+			value |= int(input[i]) << 16
+		}
+		b.WriteByte(phpassItoa64[(value>>12)&0x3f])
+		if i >= len(input) {
+			break
+		}
+		i++
+		b.WriteByte(phpassItoa64[(value>>18)&0x3f])
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPHPassHashGenerator_Generate(t *testing.T) {
+	g, err := NewPHPassHashGenerator(DefaultPHPassLog2Rounds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	got, err := g.Generate(ctx, []byte("hello world"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Regexp(t, `^\$P\$6[./0-9A-Za-z]{30}$`, string(got))
+	assert.True(t, g.Identify(got))
+	assert.False(t, g.NeedsRehash(got))
+	assert.NoError(t, g.Compare(ctx, got, []byte("hello world")))
+}
+
+func TestPHPassHashGenerator_Compare(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		hashed []byte
+		value  []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "return no error",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0"),
+				value:  []byte("test12345"),
+			},
+		},
+		{
+			name: "return error when compared values do not match",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0"),
+				value:  []byte("test12346"),
+			},
+			wantErr: ErrHashMismatch,
+		},
+		{
+			name: "return error when hash is not phpass",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$2a$10$6TJH9Dhk9tHbR57kbC1ZaOC4gJEQ1lLO.kI5gdeEwgB7REWGxayoC"),
+				value:  []byte("test12345"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &PHPassHashGenerator{
+				log2Rounds: DefaultPHPassLog2Rounds,
+			}
+			err := g.Compare(tt.args.ctx, tt.args.hashed, tt.args.value)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"io"
+	"strconv"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+var _ PasswordHashGenerator = (*ScryptHashGenerator)(nil)
+
+const scryptID = "scrypt"
+
+type ScryptParams struct {
+	// LogN is the base 2 logarithm of the CPU/memory cost N.
+	LogN       uint8
+	R          int
+	P          int
+	SaltLength int
+	KeyLength  int
+	// MaxLogN, MaxR and MaxP bound the parameters of stored hashes, which
+	// are refused above them, as scrypt needs 128 * N * r bytes of memory
+	// and p times the work.
+	MaxLogN uint8
+	MaxR    int
+	MaxP    int
+}
+
+// DefaultScryptParams are the interactive login parameters recommended by
+// the scrypt paper.
+var DefaultScryptParams = ScryptParams{
+	LogN:       15,
+	R:          8,
+	P:          1,
+	SaltLength: 16,
+	KeyLength:  32,
+	MaxLogN:    17,
+	MaxR:       16,
+	MaxP:       4,
+}
+
+type ScryptHashGenerator struct {
+	params ScryptParams
+}
+
+func NewScryptHashGenerator(params ScryptParams) (*ScryptHashGenerator, error) {
+	switch {
+	case params.LogN < 1 || params.LogN > 30:
+		return nil, errors.New("scrypt log N must be between 1 and 30")
+	case params.R < 1 || params.P < 1 || params.R*params.P >= 1<<30:
+		return nil, errors.New("scrypt r and p must be positive and r * p < 2^30")
+	case params.SaltLength < 8:
+		return nil, errors.New("scrypt salt length must be at least 8 bytes")
+	case params.KeyLength < 16:
+		return nil, errors.New("scrypt key length must be at least 16 bytes")
+	case params.MaxLogN < params.LogN || params.MaxLogN > 30 || params.MaxR < params.R || params.MaxP < params.P:
+		return nil, errors.New("scrypt maximum log N, r and p must not be below their parameters")
+	}
+	return &ScryptHashGenerator{
+		params: params,
+	}, nil
+}
+
+func (g *ScryptHashGenerator) Algorithm() HashAlgorithm {
+	return HashAlgorithmScrypt
+}
+
+func (g *ScryptHashGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
+	salt := make([]byte, g.params.SaltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key(value, salt, 1<<g.params.LogN, g.params.R, g.params.P, g.params.KeyLength)
+	if err != nil {
+		return nil, err
+	}
+	p := phcString{
+		id: scryptID,
+		params: map[string]string{
+			"ln": strconv.FormatUint(uint64(g.params.LogN), 10),
+			"r":  strconv.Itoa(g.params.R),
+			"p":  strconv.Itoa(g.params.P),
+		},
+		salt: salt,
+		hash: key,
+	}
+	return p.encode("ln", "r", "p"), nil
+}
+
+func (g *ScryptHashGenerator) Compare(ctx context.Context, hashed []byte, value []byte) error {
+	p, err := g.parse(hashed)
+	if err != nil {
+		return err
+	}
+	ln, _ := p.param("ln")
+	r, _ := p.param("r")
+	parallel, _ := p.param("p")
+	key, err := scrypt.Key(value, p.salt, 1<<ln, int(r), int(parallel), len(p.hash))
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(key, p.hash) != 1 {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+func (g *ScryptHashGenerator) Identify(hashed []byte) bool {
+	return bytes.HasPrefix(hashed, []byte("$"+scryptID+"$"))
+}
+
+func (g *ScryptHashGenerator) NeedsRehash(hashed []byte) bool {
+	p, err := g.parse(hashed)
+	if err != nil {
+		return true
+	}
+	ln, _ := p.param("ln")
+	r, _ := p.param("r")
+	parallel, _ := p.param("p")
+	return ln < uint64(g.params.LogN) ||
+		r < uint64(g.params.R) ||
+		parallel < uint64(g.params.P) ||
+		len(p.salt) < g.params.SaltLength ||
+		len(p.hash) < g.params.KeyLength
+}
+
+func (g *ScryptHashGenerator) parse(hashed []byte) (*phcString, error) {
+	p, err := parsePHCString(hashed)
+	if err != nil {
+		return nil, err
+	}
+	if p.id != scryptID {
+		return nil, ErrUnsupportedHash
+	}
+	for _, k := range []string{"ln", "r", "p"} {
+		if v, ok := p.param(k); !ok || v < 1 {
+			return nil, ErrUnsupportedHash
+		}
+	}
+	ln, _ := p.param("ln")
+	r, _ := p.param("r")
+	parallel, _ := p.param("p")
+	if ln > uint64(g.params.MaxLogN) || r > uint64(g.params.MaxR) || parallel > uint64(g.params.MaxP) {
+		return nil, ErrUnsupportedHash
+	}
+	if len(p.hash) == 0 {
+		return nil, ErrUnsupportedHash
+	}
+	return p, nil
+}
@@ -0,0 +1,144 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testScryptParams = ScryptParams{
+	LogN:       4,
+	R:          8,
+	P:          1,
+	SaltLength: 16,
+	KeyLength:  32,
+	MaxLogN:    5,
+	MaxR:       8,
+	MaxP:       2,
+}
+
+func TestScryptHashGenerator_Generate(t *testing.T) {
+	g, err := NewScryptHashGenerator(testScryptParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	got, err := g.Generate(ctx, []byte("hello world"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Regexp(t, `^\$scrypt\$ln=4,r=8,p=1\$[A-Za-z0-9+/]{22}\$[A-Za-z0-9+/]{43}$`, string(got))
+	assert.True(t, g.Identify(got))
+	assert.False(t, g.NeedsRehash(got))
+	assert.NoError(t, g.Compare(ctx, got, []byte("hello world")))
+	assert.ErrorIs(t, g.Compare(ctx, got, []byte("hello world!")), ErrHashMismatch)
+}
+
+func TestScryptHashGenerator_Compare(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		hashed []byte
+		value  []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "return no error",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$scrypt$ln=4,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+				value:  []byte("hello world"),
+			},
+		},
+		{
+			name: "return error when compared values do not match",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$scrypt$ln=4,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+				value:  []byte("hello world!"),
+			},
+			wantErr: ErrHashMismatch,
+		},
+		{
+			name: "return error when parameters are missing",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$scrypt$ln=4,r=8$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+		{
+			name: "return error when log N is above configured maximum",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$scrypt$ln=6,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+		{
+			name: "return error when r is above configured maximum",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$scrypt$ln=4,r=9,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+		{
+			name: "return error when p is above configured maximum",
+			args: args{
+				ctx:    context.Background(),
+				hashed: []byte("$scrypt$ln=4,r=8,p=3$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+				value:  []byte("hello world"),
+			},
+			wantErr: ErrUnsupportedHash,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &ScryptHashGenerator{
+				params: testScryptParams,
+			}
+			err := g.Compare(tt.args.ctx, tt.args.hashed, tt.args.value)
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestScryptHashGenerator_NeedsRehash(t *testing.T) {
+	tests := []struct {
+		name   string
+		hashed []byte
+		want   bool
+	}{
+		{
+			name:   "return false when parameters are current",
+			hashed: []byte("$scrypt$ln=4,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+			want:   false,
+		},
+		{
+			name:   "return true when cost is lower than configured",
+			hashed: []byte("$scrypt$ln=3,r=8,p=1$c29tZXNhbHRzb21lc2FsdA$apdt2mwjzuILWptInbb2Qq+h2KVudzt3vQKTvZRNYyA"),
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &ScryptHashGenerator{
+				params: testScryptParams,
+			}
+			assert.Equal(t, tt.want, g.NeedsRehash(tt.hashed))
+		})
+	}
+}
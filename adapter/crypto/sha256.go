@@ -0,0 +1,115 @@
+package crypto
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"io"
+	"strings"
+)
+
+var _ PasswordHashGenerator = (*SaltedSHA256HashGenerator)(nil)
+
+const saltedSHA256ID = "sha256"
+
+type SaltPosition int
+
+const (
+	SaltPositionPrefix SaltPosition = iota
+	SaltPositionSuffix
+)
+
+func (p SaltPosition) String() string {
+	return [...]string{
+		"prefix",
+		"suffix",
+	}[p]
+}
+
+func ParseSaltPosition(v string) SaltPosition {
+	s := strings.ToLower(v)
+	switch s {
+	default:
+		return SaltPositionPrefix
+	case "suffix":
+		return SaltPositionSuffix
+	}
+}
+
+// SaltedSHA256HashGenerator verifies single round salted SHA-256 hashes
+// imported from other systems. It has no strength parameters, so every
+// hash it recognizes needs a rehash.
+type SaltedSHA256HashGenerator struct {
+	saltLength int
+}
+
+func NewSaltedSHA256HashGenerator() *SaltedSHA256HashGenerator {
+	return &SaltedSHA256HashGenerator{
+		saltLength: 16,
+	}
+}
+
+// EncodeSaltedSHA256Hash builds the stored form
+// "$sha256$salt=<position>$<base64 salt>$<base64 digest>" of a hash
+// exported from another system.
+func EncodeSaltedSHA256Hash(salt []byte, position SaltPosition, digest []byte) []byte {
+	p := phcString{
+		id: saltedSHA256ID,
+		params: map[string]string{
+			"salt": position.String(),
+		},
+		salt: salt,
+		hash: digest,
+	}
+	return p.encode("salt")
+}
+
+func (g *SaltedSHA256HashGenerator) Algorithm() HashAlgorithm {
+	return HashAlgorithmSaltedSHA256
+}
+
+func (g *SaltedSHA256HashGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
+	salt := make([]byte, g.saltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	digest := saltedSHA256(value, salt, SaltPositionPrefix)
+	return EncodeSaltedSHA256Hash(salt, SaltPositionPrefix, digest), nil
+}
+
+func (g *SaltedSHA256HashGenerator) Compare(ctx context.Context, hashed []byte, value []byte) error {
+	p, err := parsePHCString(hashed)
+	if err != nil {
+		return err
+	}
+	position, ok := p.params["salt"]
+	if p.id != saltedSHA256ID || !ok || len(p.hash) != sha256.Size {
+		return ErrUnsupportedHash
+	}
+	digest := saltedSHA256(value, p.salt, ParseSaltPosition(position))
+	if subtle.ConstantTimeCompare(digest, p.hash) != 1 {
+		return ErrHashMismatch
+	}
+	return nil
+}
+
+func (g *SaltedSHA256HashGenerator) Identify(hashed []byte) bool {
+	return bytes.HasPrefix(hashed, []byte("$"+saltedSHA256ID+"$"))
+}
+
+func (g *SaltedSHA256HashGenerator) NeedsRehash(hashed []byte) bool {
+	return true
+}
+
+func saltedSHA256(value []byte, salt []byte, position SaltPosition) []byte {
+	var sum [sha256.Size]byte
+	switch position {
+	case SaltPositionSuffix:
+		sum = sha256.Sum256(append(append([]byte{}, value...), salt...))
+	default:
+		sum = sha256.Sum256(append(append([]byte{}, salt...), value...))
+	}
+	return sum[:]
+}
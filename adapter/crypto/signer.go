@@ -0,0 +1,218 @@
+package crypto
+
+import (
+	"context"
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrSignatureMismatch    = errors.New("signature mismatch")
+	ErrUnsupportedKeyType   = errors.New("unsupported key type")
+	ErrKeyAlgorithmMismatch = errors.New("key does not match signature algorithm")
+)
+
+// SignatureAlgorithm values are named after their JWS "alg" identifiers.
+type SignatureAlgorithm int
+
+const (
+	SignatureAlgorithmUnsupported SignatureAlgorithm = iota
+	SignatureAlgorithmRS256
+	SignatureAlgorithmPS256
+	SignatureAlgorithmES256
+	SignatureAlgorithmES384
+	SignatureAlgorithmEdDSA
+)
+
+func (a SignatureAlgorithm) String() string {
+	return [...]string{
+		"unsupported",
+		"RS256",
+		"PS256",
+		"ES256",
+		"ES384",
+		"EdDSA",
+	}[a]
+}
+
+func ParseSignatureAlgorithm(v string) SignatureAlgorithm {
+	s := strings.ToUpper(v)
+	switch s {
+	default:
+		return SignatureAlgorithmUnsupported
+	case "RS256":
+		return SignatureAlgorithmRS256
+	case "PS256":
+		return SignatureAlgorithmPS256
+	case "ES256":
+		return SignatureAlgorithmES256
+	case "ES384":
+		return SignatureAlgorithmES384
+	case "EDDSA", "ED25519":
+		return SignatureAlgorithmEdDSA
+	}
+}
+
+// SignatureAlgorithmsForKey lists the algorithms a key can be used with, in
+// order of preference.
+func SignatureAlgorithmsForKey(pub stdcrypto.PublicKey) []SignatureAlgorithm {
+	var algorithms []SignatureAlgorithm
+	for _, a := range []SignatureAlgorithm{
+		SignatureAlgorithmEdDSA,
+		SignatureAlgorithmES256,
+		SignatureAlgorithmES384,
+		SignatureAlgorithmPS256,
+		SignatureAlgorithmRS256,
+	} {
+		if a.checkKey(pub) == nil {
+			algorithms = append(algorithms, a)
+		}
+	}
+	return algorithms
+}
+
+func (a SignatureAlgorithm) checkKey(pub stdcrypto.PublicKey) error {
+	var ok bool
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		ok = a == SignatureAlgorithmRS256 || a == SignatureAlgorithmPS256
+	case *ecdsa.PublicKey:
+		ok = (a == SignatureAlgorithmES256 && k.Curve == elliptic.P256()) ||
+			(a == SignatureAlgorithmES384 && k.Curve == elliptic.P384())
+	case ed25519.PublicKey:
+		ok = a == SignatureAlgorithmEdDSA
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedKeyType, pub)
+	}
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrKeyAlgorithmMismatch, a)
+	}
+	return nil
+}
+
+func (a SignatureAlgorithm) digest(message []byte) (stdcrypto.Hash, []byte) {
+	switch a {
+	case SignatureAlgorithmES384:
+		sum := sha512.Sum384(message)
+		return stdcrypto.SHA384, sum[:]
+	default:
+		sum := sha256.Sum256(message)
+		return stdcrypto.SHA256, sum[:]
+	}
+}
+
+var _ Signer = (*signer)(nil)
+
+// Signer produces signatures in the encoding JWS uses, so ECDSA signatures
+// are the fixed width r || s rather than ASN.1.
+type Signer interface {
+	Algorithm() SignatureAlgorithm
+	PublicKey() stdcrypto.PublicKey
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+	Verify(ctx context.Context, message []byte, signature []byte) error
+}
+
+func NewSigner(algorithm SignatureAlgorithm, key stdcrypto.Signer) (*signer, error) {
+	if err := algorithm.checkKey(key.Public()); err != nil {
+		return nil, err
+	}
+	return &signer{
+		algorithm: algorithm,
+		key:       key,
+	}, nil
+}
+
+type signer struct {
+	algorithm SignatureAlgorithm
+	key       stdcrypto.Signer
+}
+
+func (s *signer) Algorithm() SignatureAlgorithm {
+	return s.algorithm
+}
+
+func (s *signer) PublicKey() stdcrypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *signer) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	if s.algorithm == SignatureAlgorithmEdDSA {
+		return s.key.Sign(rand.Reader, message, stdcrypto.Hash(0))
+	}
+	hash, digest := s.algorithm.digest(message)
+	switch s.algorithm {
+	case SignatureAlgorithmPS256:
+		return s.key.Sign(rand.Reader, digest, &rsa.PSSOptions{
+			SaltLength: rsa.PSSSaltLengthEqualsHash,
+			Hash:       hash,
+		})
+	case SignatureAlgorithmES256, SignatureAlgorithmES384:
+		key, ok := s.key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyType, s.key)
+		}
+		r, ss, err := ecdsa.Sign(rand.Reader, key, digest)
+		if err != nil {
+			return nil, err
+		}
+		size := curveByteSize(key.Curve)
+		signature := make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		ss.FillBytes(signature[size:])
+		return signature, nil
+	default:
+		return s.key.Sign(rand.Reader, digest, hash)
+	}
+}
+
+func (s *signer) Verify(ctx context.Context, message []byte, signature []byte) error {
+	return VerifySignature(s.algorithm, s.key.Public(), message, signature)
+}
+
+// VerifySignature checks a signature made by a Signer with only the public
+// key, as relying parties do with published keys.
+func VerifySignature(algorithm SignatureAlgorithm, pub stdcrypto.PublicKey, message []byte, signature []byte) error {
+	if err := algorithm.checkKey(pub); err != nil {
+		return err
+	}
+	hash, digest := algorithm.digest(message)
+	var ok bool
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if algorithm == SignatureAlgorithmPS256 {
+			ok = rsa.VerifyPSS(k, hash, digest, signature, &rsa.PSSOptions{
+				SaltLength: rsa.PSSSaltLengthEqualsHash,
+				Hash:       hash,
+			}) == nil
+		} else {
+			ok = rsa.VerifyPKCS1v15(k, hash, digest, signature) == nil
+		}
+	case *ecdsa.PublicKey:
+		size := curveByteSize(k.Curve)
+		if len(signature) == 2*size {
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			ok = ecdsa.Verify(k, digest, r, s)
+		}
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, message, signature)
+	}
+	if !ok {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+func curveByteSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
@@ -0,0 +1,167 @@
+package crypto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_signer_Sign_Verify(t *testing.T) {
+	m := NewKeyManager()
+	tests := []struct {
+		name          string
+		algorithm     SignatureAlgorithm
+		signatureSize int
+	}{
+		{
+			name:          "return RS256 signature",
+			algorithm:     SignatureAlgorithmRS256,
+			signatureSize: 256,
+		},
+		{
+			name:          "return PS256 signature",
+			algorithm:     SignatureAlgorithmPS256,
+			signatureSize: 256,
+		},
+		{
+			name:          "return ES256 signature",
+			algorithm:     SignatureAlgorithmES256,
+			signatureSize: 64,
+		},
+		{
+			name:          "return ES384 signature",
+			algorithm:     SignatureAlgorithmES384,
+			signatureSize: 96,
+		},
+		{
+			name:          "return EdDSA signature",
+			algorithm:     SignatureAlgorithmEdDSA,
+			signatureSize: 64,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			key, err := m.GenerateKey(tt.algorithm)
+			if !assert.NoError(t, err) {
+				return
+			}
+			s, err := NewSigner(tt.algorithm, key)
+			if !assert.NoError(t, err) {
+				return
+			}
+			message := []byte("header.payload")
+			signature, err := s.Sign(ctx, message)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Len(t, signature, tt.signatureSize)
+			assert.NoError(t, s.Verify(ctx, message, signature))
+			assert.ErrorIs(t, s.Verify(ctx, []byte("header.other"), signature), ErrSignatureMismatch)
+			assert.NoError(t, VerifySignature(tt.algorithm, key.Public(), message, signature))
+		})
+	}
+}
+
+func TestNewSigner(t *testing.T) {
+	m := NewKeyManager()
+	rsaKey, err := m.GenerateKey(SignatureAlgorithmRS256)
+	if err != nil {
+		panic(err)
+	}
+	p256Key, err := m.GenerateKey(SignatureAlgorithmES256)
+	if err != nil {
+		panic(err)
+	}
+	tests := []struct {
+		name      string
+		algorithm SignatureAlgorithm
+		wantErr   error
+		rsa       bool
+	}{
+		{
+			name:      "return signer for matching key",
+			algorithm: SignatureAlgorithmPS256,
+			rsa:       true,
+		},
+		{
+			name:      "return error when RSA key is used for ES256",
+			algorithm: SignatureAlgorithmES256,
+			rsa:       true,
+			wantErr:   ErrKeyAlgorithmMismatch,
+		},
+		{
+			name:      "return error when P-256 key is used for ES384",
+			algorithm: SignatureAlgorithmES384,
+			wantErr:   ErrKeyAlgorithmMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := p256Key
+			if tt.rsa {
+				key = rsaKey
+			}
+			_, err := NewSigner(tt.algorithm, key)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSignatureAlgorithmsForKey(t *testing.T) {
+	m := NewKeyManager()
+	tests := []struct {
+		name      string
+		algorithm SignatureAlgorithm
+		want      []SignatureAlgorithm
+	}{
+		{
+			name:      "return RSA algorithms",
+			algorithm: SignatureAlgorithmRS256,
+			want:      []SignatureAlgorithm{SignatureAlgorithmPS256, SignatureAlgorithmRS256},
+		},
+		{
+			name:      "return ES384 for P-384 key",
+			algorithm: SignatureAlgorithmES384,
+			want:      []SignatureAlgorithm{SignatureAlgorithmES384},
+		},
+		{
+			name:      "return EdDSA for Ed25519 key",
+			algorithm: SignatureAlgorithmEdDSA,
+			want:      []SignatureAlgorithm{SignatureAlgorithmEdDSA},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := m.GenerateKey(tt.algorithm)
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, tt.want, SignatureAlgorithmsForKey(key.Public()))
+		})
+	}
+}
+
+func TestParseSignatureAlgorithm(t *testing.T) {
+	tests := []struct {
+		v    string
+		want SignatureAlgorithm
+	}{
+		{v: "RS256", want: SignatureAlgorithmRS256},
+		{v: "ps256", want: SignatureAlgorithmPS256},
+		{v: "ES256", want: SignatureAlgorithmES256},
+		{v: "ES384", want: SignatureAlgorithmES384},
+		{v: "EdDSA", want: SignatureAlgorithmEdDSA},
+		{v: "HS256", want: SignatureAlgorithmUnsupported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.v, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseSignatureAlgorithm(tt.v))
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.CSRFTokenManager = (*CSRFTokenManager)(nil)
+
+type csrfClaims struct {
+	SessionID string `json:"sid"`
+}
+
+// CSRFTokenManager signs the session ID, so that tokens need no storage and
+// last as long as their session.
+type CSRFTokenManager struct {
+	codec signedTokenCodec
+}
+
+func NewCSRFTokenManager(mac crypto.MACGenerator) *CSRFTokenManager {
+	return &CSRFTokenManager{
+		codec: signedTokenCodec{
+			purpose: "csrf",
+			mac:     mac,
+		},
+	}
+}
+
+func (m *CSRFTokenManager) Issue(ctx context.Context, sessionID entity.ID) (string, error) {
+	return m.codec.encode(ctx, csrfClaims{
+		SessionID: sessionID.String(),
+	})
+}
+
+func (m *CSRFTokenManager) Verify(ctx context.Context, sessionID entity.ID, token string) error {
+	var claims csrfClaims
+	if err := m.codec.decode(ctx, token, &claims); err != nil {
+		return err
+	}
+	if claims.SessionID != sessionID.String() {
+		return usecase.ErrInvalidToken
+	}
+
+	return nil
+}
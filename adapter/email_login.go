@@ -0,0 +1,205 @@
+package adapter
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+const emailLoginCodeDigits = 6
+
+var _ port.EmailLoginCodeGateway = (*EmailLoginCodeGateway)(nil)
+
+// EmailLoginCodeGateway stores short-lived numeric codes. Six digits are
+// only safe to send because a code expires quickly and allows few
+// attempts.
+type EmailLoginCodeGateway struct {
+	idgen       port.IDGenerator
+	codeAccess  *rdb.EmailLoginCodeAccess
+	ttl         time.Duration
+	maxAttempts int
+	now         func() time.Time
+}
+
+func NewEmailLoginCodeGateway(
+	idgen port.IDGenerator,
+	codeAccess *rdb.EmailLoginCodeAccess,
+	ttl time.Duration,
+	maxAttempts int,
+) *EmailLoginCodeGateway {
+	return &EmailLoginCodeGateway{
+		idgen:       idgen,
+		codeAccess:  codeAccess,
+		ttl:         ttl,
+		maxAttempts: maxAttempts,
+		now:         time.Now,
+	}
+}
+
+func (g *EmailLoginCodeGateway) Issue(ctx context.Context, userID entity.ID) (*port.EmailLoginCode, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.codeAccess.DeleteByUserID(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	id, err := g.idgen.Generate()
+	if err != nil {
+		return nil, err
+	}
+	code, err := generateEmailLoginCode()
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := g.now().Add(g.ttl)
+	err = g.codeAccess.Create(ctx, tx, &rdb.EmailLoginCodeRow{
+		ID:        id.String(),
+		UserID:    userID.String(),
+		CodeHash:  hashEmailLoginCode(id, code),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &port.EmailLoginCode{
+		ID:        id,
+		Code:      code,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (g *EmailLoginCodeGateway) Verify(ctx context.Context, id entity.ID, code string) (entity.ID, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	row, err := g.get(ctx, tx, id)
+	if err != nil {
+		return "", err
+	}
+	if row.Attempts >= g.maxAttempts {
+		return "", fmt.Errorf("%w: too many attempts", usecase.ErrInvalidToken)
+	}
+	if subtle.ConstantTimeCompare([]byte(row.CodeHash), []byte(hashEmailLoginCode(id, code))) != 1 {
+		if err := g.codeAccess.IncrementAttempts(ctx, tx, id); err != nil {
+			return "", err
+		}
+		return "", usecase.ErrInvalidCredential
+	}
+	if err := g.codeAccess.Delete(ctx, tx, id); err != nil {
+		return "", err
+	}
+
+	return entity.ID(row.UserID), nil
+}
+
+func (g *EmailLoginCodeGateway) Consume(ctx context.Context, id entity.ID) (entity.ID, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	row, err := g.get(ctx, tx, id)
+	if err != nil {
+		return "", err
+	}
+	if err := g.codeAccess.Delete(ctx, tx, id); err != nil {
+		return "", err
+	}
+
+	return entity.ID(row.UserID), nil
+}
+
+func (g *EmailLoginCodeGateway) get(ctx context.Context, tx rdb.Transaction, id entity.ID) (*rdb.EmailLoginCodeRow, error) {
+	row, err := g.codeAccess.GetForUpdate(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, fmt.Errorf("%w: unknown or used code", usecase.ErrInvalidToken)
+		}
+		return nil, err
+	}
+	if !g.now().Before(row.ExpiresAt) {
+		return nil, fmt.Errorf("%w: expired", usecase.ErrInvalidToken)
+	}
+	return row, nil
+}
+
+func generateEmailLoginCode() (string, error) {
+	max := new(big.Int).Exp(big.NewInt(10), big.NewInt(emailLoginCodeDigits), nil)
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", emailLoginCodeDigits, n), nil
+}
+
+// hashEmailLoginCode mixes in the ID, so that equal codes of different
+// requests do not have equal hashes.
+func hashEmailLoginCode(id entity.ID, code string) string {
+	sum := sha256.Sum256([]byte(id.String() + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
+
+var _ port.EmailLoginTokenManager = (*EmailLoginTokenManager)(nil)
+
+type emailLoginClaims struct {
+	ID        string `json:"jti"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type EmailLoginTokenManager struct {
+	codec signedTokenCodec
+	now   func() time.Time
+}
+
+func NewEmailLoginTokenManager(mac crypto.MACGenerator) *EmailLoginTokenManager {
+	return &EmailLoginTokenManager{
+		codec: signedTokenCodec{
+			purpose: "email_login",
+			mac:     mac,
+		},
+		now: time.Now,
+	}
+}
+
+func (m *EmailLoginTokenManager) Issue(ctx context.Context, input port.EmailLoginTokenIssueInput) (string, error) {
+	return m.codec.encode(ctx, emailLoginClaims{
+		ID:        input.ID.String(),
+		ExpiresAt: input.ExpiresAt.Unix(),
+	})
+}
+
+func (m *EmailLoginTokenManager) Parse(ctx context.Context, token string) (*port.EmailLoginToken, error) {
+	var claims emailLoginClaims
+	if err := m.codec.decode(ctx, token, &claims); err != nil {
+		return nil, err
+	}
+	if !m.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, fmt.Errorf("%w: expired", usecase.ErrInvalidToken)
+	}
+	id, err := entity.ParseID(claims.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", usecase.ErrInvalidToken, err)
+	}
+
+	return &port.EmailLoginToken{
+		ID:        id,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
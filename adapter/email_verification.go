@@ -0,0 +1,70 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.EmailVerificationTokenManager = (*EmailVerificationTokenManager)(nil)
+
+type emailVerificationClaims struct {
+	UserID    string `json:"sub"`
+	Email     string `json:"email"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type EmailVerificationTokenManager struct {
+	codec signedTokenCodec
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+func NewEmailVerificationTokenManager(mac crypto.MACGenerator, ttl time.Duration) *EmailVerificationTokenManager {
+	return &EmailVerificationTokenManager{
+		codec: signedTokenCodec{
+			purpose: "email_verification",
+			mac:     mac,
+		},
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+func (m *EmailVerificationTokenManager) Issue(ctx context.Context, input port.EmailVerificationTokenIssueInput) (string, error) {
+	return m.codec.encode(ctx, emailVerificationClaims{
+		UserID:    input.UserID.String(),
+		Email:     input.Email.String(),
+		ExpiresAt: m.now().Add(m.ttl).Unix(),
+	})
+}
+
+func (m *EmailVerificationTokenManager) Parse(ctx context.Context, token string) (*port.EmailVerificationToken, error) {
+	var claims emailVerificationClaims
+	if err := m.codec.decode(ctx, token, &claims); err != nil {
+		return nil, err
+	}
+	expiresAt := time.Unix(claims.ExpiresAt, 0)
+	if !m.now().Before(expiresAt) {
+		return nil, fmt.Errorf("%w: expired", usecase.ErrInvalidToken)
+	}
+	userID, err := entity.ParseID(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", usecase.ErrInvalidToken, err)
+	}
+	email, err := entity.ParseEmail(claims.Email)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", usecase.ErrInvalidToken, err)
+	}
+
+	return &port.EmailVerificationToken{
+		UserID:    userID,
+		Email:     email,
+		ExpiresAt: expiresAt,
+	}, nil
+}
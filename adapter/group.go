@@ -0,0 +1,292 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.GroupGateway = (*GroupGateway)(nil)
+
+type GroupGateway struct {
+	idgen       port.IDGenerator
+	groupAccess *rdb.GroupAccess
+}
+
+func NewGroupGateway(
+	idgen port.IDGenerator,
+	groupAccess *rdb.GroupAccess,
+) *GroupGateway {
+	return &GroupGateway{
+		idgen:       idgen,
+		groupAccess: groupAccess,
+	}
+}
+
+func (g *GroupGateway) List(ctx context.Context) (entity.Groups, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := g.groupAccess.List(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupsFromRows(rows)
+}
+
+func (g *GroupGateway) Get(ctx context.Context, id entity.ID) (*entity.Group, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := g.groupAccess.Get(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupFromRow(row)
+}
+
+func (g *GroupGateway) Create(ctx context.Context, input port.GroupCreateInput) (*entity.Group, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.checkNameUnused(ctx, tx, input.Name, ""); err != nil {
+		return nil, err
+	}
+	id, err := g.idgen.Generate()
+	if err != nil {
+		return nil, err
+	}
+	created := entity.Group{
+		ID:          id,
+		Name:        input.Name,
+		Description: input.Description,
+	}
+	err = g.groupAccess.Create(ctx, tx, &rdb.GroupRow{
+		ID:          created.ID.String(),
+		Name:        created.Name,
+		Description: created.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (g *GroupGateway) Update(ctx context.Context, input port.GroupUpdateInput) (*entity.Group, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := g.groupAccess.Get(ctx, tx, input.ID); err != nil {
+		return nil, err
+	}
+	if err := g.checkNameUnused(ctx, tx, input.Name, input.ID); err != nil {
+		return nil, err
+	}
+	updated := entity.Group{
+		ID:          input.ID,
+		Name:        input.Name,
+		Description: input.Description,
+	}
+	err = g.groupAccess.Update(ctx, tx, &rdb.GroupRow{
+		ID:          updated.ID.String(),
+		Name:        updated.Name,
+		Description: updated.Description,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
+
+// checkNameUnused fails unless name is free or taken by the group of id.
+func (g *GroupGateway) checkNameUnused(ctx context.Context, tx rdb.Transaction, name string, id entity.ID) error {
+	row, err := g.groupAccess.GetByName(ctx, tx, name)
+	if errors.Is(err, usecase.ErrNotFoundEntity) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if row.ID != id.String() {
+		return usecase.ErrAlreadyExistsEntity
+	}
+	return nil
+}
+
+func (g *GroupGateway) Delete(ctx context.Context, id entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := g.groupAccess.Delete(ctx, tx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return usecase.ErrNotFoundEntity
+	}
+
+	return g.groupAccess.DeleteMemberships(ctx, tx, id)
+}
+
+func (g *GroupGateway) ListMembers(ctx context.Context, id entity.ID) (*entity.GroupMembers, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs, err := g.groupAccess.ListUserIDs(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := g.groupAccess.ListSubgroups(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	members := entity.GroupMembers{}
+	for _, v := range userIDs {
+		userID, err := entity.ParseID(v)
+		if err != nil {
+			return nil, err
+		}
+		members.UserIDs = append(members.UserIDs, userID)
+	}
+	members.Subgroups, err = groupsFromRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return &members, nil
+}
+
+func (g *GroupGateway) ListByUserID(ctx context.Context, userID entity.ID) (entity.Groups, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := g.groupAccess.ListByUserID(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupsFromRows(rows)
+}
+
+func (g *GroupGateway) ListAncestorIDs(ctx context.Context, id entity.ID) ([]entity.ID, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.groupAccess.LockSubgroups(ctx, tx); err != nil {
+		return nil, err
+	}
+	values, err := g.groupAccess.ListAncestorIDs(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	var ids []entity.ID
+	for _, v := range values {
+		ancestorID, err := entity.ParseID(v)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, ancestorID)
+	}
+
+	return ids, nil
+}
+
+func (g *GroupGateway) AddUser(ctx context.Context, id entity.ID, userID entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.groupAccess.AddUser(ctx, tx, id, userID)
+}
+
+func (g *GroupGateway) RemoveUser(ctx context.Context, id entity.ID, userID entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	removed, err := g.groupAccess.RemoveUser(ctx, tx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return usecase.ErrNotFoundEntity
+	}
+
+	return nil
+}
+
+func (g *GroupGateway) AddSubgroup(ctx context.Context, id entity.ID, subgroupID entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.groupAccess.AddSubgroup(ctx, tx, id, subgroupID)
+}
+
+func (g *GroupGateway) RemoveSubgroup(ctx context.Context, id entity.ID, subgroupID entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	removed, err := g.groupAccess.RemoveSubgroup(ctx, tx, id, subgroupID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return usecase.ErrNotFoundEntity
+	}
+
+	return nil
+}
+
+func groupFromRow(row *rdb.GroupRow) (*entity.Group, error) {
+	id, err := entity.ParseID(row.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Group{
+		ID:          id,
+		Name:        row.Name,
+		Description: row.Description,
+	}, nil
+}
+
+func groupsFromRows(rows []*rdb.GroupRow) (entity.Groups, error) {
+	var groups entity.Groups
+	for _, row := range rows {
+		group, err := groupFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
@@ -1,27 +1,49 @@
 package adapter
 
 import (
+	"bytes"
 	"context"
-	"crypto/rsa"
+	stdcrypto "crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 
 	"github.com/mkaiho/go-auth-api/adapter/crypto"
 	"github.com/mkaiho/go-auth-api/adapter/storage"
+	"github.com/mkaiho/go-auth-api/usecase/port"
 )
 
+const keyEnvelopeVersion = 1
+
+var ErrInvalidKeyEnvelope = errors.New("invalid key envelope")
+
+// keyEnvelope is how private keys are stored. The PEM is encrypted with a
+// data key from the KMS and only the KMS-encrypted data key is stored with
+// it, so reading the bucket alone does not expose signing keys.
+type keyEnvelope struct {
+	Version      int    `json:"version"`
+	EncryptedKey []byte `json:"encrypted_key"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
 type KeyAccess struct {
 	storageClient storage.Client
-	rsaKeyManager crypto.RSAKeyManager
+	keyManager    crypto.KeyManager
+	kms           port.KeyManagementService
 }
 
-func NewKeyAccess(storageClient storage.Client, rsaKeyManager crypto.RSAKeyManager) *KeyAccess {
+func NewKeyAccess(storageClient storage.Client, keyManager crypto.KeyManager, kms port.KeyManagementService) *KeyAccess {
 	return &KeyAccess{
 		storageClient: storageClient,
-		rsaKeyManager: rsaKeyManager,
+		keyManager:    keyManager,
+		kms:           kms,
 	}
 }
 
-func (a *KeyAccess) ReadPrivateKey(ctx context.Context, path string) (*rsa.PrivateKey, error) {
+// ReadPrivateKey also reads plain PEM files saved before keys were
+// encrypted; saving such a key again encrypts it.
+func (a *KeyAccess) ReadPrivateKey(ctx context.Context, path string) (stdcrypto.Signer, error) {
 	r, err := a.storageClient.Get(ctx, path)
 	if err != nil {
 		return nil, err
@@ -32,7 +54,13 @@ func (a *KeyAccess) ReadPrivateKey(ctx context.Context, path string) (*rsa.Priva
 	if err != nil {
 		return nil, err
 	}
-	key, err := a.rsaKeyManager.ReadPemBytes(b)
+	if !bytes.HasPrefix(bytes.TrimSpace(b), []byte("-----BEGIN")) {
+		b, err = a.open(ctx, path, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	key, err := a.keyManager.ReadBytes(b, crypto.PrivateKeyFormatPem)
 	if err != nil {
 		return nil, err
 	}
@@ -40,12 +68,16 @@ func (a *KeyAccess) ReadPrivateKey(ctx context.Context, path string) (*rsa.Priva
 	return key, nil
 }
 
-func (a *KeyAccess) Save(ctx context.Context, path string, key *rsa.PrivateKey) error {
-	b, err := a.rsaKeyManager.ConvertFormat(key, crypto.RSAPrivateKeyFormatPem)
+func (a *KeyAccess) Save(ctx context.Context, path string, key stdcrypto.Signer) error {
+	b, err := a.keyManager.ConvertFormat(key, crypto.PrivateKeyFormatPem)
+	if err != nil {
+		return err
+	}
+	b, err = a.seal(ctx, path, b)
 	if err != nil {
 		return err
 	}
-	err = a.storageClient.Save(ctx, path, storage.MimeTypePem, b)
+	err = a.storageClient.Save(ctx, path, storage.MimeTypeJSON, b)
 	if err != nil {
 		return err
 	}
@@ -61,3 +93,49 @@ func (a *KeyAccess) Remove(ctx context.Context, path string) error {
 
 	return nil
 }
+
+// seal binds the ciphertext to path, so an envelope copied to another
+// path in the bucket does not decrypt.
+func (a *KeyAccess) seal(ctx context.Context, path string, plaintext []byte) ([]byte, error) {
+	dataKey, err := a.kms.GenerateDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(dataKey.Plaintext)
+
+	cryptor, err := crypto.NewAESGCMCryptor(dataKey.Plaintext)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := cryptor.Seal(ctx, plaintext, []byte(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(keyEnvelope{
+		Version:      keyEnvelopeVersion,
+		EncryptedKey: dataKey.EncryptedKey,
+		Ciphertext:   ciphertext,
+	})
+}
+
+func (a *KeyAccess) open(ctx context.Context, path string, b []byte) ([]byte, error) {
+	var envelope keyEnvelope
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeyEnvelope, err)
+	}
+	if envelope.Version != keyEnvelopeVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidKeyEnvelope, envelope.Version)
+	}
+	dataKey, err := a.kms.DecryptDataKey(ctx, envelope.EncryptedKey)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(dataKey)
+
+	cryptor, err := crypto.NewAESGCMCryptor(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cryptor.Open(ctx, envelope.Ciphertext, []byte(path))
+}
@@ -0,0 +1,105 @@
+package kms
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+const dataKeyLength = 32
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+
+	dataKeyAssociatedData = []byte("kms:data-key")
+	signingKeyLabel       = []byte("kms:sign")
+)
+
+var _ port.KeyManagementService = (*LocalKMS)(nil)
+
+// LocalKMS keeps its master keys in process memory, loaded from a file or
+// the environment. Data keys are wrapped with AES-256-GCM under the current
+// master key and carry its ID, so older master keys only need to stay
+// configured until everything wrapped with them has been re-encrypted.
+type LocalKMS struct {
+	keyring     *crypto.Keyring
+	signingKeys map[string][]byte
+}
+
+func NewLocalKMS(currentKeyID string, masterKeys map[string][]byte) (*LocalKMS, error) {
+	keyring, err := crypto.NewKeyring(crypto.AEADAlgorithmAES256GCM, currentKeyID, masterKeys)
+	if err != nil {
+		return nil, err
+	}
+	// Signing uses a key derived from the master key so that the same
+	// bytes are never used for both encryption and MACs.
+	signingKeys := make(map[string][]byte, len(masterKeys))
+	for id, key := range masterKeys {
+		h := hmac.New(sha256.New, key)
+		h.Write(signingKeyLabel)
+		signingKeys[id] = h.Sum(nil)
+	}
+	return &LocalKMS{
+		keyring:     keyring,
+		signingKeys: signingKeys,
+	}, nil
+}
+
+func (k *LocalKMS) GenerateDataKey(ctx context.Context) (*port.DataKey, error) {
+	plaintext := make([]byte, dataKeyLength)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		return nil, err
+	}
+	encrypted, err := k.keyring.Seal(ctx, plaintext, dataKeyAssociatedData)
+	if err != nil {
+		return nil, err
+	}
+	return &port.DataKey{
+		Plaintext:    plaintext,
+		EncryptedKey: encrypted,
+	}, nil
+}
+
+func (k *LocalKMS) DecryptDataKey(ctx context.Context, encryptedKey []byte) ([]byte, error) {
+	return k.keyring.Open(ctx, encryptedKey, dataKeyAssociatedData)
+}
+
+// Sign returns "<key ID length><key ID><HMAC-SHA256>" so that Verify can
+// pick the key a signature was made with after rotation.
+func (k *LocalKMS) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	keyID := k.keyring.CurrentKeyID()
+	mac := k.mac(k.signingKeys[keyID], message)
+
+	signature := make([]byte, 0, 1+len(keyID)+len(mac))
+	signature = append(signature, byte(len(keyID)))
+	signature = append(signature, keyID...)
+	return append(signature, mac...), nil
+}
+
+func (k *LocalKMS) Verify(ctx context.Context, message []byte, signature []byte) error {
+	if len(signature) < 1 || len(signature) < 1+int(signature[0]) {
+		return ErrInvalidSignature
+	}
+	keyID := string(signature[1 : 1+int(signature[0])])
+	key, ok := k.signingKeys[keyID]
+	if !ok {
+		return fmt.Errorf("%w: %s", crypto.ErrUnknownKey, keyID)
+	}
+	if !hmac.Equal(k.mac(key, message), signature[1+len(keyID):]) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (k *LocalKMS) mac(key []byte, message []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(message)
+	return h.Sum(nil)
+}
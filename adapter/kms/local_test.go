@@ -0,0 +1,130 @@
+package kms
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalKMS_DataKey(t *testing.T) {
+	ctx := context.Background()
+	k1 := bytes.Repeat([]byte{1}, 32)
+	k2 := bytes.Repeat([]byte{2}, 32)
+
+	old, err := NewLocalKMS("k1", map[string][]byte{"k1": k1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	dataKey, err := old.GenerateDataKey(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, dataKey.Plaintext, 32)
+	assert.NotContains(t, string(dataKey.EncryptedKey), string(dataKey.Plaintext))
+
+	rotated, err := NewLocalKMS("k2", map[string][]byte{"k1": k1, "k2": k2})
+	if !assert.NoError(t, err) {
+		return
+	}
+	got, err := rotated.DecryptDataKey(ctx, dataKey.EncryptedKey)
+	if assert.NoError(t, err) {
+		assert.Equal(t, dataKey.Plaintext, got)
+	}
+
+	other, err := NewLocalKMS("k2", map[string][]byte{"k2": k2})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = other.DecryptDataKey(ctx, dataKey.EncryptedKey)
+	assert.ErrorIs(t, err, crypto.ErrUnknownKey)
+}
+
+func TestLocalKMS_Sign_Verify(t *testing.T) {
+	k1 := bytes.Repeat([]byte{1}, 32)
+	k2 := bytes.Repeat([]byte{2}, 32)
+	signer, err := NewLocalKMS("k1", map[string][]byte{"k1": k1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	type args struct {
+		verifier  func() (*LocalKMS, error)
+		message   []byte
+		signature func(signature []byte) []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr error
+	}{
+		{
+			name: "return nil when signature is valid",
+			args: args{
+				message: []byte("hello world"),
+			},
+		},
+		{
+			name: "return nil when verified after rotation",
+			args: args{
+				verifier: func() (*LocalKMS, error) {
+					return NewLocalKMS("k2", map[string][]byte{"k1": k1, "k2": k2})
+				},
+				message: []byte("hello world"),
+			},
+		},
+		{
+			name: "return error when message differs",
+			args: args{
+				message: []byte("hello world!"),
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "return error when signature is truncated",
+			args: args{
+				message: []byte("hello world"),
+				signature: func(signature []byte) []byte {
+					return signature[:2]
+				},
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "return error when key is unknown",
+			args: args{
+				verifier: func() (*LocalKMS, error) {
+					return NewLocalKMS("k2", map[string][]byte{"k2": k2})
+				},
+				message: []byte("hello world"),
+			},
+			wantErr: crypto.ErrUnknownKey,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			signature, err := signer.Sign(ctx, []byte("hello world"))
+			if !assert.NoError(t, err) {
+				return
+			}
+			if tt.args.signature != nil {
+				signature = tt.args.signature(signature)
+			}
+			verifier := signer
+			if tt.args.verifier != nil {
+				verifier, err = tt.args.verifier()
+				if !assert.NoError(t, err) {
+					return
+				}
+			}
+			err = verifier.Verify(ctx, tt.args.message, signature)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.LoginFailureGateway = (*LoginFailureGateway)(nil)
+
+type LoginFailureGateway struct {
+	failureAccess *rdb.LoginFailureAccess
+}
+
+func NewLoginFailureGateway(failureAccess *rdb.LoginFailureAccess) *LoginFailureGateway {
+	return &LoginFailureGateway{
+		failureAccess: failureAccess,
+	}
+}
+
+func (g *LoginFailureGateway) Get(ctx context.Context, userID entity.ID) (*entity.LoginFailures, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := g.failureAccess.Get(ctx, tx, userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return &entity.LoginFailures{}, nil
+		}
+		return nil, err
+	}
+
+	return &entity.LoginFailures{
+		Count:        row.FailedCount,
+		LastFailedAt: row.LastFailedAt,
+	}, nil
+}
+
+func (g *LoginFailureGateway) Record(ctx context.Context, input port.LoginFailureRecordInput) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.failureAccess.Increment(ctx, tx, input.UserID, input.FailedAt, input.ForgetBefore)
+}
+
+func (g *LoginFailureGateway) Reset(ctx context.Context, userID entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.failureAccess.Delete(ctx, tx, userID)
+}
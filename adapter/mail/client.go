@@ -0,0 +1,15 @@
+package mail
+
+import (
+	"context"
+)
+
+type Message struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+type Client interface {
+	Send(ctx context.Context, msg *Message) error
+}
@@ -0,0 +1,112 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/mkaiho/go-auth-api/adapter/mail"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.Mailer = (*Mailer)(nil)
+
+type Mailer struct {
+	client               mail.Client
+	emailVerificationURL string
+	passwordResetURL     string
+	emailLoginURL        string
+}
+
+func NewMailer(client mail.Client, emailVerificationURL string, passwordResetURL string, emailLoginURL string) *Mailer {
+	return &Mailer{
+		client:               client,
+		emailVerificationURL: emailVerificationURL,
+		passwordResetURL:     passwordResetURL,
+		emailLoginURL:        emailLoginURL,
+	}
+}
+
+func (m *Mailer) SendEmailVerification(ctx context.Context, input port.EmailVerificationMailInput) error {
+	link, err := url.JoinPath(m.emailVerificationURL, url.PathEscape(input.Token))
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf(
+		"Hello %s,\n\nPlease verify your email address by opening the link below.\n\n%s\n",
+		input.Name,
+		link,
+	)
+	err = m.client.Send(ctx, &mail.Message{
+		To:      []string{input.To.String()},
+		Subject: "Verify your email address",
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Mailer) SendPasswordReset(ctx context.Context, input port.PasswordResetMailInput) error {
+	link, err := url.JoinPath(m.passwordResetURL, url.PathEscape(input.Token))
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf(
+		"Hello %s,\n\nA password reset was requested for your account. Open the link below to choose a new password.\nIf you did not request this, you can ignore this email.\n\n%s\n",
+		input.Name,
+		link,
+	)
+	err = m.client.Send(ctx, &mail.Message{
+		To:      []string{input.To.String()},
+		Subject: "Reset your password",
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Mailer) SendEmailLogin(ctx context.Context, input port.EmailLoginMailInput) error {
+	link, err := url.JoinPath(m.emailLoginURL, url.PathEscape(input.Token))
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf(
+		"Hello %s,\n\nYour sign-in code is %s. You can also sign in by opening the link below.\nIf you did not try to sign in, you can ignore this email.\n\n%s\n",
+		input.Name,
+		input.Code,
+		link,
+	)
+	err = m.client.Send(ctx, &mail.Message{
+		To:      []string{input.To.String()},
+		Subject: "Your sign-in code",
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (m *Mailer) SendAccountExists(ctx context.Context, input port.AccountExistsMailInput) error {
+	body := fmt.Sprintf(
+		"Hello %s,\n\nSomeone tried to sign up with your email address, which already has an account.\nIf it was you, sign in or reset your password instead. Otherwise, you can ignore this email.\n",
+		input.Name,
+	)
+	err := m.client.Send(ctx, &mail.Message{
+		To:      []string{input.To.String()},
+		Subject: "You already have an account",
+		Body:    body,
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
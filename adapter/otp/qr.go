@@ -0,0 +1,12 @@
+package otp
+
+import (
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+const qrCodeSize = 256
+
+// QRCodePNG renders content, typically a TOTP URI, as a PNG QR code.
+func QRCodePNG(content string) ([]byte, error) {
+	return qrcode.Encode(content, qrcode.Medium, qrCodeSize)
+}
@@ -0,0 +1,120 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultDigits = 6
+	DefaultPeriod = 30 * time.Second
+	DefaultSkew   = 1
+	// SecretLength is the 160 bits RFC 4226 recommends for HMAC-SHA1.
+	SecretLength = 20
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP generates and validates RFC 6238 time-based one-time passwords with
+// HMAC-SHA1, which is what authenticator apps support universally.
+type TOTP struct {
+	digits int
+	period time.Duration
+	// skew is how many steps before and after the current one are accepted
+	// to allow for clock drift between the server and the device.
+	skew int64
+	now  func() time.Time
+}
+
+func NewTOTP(digits int, period time.Duration, skew int) *TOTP {
+	return &TOTP{
+		digits: digits,
+		period: period,
+		skew:   int64(skew),
+		now:    time.Now,
+	}
+}
+
+func (t *TOTP) GenerateSecret() ([]byte, error) {
+	secret := make([]byte, SecretLength)
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// Step returns the time step at.
+func (t *TOTP) Step(at time.Time) int64 {
+	return at.Unix() / int64(t.period/time.Second)
+}
+
+func (t *TOTP) Code(secret []byte, step int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+	h := hmac.New(sha1.New, secret)
+	h.Write(msg[:])
+	sum := h.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for i := 0; i < t.digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", t.digits, value%mod)
+}
+
+// Validate returns the time step code was generated for, searching the
+// steps around now allowed by the skew.
+func (t *TOTP) Validate(secret []byte, code string) (int64, bool) {
+	if len(code) != t.digits {
+		return 0, false
+	}
+	if _, err := strconv.ParseUint(code, 10, 64); err != nil {
+		return 0, false
+	}
+	current := t.Step(t.now())
+	var matched int64
+	var ok bool
+	// Every candidate is compared so that timing does not reveal which
+	// step matched.
+	for step := current - t.skew; step <= current+t.skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(t.Code(secret, step)), []byte(code)) == 1 {
+			matched, ok = step, true
+		}
+	}
+	return matched, ok
+}
+
+// URI returns the otpauth:// URI authenticator apps import, usually from a
+// QR code.
+func (t *TOTP) URI(issuer string, account string, secret []byte) string {
+	params := url.Values{}
+	params.Set("secret", EncodeSecret(secret))
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", strconv.Itoa(t.digits))
+	params.Set("period", strconv.Itoa(int(t.period/time.Second)))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+// EncodeSecret returns secret in the unpadded base32 form users type into
+// authenticator apps.
+func EncodeSecret(secret []byte) string {
+	return secretEncoding.EncodeToString(secret)
+}
@@ -0,0 +1,116 @@
+package otp
+
+import (
+	"bytes"
+	"image/png"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Test vectors from RFC 6238 Appendix B for the SHA-1 secret.
+func TestTOTP_Code(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	tests := []struct {
+		at   int64
+		want string
+	}{
+		{at: 59, want: "94287082"},
+		{at: 1111111109, want: "07081804"},
+		{at: 1111111111, want: "14050471"},
+		{at: 1234567890, want: "89005924"},
+		{at: 2000000000, want: "69279037"},
+		{at: 20000000000, want: "65353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			totp := NewTOTP(8, DefaultPeriod, DefaultSkew)
+			got := totp.Code(secret, totp.Step(time.Unix(tt.at, 0)))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTOTP_Validate(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	now := time.Unix(1111111111, 0)
+	totp := NewTOTP(DefaultDigits, DefaultPeriod, DefaultSkew)
+	totp.now = func() time.Time { return now }
+	current := totp.Step(now)
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{
+			name:     "return current step",
+			code:     totp.Code(secret, current),
+			wantStep: current,
+			wantOK:   true,
+		},
+		{
+			name:     "return previous step within skew",
+			code:     totp.Code(secret, current-1),
+			wantStep: current - 1,
+			wantOK:   true,
+		},
+		{
+			name:     "return next step within skew",
+			code:     totp.Code(secret, current+1),
+			wantStep: current + 1,
+			wantOK:   true,
+		},
+		{
+			name:   "return false outside skew",
+			code:   totp.Code(secret, current-2),
+			wantOK: false,
+		},
+		{
+			name:   "return false when code is not numeric",
+			code:   "abcdef",
+			wantOK: false,
+		},
+		{
+			name:   "return false when code length differs",
+			code:   totp.Code(secret, current)[1:],
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOK := totp.Validate(secret, tt.code)
+			assert.Equal(t, tt.wantOK, gotOK)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantStep, gotStep)
+			}
+		})
+	}
+}
+
+func TestTOTP_URI(t *testing.T) {
+	totp := NewTOTP(DefaultDigits, DefaultPeriod, DefaultSkew)
+	got, err := url.Parse(totp.URI("go-auth-api", "user@example.com", []byte("12345678901234567890")))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "otpauth", got.Scheme)
+	assert.Equal(t, "totp", got.Host)
+	assert.Equal(t, "/go-auth-api:user@example.com", got.Path)
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", got.Query().Get("secret"))
+	assert.Equal(t, "go-auth-api", got.Query().Get("issuer"))
+	assert.Equal(t, "6", got.Query().Get("digits"))
+	assert.Equal(t, "30", got.Query().Get("period"))
+}
+
+func TestQRCodePNG(t *testing.T) {
+	got, err := QRCodePNG("otpauth://totp/go-auth-api:user@example.com?secret=GEZDGNBVGY3TQOJQ")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = png.Decode(bytes.NewReader(got))
+	assert.NoError(t, err)
+}
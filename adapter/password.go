@@ -11,12 +11,16 @@ import (
 var _ (port.PasswordManager) = (*PasswordManager)(nil)
 
 type PasswordManager struct {
-	generator crypto.HashGenerator
+	generator crypto.PasswordHashGenerator
+	verifiers []crypto.PasswordHashGenerator
 }
 
-func NewPasswordManager(generator crypto.HashGenerator) *PasswordManager {
+// NewPasswordManager hashes new passwords with generator and verifies
+// stored hashes with whichever of generator and legacy produced them.
+func NewPasswordManager(generator crypto.PasswordHashGenerator, legacy ...crypto.PasswordHashGenerator) *PasswordManager {
 	return &PasswordManager{
 		generator: generator,
+		verifiers: append([]crypto.PasswordHashGenerator{generator}, legacy...),
 	}
 }
 
@@ -33,10 +37,23 @@ func (pm *PasswordManager) Hash(ctx context.Context, value string) (entity.Hashe
 }
 
 func (pm *PasswordManager) Compare(ctx context.Context, hashedPassword entity.HashedPassword, password entity.Password) error {
-	err := pm.generator.Compare(ctx, []byte(hashedPassword), []byte(password))
-	if err != nil {
-		return err
+	for _, v := range pm.verifiers {
+		if !v.Identify([]byte(hashedPassword)) {
+			continue
+		}
+		err := v.Compare(ctx, []byte(hashedPassword), []byte(password))
+		if err != nil {
+			return err
+		}
+		return nil
 	}
 
-	return nil
+	return crypto.ErrUnsupportedHash
+}
+
+func (pm *PasswordManager) NeedsRehash(ctx context.Context, hashedPassword entity.HashedPassword) bool {
+	if !pm.generator.Identify([]byte(hashedPassword)) {
+		return true
+	}
+	return pm.generator.NeedsRehash([]byte(hashedPassword))
 }
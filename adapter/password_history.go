@@ -0,0 +1,45 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.PasswordHistoryGateway = (*PasswordHistoryGateway)(nil)
+
+type PasswordHistoryGateway struct {
+	historyAccess *rdb.PasswordHistoryAccess
+	pepper        *PasswordPepper
+}
+
+func NewPasswordHistoryGateway(historyAccess *rdb.PasswordHistoryAccess, pepper *PasswordPepper) port.PasswordHistoryGateway {
+	return &PasswordHistoryGateway{
+		historyAccess: historyAccess,
+		pepper:        pepper,
+	}
+}
+
+func (g *PasswordHistoryGateway) ListRecent(ctx context.Context, userID entity.ID, limit int) ([]entity.HashedPassword, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := g.historyAccess.ListRecentByUserID(ctx, tx, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	passwords := make([]entity.HashedPassword, 0, len(rows))
+	for _, row := range rows {
+		pwd, err := g.pepper.Unwrap(ctx, userID, row.Password, row.PasswordKeyID)
+		if err != nil {
+			return nil, err
+		}
+		passwords = append(passwords, pwd)
+	}
+
+	return passwords, nil
+}
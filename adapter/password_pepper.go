@@ -0,0 +1,155 @@
+package adapter
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+// PasswordPepper encrypts password hashes before they are stored so that a
+// database dump alone is not enough to start cracking them. Hashes are
+// sealed with the user ID as associated data, so they can not be moved to
+// another account, and the ID of the key used is stored next to each hash
+// so that keys can be rotated by re-wrapping without knowing passwords.
+type PasswordPepper struct {
+	keyring *crypto.Keyring
+	legacy  map[string]crypto.Cryptor
+}
+
+// NewPasswordPepper wraps new hashes with keyring, or stores them unwrapped
+// when keyring is nil. legacy holds the AES-CFB keys hashes were wrapped
+// with before the keyring existed; they are only used to read those hashes
+// until they are re-wrapped.
+func NewPasswordPepper(keyring *crypto.Keyring, legacy map[string]crypto.Cryptor) (*PasswordPepper, error) {
+	for id := range legacy {
+		if keyring != nil && keyring.HasKey(id) {
+			return nil, fmt.Errorf("password pepper key %s is both a legacy and a keyring key", id)
+		}
+	}
+	return &PasswordPepper{
+		keyring: keyring,
+		legacy:  legacy,
+	}, nil
+}
+
+func (p *PasswordPepper) Wrap(ctx context.Context, userID entity.ID, hashed entity.HashedPassword) (string, string, error) {
+	if p.keyring == nil {
+		return hashed.String(), "", nil
+	}
+	ciphertext, err := p.keyring.Seal(ctx, []byte(hashed), []byte(userID))
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(ciphertext), p.keyring.CurrentKeyID(), nil
+}
+
+func (p *PasswordPepper) Unwrap(ctx context.Context, userID entity.ID, value string, keyID string) (entity.HashedPassword, error) {
+	if len(keyID) == 0 {
+		return entity.ParseHashedPassword(value)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+
+	var plaintext []byte
+	if cryptor, ok := p.legacy[keyID]; ok {
+		plaintext, err = cryptor.Decrypt(ctx, ciphertext)
+	} else if p.keyring != nil {
+		plaintext, err = p.keyring.Open(ctx, ciphertext, []byte(userID))
+	} else {
+		err = fmt.Errorf("%w: %s", crypto.ErrUnknownKey, keyID)
+	}
+	if err != nil {
+		return "", err
+	}
+	return entity.ParseHashedPassword(string(plaintext))
+}
+
+func (p *PasswordPepper) CurrentKeyID() string {
+	if p.keyring == nil {
+		return ""
+	}
+	return p.keyring.CurrentKeyID()
+}
+
+// PasswordRewrapper moves stored hashes to the current pepper key. This is
+// also how hashes wrapped with legacy AES-CFB keys are re-encrypted.
+type PasswordRewrapper struct {
+	pepper         *PasswordPepper
+	userCredAccess *rdb.UserCredentialAccess
+	historyAccess  *rdb.PasswordHistoryAccess
+}
+
+func NewPasswordRewrapper(
+	pepper *PasswordPepper,
+	userCredAccess *rdb.UserCredentialAccess,
+	historyAccess *rdb.PasswordHistoryAccess,
+) *PasswordRewrapper {
+	return &PasswordRewrapper{
+		pepper:         pepper,
+		userCredAccess: userCredAccess,
+		historyAccess:  historyAccess,
+	}
+}
+
+// Rewrap re-wraps up to limit stored hashes that are not wrapped with the
+// current key and returns how many it updated, so callers repeat it in new
+// transactions until it returns zero.
+func (r *PasswordRewrapper) Rewrap(ctx context.Context, limit int) (int, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	credRows, err := r.userCredAccess.ListByPasswordKeyIDNot(ctx, tx, r.pepper.CurrentKeyID(), limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range credRows {
+		row.Password, row.PasswordKeyID, err = r.rewrap(ctx, row.UserID, row.Password, row.PasswordKeyID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to rewrap credential %s: %w", row.ID, err)
+		}
+		if err := r.userCredAccess.UpdatePasswordKeyByID(ctx, tx, row); err != nil {
+			return 0, err
+		}
+	}
+	if len(credRows) >= limit {
+		return len(credRows), nil
+	}
+
+	historyRows, err := r.historyAccess.ListByPasswordKeyIDNot(ctx, tx, r.pepper.CurrentKeyID(), limit-len(credRows))
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range historyRows {
+		row.Password, row.PasswordKeyID, err = r.rewrap(ctx, row.UserID, row.Password, row.PasswordKeyID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to rewrap password history %s: %w", row.ID, err)
+		}
+		if err := r.historyAccess.UpdatePasswordKeyByID(ctx, tx, row); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(credRows) + len(historyRows), nil
+}
+
+// rewrap parses the stored user ID, which is the associated data of both
+// the old and the new wrapping.
+func (r *PasswordRewrapper) rewrap(ctx context.Context, rowUserID string, value string, keyID string) (string, string, error) {
+	userID, err := entity.ParseID(rowUserID)
+	if err != nil {
+		return "", "", err
+	}
+	hashed, err := r.pepper.Unwrap(ctx, userID, value, keyID)
+	if err != nil {
+		return "", "", err
+	}
+	return r.pepper.Wrap(ctx, userID, hashed)
+}
@@ -0,0 +1,94 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.PasswordResetTokenManager = (*PasswordResetTokenManager)(nil)
+
+type passwordResetClaims struct {
+	UserID        string `json:"sub"`
+	PasswordStamp string `json:"pwd"`
+	ExpiresAt     int64  `json:"exp"`
+}
+
+type PasswordResetTokenManager struct {
+	codec signedTokenCodec
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+func NewPasswordResetTokenManager(mac crypto.MACGenerator, ttl time.Duration) *PasswordResetTokenManager {
+	return &PasswordResetTokenManager{
+		codec: signedTokenCodec{
+			purpose: "password_reset",
+			mac:     mac,
+		},
+		ttl: ttl,
+		now: time.Now,
+	}
+}
+
+func (m *PasswordResetTokenManager) Issue(ctx context.Context, input port.PasswordResetTokenIssueInput) (string, error) {
+	return m.codec.encode(ctx, passwordResetClaims{
+		UserID:        input.UserID.String(),
+		PasswordStamp: passwordStamp(input.Password),
+		ExpiresAt:     m.now().Add(m.ttl).Unix(),
+	})
+}
+
+func (m *PasswordResetTokenManager) Parse(ctx context.Context, token string) (*port.PasswordResetToken, error) {
+	claims, err := m.decode(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := entity.ParseID(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", usecase.ErrInvalidToken, err)
+	}
+
+	return &port.PasswordResetToken{
+		UserID:    userID,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0),
+	}, nil
+}
+
+func (m *PasswordResetTokenManager) Verify(ctx context.Context, token string, password entity.HashedPassword) error {
+	claims, err := m.decode(ctx, token)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare([]byte(claims.PasswordStamp), []byte(passwordStamp(password))) != 1 {
+		return fmt.Errorf("%w: already used", usecase.ErrInvalidToken)
+	}
+
+	return nil
+}
+
+func (m *PasswordResetTokenManager) decode(ctx context.Context, token string) (*passwordResetClaims, error) {
+	var claims passwordResetClaims
+	if err := m.codec.decode(ctx, token, &claims); err != nil {
+		return nil, err
+	}
+	if !m.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, fmt.Errorf("%w: expired", usecase.ErrInvalidToken)
+	}
+	return &claims, nil
+}
+
+// passwordStamp is a short digest of the stored hash. It changes whenever
+// the password changes without disclosing the hash itself.
+func passwordStamp(password entity.HashedPassword) string {
+	sum := sha256.Sum256([]byte(password))
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
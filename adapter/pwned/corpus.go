@@ -0,0 +1,154 @@
+package pwned
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+)
+
+const (
+	hashHexLength = sha1.Size * 2
+	// A line is "<40 hex>:<count>\r\n"; counts never come close to 20 digits.
+	maxLineLength = hashHexLength + 1 + 20 + 2
+)
+
+var ErrInvalidCorpus = errors.New("invalid pwned passwords corpus")
+
+// Corpus looks up SHA-1 hashes in the Have I Been Pwned "Pwned Passwords"
+// download format, one "<upper case hex SHA-1>:<count>" per line sorted by
+// hash. Lookups binary search the underlying reader so the corpus does not
+// need to fit in memory when backed by a file.
+type Corpus struct {
+	r      io.ReaderAt
+	size   int64
+	closer io.Closer
+}
+
+func NewCorpus(r io.ReaderAt, size int64) *Corpus {
+	return &Corpus{
+		r:    r,
+		size: size,
+	}
+}
+
+func NewCorpusFromBytes(b []byte) *Corpus {
+	return NewCorpus(bytes.NewReader(b), int64(len(b)))
+}
+
+func OpenCorpusFile(filename string) (*Corpus, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	c := NewCorpus(f, info.Size())
+	c.closer = f
+	return c, nil
+}
+
+func (c *Corpus) Close() error {
+	if c.closer == nil {
+		return nil
+	}
+	return c.closer.Close()
+}
+
+// Lookup returns how many times the hash was seen in breaches, or zero when
+// it is not in the corpus.
+func (c *Corpus) Lookup(sum [sha1.Size]byte) (int, error) {
+	target := make([]byte, hashHexLength)
+	hex.Encode(target, sum[:])
+	target = bytes.ToUpper(target)
+
+	// Invariant: the line for target, if any, starts within [lo, hi).
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, err := c.lineStart(mid)
+		if err != nil {
+			return 0, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+		line, next, err := c.readLine(start)
+		if err != nil {
+			return 0, err
+		}
+		if len(line) < hashHexLength {
+			return 0, ErrInvalidCorpus
+		}
+		switch bytes.Compare(bytes.ToUpper(line[:hashHexLength]), target) {
+		case 0:
+			return parseCount(line)
+		case -1:
+			lo = next
+		default:
+			hi = start
+		}
+	}
+
+	return 0, nil
+}
+
+// lineStart returns the offset of the first line starting at or after pos.
+func (c *Corpus) lineStart(pos int64) (int64, error) {
+	if pos == 0 {
+		return 0, nil
+	}
+	buf := make([]byte, maxLineLength)
+	for off := pos - 1; off < c.size; off += int64(len(buf)) {
+		n, err := c.r.ReadAt(buf, off)
+		if i := bytes.IndexByte(buf[:n], '\n'); i >= 0 {
+			return off + int64(i) + 1, nil
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return 0, err
+		}
+	}
+	return c.size, nil
+}
+
+// readLine returns the line at start without its terminator and the offset
+// of the following line.
+func (c *Corpus) readLine(start int64) ([]byte, int64, error) {
+	buf := make([]byte, maxLineLength)
+	n, err := c.r.ReadAt(buf, start)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, 0, err
+	}
+	buf = buf[:n]
+	next := start + int64(n)
+	if i := bytes.IndexByte(buf, '\n'); i >= 0 {
+		buf = buf[:i]
+		next = start + int64(i) + 1
+	} else if next < c.size {
+		return nil, 0, ErrInvalidCorpus
+	}
+	return bytes.TrimSuffix(buf, []byte("\r")), next, nil
+}
+
+func parseCount(line []byte) (int, error) {
+	rest := line[hashHexLength:]
+	if len(rest) == 0 {
+		// Hash only lists carry no count.
+		return 1, nil
+	}
+	if rest[0] != ':' {
+		return 0, ErrInvalidCorpus
+	}
+	count, err := strconv.Atoi(string(rest[1:]))
+	if err != nil {
+		return 0, ErrInvalidCorpus
+	}
+	return count, nil
+}
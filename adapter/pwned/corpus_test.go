@@ -0,0 +1,104 @@
+package pwned
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestCorpusBytes(passwords map[string]int, lineEnd string) []byte {
+	var lines []string
+	for p, count := range passwords {
+		lines = append(lines, fmt.Sprintf("%X:%d", sha1.Sum([]byte(p)), count))
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, lineEnd) + lineEnd)
+}
+
+func TestCorpus_Lookup(t *testing.T) {
+	passwords := map[string]int{}
+	for i := 0; i < 500; i++ {
+		passwords[fmt.Sprintf("password%d", i)] = i + 1
+	}
+	tests := []struct {
+		name    string
+		corpus  []byte
+		lookups map[string]int
+	}{
+		{
+			name:   "return counts from LF terminated corpus",
+			corpus: newTestCorpusBytes(passwords, "\n"),
+			lookups: map[string]int{
+				"password0":   1,
+				"password123": 124,
+				"password499": 500,
+				"not-pwned":   0,
+				"":            0,
+			},
+		},
+		{
+			name:   "return counts from CRLF terminated corpus",
+			corpus: newTestCorpusBytes(passwords, "\r\n"),
+			lookups: map[string]int{
+				"password7":   8,
+				"password250": 251,
+				"not-pwned":   0,
+			},
+		},
+		{
+			name:   "return counts from single line corpus",
+			corpus: newTestCorpusBytes(map[string]int{"hunter2": 42}, "\n"),
+			lookups: map[string]int{
+				"hunter2":   42,
+				"not-pwned": 0,
+			},
+		},
+		{
+			name:   "return zero from empty corpus",
+			corpus: []byte{},
+			lookups: map[string]int{
+				"hunter2": 0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCorpusFromBytes(tt.corpus)
+			for p, want := range tt.lookups {
+				got, err := c.Lookup(sha1.Sum([]byte(p)))
+				assert.NoError(t, err)
+				assert.Equal(t, want, got, "Corpus.Lookup(%q) = %v, want %v", p, got, want)
+			}
+		})
+	}
+}
+
+func TestOpenCorpusFile(t *testing.T) {
+	dest, err := os.MkdirTemp("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dest)
+	filename := filepath.Join(dest, "pwned-passwords.txt")
+	if err := os.WriteFile(filename, newTestCorpusBytes(map[string]int{"hunter2": 42, "letmein": 7}, "\r\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := OpenCorpusFile(filename)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer c.Close()
+	got, err := c.Lookup(sha1.Sum([]byte("letmein")))
+	assert.NoError(t, err)
+	assert.Equal(t, 7, got)
+
+	_, err = OpenCorpusFile(filepath.Join(dest, "not_found.txt"))
+	assert.Error(t, err)
+}
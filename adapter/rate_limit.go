@@ -0,0 +1,170 @@
+package adapter
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// rateLimitSweepInterval is how often buckets that are full again are
+// deleted.
+const rateLimitSweepInterval = time.Minute
+
+var _ port.RateLimitStore = (*RateLimitStore)(nil)
+
+// RateLimitStore keeps buckets in the database, so that all instances share
+// them. Keys are stored hashed, as they hold email and IP addresses.
+type RateLimitStore struct {
+	bucketAccess *rdb.RateLimitBucketAccess
+	now          func() time.Time
+	sweptAt      time.Time
+	mux          sync.Mutex
+}
+
+func NewRateLimitStore(bucketAccess *rdb.RateLimitBucketAccess) *RateLimitStore {
+	return &RateLimitStore{
+		bucketAccess: bucketAccess,
+		now:          time.Now,
+	}
+}
+
+// Take locks the bucket row until the end of the transaction. Requests
+// racing to create a bucket may each take a token from a full one.
+func (s *RateLimitStore) Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitResult, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key = hashRateLimitKey(key)
+	now := s.now()
+	if s.sweepDue(now) {
+		if err := s.bucketAccess.DeleteFull(ctx, tx, now); err != nil {
+			return nil, err
+		}
+	}
+	var bucket *entity.TokenBucket
+	row, err := s.bucketAccess.GetForUpdate(ctx, tx, key)
+	if err != nil {
+		if !errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, err
+		}
+	} else {
+		bucket = &entity.TokenBucket{
+			Tokens:    row.Tokens,
+			UpdatedAt: row.UpdatedAt,
+		}
+	}
+	bucket, result := limit.Take(bucket, now)
+	if !result.Allowed {
+		return result, nil
+	}
+	err = s.bucketAccess.Save(ctx, tx, &rdb.RateLimitBucketRow{
+		Key:       key,
+		Tokens:    bucket.Tokens,
+		UpdatedAt: bucket.UpdatedAt,
+		FullAt:    now.Add(result.ResetAfter),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *RateLimitStore) sweepDue(now time.Time) bool {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if now.Sub(s.sweptAt) < rateLimitSweepInterval {
+		return false
+	}
+	s.sweptAt = now
+	return true
+}
+
+func hashRateLimitKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+var _ port.RateLimitStore = (*MemoryRateLimitStore)(nil)
+
+// MemoryRateLimitStore keeps buckets in the process, so each instance
+// limits requests on its own. Keys are spread over shards, each with its
+// own lock, so that requests for different keys rarely wait on each other.
+type MemoryRateLimitStore struct {
+	shards []*rateLimitShard
+	now    func() time.Time
+}
+
+type rateLimitShard struct {
+	buckets map[string]*rateLimitBucket
+	sweptAt time.Time
+	mux     sync.Mutex
+}
+
+type rateLimitBucket struct {
+	entity.TokenBucket
+	fullAt time.Time
+}
+
+func NewMemoryRateLimitStore(shardCount int) *MemoryRateLimitStore {
+	if shardCount < 1 {
+		shardCount = 1
+	}
+	shards := make([]*rateLimitShard, shardCount)
+	for i := range shards {
+		shards[i] = &rateLimitShard{
+			buckets: make(map[string]*rateLimitBucket),
+		}
+	}
+	return &MemoryRateLimitStore{
+		shards: shards,
+		now:    time.Now,
+	}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitResult, error) {
+	shard := s.shard(key)
+	shard.mux.Lock()
+	defer shard.mux.Unlock()
+
+	now := s.now()
+	if now.Sub(shard.sweptAt) >= rateLimitSweepInterval {
+		for k, b := range shard.buckets {
+			if !now.Before(b.fullAt) {
+				delete(shard.buckets, k)
+			}
+		}
+		shard.sweptAt = now
+	}
+	var bucket *entity.TokenBucket
+	if b, ok := shard.buckets[key]; ok {
+		bucket = &b.TokenBucket
+	}
+	bucket, result := limit.Take(bucket, now)
+	if result.Allowed {
+		shard.buckets[key] = &rateLimitBucket{
+			TokenBucket: *bucket,
+			fullAt:      now.Add(result.ResetAfter),
+		}
+	}
+
+	return result, nil
+}
+
+func (s *MemoryRateLimitStore) shard(key string) *rateLimitShard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return s.shards[h.Sum32()%uint32(len(s.shards))]
+}
@@ -0,0 +1,125 @@
+package adapter
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMemoryRateLimitStore(t *testing.T) {
+	tests := []struct {
+		name       string
+		shardCount int
+		want       int
+	}{
+		{
+			name:       "return store with shards",
+			shardCount: 4,
+			want:       4,
+		},
+		{
+			name:       "return store with one shard when count is not positive",
+			shardCount: 0,
+			want:       1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMemoryRateLimitStore(tt.shardCount)
+			assert.Len(t, got.shards, tt.want)
+		})
+	}
+}
+
+func TestMemoryRateLimitStore_Take(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	limit := entity.RateLimit{Limit: 2, Period: time.Minute}
+	tests := []struct {
+		name    string
+		takes   []string
+		elapsed []time.Duration
+		want    []bool
+	}{
+		{
+			name:  "refuse once the bucket is empty",
+			takes: []string{"test_key_001", "test_key_001", "test_key_001"},
+			want:  []bool{true, true, false},
+		},
+		{
+			name:  "count keys on their own",
+			takes: []string{"test_key_001", "test_key_001", "test_key_002", "test_key_002"},
+			want:  []bool{true, true, true, true},
+		},
+		{
+			name:    "allow again once a token is refilled",
+			takes:   []string{"test_key_001", "test_key_001", "test_key_001", "test_key_001"},
+			elapsed: []time.Duration{0, 0, 0, 30 * time.Second},
+			want:    []bool{true, true, false, true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			s := NewMemoryRateLimitStore(4)
+			current := now
+			s.now = func() time.Time { return current }
+
+			for i, key := range tt.takes {
+				if tt.elapsed != nil {
+					current = current.Add(tt.elapsed[i])
+				}
+				got, err := s.Take(ctx, key, limit)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want[i], got.Allowed, "take %d of %s", i, key)
+			}
+		})
+	}
+}
+
+func TestMemoryRateLimitStore_shard(t *testing.T) {
+	s := NewMemoryRateLimitStore(8)
+	used := make(map[*rateLimitShard]bool)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("test_key_%03d", i)
+		shard := s.shard(key)
+		assert.Same(t, shard, s.shard(key), "key must stay in its shard")
+		used[shard] = true
+	}
+	assert.Greater(t, len(used), 1, "keys must be spread over shards")
+}
+
+func TestMemoryRateLimitStore_Take_sweep(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	limit := entity.RateLimit{Limit: 2, Period: time.Minute}
+	s := NewMemoryRateLimitStore(1)
+	current := now
+	s.now = func() time.Time { return current }
+
+	_, err := s.Take(ctx, "test_key_001", limit)
+	assert.NoError(t, err)
+	current = current.Add(10 * time.Second)
+	_, err = s.Take(ctx, "test_key_002", limit)
+	assert.NoError(t, err)
+	_, err = s.Take(ctx, "test_key_002", limit)
+	assert.NoError(t, err)
+	assert.Len(t, s.shards[0].buckets, 2)
+
+	// test_key_001 is full again after 30s, test_key_002 only after 70s.
+	current = now.Add(rateLimitSweepInterval)
+	_, err = s.Take(ctx, "test_key_003", limit)
+	assert.NoError(t, err)
+	assert.Contains(t, s.shards[0].buckets, "test_key_002")
+	assert.Contains(t, s.shards[0].buckets, "test_key_003")
+	assert.NotContains(t, s.shards[0].buckets, "test_key_001")
+
+	// Sweeps wait for the interval to pass again.
+	current = current.Add(rateLimitSweepInterval / 2)
+	_, err = s.Take(ctx, "test_key_003", limit)
+	assert.NoError(t, err)
+	assert.Contains(t, s.shards[0].buckets, "test_key_002")
+}
@@ -0,0 +1,108 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+var allEmailLoginCodeColumns = []string{
+	"id",
+	"user_id",
+	"code_hash",
+	"attempts",
+	"expires_at",
+}
+
+type EmailLoginCodeRow struct {
+	ID        string    `db:"id" json:"id"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	CodeHash  string    `db:"code_hash" json:"code_hash"`
+	Attempts  int       `db:"attempts" json:"attempts"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+}
+
+type EmailLoginCodeAccess struct {
+}
+
+func NewEmailLoginCodeAccess() *EmailLoginCodeAccess {
+	return &EmailLoginCodeAccess{}
+}
+
+// GetForUpdate locks the row, so that concurrent attempts are counted one
+// after another.
+func (a *EmailLoginCodeAccess) GetForUpdate(ctx context.Context, tx Transaction, id entity.ID) (*EmailLoginCodeRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM email_login_codes WHERE id = ? FOR UPDATE",
+		strings.Join(allEmailLoginCodeColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, id)
+
+	var row EmailLoginCodeRow
+	err := tx.Get(ctx, &row, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (a *EmailLoginCodeAccess) Create(ctx context.Context, tx Transaction, row *EmailLoginCodeRow) error {
+	query := `
+INSERT INTO email_login_codes (id, user_id, code_hash, attempts, expires_at)
+VALUES (:id, :user_id, :code_hash, :attempts, :expires_at)
+`
+	defer printQueryExecuted(ctx, query, EmailLoginCodeRow{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		CodeHash:  "*****",
+		Attempts:  row.Attempts,
+		ExpiresAt: row.ExpiresAt,
+	})
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *EmailLoginCodeAccess) IncrementAttempts(ctx context.Context, tx Transaction, id entity.ID) error {
+	query := "UPDATE email_login_codes SET attempts = attempts + 1 WHERE id = ?"
+	defer printQueryExecuted(ctx, query, id)
+
+	_, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *EmailLoginCodeAccess) Delete(ctx context.Context, tx Transaction, id entity.ID) error {
+	query := "DELETE FROM email_login_codes WHERE id = ?"
+	defer printQueryExecuted(ctx, query, id)
+
+	_, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *EmailLoginCodeAccess) DeleteByUserID(ctx context.Context, tx Transaction, userID entity.ID) error {
+	query := "DELETE FROM email_login_codes WHERE user_id = ?"
+	defer printQueryExecuted(ctx, query, userID)
+
+	_, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
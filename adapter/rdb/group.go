@@ -0,0 +1,268 @@
+package rdb
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type GroupRow struct {
+	ID          string `db:"id" json:"id"`
+	Name        string `db:"name" json:"name"`
+	Description string `db:"description" json:"description"`
+}
+
+type GroupAccess struct {
+}
+
+func NewGroupAccess() *GroupAccess {
+	return &GroupAccess{}
+}
+
+func (a *GroupAccess) List(ctx context.Context, tx Transaction) ([]*GroupRow, error) {
+	query := "SELECT id, name, description FROM `groups` ORDER BY name"
+	defer printQueryExecuted(ctx, query)
+
+	var rows []*GroupRow
+	err := tx.Select(ctx, &rows, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (a *GroupAccess) Get(ctx context.Context, tx Transaction, id entity.ID) (*GroupRow, error) {
+	query := "SELECT id, name, description FROM `groups` WHERE id = ?"
+	defer printQueryExecuted(ctx, query, id)
+
+	var row GroupRow
+	err := tx.Get(ctx, &row, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (a *GroupAccess) GetByName(ctx context.Context, tx Transaction, name string) (*GroupRow, error) {
+	query := "SELECT id, name, description FROM `groups` WHERE name = ?"
+	defer printQueryExecuted(ctx, query, name)
+
+	var row GroupRow
+	err := tx.Get(ctx, &row, query, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (a *GroupAccess) Create(ctx context.Context, tx Transaction, row *GroupRow) error {
+	query := "INSERT INTO `groups` (id, name, description) VALUES (:id, :name, :description)"
+	defer printQueryExecuted(ctx, query, row)
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *GroupAccess) Update(ctx context.Context, tx Transaction, row *GroupRow) error {
+	query := "UPDATE `groups` SET name = :name, description = :description WHERE id = :id"
+	defer printQueryExecuted(ctx, query, row)
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete reports whether there was the group. Its memberships are left to
+// the caller.
+func (a *GroupAccess) Delete(ctx context.Context, tx Transaction, id entity.ID) (bool, error) {
+	query := "DELETE FROM `groups` WHERE id = ?"
+	defer printQueryExecuted(ctx, query, id)
+
+	result, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (a *GroupAccess) ListUserIDs(ctx context.Context, tx Transaction, id entity.ID) ([]string, error) {
+	query := "SELECT user_id FROM group_users WHERE group_id = ? ORDER BY user_id"
+	defer printQueryExecuted(ctx, query, id)
+
+	var userIDs []string
+	err := tx.Select(ctx, &userIDs, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+func (a *GroupAccess) ListSubgroups(ctx context.Context, tx Transaction, id entity.ID) ([]*GroupRow, error) {
+	query := "SELECT g.id, g.name, g.description FROM group_subgroups s INNER JOIN `groups` g ON g.id = s.subgroup_id WHERE s.group_id = ? ORDER BY g.name"
+	defer printQueryExecuted(ctx, query, id)
+
+	var rows []*GroupRow
+	err := tx.Select(ctx, &rows, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// ListByUserID returns the groups the user is a member of, directly or
+// through subgroups. UNION drops the groups already found, so that the
+// recursion ends even on cycles.
+func (a *GroupAccess) ListByUserID(ctx context.Context, tx Transaction, userID entity.ID) ([]*GroupRow, error) {
+	query := `
+WITH RECURSIVE memberships (group_id) AS (
+  SELECT group_id FROM group_users WHERE user_id = ?
+  UNION
+  SELECT s.group_id FROM group_subgroups s INNER JOIN memberships m ON s.subgroup_id = m.group_id
+)
+SELECT g.id, g.name, g.description
+FROM memberships m INNER JOIN ` + "`groups`" + ` g ON g.id = m.group_id
+ORDER BY g.name
+`
+	defer printQueryExecuted(ctx, query, userID)
+
+	var rows []*GroupRow
+	err := tx.Select(ctx, &rows, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// ListAncestorIDs returns the group and the groups it is nested in,
+// directly or not.
+func (a *GroupAccess) ListAncestorIDs(ctx context.Context, tx Transaction, id entity.ID) ([]string, error) {
+	query := `
+WITH RECURSIVE ancestors (group_id) AS (
+  SELECT CAST(? AS CHAR(40))
+  UNION
+  SELECT s.group_id FROM group_subgroups s INNER JOIN ancestors a ON s.subgroup_id = a.group_id
+)
+SELECT group_id FROM ancestors
+`
+	defer printQueryExecuted(ctx, query, id)
+
+	var ids []string
+	err := tx.Select(ctx, &ids, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// LockSubgroups locks every nesting, and the gaps between them, until the
+// end of the transaction. Nestings made concurrently could otherwise form a
+// cycle together that neither sees.
+func (a *GroupAccess) LockSubgroups(ctx context.Context, tx Transaction) error {
+	query := "SELECT COUNT(*) FROM group_subgroups FOR UPDATE"
+	defer printQueryExecuted(ctx, query)
+
+	var count int
+	err := tx.Get(ctx, &count, query)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddUser does nothing when the user is already a member.
+func (a *GroupAccess) AddUser(ctx context.Context, tx Transaction, id entity.ID, userID entity.ID) error {
+	query := "INSERT IGNORE INTO group_users (group_id, user_id) VALUES (?, ?)"
+	defer printQueryExecuted(ctx, query, id, userID)
+
+	_, err := tx.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveUser reports whether the user was a member.
+func (a *GroupAccess) RemoveUser(ctx context.Context, tx Transaction, id entity.ID, userID entity.ID) (bool, error) {
+	query := "DELETE FROM group_users WHERE group_id = ? AND user_id = ?"
+	defer printQueryExecuted(ctx, query, id, userID)
+
+	result, err := tx.Exec(ctx, query, id, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// AddSubgroup does nothing when the subgroup is already nested.
+func (a *GroupAccess) AddSubgroup(ctx context.Context, tx Transaction, id entity.ID, subgroupID entity.ID) error {
+	query := "INSERT IGNORE INTO group_subgroups (group_id, subgroup_id) VALUES (?, ?)"
+	defer printQueryExecuted(ctx, query, id, subgroupID)
+
+	_, err := tx.Exec(ctx, query, id, subgroupID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// RemoveSubgroup reports whether the subgroup was nested.
+func (a *GroupAccess) RemoveSubgroup(ctx context.Context, tx Transaction, id entity.ID, subgroupID entity.ID) (bool, error) {
+	query := "DELETE FROM group_subgroups WHERE group_id = ? AND subgroup_id = ?"
+	defer printQueryExecuted(ctx, query, id, subgroupID)
+
+	result, err := tx.Exec(ctx, query, id, subgroupID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteMemberships deletes the group's members, and its nesting in
+// other groups and theirs in it.
+func (a *GroupAccess) DeleteMemberships(ctx context.Context, tx Transaction, id entity.ID) error {
+	query := "DELETE FROM group_users WHERE group_id = ?"
+	defer printQueryExecuted(ctx, query, id)
+	if _, err := tx.Exec(ctx, query, id); err != nil {
+		return err
+	}
+
+	nestingQuery := "DELETE FROM group_subgroups WHERE group_id = ? OR subgroup_id = ?"
+	defer printQueryExecuted(ctx, nestingQuery, id, id)
+	if _, err := tx.Exec(ctx, nestingQuery, id, id); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+var allLoginFailureColumns = []string{
+	"user_id",
+	"failed_count",
+	"last_failed_at",
+}
+
+type LoginFailureRow struct {
+	UserID       string    `db:"user_id" json:"user_id"`
+	FailedCount  int       `db:"failed_count" json:"failed_count"`
+	LastFailedAt time.Time `db:"last_failed_at" json:"last_failed_at"`
+}
+
+type LoginFailureAccess struct {
+}
+
+func NewLoginFailureAccess() *LoginFailureAccess {
+	return &LoginFailureAccess{}
+}
+
+func (a *LoginFailureAccess) Get(ctx context.Context, tx Transaction, userID entity.ID) (*LoginFailureRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM login_failures WHERE user_id = ?",
+		strings.Join(allLoginFailureColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, userID)
+
+	var row LoginFailureRow
+	err := tx.Get(ctx, &row, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// Increment counts a failure in a single statement, so that concurrent
+// failures are all counted. The count restarts when the last failure was
+// at or before forgetBefore.
+func (a *LoginFailureAccess) Increment(ctx context.Context, tx Transaction, userID entity.ID, failedAt time.Time, forgetBefore time.Time) error {
+	query := `
+INSERT INTO login_failures (user_id, failed_count, last_failed_at)
+VALUES (?, 1, ?)
+ON DUPLICATE KEY UPDATE
+  failed_count = IF(last_failed_at <= ?, 1, failed_count + 1),
+  last_failed_at = ?
+`
+	defer printQueryExecuted(ctx, query, userID, failedAt, forgetBefore, failedAt)
+
+	_, err := tx.Exec(ctx, query, userID, failedAt, forgetBefore, failedAt)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *LoginFailureAccess) Delete(ctx context.Context, tx Transaction, userID entity.ID) error {
+	query := "DELETE FROM login_failures WHERE user_id = ?"
+	defer printQueryExecuted(ctx, query, userID)
+
+	_, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
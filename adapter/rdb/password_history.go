@@ -0,0 +1,102 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+var allPasswordHistoryColumns = []string{
+	"id",
+	"user_id",
+	"password",
+	"password_key_id",
+}
+
+type PasswordHistoryRow struct {
+	ID            string `db:"id" json:"id"`
+	UserID        string `db:"user_id" json:"user_id"`
+	Password      string `db:"password" json:"password"`
+	PasswordKeyID string `db:"password_key_id" json:"password_key_id"`
+}
+
+type PasswordHistoryAccess struct {
+}
+
+func NewPasswordHistoryAccess() *PasswordHistoryAccess {
+	return &PasswordHistoryAccess{}
+}
+
+// ListRecentByUserID returns up to limit rows, most recent first.
+func (a *PasswordHistoryAccess) ListRecentByUserID(ctx context.Context, tx Transaction, userID entity.ID, limit int) ([]*PasswordHistoryRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM password_history WHERE user_id = ? ORDER BY created_at DESC, id DESC LIMIT ?",
+		strings.Join(allPasswordHistoryColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, userID, limit)
+
+	var rows []*PasswordHistoryRow
+	err := tx.Select(ctx, &rows, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (a *PasswordHistoryAccess) Create(ctx context.Context, tx Transaction, row *PasswordHistoryRow) error {
+	query := `
+INSERT INTO password_history (id, user_id, password, password_key_id)
+VALUES (:id, :user_id, :password, :password_key_id)
+`
+	defer printQueryExecuted(ctx, query, PasswordHistoryRow{
+		ID:            row.ID,
+		UserID:        row.UserID,
+		Password:      "*****",
+		PasswordKeyID: row.PasswordKeyID,
+	})
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListByPasswordKeyIDNot returns up to limit rows whose password is not
+// wrapped with keyID.
+func (a *PasswordHistoryAccess) ListByPasswordKeyIDNot(ctx context.Context, tx Transaction, keyID string, limit int) ([]*PasswordHistoryRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM password_history WHERE password_key_id <> ? ORDER BY id LIMIT ? FOR UPDATE",
+		strings.Join(allPasswordHistoryColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, keyID, limit)
+
+	var rows []*PasswordHistoryRow
+	err := tx.Select(ctx, &rows, query, keyID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (a *PasswordHistoryAccess) UpdatePasswordKeyByID(ctx context.Context, tx Transaction, row *PasswordHistoryRow) error {
+	query := "UPDATE password_history SET password = :password, password_key_id = :password_key_id WHERE id = :id"
+	defer printQueryExecuted(ctx, query, PasswordHistoryRow{
+		ID:            row.ID,
+		UserID:        row.UserID,
+		Password:      "*****",
+		PasswordKeyID: row.PasswordKeyID,
+	})
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var allRateLimitBucketColumns = []string{
+	"bucket_key",
+	"tokens",
+	"updated_at",
+	"full_at",
+}
+
+type RateLimitBucketRow struct {
+	Key       string    `db:"bucket_key" json:"bucket_key"`
+	Tokens    float64   `db:"tokens" json:"tokens"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	FullAt    time.Time `db:"full_at" json:"full_at"`
+}
+
+type RateLimitBucketAccess struct {
+}
+
+func NewRateLimitBucketAccess() *RateLimitBucketAccess {
+	return &RateLimitBucketAccess{}
+}
+
+// GetForUpdate locks the bucket until the end of the transaction, so that
+// concurrent requests take tokens one after another.
+func (a *RateLimitBucketAccess) GetForUpdate(ctx context.Context, tx Transaction, key string) (*RateLimitBucketRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM rate_limit_buckets WHERE bucket_key = ? FOR UPDATE",
+		strings.Join(allRateLimitBucketColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, key)
+
+	var row RateLimitBucketRow
+	err := tx.Get(ctx, &row, query, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// Save creates the bucket or overwrites it.
+func (a *RateLimitBucketAccess) Save(ctx context.Context, tx Transaction, row *RateLimitBucketRow) error {
+	query := `
+INSERT INTO rate_limit_buckets (bucket_key, tokens, updated_at, full_at)
+VALUES (:bucket_key, :tokens, :updated_at, :full_at)
+ON DUPLICATE KEY UPDATE
+  tokens = VALUES(tokens),
+  updated_at = VALUES(updated_at),
+  full_at = VALUES(full_at)
+`
+	defer printQueryExecuted(ctx, query, row)
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *RateLimitBucketAccess) DeleteFull(ctx context.Context, tx Transaction, now time.Time) error {
+	query := "DELETE FROM rate_limit_buckets WHERE full_at <= ?"
+	defer printQueryExecuted(ctx, query, now)
+
+	_, err := tx.Exec(ctx, query, now)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package rdb
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type RecoveryCodeRow struct {
+	ID       string `db:"id" json:"id"`
+	UserID   string `db:"user_id" json:"user_id"`
+	CodeHash string `db:"code_hash" json:"code_hash"`
+}
+
+type RecoveryCodeAccess struct {
+}
+
+func NewRecoveryCodeAccess() *RecoveryCodeAccess {
+	return &RecoveryCodeAccess{}
+}
+
+func (a *RecoveryCodeAccess) Create(ctx context.Context, tx Transaction, row *RecoveryCodeRow) error {
+	query := `
+INSERT INTO recovery_codes (id, user_id, code_hash)
+VALUES (:id, :user_id, :code_hash)
+`
+	defer printQueryExecuted(ctx, query, row)
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Use marks the unused code with codeHash as used and reports whether there
+// was one.
+func (a *RecoveryCodeAccess) Use(ctx context.Context, tx Transaction, userID entity.ID, codeHash string) (bool, error) {
+	query := "UPDATE recovery_codes SET used_at = CURRENT_TIMESTAMP WHERE user_id = ? AND code_hash = ? AND used_at IS NULL"
+	defer printQueryExecuted(ctx, query, userID, codeHash)
+
+	result, err := tx.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (a *RecoveryCodeAccess) DeleteByUserID(ctx context.Context, tx Transaction, userID entity.ID) error {
+	query := "DELETE FROM recovery_codes WHERE user_id = ?"
+	defer printQueryExecuted(ctx, query, userID)
+
+	_, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
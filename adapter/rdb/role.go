@@ -0,0 +1,175 @@
+package rdb
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+// RolePermissionRow is a role with one of its permissions, or without any
+// when Permission is null.
+type RolePermissionRow struct {
+	RoleName   string         `db:"role_name" json:"role_name"`
+	Permission sql.NullString `db:"permission" json:"permission"`
+}
+
+type RoleAccess struct {
+}
+
+func NewRoleAccess() *RoleAccess {
+	return &RoleAccess{}
+}
+
+func (a *RoleAccess) List(ctx context.Context, tx Transaction) ([]*RolePermissionRow, error) {
+	query := `
+SELECT r.name AS role_name, p.permission
+FROM roles r LEFT JOIN role_permissions p ON p.role_name = r.name
+ORDER BY r.name, p.permission
+`
+	defer printQueryExecuted(ctx, query)
+
+	var rows []*RolePermissionRow
+	err := tx.Select(ctx, &rows, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Get returns no rows for unknown roles.
+func (a *RoleAccess) Get(ctx context.Context, tx Transaction, name string) ([]*RolePermissionRow, error) {
+	query := `
+SELECT r.name AS role_name, p.permission
+FROM roles r LEFT JOIN role_permissions p ON p.role_name = r.name
+WHERE r.name = ?
+ORDER BY p.permission
+`
+	defer printQueryExecuted(ctx, query, name)
+
+	var rows []*RolePermissionRow
+	err := tx.Select(ctx, &rows, query, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (a *RoleAccess) ListByUserID(ctx context.Context, tx Transaction, userID entity.ID) ([]*RolePermissionRow, error) {
+	query := `
+SELECT r.name AS role_name, p.permission
+FROM user_roles u
+INNER JOIN roles r ON r.name = u.role_name
+LEFT JOIN role_permissions p ON p.role_name = r.name
+WHERE u.user_id = ?
+ORDER BY r.name, p.permission
+`
+	defer printQueryExecuted(ctx, query, userID)
+
+	var rows []*RolePermissionRow
+	err := tx.Select(ctx, &rows, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Create adds the role unless it exists.
+func (a *RoleAccess) Create(ctx context.Context, tx Transaction, name string) error {
+	query := "INSERT INTO roles (name) VALUES (?) ON DUPLICATE KEY UPDATE updated_at = CURRENT_TIMESTAMP"
+	defer printQueryExecuted(ctx, query, name)
+
+	_, err := tx.Exec(ctx, query, name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *RoleAccess) CreatePermission(ctx context.Context, tx Transaction, name string, permission entity.Permission) error {
+	query := "INSERT INTO role_permissions (role_name, permission) VALUES (?, ?)"
+	defer printQueryExecuted(ctx, query, name, permission)
+
+	_, err := tx.Exec(ctx, query, name, permission)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *RoleAccess) DeletePermissions(ctx context.Context, tx Transaction, name string) error {
+	query := "DELETE FROM role_permissions WHERE role_name = ?"
+	defer printQueryExecuted(ctx, query, name)
+
+	_, err := tx.Exec(ctx, query, name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete reports whether there was the role. Its permissions and
+// assignments are left to the caller.
+func (a *RoleAccess) Delete(ctx context.Context, tx Transaction, name string) (bool, error) {
+	query := "DELETE FROM roles WHERE name = ?"
+	defer printQueryExecuted(ctx, query, name)
+
+	result, err := tx.Exec(ctx, query, name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Assign does nothing when the user already has the role.
+func (a *RoleAccess) Assign(ctx context.Context, tx Transaction, userID entity.ID, name string) error {
+	query := "INSERT IGNORE INTO user_roles (user_id, role_name) VALUES (?, ?)"
+	defer printQueryExecuted(ctx, query, userID, name)
+
+	_, err := tx.Exec(ctx, query, userID, name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Unassign reports whether the user had the role.
+func (a *RoleAccess) Unassign(ctx context.Context, tx Transaction, userID entity.ID, name string) (bool, error) {
+	query := "DELETE FROM user_roles WHERE user_id = ? AND role_name = ?"
+	defer printQueryExecuted(ctx, query, userID, name)
+
+	result, err := tx.Exec(ctx, query, userID, name)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (a *RoleAccess) DeleteAssignments(ctx context.Context, tx Transaction, name string) error {
+	query := "DELETE FROM user_roles WHERE role_name = ?"
+	defer printQueryExecuted(ctx, query, name)
+
+	_, err := tx.Exec(ctx, query, name)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+var allSessionColumns = []string{
+	"id",
+	"user_id",
+	"token_hash",
+	"level",
+	"available_level",
+	"methods",
+	"auth_time",
+	"user_agent",
+	"ip_address",
+	"last_seen_at",
+	"expires_at",
+	"created_at",
+}
+
+type SessionRow struct {
+	ID             string    `db:"id" json:"id"`
+	UserID         string    `db:"user_id" json:"user_id"`
+	TokenHash      string    `db:"token_hash" json:"token_hash"`
+	Level          string    `db:"level" json:"level"`
+	AvailableLevel string    `db:"available_level" json:"available_level"`
+	Methods        string    `db:"methods" json:"methods"`
+	AuthTime       time.Time `db:"auth_time" json:"auth_time"`
+	UserAgent      string    `db:"user_agent" json:"user_agent"`
+	IPAddress      string    `db:"ip_address" json:"ip_address"`
+	LastSeenAt     time.Time `db:"last_seen_at" json:"last_seen_at"`
+	ExpiresAt      time.Time `db:"expires_at" json:"expires_at"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+type SessionAccess struct {
+}
+
+func NewSessionAccess() *SessionAccess {
+	return &SessionAccess{}
+}
+
+func (a *SessionAccess) Get(ctx context.Context, tx Transaction, id entity.ID) (*SessionRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM sessions WHERE id = ?",
+		strings.Join(allSessionColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, id)
+
+	var row SessionRow
+	err := tx.Get(ctx, &row, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (a *SessionAccess) GetByTokenHash(ctx context.Context, tx Transaction, tokenHash string) (*SessionRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM sessions WHERE token_hash = ?",
+		strings.Join(allSessionColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, "*****")
+
+	var row SessionRow
+	err := tx.Get(ctx, &row, query, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (a *SessionAccess) ListByUserID(ctx context.Context, tx Transaction, userID entity.ID) ([]*SessionRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM sessions WHERE user_id = ? ORDER BY created_at DESC, id DESC",
+		strings.Join(allSessionColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, userID)
+
+	var rows []*SessionRow
+	err := tx.Select(ctx, &rows, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (a *SessionAccess) Create(ctx context.Context, tx Transaction, row *SessionRow) error {
+	query := `
+INSERT INTO sessions (id, user_id, token_hash, level, available_level, methods, auth_time, user_agent, ip_address, last_seen_at, expires_at, created_at)
+VALUES (:id, :user_id, :token_hash, :level, :available_level, :methods, :auth_time, :user_agent, :ip_address, :last_seen_at, :expires_at, :created_at)
+`
+	masked := *row
+	masked.TokenHash = "*****"
+	defer printQueryExecuted(ctx, query, masked)
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *SessionAccess) UpdateLastSeenAt(ctx context.Context, tx Transaction, id entity.ID, lastSeenAt time.Time) error {
+	query := "UPDATE sessions SET last_seen_at = ? WHERE id = ?"
+	defer printQueryExecuted(ctx, query, lastSeenAt, id)
+
+	_, err := tx.Exec(ctx, query, lastSeenAt, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *SessionAccess) Delete(ctx context.Context, tx Transaction, id entity.ID) error {
+	query := "DELETE FROM sessions WHERE id = ?"
+	defer printQueryExecuted(ctx, query, id)
+
+	_, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteByUserID deletes the user's sessions other than exceptID, which may
+// be empty.
+func (a *SessionAccess) DeleteByUserID(ctx context.Context, tx Transaction, userID entity.ID, exceptID entity.ID) error {
+	query := "DELETE FROM sessions WHERE user_id = ? AND id <> ?"
+	defer printQueryExecuted(ctx, query, userID, exceptID)
+
+	_, err := tx.Exec(ctx, query, userID, exceptID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *SessionAccess) DeleteExpired(ctx context.Context, tx Transaction, now time.Time) error {
+	query := "DELETE FROM sessions WHERE expires_at <= ?"
+	defer printQueryExecuted(ctx, query, now)
+
+	_, err := tx.Exec(ctx, query, now)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
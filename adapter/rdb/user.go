@@ -13,12 +13,16 @@ var allUserColumns = []string{
 	"id",
 	"name",
 	"email",
+	"email_verified",
+	"normalized_email",
 }
 
 type UserRow struct {
-	ID    string `db:"id" json:"id"`
-	Name  string `db:"name" json:"name"`
-	Email string `db:"email" json:"email"`
+	ID              string `db:"id" json:"id"`
+	Name            string `db:"name" json:"name"`
+	Email           string `db:"email" json:"email"`
+	EmailVerified   bool   `db:"email_verified" json:"email_verified"`
+	NormalizedEmail string `db:"normalized_email" json:"normalized_email"`
 }
 
 type UserAccess struct {
@@ -49,7 +53,7 @@ func (a *UserAccess) ListCount(ctx context.Context, tx Transaction, input port.U
 	var where []string
 	var args []interface{}
 	if input.Email != nil {
-		where = append(where, "email = ?")
+		where = append(where, "normalized_email = ?")
 		args = append(args, *input.Email)
 	}
 	if len(where) > 0 {
@@ -74,7 +78,7 @@ func (a *UserAccess) List(ctx context.Context, tx Transaction, input port.UserLi
 	var where []string
 	var args []interface{}
 	if input.Email != nil {
-		where = append(where, "email = ?")
+		where = append(where, "normalized_email = ?")
 		args = append(args, *input.Email)
 	}
 	if len(where) > 0 {
@@ -93,8 +97,8 @@ func (a *UserAccess) List(ctx context.Context, tx Transaction, input port.UserLi
 
 func (a *UserAccess) Create(ctx context.Context, tx Transaction, row *UserRow) error {
 	query := `
-INSERT INTO users (id, name, email)
-VALUES (:id, :name, :email)
+INSERT INTO users (id, name, email, email_verified, normalized_email)
+VALUES (:id, :name, :email, :email_verified, :normalized_email)
 `
 	defer printQueryExecuted(ctx, query, row)
 
@@ -107,7 +111,7 @@ VALUES (:id, :name, :email)
 }
 
 func (a *UserAccess) Update(ctx context.Context, tx Transaction, row *UserRow) error {
-	query := "UPDATE users SET name = :name, email = :email WHERE id = :id"
+	query := "UPDATE users SET name = :name, email = :email, email_verified = :email_verified, normalized_email = :normalized_email WHERE id = :id"
 	defer printQueryExecuted(ctx, query, row)
 
 	_, err := tx.NamedExec(ctx, query, row)
@@ -13,13 +13,17 @@ var allUserCredentialColumns = []string{
 	"u.id user_id",
 	"u.email",
 	"c.password",
+	"c.password_breached",
+	"c.password_key_id",
 }
 
 type UserCredentialRow struct {
-	ID       string `db:"id" json:"id"`
-	UserID   string `db:"user_id" json:"user_id"`
-	Email    string `db:"email" json:"email"`
-	Password string `db:"password" json:"password"`
+	ID               string `db:"id" json:"id"`
+	UserID           string `db:"user_id" json:"user_id"`
+	Email            string `db:"email" json:"email"`
+	Password         string `db:"password" json:"password"`
+	PasswordBreached bool   `db:"password_breached" json:"password_breached"`
+	PasswordKeyID    string `db:"password_key_id" json:"password_key_id"`
 }
 
 type UserCredentialAccess struct {
@@ -47,7 +51,7 @@ func (a *UserCredentialAccess) GetByUserID(ctx context.Context, tx Transaction,
 
 func (a *UserCredentialAccess) GetByEmail(ctx context.Context, tx Transaction, email entity.Email) (*UserCredentialRow, error) {
 	query := fmt.Sprintf(
-		"SELECT %s FROM user_credentials c INNER JOIN users u ON c.user_id = u.id WHERE u.email = ?",
+		"SELECT %s FROM user_credentials c INNER JOIN users u ON c.user_id = u.id WHERE u.normalized_email = ?",
 		strings.Join(allUserCredentialColumns, ", "),
 	)
 	defer printQueryExecuted(ctx, query, email)
@@ -63,10 +67,15 @@ func (a *UserCredentialAccess) GetByEmail(ctx context.Context, tx Transaction, e
 
 func (a *UserCredentialAccess) Create(ctx context.Context, tx Transaction, row *UserCredentialRow) error {
 	query := `
-INSERT INTO user_credentials (id, user_id, password)
-VALUES (:id, :user_id, :password)
+INSERT INTO user_credentials (id, user_id, password, password_key_id)
+VALUES (:id, :user_id, :password, :password_key_id)
 `
-	defer printQueryExecuted(ctx, query, row)
+	defer printQueryExecuted(ctx, query, UserCredentialRow{
+		ID:            row.ID,
+		UserID:        row.UserID,
+		Password:      "*****",
+		PasswordKeyID: row.PasswordKeyID,
+	})
 
 	_, err := tx.NamedExec(ctx, query, row)
 	if err != nil {
@@ -77,11 +86,62 @@ VALUES (:id, :user_id, :password)
 }
 
 func (a *UserCredentialAccess) UpdateByUserID(ctx context.Context, tx Transaction, row *UserCredentialRow) error {
-	query := "UPDATE user_credentials SET password = :password WHERE user_id = :user_id"
+	query := "UPDATE user_credentials SET password = :password, password_breached = :password_breached, password_key_id = :password_key_id WHERE user_id = :user_id"
+	defer printQueryExecuted(ctx, query, UserCredentialRow{
+		ID:               row.UserID,
+		UserID:           row.UserID,
+		Password:         "*****",
+		PasswordBreached: row.PasswordBreached,
+		PasswordKeyID:    row.PasswordKeyID,
+	})
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *UserCredentialAccess) UpdatePasswordBreachedByUserID(ctx context.Context, tx Transaction, userID entity.ID, breached bool) error {
+	query := "UPDATE user_credentials SET password_breached = ? WHERE user_id = ?"
+	defer printQueryExecuted(ctx, query, breached, userID)
+
+	_, err := tx.Exec(ctx, query, breached, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ListByPasswordKeyIDNot returns up to limit rows whose password is not
+// wrapped with keyID.
+func (a *UserCredentialAccess) ListByPasswordKeyIDNot(ctx context.Context, tx Transaction, keyID string, limit int) ([]*UserCredentialRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM user_credentials c INNER JOIN users u ON c.user_id = u.id WHERE c.password_key_id <> ? ORDER BY c.id LIMIT ? FOR UPDATE",
+		strings.Join(allUserCredentialColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, keyID, limit)
+
+	var rows []*UserCredentialRow
+	err := tx.Select(ctx, &rows, query, keyID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// UpdatePasswordKeyByID replaces the wrapped password without touching
+// anything else about the credential.
+func (a *UserCredentialAccess) UpdatePasswordKeyByID(ctx context.Context, tx Transaction, row *UserCredentialRow) error {
+	query := "UPDATE user_credentials SET password = :password, password_key_id = :password_key_id WHERE id = :id"
 	defer printQueryExecuted(ctx, query, UserCredentialRow{
-		ID:       row.UserID,
-		UserID:   row.UserID,
-		Password: "*****",
+		ID:            row.ID,
+		UserID:        row.UserID,
+		Password:      "*****",
+		PasswordKeyID: row.PasswordKeyID,
 	})
 
 	_, err := tx.NamedExec(ctx, query, row)
@@ -0,0 +1,109 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+var allUserTOTPColumns = []string{
+	"user_id",
+	"secret",
+	"confirmed",
+	"last_used_step",
+}
+
+type UserTOTPRow struct {
+	UserID       string `db:"user_id" json:"user_id"`
+	Secret       string `db:"secret" json:"secret"`
+	Confirmed    bool   `db:"confirmed" json:"confirmed"`
+	LastUsedStep int64  `db:"last_used_step" json:"last_used_step"`
+}
+
+type UserTOTPAccess struct {
+}
+
+func NewUserTOTPAccess() *UserTOTPAccess {
+	return &UserTOTPAccess{}
+}
+
+func (a *UserTOTPAccess) GetByUserID(ctx context.Context, tx Transaction, userID entity.ID) (*UserTOTPRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM user_totps WHERE user_id = ?",
+		strings.Join(allUserTOTPColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, userID)
+
+	var row UserTOTPRow
+	err := tx.Get(ctx, &row, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// Replace stores row in place of any enrollment of the same user.
+func (a *UserTOTPAccess) Replace(ctx context.Context, tx Transaction, row *UserTOTPRow) error {
+	query := `
+REPLACE INTO user_totps (user_id, secret, confirmed, last_used_step)
+VALUES (:user_id, :secret, :confirmed, :last_used_step)
+`
+	defer printQueryExecuted(ctx, query, UserTOTPRow{
+		UserID:       row.UserID,
+		Secret:       "*****",
+		Confirmed:    row.Confirmed,
+		LastUsedStep: row.LastUsedStep,
+	})
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *UserTOTPAccess) Confirm(ctx context.Context, tx Transaction, userID entity.ID, step int64) error {
+	query := "UPDATE user_totps SET confirmed = TRUE, last_used_step = ? WHERE user_id = ?"
+	defer printQueryExecuted(ctx, query, step, userID)
+
+	_, err := tx.Exec(ctx, query, step, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateLastUsedStep only moves the step forward and reports whether it
+// did, which makes checking and recording a code atomic.
+func (a *UserTOTPAccess) UpdateLastUsedStep(ctx context.Context, tx Transaction, userID entity.ID, step int64) (bool, error) {
+	query := "UPDATE user_totps SET last_used_step = ? WHERE user_id = ? AND last_used_step < ?"
+	defer printQueryExecuted(ctx, query, step, userID, step)
+
+	result, err := tx.Exec(ctx, query, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (a *UserTOTPAccess) DeleteByUserID(ctx context.Context, tx Transaction, userID entity.ID) error {
+	query := "DELETE FROM user_totps WHERE user_id = ?"
+	defer printQueryExecuted(ctx, query, userID)
+
+	_, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
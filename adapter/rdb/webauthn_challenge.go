@@ -0,0 +1,95 @@
+package rdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+var allWebAuthnChallengeColumns = []string{
+	"id",
+	"user_id",
+	"ceremony",
+	"challenge",
+	"expires_at",
+}
+
+type WebAuthnChallengeRow struct {
+	ID        string         `db:"id" json:"id"`
+	UserID    sql.NullString `db:"user_id" json:"user_id"`
+	Ceremony  string         `db:"ceremony" json:"ceremony"`
+	Challenge []byte         `db:"challenge" json:"challenge"`
+	ExpiresAt time.Time      `db:"expires_at" json:"expires_at"`
+}
+
+type WebAuthnChallengeAccess struct {
+}
+
+func NewWebAuthnChallengeAccess() *WebAuthnChallengeAccess {
+	return &WebAuthnChallengeAccess{}
+}
+
+func (a *WebAuthnChallengeAccess) Get(ctx context.Context, tx Transaction, id entity.ID) (*WebAuthnChallengeRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM webauthn_challenges WHERE id = ?",
+		strings.Join(allWebAuthnChallengeColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, id)
+
+	var row WebAuthnChallengeRow
+	err := tx.Get(ctx, &row, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (a *WebAuthnChallengeAccess) Create(ctx context.Context, tx Transaction, row *WebAuthnChallengeRow) error {
+	query := `
+INSERT INTO webauthn_challenges (id, user_id, ceremony, challenge, expires_at)
+VALUES (:id, :user_id, :ceremony, :challenge, :expires_at)
+`
+	defer printQueryExecuted(ctx, query, row)
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Delete reports whether the row existed, so that of two requests racing
+// to use a challenge only one succeeds.
+func (a *WebAuthnChallengeAccess) Delete(ctx context.Context, tx Transaction, id entity.ID) (bool, error) {
+	query := "DELETE FROM webauthn_challenges WHERE id = ?"
+	defer printQueryExecuted(ctx, query, id)
+
+	result, err := tx.Exec(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (a *WebAuthnChallengeAccess) DeleteExpired(ctx context.Context, tx Transaction, now time.Time) error {
+	query := "DELETE FROM webauthn_challenges WHERE expires_at <= ?"
+	defer printQueryExecuted(ctx, query, now)
+
+	_, err := tx.Exec(ctx, query, now)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package rdb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+var allWebAuthnCredentialColumns = []string{
+	"id",
+	"user_id",
+	"credential_id",
+	"public_key",
+	"sign_count",
+}
+
+type WebAuthnCredentialRow struct {
+	ID           string `db:"id" json:"id"`
+	UserID       string `db:"user_id" json:"user_id"`
+	CredentialID []byte `db:"credential_id" json:"credential_id"`
+	PublicKey    []byte `db:"public_key" json:"public_key"`
+	SignCount    uint32 `db:"sign_count" json:"sign_count"`
+}
+
+type WebAuthnCredentialAccess struct {
+}
+
+func NewWebAuthnCredentialAccess() *WebAuthnCredentialAccess {
+	return &WebAuthnCredentialAccess{}
+}
+
+func (a *WebAuthnCredentialAccess) ListByUserID(ctx context.Context, tx Transaction, userID entity.ID) ([]*WebAuthnCredentialRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM webauthn_credentials WHERE user_id = ? ORDER BY created_at, id",
+		strings.Join(allWebAuthnCredentialColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, userID)
+
+	var rows []*WebAuthnCredentialRow
+	err := tx.Select(ctx, &rows, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (a *WebAuthnCredentialAccess) GetByCredentialID(ctx context.Context, tx Transaction, credentialID []byte) (*WebAuthnCredentialRow, error) {
+	query := fmt.Sprintf(
+		"SELECT %s FROM webauthn_credentials WHERE credential_id = ?",
+		strings.Join(allWebAuthnCredentialColumns, ", "),
+	)
+	defer printQueryExecuted(ctx, query, credentialID)
+
+	var row WebAuthnCredentialRow
+	err := tx.Get(ctx, &row, query, credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (a *WebAuthnCredentialAccess) Create(ctx context.Context, tx Transaction, row *WebAuthnCredentialRow) error {
+	query := `
+INSERT INTO webauthn_credentials (id, user_id, credential_id, public_key, sign_count)
+VALUES (:id, :user_id, :credential_id, :public_key, :sign_count)
+`
+	defer printQueryExecuted(ctx, query, row)
+
+	_, err := tx.NamedExec(ctx, query, row)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (a *WebAuthnCredentialAccess) UpdateSignCount(ctx context.Context, tx Transaction, id entity.ID, signCount uint32) error {
+	query := "UPDATE webauthn_credentials SET sign_count = ?, last_used_at = CURRENT_TIMESTAMP WHERE id = ?"
+	defer printQueryExecuted(ctx, query, signCount, id)
+
+	_, err := tx.Exec(ctx, query, signCount, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package adapter
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"io"
+	"strings"
+
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// Recovery codes are 10 base32 characters, 50 random bits, which is enough
+// for a plain SHA-256 to be a safe way to store them.
+const recoveryCodeLength = 10
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var _ port.RecoveryCodeGateway = (*RecoveryCodeGateway)(nil)
+
+type RecoveryCodeGateway struct {
+	idgen              port.IDGenerator
+	recoveryCodeAccess *rdb.RecoveryCodeAccess
+}
+
+func NewRecoveryCodeGateway(idgen port.IDGenerator, recoveryCodeAccess *rdb.RecoveryCodeAccess) *RecoveryCodeGateway {
+	return &RecoveryCodeGateway{
+		idgen:              idgen,
+		recoveryCodeAccess: recoveryCodeAccess,
+	}
+}
+
+func (g *RecoveryCodeGateway) Generate(ctx context.Context, userID entity.ID, count int) ([]string, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.recoveryCodeAccess.DeleteByUserID(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, count)
+	for len(codes) < count {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		id, err := g.idgen.Generate()
+		if err != nil {
+			return nil, err
+		}
+		err = g.recoveryCodeAccess.Create(ctx, tx, &rdb.RecoveryCodeRow{
+			ID:       id.String(),
+			UserID:   userID.String(),
+			CodeHash: hashRecoveryCode(code),
+		})
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+
+	return codes, nil
+}
+
+func (g *RecoveryCodeGateway) Use(ctx context.Context, userID entity.ID, code string) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	used, err := g.recoveryCodeAccess.Use(ctx, tx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return usecase.ErrInvalidSecondFactor
+	}
+
+	return nil
+}
+
+func (g *RecoveryCodeGateway) DeleteByUserID(ctx context.Context, userID entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.recoveryCodeAccess.DeleteByUserID(ctx, tx, userID)
+}
+
+// generateRecoveryCode returns a code formatted as "xxxxx-xxxxx".
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))[:recoveryCodeLength]
+	return code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:], nil
+}
+
+// hashRecoveryCode ignores case and separators, which users tend to get
+// wrong when typing codes back in.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,155 @@
+package adapter
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.RoleGateway = (*RoleGateway)(nil)
+
+type RoleGateway struct {
+	roleAccess *rdb.RoleAccess
+}
+
+func NewRoleGateway(roleAccess *rdb.RoleAccess) *RoleGateway {
+	return &RoleGateway{
+		roleAccess: roleAccess,
+	}
+}
+
+func (g *RoleGateway) List(ctx context.Context) (entity.Roles, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := g.roleAccess.List(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rolesFromRows(rows), nil
+}
+
+func (g *RoleGateway) Get(ctx context.Context, name string) (*entity.Role, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := g.roleAccess.Get(ctx, tx, name)
+	if err != nil {
+		return nil, err
+	}
+	roles := rolesFromRows(rows)
+	if len(roles) == 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+
+	return roles[0], nil
+}
+
+func (g *RoleGateway) Save(ctx context.Context, input port.RoleSaveInput) (*entity.Role, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := g.roleAccess.Create(ctx, tx, input.Name); err != nil {
+		return nil, err
+	}
+	if err := g.roleAccess.DeletePermissions(ctx, tx, input.Name); err != nil {
+		return nil, err
+	}
+	for _, permission := range input.Permissions {
+		if err := g.roleAccess.CreatePermission(ctx, tx, input.Name, permission); err != nil {
+			return nil, err
+		}
+	}
+
+	return &entity.Role{
+		Name:        input.Name,
+		Permissions: input.Permissions,
+	}, nil
+}
+
+func (g *RoleGateway) Delete(ctx context.Context, name string) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := g.roleAccess.Delete(ctx, tx, name)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return usecase.ErrNotFoundEntity
+	}
+	if err := g.roleAccess.DeletePermissions(ctx, tx, name); err != nil {
+		return err
+	}
+
+	return g.roleAccess.DeleteAssignments(ctx, tx, name)
+}
+
+func (g *RoleGateway) ListByUserID(ctx context.Context, userID entity.ID) (entity.Roles, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := g.roleAccess.ListByUserID(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return rolesFromRows(rows), nil
+}
+
+func (g *RoleGateway) Assign(ctx context.Context, userID entity.ID, name string) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.roleAccess.Assign(ctx, tx, userID, name)
+}
+
+func (g *RoleGateway) Unassign(ctx context.Context, userID entity.ID, name string) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	unassigned, err := g.roleAccess.Unassign(ctx, tx, userID, name)
+	if err != nil {
+		return err
+	}
+	if !unassigned {
+		return usecase.ErrNotFoundEntity
+	}
+
+	return nil
+}
+
+// rolesFromRows groups rows ordered by role name.
+func rolesFromRows(rows []*rdb.RolePermissionRow) entity.Roles {
+	var roles entity.Roles
+	for _, row := range rows {
+		if len(roles) == 0 || roles[len(roles)-1].Name != row.RoleName {
+			roles = append(roles, &entity.Role{
+				Name: row.RoleName,
+			})
+		}
+		if row.Permission.Valid {
+			role := roles[len(roles)-1]
+			role.Permissions = append(role.Permissions, entity.Permission(row.Permission.String))
+		}
+	}
+	return roles
+}
@@ -0,0 +1,348 @@
+package adapter
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+const sessionTokenLength = 32
+
+var _ port.SessionStore = (*SessionStore)(nil)
+
+type SessionStore struct {
+	idgen         port.IDGenerator
+	sessionAccess *rdb.SessionAccess
+	now           func() time.Time
+}
+
+func NewSessionStore(
+	idgen port.IDGenerator,
+	sessionAccess *rdb.SessionAccess,
+) *SessionStore {
+	return &SessionStore{
+		idgen:         idgen,
+		sessionAccess: sessionAccess,
+		now:           time.Now,
+	}
+}
+
+func (s *SessionStore) Create(ctx context.Context, input port.SessionCreateInput) (*entity.Session, string, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := s.now()
+	if err := s.sessionAccess.DeleteExpired(ctx, tx, now); err != nil {
+		return nil, "", err
+	}
+	session, token, err := newSession(s.idgen, input, now)
+	if err != nil {
+		return nil, "", err
+	}
+	err = s.sessionAccess.Create(ctx, tx, &rdb.SessionRow{
+		ID:             session.ID.String(),
+		UserID:         session.UserID.String(),
+		TokenHash:      hashSessionToken(token),
+		Level:          session.Level.String(),
+		AvailableLevel: session.AvailableLevel.String(),
+		Methods:        strings.Join(session.Methods, " "),
+		AuthTime:       session.AuthTime,
+		UserAgent:      session.UserAgent,
+		IPAddress:      session.IPAddress,
+		LastSeenAt:     session.LastSeenAt,
+		ExpiresAt:      session.ExpiresAt,
+		CreatedAt:      session.CreatedAt,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	return session, token, nil
+}
+
+func (s *SessionStore) Get(ctx context.Context, id entity.ID) (*entity.Session, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.sessionAccess.Get(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return sessionFromRow(row)
+}
+
+func (s *SessionStore) GetByToken(ctx context.Context, token string) (*entity.Session, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := s.sessionAccess.GetByTokenHash(ctx, tx, hashSessionToken(token))
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, usecase.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return sessionFromRow(row)
+}
+
+func (s *SessionStore) ListByUserID(ctx context.Context, userID entity.ID) (entity.Sessions, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.sessionAccess.ListByUserID(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sessions := make(entity.Sessions, len(rows))
+	for i, row := range rows {
+		sessions[i], err = sessionFromRow(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sessions, nil
+}
+
+func (s *SessionStore) Touch(ctx context.Context, id entity.ID, lastSeenAt time.Time) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.sessionAccess.UpdateLastSeenAt(ctx, tx, id, lastSeenAt)
+}
+
+func (s *SessionStore) Delete(ctx context.Context, id entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.sessionAccess.Delete(ctx, tx, id)
+}
+
+func (s *SessionStore) DeleteByUserID(ctx context.Context, input port.SessionDeleteByUserIDInput) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var exceptID entity.ID
+	if input.ExceptID != nil {
+		exceptID = *input.ExceptID
+	}
+	return s.sessionAccess.DeleteByUserID(ctx, tx, input.UserID, exceptID)
+}
+
+func sessionFromRow(row *rdb.SessionRow) (*entity.Session, error) {
+	level, err := entity.ParseAuthLevel(row.Level)
+	if err != nil {
+		return nil, err
+	}
+	availableLevel, err := entity.ParseAuthLevel(row.AvailableLevel)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Session{
+		ID:             entity.ID(row.ID),
+		UserID:         entity.ID(row.UserID),
+		Level:          level,
+		AvailableLevel: availableLevel,
+		Methods:        strings.Fields(row.Methods),
+		AuthTime:       row.AuthTime,
+		UserAgent:      row.UserAgent,
+		IPAddress:      row.IPAddress,
+		CreatedAt:      row.CreatedAt,
+		LastSeenAt:     row.LastSeenAt,
+		ExpiresAt:      row.ExpiresAt,
+	}, nil
+}
+
+var _ port.SessionStore = (*MemorySessionStore)(nil)
+
+// MemorySessionStore keeps sessions in the process, for single instance
+// deployments. Its sessions are lost on restart.
+type MemorySessionStore struct {
+	idgen    port.IDGenerator
+	sessions map[string]*entity.Session
+	tokens   map[entity.ID]string
+	mux      sync.RWMutex
+	now      func() time.Time
+}
+
+func NewMemorySessionStore(idgen port.IDGenerator) *MemorySessionStore {
+	return &MemorySessionStore{
+		idgen:    idgen,
+		sessions: make(map[string]*entity.Session),
+		tokens:   make(map[entity.ID]string),
+		now:      time.Now,
+	}
+}
+
+func (s *MemorySessionStore) Create(ctx context.Context, input port.SessionCreateInput) (*entity.Session, string, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	now := s.now()
+	for hash, session := range s.sessions {
+		if !now.Before(session.ExpiresAt) {
+			s.delete(session.ID, hash)
+		}
+	}
+	session, token, err := newSession(s.idgen, input, now)
+	if err != nil {
+		return nil, "", err
+	}
+	hash := hashSessionToken(token)
+	s.sessions[hash] = session
+	s.tokens[session.ID] = hash
+
+	copied := *session
+	return &copied, token, nil
+}
+
+func (s *MemorySessionStore) Get(ctx context.Context, id entity.ID) (*entity.Session, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	hash, ok := s.tokens[id]
+	if !ok {
+		return nil, usecase.ErrNotFoundEntity
+	}
+
+	copied := *s.sessions[hash]
+	return &copied, nil
+}
+
+func (s *MemorySessionStore) GetByToken(ctx context.Context, token string) (*entity.Session, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	session, ok := s.sessions[hashSessionToken(token)]
+	if !ok {
+		return nil, usecase.ErrInvalidToken
+	}
+
+	copied := *session
+	return &copied, nil
+}
+
+func (s *MemorySessionStore) ListByUserID(ctx context.Context, userID entity.ID) (entity.Sessions, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	var sessions entity.Sessions
+	for _, session := range s.sessions {
+		if session.UserID != userID {
+			continue
+		}
+		copied := *session
+		sessions = append(sessions, &copied)
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if sessions[i].CreatedAt.Equal(sessions[j].CreatedAt) {
+			return sessions[i].ID > sessions[j].ID
+		}
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+
+	return sessions, nil
+}
+
+func (s *MemorySessionStore) Touch(ctx context.Context, id entity.ID, lastSeenAt time.Time) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if hash, ok := s.tokens[id]; ok {
+		s.sessions[hash].LastSeenAt = lastSeenAt
+	}
+
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(ctx context.Context, id entity.ID) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	if hash, ok := s.tokens[id]; ok {
+		s.delete(id, hash)
+	}
+
+	return nil
+}
+
+func (s *MemorySessionStore) DeleteByUserID(ctx context.Context, input port.SessionDeleteByUserIDInput) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	for hash, session := range s.sessions {
+		if session.UserID != input.UserID {
+			continue
+		}
+		if input.ExceptID != nil && session.ID == *input.ExceptID {
+			continue
+		}
+		s.delete(session.ID, hash)
+	}
+
+	return nil
+}
+
+func (s *MemorySessionStore) delete(id entity.ID, hash string) {
+	delete(s.sessions, hash)
+	delete(s.tokens, id)
+}
+
+func newSession(idgen port.IDGenerator, input port.SessionCreateInput, now time.Time) (*entity.Session, string, error) {
+	id, err := idgen.Generate()
+	if err != nil {
+		return nil, "", err
+	}
+	b := make([]byte, sessionTokenLength)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return nil, "", err
+	}
+	session := &entity.Session{
+		ID:             id,
+		UserID:         input.UserID,
+		Level:          input.Level,
+		AvailableLevel: input.AvailableLevel,
+		Methods:        input.Methods,
+		AuthTime:       input.AuthTime,
+		UserAgent:      input.UserAgent,
+		IPAddress:      input.IPAddress,
+		CreatedAt:      now,
+		LastSeenAt:     now,
+		ExpiresAt:      input.ExpiresAt,
+	}
+	return session, base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
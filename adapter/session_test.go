@@ -0,0 +1,219 @@
+package adapter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMemorySessionStore(t *testing.T, now *time.Time, ids ...entity.ID) *MemorySessionStore {
+	idgen := portmocks.NewIDGenerator(t)
+	for _, id := range ids {
+		idgen.On("Generate").Return(id, nil).Once()
+	}
+	s := NewMemorySessionStore(idgen)
+	s.now = func() time.Time { return *now }
+	return s
+}
+
+func TestMemorySessionStore_Create(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s := newTestMemorySessionStore(t, &now, "test_session_id_001", "test_session_id_002")
+	input := port.SessionCreateInput{
+		UserID:    "test_user_id_001",
+		Level:     entity.AuthLevelMFA,
+		Methods:   []string{entity.AuthMethodPassword, entity.AuthMethodOTP},
+		AuthTime:  now,
+		UserAgent: "test_user_agent",
+		IPAddress: "192.0.2.1",
+		ExpiresAt: now.Add(time.Hour),
+	}
+
+	session, token, err := s.Create(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.Session{
+		ID:         "test_session_id_001",
+		UserID:     input.UserID,
+		Level:      input.Level,
+		Methods:    input.Methods,
+		AuthTime:   input.AuthTime,
+		UserAgent:  input.UserAgent,
+		IPAddress:  input.IPAddress,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  input.ExpiresAt,
+	}, session)
+	assert.NotEmpty(t, token)
+	assert.NotContains(t, s.sessions, token, "tokens must be kept hashed")
+
+	got, err := s.GetByToken(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, session, got)
+
+	// Expired sessions are dropped when the next one is created.
+	now = now.Add(time.Hour)
+	_, _, err = s.Create(ctx, input)
+	assert.NoError(t, err)
+	_, err = s.GetByToken(ctx, token)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+	_, err = s.Get(ctx, session.ID)
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+}
+
+func TestMemorySessionStore_Get(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s := newTestMemorySessionStore(t, &now, "test_session_id_001")
+	session, token, err := s.Create(ctx, port.SessionCreateInput{
+		UserID:    "test_user_id_001",
+		ExpiresAt: now.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+	tests := []struct {
+		name    string
+		id      entity.ID
+		token   string
+		wantErr error
+	}{
+		{
+			name:  "return session",
+			id:    session.ID,
+			token: token,
+		},
+		{
+			name:    "return error for unknown session",
+			id:      "test_session_id_999",
+			token:   "test_token_999",
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Get(ctx, tt.id)
+			byToken, tokenErr := s.GetByToken(ctx, tt.token)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, tokenErr, usecase.ErrInvalidToken)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, tokenErr)
+			assert.Equal(t, session, got)
+			assert.Equal(t, session, byToken)
+
+			// Sessions are returned as copies.
+			got.UserID = "test_user_id_999"
+			again, _ := s.Get(ctx, tt.id)
+			assert.Equal(t, session.UserID, again.UserID)
+		})
+	}
+}
+
+func TestMemorySessionStore_ListByUserID(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s := newTestMemorySessionStore(t, &now, "test_session_id_001", "test_session_id_002", "test_session_id_003")
+	var created entity.Sessions
+	for _, userID := range []entity.ID{"test_user_id_001", "test_user_id_002", "test_user_id_001"} {
+		session, _, err := s.Create(ctx, port.SessionCreateInput{
+			UserID:    userID,
+			ExpiresAt: now.Add(time.Hour),
+		})
+		assert.NoError(t, err)
+		created = append(created, session)
+		now = now.Add(time.Second)
+	}
+
+	got, err := s.ListByUserID(ctx, "test_user_id_001")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Sessions{created[2], created[0]}, got, "newest sessions first")
+}
+
+func TestMemorySessionStore_Touch(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s := newTestMemorySessionStore(t, &now, "test_session_id_001")
+	session, _, err := s.Create(ctx, port.SessionCreateInput{
+		UserID:    "test_user_id_001",
+		ExpiresAt: now.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	lastSeenAt := now.Add(time.Minute)
+	assert.NoError(t, s.Touch(ctx, session.ID, lastSeenAt))
+	assert.NoError(t, s.Touch(ctx, "test_session_id_999", lastSeenAt))
+	got, err := s.Get(ctx, session.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, lastSeenAt, got.LastSeenAt)
+}
+
+func TestMemorySessionStore_Delete(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s := newTestMemorySessionStore(t, &now, "test_session_id_001")
+	session, token, err := s.Create(ctx, port.SessionCreateInput{
+		UserID:    "test_user_id_001",
+		ExpiresAt: now.Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, s.Delete(ctx, session.ID))
+	assert.NoError(t, s.Delete(ctx, session.ID))
+	_, err = s.Get(ctx, session.ID)
+	assert.ErrorIs(t, err, usecase.ErrNotFoundEntity)
+	_, err = s.GetByToken(ctx, token)
+	assert.ErrorIs(t, err, usecase.ErrInvalidToken)
+}
+
+func TestMemorySessionStore_DeleteByUserID(t *testing.T) {
+	exceptID := entity.ID("test_session_id_002")
+	tests := []struct {
+		name     string
+		exceptID *entity.ID
+		wantIDs  []entity.ID
+	}{
+		{
+			name:    "delete every session of user",
+			wantIDs: []entity.ID{"test_session_id_003"},
+		},
+		{
+			name:     "delete sessions of user except one",
+			exceptID: &exceptID,
+			wantIDs:  []entity.ID{"test_session_id_002", "test_session_id_003"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			now := time.Unix(1700000000, 0)
+			s := newTestMemorySessionStore(t, &now, "test_session_id_001", "test_session_id_002", "test_session_id_003")
+			for _, userID := range []entity.ID{"test_user_id_001", "test_user_id_001", "test_user_id_002"} {
+				_, _, err := s.Create(ctx, port.SessionCreateInput{
+					UserID:    userID,
+					ExpiresAt: now.Add(time.Hour),
+				})
+				assert.NoError(t, err)
+			}
+
+			err := s.DeleteByUserID(ctx, port.SessionDeleteByUserIDInput{
+				UserID:   "test_user_id_001",
+				ExceptID: tt.exceptID,
+			})
+			assert.NoError(t, err)
+			var gotIDs []entity.ID
+			for _, id := range []entity.ID{"test_session_id_001", "test_session_id_002", "test_session_id_003"} {
+				if _, err := s.Get(ctx, id); err == nil {
+					gotIDs = append(gotIDs, id)
+				}
+			}
+			assert.Equal(t, tt.wantIDs, gotIDs)
+		})
+	}
+}
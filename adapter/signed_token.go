@@ -0,0 +1,64 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/mkaiho/go-auth-api/usecase"
+)
+
+// signedTokenCodec encodes claims as "<base64url(json)>.<base64url(mac)>".
+// The purpose is mixed into the MAC so that a token issued for one use
+// can not be replayed for another.
+type signedTokenCodec struct {
+	purpose string
+	mac     crypto.MACGenerator
+}
+
+func (c signedTokenCodec) encode(ctx context.Context, claims interface{}) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	sig, err := c.mac.Generate(ctx, c.signingInput(encoded))
+	if err != nil {
+		return "", err
+	}
+
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func (c signedTokenCodec) decode(ctx context.Context, token string, claims interface{}) error {
+	encoded, encodedSig, ok := strings.Cut(token, ".")
+	if !ok {
+		return fmt.Errorf("%w: malformed", usecase.ErrInvalidToken)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSig)
+	if err != nil {
+		return fmt.Errorf("%w: %s", usecase.ErrInvalidToken, err)
+	}
+	if err := c.mac.Verify(ctx, c.signingInput(encoded), sig); err != nil {
+		return fmt.Errorf("%w: %s", usecase.ErrInvalidToken, err)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("%w: %s", usecase.ErrInvalidToken, err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(claims); err != nil {
+		return fmt.Errorf("%w: %s", usecase.ErrInvalidToken, err)
+	}
+
+	return nil
+}
+
+func (c signedTokenCodec) signingInput(encoded string) []byte {
+	return []byte(c.purpose + "." + encoded)
+}
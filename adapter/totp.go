@@ -0,0 +1,152 @@
+package adapter
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	"github.com/mkaiho/go-auth-api/adapter/otp"
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.TOTPManager = (*TOTPManager)(nil)
+
+type TOTPManager struct {
+	totp   *otp.TOTP
+	issuer string
+}
+
+func NewTOTPManager(totp *otp.TOTP, issuer string) *TOTPManager {
+	return &TOTPManager{
+		totp:   totp,
+		issuer: issuer,
+	}
+}
+
+func (m *TOTPManager) Generate(ctx context.Context, account string) (*port.TOTPKey, error) {
+	secret, err := m.totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	return &port.TOTPKey{
+		Secret:        secret,
+		EncodedSecret: otp.EncodeSecret(secret),
+		URI:           m.totp.URI(m.issuer, account, secret),
+	}, nil
+}
+
+func (m *TOTPManager) QRCode(ctx context.Context, uri string) ([]byte, error) {
+	return otp.QRCodePNG(uri)
+}
+
+func (m *TOTPManager) Validate(ctx context.Context, secret []byte, code string) (int64, error) {
+	step, ok := m.totp.Validate(secret, code)
+	if !ok {
+		return 0, usecase.ErrInvalidSecondFactor
+	}
+	return step, nil
+}
+
+var _ port.TOTPGateway = (*TOTPGateway)(nil)
+
+// TOTPGateway encrypts secrets with the user ID as associated data, so a
+// secret copied to another user's row does not decrypt.
+type TOTPGateway struct {
+	totpAccess *rdb.UserTOTPAccess
+	cryptor    crypto.AEADCryptor
+}
+
+func NewTOTPGateway(totpAccess *rdb.UserTOTPAccess, cryptor crypto.AEADCryptor) *TOTPGateway {
+	return &TOTPGateway{
+		totpAccess: totpAccess,
+		cryptor:    cryptor,
+	}
+}
+
+func (g *TOTPGateway) Get(ctx context.Context, userID entity.ID) (*entity.TOTP, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := g.totpAccess.GetByUserID(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(row.Secret)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := g.cryptor.Open(ctx, ciphertext, totpAssociatedData(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TOTP{
+		UserID:       userID,
+		Secret:       secret,
+		Confirmed:    row.Confirmed,
+		LastUsedStep: row.LastUsedStep,
+	}, nil
+}
+
+func (g *TOTPGateway) Save(ctx context.Context, totp entity.TOTP) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	ciphertext, err := g.cryptor.Seal(ctx, totp.Secret, totpAssociatedData(totp.UserID))
+	if err != nil {
+		return err
+	}
+
+	return g.totpAccess.Replace(ctx, tx, &rdb.UserTOTPRow{
+		UserID:       totp.UserID.String(),
+		Secret:       base64.StdEncoding.EncodeToString(ciphertext),
+		Confirmed:    totp.Confirmed,
+		LastUsedStep: totp.LastUsedStep,
+	})
+}
+
+func (g *TOTPGateway) Confirm(ctx context.Context, userID entity.ID, step int64) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.totpAccess.Confirm(ctx, tx, userID, step)
+}
+
+func (g *TOTPGateway) UseStep(ctx context.Context, userID entity.ID, step int64) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated, err := g.totpAccess.UpdateLastUsedStep(ctx, tx, userID, step)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return usecase.ErrSecondFactorUsed
+	}
+
+	return nil
+}
+
+func (g *TOTPGateway) Delete(ctx context.Context, userID entity.ID) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.totpAccess.DeleteByUserID(ctx, tx, userID)
+}
+
+func totpAssociatedData(userID entity.ID) []byte {
+	return []byte("totp:" + userID.String())
+}
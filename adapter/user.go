@@ -12,17 +12,20 @@ import (
 var _ port.UserGateway = (*UserGateway)(nil)
 
 type UserGateway struct {
-	idgen      port.IDGenerator
-	userAccess *rdb.UserAccess
+	idgen       port.IDGenerator
+	userAccess  *rdb.UserAccess
+	emailPolicy entity.EmailLocalPartPolicy
 }
 
 func NewUserGateway(
 	idgen port.IDGenerator,
 	userAccess *rdb.UserAccess,
+	emailPolicy entity.EmailLocalPartPolicy,
 ) *UserGateway {
 	return &UserGateway{
-		idgen:      idgen,
-		userAccess: userAccess,
+		idgen:       idgen,
+		userAccess:  userAccess,
+		emailPolicy: emailPolicy,
 	}
 }
 
@@ -41,9 +44,10 @@ func (g *UserGateway) Get(ctx context.Context, id entity.ID) (*entity.User, erro
 		return nil, err
 	}
 	user := entity.User{
-		ID:    id,
-		Name:  row.Name,
-		Email: email,
+		ID:            id,
+		Name:          row.Name,
+		Email:         email,
+		EmailVerified: row.EmailVerified,
 	}
 
 	return &user, nil
@@ -55,6 +59,13 @@ func (g *UserGateway) List(ctx context.Context, input port.UserListInput) (entit
 		return nil, err
 	}
 
+	if input.Email != nil {
+		normalized, err := input.Email.Normalize(g.emailPolicy)
+		if err != nil {
+			return nil, err
+		}
+		input.Email = &normalized
+	}
 	rows, err := g.userAccess.List(ctx, tx, input)
 	if err != nil {
 		return nil, err
@@ -71,9 +82,10 @@ func (g *UserGateway) List(ctx context.Context, input port.UserListInput) (entit
 			return nil, err
 		}
 		users = append(users, &entity.User{
-			ID:    id,
-			Name:  row.Name,
-			Email: email,
+			ID:            id,
+			Name:          row.Name,
+			Email:         email,
+			EmailVerified: row.EmailVerified,
 		})
 	}
 
@@ -86,8 +98,12 @@ func (g *UserGateway) Create(ctx context.Context, input port.UserCreateInput) (*
 		return nil, err
 	}
 
+	normalized, err := input.Email.Normalize(g.emailPolicy)
+	if err != nil {
+		return nil, err
+	}
 	count, err := g.userAccess.ListCount(ctx, tx, port.UserListInput{
-		Email: &input.Email,
+		Email: &normalized,
 	})
 	if err != nil {
 		return nil, err
@@ -101,14 +117,17 @@ func (g *UserGateway) Create(ctx context.Context, input port.UserCreateInput) (*
 		return nil, err
 	}
 	created := entity.User{
-		ID:    id,
-		Name:  input.Name,
-		Email: input.Email,
+		ID:            id,
+		Name:          input.Name,
+		Email:         input.Email,
+		EmailVerified: input.EmailVerified,
 	}
 	err = g.userAccess.Create(ctx, tx, &rdb.UserRow{
-		ID:    created.ID.String(),
-		Name:  created.Name,
-		Email: created.Email.String(),
+		ID:              created.ID.String(),
+		Name:            created.Name,
+		Email:           created.Email.String(),
+		EmailVerified:   created.EmailVerified,
+		NormalizedEmail: normalized.String(),
 	})
 	if err != nil {
 		return nil, err
@@ -127,16 +146,34 @@ func (g *UserGateway) Update(ctx context.Context, input port.UserUpdateInput) (*
 	if err != nil {
 		return nil, err
 	}
+	normalized, err := input.Email.Normalize(g.emailPolicy)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := g.userAccess.List(ctx, tx, port.UserListInput{
+		Email: &normalized,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if row.ID != input.ID.String() {
+			return nil, usecase.ErrAlreadyExistsEntity
+		}
+	}
 
 	updated := entity.User{
-		ID:    input.ID,
-		Name:  input.Name,
-		Email: input.Email,
+		ID:            input.ID,
+		Name:          input.Name,
+		Email:         input.Email,
+		EmailVerified: input.EmailVerified,
 	}
 	err = g.userAccess.Update(ctx, tx, &rdb.UserRow{
-		ID:    updated.ID.String(),
-		Name:  updated.Name,
-		Email: updated.Email.String(),
+		ID:              updated.ID.String(),
+		Name:            updated.Name,
+		Email:           updated.Email.String(),
+		EmailVerified:   updated.EmailVerified,
+		NormalizedEmail: normalized.String(),
 	})
 	if err != nil {
 		return nil, err
@@ -3,6 +3,7 @@ package adapter
 import (
 	"context"
 	"errors"
+	"sync"
 
 	"github.com/mkaiho/go-auth-api/adapter/rdb"
 	"github.com/mkaiho/go-auth-api/entity"
@@ -18,6 +19,16 @@ type UserCredentialGateway struct {
 	passwordManager port.PasswordManager
 	userAccess      *rdb.UserAccess
 	userCredAccess  *rdb.UserCredentialAccess
+	historyAccess   *rdb.PasswordHistoryAccess
+	pepper          *PasswordPepper
+	emailPolicy     entity.EmailLocalPartPolicy
+	// breaches is optional, nil disables flagging breached passwords at login.
+	breaches port.BreachedPasswordChecker
+	// dummyHash is compared against for unknown emails, so that they take
+	// as long to check as known ones.
+	dummyHash     entity.HashedPassword
+	dummyHashErr  error
+	dummyHashOnce sync.Once
 }
 
 func NewUserCredentialGateway(
@@ -25,12 +36,20 @@ func NewUserCredentialGateway(
 	passwordManager port.PasswordManager,
 	userAccess *rdb.UserAccess,
 	userCredAccess *rdb.UserCredentialAccess,
+	historyAccess *rdb.PasswordHistoryAccess,
+	pepper *PasswordPepper,
+	emailPolicy entity.EmailLocalPartPolicy,
+	breaches port.BreachedPasswordChecker,
 ) port.UserCredentialGateway {
 	return &UserCredentialGateway{
 		idgen:           idgen,
 		passwordManager: passwordManager,
 		userAccess:      userAccess,
 		userCredAccess:  userCredAccess,
+		historyAccess:   historyAccess,
+		pepper:          pepper,
+		emailPolicy:     emailPolicy,
+		breaches:        breaches,
 	}
 }
 
@@ -40,7 +59,11 @@ func (g *UserCredentialGateway) GetByEmail(ctx context.Context, email entity.Ema
 		return nil, err
 	}
 
-	row, err := g.userCredAccess.GetByEmail(ctx, tx, email)
+	normalized, err := email.Normalize(g.emailPolicy)
+	if err != nil {
+		return nil, err
+	}
+	row, err := g.userCredAccess.GetByEmail(ctx, tx, normalized)
 	if err != nil {
 		return nil, err
 	}
@@ -53,19 +76,19 @@ func (g *UserCredentialGateway) GetByEmail(ctx context.Context, email entity.Ema
 	if err != nil {
 		return nil, err
 	}
-	pwd, err := entity.ParseHashedPassword(row.Password)
+	pwd, err := g.pepper.Unwrap(ctx, userID, row.Password, row.PasswordKeyID)
 	if err != nil {
 		return nil, err
 	}
 	userCred := entity.UserCredential{
-		ID:       id,
-		UserID:   userID,
-		Email:    email,
-		Password: pwd,
+		ID:               id,
+		UserID:           userID,
+		Email:            email,
+		Password:         pwd,
+		PasswordBreached: row.PasswordBreached,
 	}
 
-	return &userCred, usecase.ErrNotFoundEntity
-
+	return &userCred, nil
 }
 
 func (g *UserCredentialGateway) Create(ctx context.Context, input port.UserCredentialCreateInput) (*entity.UserCredential, error) {
@@ -100,15 +123,24 @@ func (g *UserCredentialGateway) Create(ctx context.Context, input port.UserCrede
 		Password: input.Password,
 	}
 
+	wrapped, keyID, err := g.pepper.Wrap(ctx, created.UserID, created.Password)
+	if err != nil {
+		return nil, err
+	}
 	err = g.userCredAccess.Create(ctx, tx, &rdb.UserCredentialRow{
-		ID:       created.ID.String(),
-		UserID:   created.UserID.String(),
-		Email:    created.Email.String(),
-		Password: created.Password.String(),
+		ID:            created.ID.String(),
+		UserID:        created.UserID.String(),
+		Email:         created.Email.String(),
+		Password:      wrapped,
+		PasswordKeyID: keyID,
 	})
 	if err != nil {
 		return nil, err
 	}
+	err = g.addPasswordHistory(ctx, tx, created.UserID, created.Password)
+	if err != nil {
+		return nil, err
+	}
 
 	return &created, nil
 }
@@ -143,19 +175,52 @@ func (g *UserCredentialGateway) Update(ctx context.Context, input port.UserCrede
 		Password: input.Password,
 	}
 
-	err = g.userCredAccess.Create(ctx, tx, &rdb.UserCredentialRow{
-		ID:       updated.ID.String(),
-		UserID:   updated.UserID.String(),
-		Email:    updated.Email.String(),
-		Password: updated.Password.String(),
+	wrapped, keyID, err := g.pepper.Wrap(ctx, updated.UserID, updated.Password)
+	if err != nil {
+		return nil, err
+	}
+	err = g.userCredAccess.UpdateByUserID(ctx, tx, &rdb.UserCredentialRow{
+		ID:            updated.ID.String(),
+		UserID:        updated.UserID.String(),
+		Email:         updated.Email.String(),
+		Password:      wrapped,
+		PasswordKeyID: keyID,
 	})
 	if err != nil {
 		return nil, err
 	}
+	err = g.addPasswordHistory(ctx, tx, updated.UserID, updated.Password)
+	if err != nil {
+		return nil, err
+	}
 
 	return &updated, nil
 }
 
+func (g *UserCredentialGateway) addPasswordHistory(
+	ctx context.Context,
+	tx rdb.Transaction,
+	userID entity.ID,
+	password entity.HashedPassword,
+) error {
+	id, err := g.idgen.Generate()
+	if err != nil {
+		return err
+	}
+
+	wrapped, keyID, err := g.pepper.Wrap(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+
+	return g.historyAccess.Create(ctx, tx, &rdb.PasswordHistoryRow{
+		ID:            id.String(),
+		UserID:        userID.String(),
+		Password:      wrapped,
+		PasswordKeyID: keyID,
+	})
+}
+
 func (g *UserCredentialGateway) Check(ctx context.Context, email entity.Email, password entity.Password) error {
 	logger := util.FromContext(ctx)
 	tx, err := rdb.TxFromContext(ctx)
@@ -163,14 +228,24 @@ func (g *UserCredentialGateway) Check(ctx context.Context, email entity.Email, p
 		return err
 	}
 
-	credRow, err := g.userCredAccess.GetByEmail(ctx, tx, email)
+	normalized, err := email.Normalize(g.emailPolicy)
+	if err != nil {
+		return g.compareDummy(ctx, password)
+	}
+	credRow, err := g.userCredAccess.GetByEmail(ctx, tx, normalized)
 	if err != nil {
 		if errors.Is(err, usecase.ErrNotFoundEntity) {
-			return usecase.ErrNoAuthUser
+			return g.compareDummy(ctx, password)
 		}
 		return err
 	}
-	hashed, err := entity.ParseHashedPassword(credRow.Password)
+	// The user ID is the associated data of the wrapped hash, so it is
+	// parsed once and used for both unwrapping and rewrapping.
+	userID, err := entity.ParseID(credRow.UserID)
+	if err != nil {
+		return err
+	}
+	hashed, err := g.pepper.Unwrap(ctx, userID, credRow.Password, credRow.PasswordKeyID)
 	if err != nil {
 		return err
 	}
@@ -180,5 +255,81 @@ func (g *UserCredentialGateway) Check(ctx context.Context, email entity.Email, p
 		return usecase.ErrInvalidCredential
 	}
 
+	// The password is known to be correct here, so this is the only chance
+	// to upgrade the stored hash.
+	if g.passwordManager.NeedsRehash(ctx, hashed) {
+		if err := g.rehashPassword(ctx, tx, userID, credRow, password); err != nil {
+			logger.Error(err, "failed to rehash password")
+		}
+	}
+	if g.breaches != nil {
+		if err := g.flagBreachedPassword(ctx, tx, userID, credRow, password); err != nil {
+			// Screening must not lock users out, so only log it.
+			logger.Error(err, "failed to check breached password")
+		}
+	}
+
 	return nil
 }
+
+func (g *UserCredentialGateway) rehashPassword(
+	ctx context.Context,
+	tx rdb.Transaction,
+	userID entity.ID,
+	credRow *rdb.UserCredentialRow,
+	password entity.Password,
+) error {
+	rehashed, err := g.passwordManager.Hash(ctx, password.String())
+	if err != nil {
+		return err
+	}
+	wrapped, keyID, err := g.pepper.Wrap(ctx, userID, rehashed)
+	if err != nil {
+		return err
+	}
+
+	return g.userCredAccess.UpdateByUserID(ctx, tx, &rdb.UserCredentialRow{
+		ID:               credRow.ID,
+		UserID:           credRow.UserID,
+		Password:         wrapped,
+		PasswordBreached: credRow.PasswordBreached,
+		PasswordKeyID:    keyID,
+	})
+}
+
+func (g *UserCredentialGateway) flagBreachedPassword(
+	ctx context.Context,
+	tx rdb.Transaction,
+	userID entity.ID,
+	credRow *rdb.UserCredentialRow,
+	password entity.Password,
+) error {
+	logger := util.FromContext(ctx)
+
+	breached, err := g.breaches.IsBreached(ctx, password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		logger.Info("password found in breach corpus", "userID", userID)
+	}
+	if breached == credRow.PasswordBreached {
+		return nil
+	}
+
+	return g.userCredAccess.UpdatePasswordBreachedByUserID(ctx, tx, userID, breached)
+}
+
+// compareDummy spends the time of a password check and returns
+// usecase.ErrNoAuthUser. The dummy hash is made on first use with the
+// current hash parameters.
+func (g *UserCredentialGateway) compareDummy(ctx context.Context, password entity.Password) error {
+	g.dummyHashOnce.Do(func() {
+		g.dummyHash, g.dummyHashErr = g.passwordManager.Hash(ctx, "dummy-password-for-unknown-users")
+	})
+	if g.dummyHashErr != nil {
+		return g.dummyHashErr
+	}
+	_ = g.passwordManager.Compare(ctx, g.dummyHash, password)
+	return usecase.ErrNoAuthUser
+}
@@ -55,9 +55,10 @@ func (g *StubUserGateway) Create(ctx context.Context, input port.UserCreateInput
 	g.createCall.
 		Times(g.calledTimes).
 		Return(&entity.User{
-			ID:    id,
-			Name:  input.Name,
-			Email: input.Email,
+			ID:            id,
+			Name:          input.Name,
+			Email:         input.Email,
+			EmailVerified: input.EmailVerified,
 		}, nil)
 
 	user, err := g.m.Create(ctx, input)
@@ -76,6 +77,7 @@ func (g *StubUserGateway) Update(ctx context.Context, input port.UserUpdateInput
 		if user.ID == input.ID {
 			g.users[input.ID].Name = input.Name
 			g.users[input.ID].Email = input.Email
+			g.users[input.ID].EmailVerified = input.EmailVerified
 			return g.users[input.ID], nil
 		}
 	}
@@ -0,0 +1,261 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/adapter/webauthn"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var _ port.WebAuthnVerifier = (*WebAuthnVerifier)(nil)
+
+type WebAuthnVerifier struct {
+	rp *webauthn.RelyingParty
+}
+
+func NewWebAuthnVerifier(rp *webauthn.RelyingParty) *WebAuthnVerifier {
+	return &WebAuthnVerifier{
+		rp: rp,
+	}
+}
+
+func (v *WebAuthnVerifier) RelyingParty(ctx context.Context) port.WebAuthnRelyingParty {
+	return port.WebAuthnRelyingParty{
+		ID:         v.rp.ID,
+		Name:       v.rp.Name,
+		Algorithms: webauthn.SupportedAlgorithms,
+	}
+}
+
+func (v *WebAuthnVerifier) VerifyRegistration(ctx context.Context, input port.WebAuthnRegistrationInput) (*port.WebAuthnRegistration, error) {
+	registration, err := v.rp.VerifyRegistration(
+		input.Challenge,
+		input.ClientDataJSON,
+		input.AttestationObject,
+		input.RequireUserVerification,
+	)
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+	return &port.WebAuthnRegistration{
+		CredentialID: registration.CredentialID,
+		PublicKey:    registration.PublicKey,
+		SignCount:    registration.SignCount,
+	}, nil
+}
+
+func (v *WebAuthnVerifier) VerifyAssertion(ctx context.Context, input port.WebAuthnAssertionInput) (*port.WebAuthnAssertion, error) {
+	assertion, err := v.rp.VerifyAssertion(
+		input.Challenge,
+		input.PublicKey,
+		input.SignCount,
+		input.ClientDataJSON,
+		input.AuthenticatorData,
+		input.Signature,
+		input.RequireUserVerification,
+	)
+	if err != nil {
+		return nil, webAuthnError(err)
+	}
+	return &port.WebAuthnAssertion{
+		SignCount: assertion.SignCount,
+	}, nil
+}
+
+func webAuthnError(err error) error {
+	if errors.Is(err, webauthn.ErrVerification) || errors.Is(err, webauthn.ErrSignCountRegression) {
+		return fmt.Errorf("%w: %v", usecase.ErrInvalidCredential, err)
+	}
+	return err
+}
+
+var _ port.WebAuthnCredentialGateway = (*WebAuthnCredentialGateway)(nil)
+
+type WebAuthnCredentialGateway struct {
+	idgen            port.IDGenerator
+	credentialAccess *rdb.WebAuthnCredentialAccess
+}
+
+func NewWebAuthnCredentialGateway(idgen port.IDGenerator, credentialAccess *rdb.WebAuthnCredentialAccess) *WebAuthnCredentialGateway {
+	return &WebAuthnCredentialGateway{
+		idgen:            idgen,
+		credentialAccess: credentialAccess,
+	}
+}
+
+func (g *WebAuthnCredentialGateway) ListByUserID(ctx context.Context, userID entity.ID) (entity.WebAuthnCredentials, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := g.credentialAccess.ListByUserID(ctx, tx, userID)
+	if err != nil {
+		return nil, err
+	}
+	credentials := make(entity.WebAuthnCredentials, 0, len(rows))
+	for _, row := range rows {
+		credentials = append(credentials, webAuthnCredentialFromRow(row))
+	}
+
+	return credentials, nil
+}
+
+func (g *WebAuthnCredentialGateway) GetByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := g.credentialAccess.GetByCredentialID(ctx, tx, credentialID)
+	if err != nil {
+		return nil, err
+	}
+
+	return webAuthnCredentialFromRow(row), nil
+}
+
+func (g *WebAuthnCredentialGateway) Create(ctx context.Context, input port.WebAuthnCredentialCreateInput) (*entity.WebAuthnCredential, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := g.idgen.Generate()
+	if err != nil {
+		return nil, err
+	}
+	row := &rdb.WebAuthnCredentialRow{
+		ID:           id.String(),
+		UserID:       input.UserID.String(),
+		CredentialID: input.CredentialID,
+		PublicKey:    input.PublicKey,
+		SignCount:    input.SignCount,
+	}
+	if err := g.credentialAccess.Create(ctx, tx, row); err != nil {
+		return nil, err
+	}
+
+	return webAuthnCredentialFromRow(row), nil
+}
+
+func (g *WebAuthnCredentialGateway) UpdateSignCount(ctx context.Context, id entity.ID, signCount uint32) error {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return g.credentialAccess.UpdateSignCount(ctx, tx, id, signCount)
+}
+
+func webAuthnCredentialFromRow(row *rdb.WebAuthnCredentialRow) *entity.WebAuthnCredential {
+	return &entity.WebAuthnCredential{
+		ID:           entity.ID(row.ID),
+		UserID:       entity.ID(row.UserID),
+		CredentialID: row.CredentialID,
+		PublicKey:    row.PublicKey,
+		SignCount:    row.SignCount,
+	}
+}
+
+var _ port.WebAuthnChallengeGateway = (*WebAuthnChallengeGateway)(nil)
+
+type WebAuthnChallengeGateway struct {
+	idgen           port.IDGenerator
+	challengeAccess *rdb.WebAuthnChallengeAccess
+	ttl             time.Duration
+	now             func() time.Time
+}
+
+func NewWebAuthnChallengeGateway(idgen port.IDGenerator, challengeAccess *rdb.WebAuthnChallengeAccess, ttl time.Duration) *WebAuthnChallengeGateway {
+	return &WebAuthnChallengeGateway{
+		idgen:           idgen,
+		challengeAccess: challengeAccess,
+		ttl:             ttl,
+		now:             time.Now,
+	}
+}
+
+func (g *WebAuthnChallengeGateway) Create(ctx context.Context, input port.WebAuthnChallengeCreateInput) (*entity.WebAuthnChallenge, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Abandoned ceremonies leave challenges behind, so they are cleaned up
+	// whenever a new one starts.
+	now := g.now()
+	if err := g.challengeAccess.DeleteExpired(ctx, tx, now); err != nil {
+		return nil, err
+	}
+	id, err := g.idgen.Generate()
+	if err != nil {
+		return nil, err
+	}
+	value, err := webauthn.NewChallenge()
+	if err != nil {
+		return nil, err
+	}
+	challenge := &entity.WebAuthnChallenge{
+		ID:        id,
+		UserID:    input.UserID,
+		Ceremony:  input.Ceremony,
+		Challenge: value,
+		ExpiresAt: now.Add(g.ttl),
+	}
+	row := &rdb.WebAuthnChallengeRow{
+		ID:        challenge.ID.String(),
+		Ceremony:  challenge.Ceremony.String(),
+		Challenge: challenge.Challenge,
+		ExpiresAt: challenge.ExpiresAt,
+	}
+	if input.UserID != nil {
+		row.UserID = sql.NullString{String: input.UserID.String(), Valid: true}
+	}
+	if err := g.challengeAccess.Create(ctx, tx, row); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+func (g *WebAuthnChallengeGateway) Consume(ctx context.Context, id entity.ID, ceremony entity.WebAuthnCeremony) (*entity.WebAuthnChallenge, error) {
+	tx, err := rdb.TxFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	row, err := g.challengeAccess.Get(ctx, tx, id)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, usecase.ErrInvalidToken
+		}
+		return nil, err
+	}
+	deleted, err := g.challengeAccess.Delete(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted || row.Ceremony != ceremony.String() || !g.now().Before(row.ExpiresAt) {
+		return nil, usecase.ErrInvalidToken
+	}
+	challenge := &entity.WebAuthnChallenge{
+		ID:        entity.ID(row.ID),
+		Ceremony:  entity.ParseWebAuthnCeremony(row.Ceremony),
+		Challenge: row.Challenge,
+		ExpiresAt: row.ExpiresAt,
+	}
+	if row.UserID.Valid {
+		userID := entity.ID(row.UserID.String)
+		challenge.UserID = &userID
+	}
+
+	return challenge, nil
+}
@@ -0,0 +1,160 @@
+package webauthn
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+)
+
+// COSE identifiers from RFC 9053 and the IANA COSE registry.
+const (
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseAlgES256 = -7
+	coseAlgEdDSA = -8
+	coseAlgRS256 = -257
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+)
+
+// SupportedAlgorithms are the COSE algorithms credentials may use, in order
+// of preference, as offered in pubKeyCredParams.
+var SupportedAlgorithms = []int{coseAlgES256, coseAlgEdDSA, coseAlgRS256}
+
+type publicKey struct {
+	algorithm int
+	key       stdcrypto.PublicKey
+}
+
+func parsePublicKey(b []byte) (*publicKey, []byte, error) {
+	var m map[int]cbor.RawMessage
+	rest, err := cbor.UnmarshalFirst(b, &m)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid COSE key: %v", ErrVerification, err)
+	}
+	var kty, alg int
+	if err := unmarshalCOSEParam(m, 1, &kty); err != nil {
+		return nil, nil, err
+	}
+	if err := unmarshalCOSEParam(m, 3, &alg); err != nil {
+		return nil, nil, err
+	}
+
+	var key stdcrypto.PublicKey
+	switch {
+	case kty == coseKeyTypeEC2 && alg == coseAlgES256:
+		var crv int
+		var x, y []byte
+		if err := unmarshalCOSEParam(m, -1, &crv); err != nil {
+			return nil, nil, err
+		}
+		if err := unmarshalCOSEParam(m, -2, &x); err != nil {
+			return nil, nil, err
+		}
+		if err := unmarshalCOSEParam(m, -3, &y); err != nil {
+			return nil, nil, err
+		}
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return nil, nil, fmt.Errorf("%w: invalid EC2 key", ErrVerification)
+		}
+		pub := &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, nil, fmt.Errorf("%w: EC2 point is not on curve", ErrVerification)
+		}
+		key = pub
+	case kty == coseKeyTypeOKP && alg == coseAlgEdDSA:
+		var crv int
+		var x []byte
+		if err := unmarshalCOSEParam(m, -1, &crv); err != nil {
+			return nil, nil, err
+		}
+		if err := unmarshalCOSEParam(m, -2, &x); err != nil {
+			return nil, nil, err
+		}
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return nil, nil, fmt.Errorf("%w: invalid OKP key", ErrVerification)
+		}
+		key = ed25519.PublicKey(x)
+	case kty == coseKeyTypeRSA && alg == coseAlgRS256:
+		var n, e []byte
+		if err := unmarshalCOSEParam(m, -1, &n); err != nil {
+			return nil, nil, err
+		}
+		if err := unmarshalCOSEParam(m, -2, &e); err != nil {
+			return nil, nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if len(n) < 256 || !exponent.IsInt64() {
+			return nil, nil, fmt.Errorf("%w: invalid RSA key", ErrVerification)
+		}
+		key = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(exponent.Int64()),
+		}
+	default:
+		return nil, nil, fmt.Errorf("%w: unsupported COSE key type %d with algorithm %d", ErrVerification, kty, alg)
+	}
+
+	return &publicKey{
+		algorithm: alg,
+		key:       key,
+	}, rest, nil
+}
+
+func unmarshalCOSEParam(m map[int]cbor.RawMessage, label int, v interface{}) error {
+	raw, ok := m[label]
+	if !ok {
+		return fmt.Errorf("%w: COSE key has no parameter %d", ErrVerification, label)
+	}
+	if err := cbor.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("%w: invalid COSE key parameter %d: %v", ErrVerification, label, err)
+	}
+	return nil
+}
+
+// verifySignature checks a WebAuthn signature, which for ECDSA is ASN.1 encoded
+// rather than the fixed width form crypto.VerifySignature takes.
+func verifySignature(algorithm int, key stdcrypto.PublicKey, message []byte, signature []byte) error {
+	var err error
+	switch algorithm {
+	case coseAlgES256:
+		var sig struct {
+			R, S *big.Int
+		}
+		if rest, aErr := asn1.Unmarshal(signature, &sig); aErr != nil || len(rest) > 0 {
+			return fmt.Errorf("%w: malformed ECDSA signature", ErrVerification)
+		}
+		raw := make([]byte, 64)
+		if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 || sig.R.BitLen() > 256 || sig.S.BitLen() > 256 {
+			return fmt.Errorf("%w: malformed ECDSA signature", ErrVerification)
+		}
+		sig.R.FillBytes(raw[:32])
+		sig.S.FillBytes(raw[32:])
+		err = crypto.VerifySignature(crypto.SignatureAlgorithmES256, key, message, raw)
+	case coseAlgEdDSA:
+		err = crypto.VerifySignature(crypto.SignatureAlgorithmEdDSA, key, message, signature)
+	case coseAlgRS256:
+		err = crypto.VerifySignature(crypto.SignatureAlgorithmRS256, key, message, signature)
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %d", ErrVerification, algorithm)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerification, err)
+	}
+	return nil
+}
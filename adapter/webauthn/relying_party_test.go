@@ -0,0 +1,269 @@
+package webauthn
+
+import (
+	stdcrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+type fakeAuthenticator struct {
+	t            *testing.T
+	rpID         string
+	credentialID []byte
+	signer       stdcrypto.Signer
+	cose         []byte
+	signCount    uint32
+	flags        byte
+}
+
+func newFakeAuthenticator(t *testing.T, alg int) *fakeAuthenticator {
+	a := &fakeAuthenticator{
+		t:            t,
+		rpID:         testRPID,
+		credentialID: []byte("credential-id"),
+		flags:        flagUserPresent | flagUserVerified,
+	}
+	var key map[int]interface{}
+	switch alg {
+	case coseAlgES256:
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		a.signer = priv
+		key = map[int]interface{}{
+			1:  coseKeyTypeEC2,
+			3:  coseAlgES256,
+			-1: coseCurveP256,
+			-2: priv.X.FillBytes(make([]byte, 32)),
+			-3: priv.Y.FillBytes(make([]byte, 32)),
+		}
+	case coseAlgEdDSA:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		a.signer = priv
+		key = map[int]interface{}{
+			1:  coseKeyTypeOKP,
+			3:  coseAlgEdDSA,
+			-1: coseCurveEd25519,
+			-2: []byte(pub),
+		}
+	}
+	cose, err := cbor.Marshal(key)
+	require.NoError(t, err)
+	a.cose = cose
+	return a
+}
+
+func (a *fakeAuthenticator) clientData(typ string, challenge []byte, origin string) []byte {
+	b, err := json.Marshal(clientData{
+		Type:      typ,
+		Challenge: base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:    origin,
+	})
+	require.NoError(a.t, err)
+	return b
+}
+
+func (a *fakeAuthenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	b := append([]byte{}, rpIDHash[:]...)
+	flags := a.flags
+	if attested {
+		flags |= flagAttestedCredential
+	}
+	b = append(b, flags)
+	b = binary.BigEndian.AppendUint32(b, a.signCount)
+	if attested {
+		b = append(b, make([]byte, aaguidLength)...)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(a.credentialID)))
+		b = append(b, a.credentialID...)
+		b = append(b, a.cose...)
+	}
+	return b
+}
+
+func (a *fakeAuthenticator) sign(authData []byte, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	message := append(append([]byte{}, authData...), clientDataHash[:]...)
+	var (
+		sig []byte
+		err error
+	)
+	if _, ok := a.signer.(ed25519.PrivateKey); ok {
+		sig, err = a.signer.Sign(rand.Reader, message, stdcrypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(message)
+		sig, err = a.signer.Sign(rand.Reader, digest[:], stdcrypto.SHA256)
+	}
+	require.NoError(a.t, err)
+	return sig
+}
+
+func (a *fakeAuthenticator) create(challenge []byte, format string) ([]byte, []byte) {
+	clientDataJSON := a.clientData(clientDataTypeCreate, challenge, testOrigin)
+	authData := a.authData(true)
+	var statement map[string]interface{}
+	switch format {
+	case "none":
+		statement = map[string]interface{}{}
+	case "packed":
+		alg := coseAlgES256
+		if _, ok := a.signer.(ed25519.PrivateKey); ok {
+			alg = coseAlgEdDSA
+		}
+		statement = map[string]interface{}{
+			"alg": alg,
+			"sig": a.sign(authData, clientDataJSON),
+		}
+	}
+	attestation, err := cbor.Marshal(map[string]interface{}{
+		"fmt":      format,
+		"attStmt":  statement,
+		"authData": authData,
+	})
+	require.NoError(a.t, err)
+	return clientDataJSON, attestation
+}
+
+func (a *fakeAuthenticator) get(challenge []byte) ([]byte, []byte, []byte) {
+	a.signCount++
+	clientDataJSON := a.clientData(clientDataTypeGet, challenge, testOrigin)
+	authData := a.authData(false)
+	return clientDataJSON, authData, a.sign(authData, clientDataJSON)
+}
+
+func TestRelyingParty_VerifyRegistration(t *testing.T) {
+	rp := NewRelyingParty(testRPID, "Example", []string{testOrigin})
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		alg    int
+		format string
+	}{
+		{name: "none ES256", alg: coseAlgES256, format: "none"},
+		{name: "packed self attestation ES256", alg: coseAlgES256, format: "packed"},
+		{name: "packed self attestation EdDSA", alg: coseAlgEdDSA, format: "packed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newFakeAuthenticator(t, tt.alg)
+			clientDataJSON, attestation := a.create(challenge, tt.format)
+			got, err := rp.VerifyRegistration(challenge, clientDataJSON, attestation, true)
+			require.NoError(t, err)
+			assert.Equal(t, a.credentialID, got.CredentialID)
+			assert.Equal(t, a.cose, got.PublicKey)
+			assert.Equal(t, tt.format, got.Format)
+			assert.True(t, got.UserVerified)
+		})
+	}
+}
+
+func TestRelyingParty_VerifyRegistration_Invalid(t *testing.T) {
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		rp    *RelyingParty
+		setup func(a *fakeAuthenticator) ([]byte, []byte)
+	}{
+		{
+			name: "challenge mismatch",
+			rp:   NewRelyingParty(testRPID, "Example", []string{testOrigin}),
+			setup: func(a *fakeAuthenticator) ([]byte, []byte) {
+				return a.create([]byte("other challenge"), "none")
+			},
+		},
+		{
+			name: "origin not allowed",
+			rp:   NewRelyingParty(testRPID, "Example", []string{"https://other.example.com"}),
+			setup: func(a *fakeAuthenticator) ([]byte, []byte) {
+				return a.create(challenge, "none")
+			},
+		},
+		{
+			name: "RP ID mismatch",
+			rp:   NewRelyingParty(testRPID, "Example", []string{testOrigin}),
+			setup: func(a *fakeAuthenticator) ([]byte, []byte) {
+				a.rpID = "evil.example"
+				return a.create(challenge, "none")
+			},
+		},
+		{
+			name: "user not verified",
+			rp:   NewRelyingParty(testRPID, "Example", []string{testOrigin}),
+			setup: func(a *fakeAuthenticator) ([]byte, []byte) {
+				a.flags = flagUserPresent
+				return a.create(challenge, "none")
+			},
+		},
+		{
+			name: "packed signature by another key",
+			rp:   NewRelyingParty(testRPID, "Example", []string{testOrigin}),
+			setup: func(a *fakeAuthenticator) ([]byte, []byte) {
+				a.cose = newFakeAuthenticator(t, coseAlgES256).cose
+				return a.create(challenge, "packed")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newFakeAuthenticator(t, coseAlgES256)
+			clientDataJSON, attestation := tt.setup(a)
+			_, err := tt.rp.VerifyRegistration(challenge, clientDataJSON, attestation, true)
+			assert.ErrorIs(t, err, ErrVerification)
+		})
+	}
+}
+
+func TestRelyingParty_VerifyAssertion(t *testing.T) {
+	rp := NewRelyingParty(testRPID, "Example", []string{testOrigin})
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	for _, alg := range []int{coseAlgES256, coseAlgEdDSA} {
+		a := newFakeAuthenticator(t, alg)
+		clientDataJSON, authData, sig := a.get(challenge)
+		got, err := rp.VerifyAssertion(challenge, a.cose, 0, clientDataJSON, authData, sig, true)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(1), got.SignCount)
+
+		_, err = rp.VerifyAssertion(challenge, a.cose, 0, clientDataJSON, authData, append(sig[:len(sig)-1:len(sig)-1], sig[len(sig)-1]^0xff), true)
+		assert.ErrorIs(t, err, ErrVerification)
+
+		_, err = rp.VerifyAssertion(challenge, a.cose, 1, clientDataJSON, authData, sig, true)
+		assert.ErrorIs(t, err, ErrSignCountRegression)
+
+		_, err = rp.VerifyAssertion([]byte("other challenge"), a.cose, 0, clientDataJSON, authData, sig, true)
+		assert.ErrorIs(t, err, ErrVerification)
+	}
+}
+
+func TestRelyingParty_VerifyAssertion_ZeroCounter(t *testing.T) {
+	rp := NewRelyingParty(testRPID, "Example", []string{testOrigin})
+	challenge, err := NewChallenge()
+	require.NoError(t, err)
+
+	a := newFakeAuthenticator(t, coseAlgEdDSA)
+	clientDataJSON := a.clientData(clientDataTypeGet, challenge, testOrigin)
+	authData := a.authData(false)
+	_, err = rp.VerifyAssertion(challenge, a.cose, 0, clientDataJSON, authData, a.sign(authData, clientDataJSON), true)
+	assert.NoError(t, err)
+}
@@ -8,10 +8,15 @@ import (
 	"github.com/mkaiho/go-auth-api/adapter"
 	"github.com/mkaiho/go-auth-api/adapter/crypto"
 	idAdapter "github.com/mkaiho/go-auth-api/adapter/id"
+	"github.com/mkaiho/go-auth-api/adapter/mail"
+	"github.com/mkaiho/go-auth-api/adapter/pwned"
 	rdbAdapter "github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/adapter/storage"
 	"github.com/mkaiho/go-auth-api/controller/web"
 	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
 	"github.com/mkaiho/go-auth-api/controller/web/routes"
+	"github.com/mkaiho/go-auth-api/entity"
 	"github.com/mkaiho/go-auth-api/infrastructure"
 	"github.com/mkaiho/go-auth-api/usecase/interactor"
 	"github.com/mkaiho/go-auth-api/usecase/port"
@@ -91,7 +96,7 @@ func handle(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	server, err := server()
+	server, err := server(ctx)
 	if err != nil {
 		return err
 	}
@@ -103,12 +108,41 @@ func handle(cmd *cobra.Command, args []string) (err error) {
 	return server.Run(fmt.Sprintf("%s:%d", "", port))
 }
 
-func server() (*web.Server, error) {
+func server(ctx context.Context) (*web.Server, error) {
 	var err error
+	logger := util.FromContext(ctx)
 	// infra
 	var (
-		rdb     rdbAdapter.DB
-		hashGen crypto.HashGenerator
+		rdb                     rdbAdapter.DB
+		hashGens                []crypto.PasswordHashGenerator
+		passwordPepper          *adapter.PasswordPepper
+		mailClient              mail.Client
+		emailConfig             *infrastructure.EmailConfig
+		emailVerificationConfig *infrastructure.EmailVerificationConfig
+		passwordResetConfig     *infrastructure.PasswordResetConfig
+		emailLoginConfig        *infrastructure.EmailLoginConfig
+		passwordPolicy          *entity.PasswordPolicy
+		pwnedPasswordsConfig    *infrastructure.PwnedPasswordsConfig
+		pwnedCorpus             *pwned.Corpus
+		pwnedStorage            storage.Client
+		totpConfig              *infrastructure.TOTPConfig
+		totpKeyring             *crypto.Keyring
+		webAuthnConfig          *infrastructure.WebAuthnConfig
+		stepUpConfig            *infrastructure.StepUpConfig
+		sessionConfig           *infrastructure.SessionConfig
+		csrfConfig              *infrastructure.CSRFConfig
+		loginLockoutConfig      *infrastructure.LoginLockoutConfig
+		rateLimitConfig         *infrastructure.RateLimitConfig
+		signupConfig            *infrastructure.SignupConfig
+		serverConfig            *infrastructure.ServerConfig
+		backgroundJobsConfig    *infrastructure.BackgroundJobsConfig
+		ipPolicyConfig          *infrastructure.IPPolicyConfig
+		ipPolicyFile            []byte
+		ipPolicyRules           []middlewares.IPPolicyRule
+		accessPolicyConfig      *infrastructure.AccessPolicyConfig
+		accessPolicyFile        []byte
+		accessPolicy            *entity.AccessPolicy
+		rateLimitRules          []middlewares.RateLimitRule
 	)
 	{
 		// RDB
@@ -121,57 +155,689 @@ func server() (*web.Server, error) {
 		if err != nil {
 			return nil, err
 		}
-		// BCrypt
-		hashGen = crypto.NewBcryptoHashGenerator()
+		// Password hash
+		var passwordHashConfig *infrastructure.PasswordHashConfig
+		passwordHashConfig, err = infrastructure.LoadPasswordHashConfig()
+		if err != nil {
+			return nil, err
+		}
+		hashGens, err = passwordHashConfig.GetHashGenerators()
+		if err != nil {
+			return nil, err
+		}
+		// Password pepper
+		var passwordPepperConfig *infrastructure.PasswordPepperConfig
+		passwordPepperConfig, err = infrastructure.LoadPasswordPepperConfig()
+		if err != nil {
+			return nil, err
+		}
+		var pepperKeyring *crypto.Keyring
+		if passwordPepperConfig.Enabled {
+			var keyringConfig *infrastructure.KeyringConfig
+			keyringConfig, err = infrastructure.LoadKeyringConfig()
+			if err != nil {
+				return nil, err
+			}
+			if !keyringConfig.Enabled() {
+				return nil, fmt.Errorf("password pepper requires CRYPTO_KEYRING_KEYS")
+			}
+			pepperKeyring, err = keyringConfig.GetKeyring()
+			if err != nil {
+				return nil, err
+			}
+		}
+		var pepperCryptors map[string]crypto.Cryptor
+		pepperCryptors, err = passwordPepperConfig.GetLegacyCryptors()
+		if err != nil {
+			return nil, err
+		}
+		passwordPepper, err = adapter.NewPasswordPepper(pepperKeyring, pepperCryptors)
+		if err != nil {
+			return nil, err
+		}
+		// SMTP
+		var smtpConfig *infrastructure.SMTPConfig
+		smtpConfig, err = infrastructure.LoadSMTPConfig()
+		if err != nil {
+			return nil, err
+		}
+		mailClient = infrastructure.NewSMTPClient(smtpConfig)
+		// Email
+		emailConfig, err = infrastructure.LoadEmailConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Email verification
+		emailVerificationConfig, err = infrastructure.LoadEmailVerificationConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Password reset
+		passwordResetConfig, err = infrastructure.LoadPasswordResetConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Email login
+		emailLoginConfig, err = infrastructure.LoadEmailLoginConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Password policy
+		var passwordPolicyConfig *infrastructure.PasswordPolicyConfig
+		passwordPolicyConfig, err = infrastructure.LoadPasswordPolicyConfig()
+		if err != nil {
+			return nil, err
+		}
+		passwordPolicy, err = passwordPolicyConfig.GetPasswordPolicy()
+		if err != nil {
+			return nil, err
+		}
+		// Pwned passwords
+		pwnedPasswordsConfig, err = infrastructure.LoadPwnedPasswordsConfig()
+		if err != nil {
+			return nil, err
+		}
+		if pwnedPasswordsConfig.Enabled() {
+			switch pwnedPasswordsConfig.Source {
+			case "s3":
+				var awsConfig *infrastructure.AWSConfig
+				awsConfig, err = infrastructure.LoadAWSConfig()
+				if err != nil {
+					return nil, err
+				}
+				pwnedStorage = infrastructure.NewS3Client(pwnedPasswordsConfig.Bucket, awsConfig.GetConfig())
+			default:
+				pwnedCorpus, err = pwned.OpenCorpusFile(pwnedPasswordsConfig.File)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+		// TOTP
+		totpConfig, err = infrastructure.LoadTOTPConfig()
+		if err != nil {
+			return nil, err
+		}
+		if totpConfig.Enabled {
+			var keyringConfig *infrastructure.KeyringConfig
+			keyringConfig, err = infrastructure.LoadKeyringConfig()
+			if err != nil {
+				return nil, err
+			}
+			if !keyringConfig.Enabled() {
+				return nil, fmt.Errorf("TOTP requires CRYPTO_KEYRING_KEYS")
+			}
+			totpKeyring, err = keyringConfig.GetKeyring()
+			if err != nil {
+				return nil, err
+			}
+		}
+		// WebAuthn
+		webAuthnConfig, err = infrastructure.LoadWebAuthnConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Step-up
+		stepUpConfig, err = infrastructure.LoadStepUpConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Session
+		sessionConfig, err = infrastructure.LoadSessionConfig()
+		if err != nil {
+			return nil, err
+		}
+		// CSRF
+		csrfConfig, err = infrastructure.LoadCSRFConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Login lockout
+		loginLockoutConfig, err = infrastructure.LoadLoginLockoutConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Server
+		serverConfig, err = infrastructure.LoadServerConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Background jobs
+		backgroundJobsConfig, err = infrastructure.LoadBackgroundJobsConfig()
+		if err != nil {
+			return nil, err
+		}
+		// IP policy
+		ipPolicyConfig, err = infrastructure.LoadIPPolicyConfig()
+		if err != nil {
+			return nil, err
+		}
+		if ipPolicyConfig.Enabled() {
+			ipPolicyFile, err = os.ReadFile(ipPolicyConfig.File)
+			if err != nil {
+				return nil, err
+			}
+			ipPolicyRules, err = middlewares.ParseIPPolicyRules(string(ipPolicyFile))
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Access policy
+		accessPolicyConfig, err = infrastructure.LoadAccessPolicyConfig()
+		if err != nil {
+			return nil, err
+		}
+		if accessPolicyConfig.Enabled() {
+			accessPolicyFile, err = os.ReadFile(accessPolicyConfig.File)
+			if err != nil {
+				return nil, err
+			}
+			accessPolicy, err = entity.ParseAccessPolicy(string(accessPolicyFile))
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Signup
+		signupConfig, err = infrastructure.LoadSignupConfig()
+		if err != nil {
+			return nil, err
+		}
+		// Rate limit
+		rateLimitConfig, err = infrastructure.LoadRateLimitConfig()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range rateLimitConfig.Rules {
+			var rule middlewares.RateLimitRule
+			rule, err = middlewares.ParseRateLimitRule(v)
+			if err != nil {
+				return nil, err
+			}
+			rateLimitRules = append(rateLimitRules, rule)
+		}
 	}
 
 	// ports
 	var (
-		txm                   port.TransactionManager
-		passwordManager       port.PasswordManager
-		userGateway           port.UserGateway
-		userCredentialGateway port.UserCredentialGateway
+		txm                    port.TransactionManager
+		passwordManager        port.PasswordManager
+		userGateway            port.UserGateway
+		userCredentialGateway  port.UserCredentialGateway
+		verificationTokens     port.EmailVerificationTokenManager
+		resetTokens            port.PasswordResetTokenManager
+		emailLoginCodes        port.EmailLoginCodeGateway
+		emailLoginTokens       port.EmailLoginTokenManager
+		mailer                 port.Mailer
+		breachedPasswords      port.BreachedPasswordChecker
+		loginBreachedPasswords port.BreachedPasswordChecker
+		passwordHistoryGateway port.PasswordHistoryGateway
+		totpGateway            port.TOTPGateway
+		totpManager            port.TOTPManager
+		recoveryCodeGateway    port.RecoveryCodeGateway
+		webAuthnCredentials    port.WebAuthnCredentialGateway
+		webAuthnChallenges     port.WebAuthnChallengeGateway
+		webAuthnVerifier       port.WebAuthnVerifier
+		sessionStore           port.SessionStore
+		csrfTokens             port.CSRFTokenManager
+		loginFailures          port.LoginFailureGateway
+		rateLimitStore         port.RateLimitStore
+		roleGateway            port.RoleGateway
+		groupGateway           port.GroupGateway
+		accessPolicies         port.AccessPolicySource
 	)
 	{
 		txm = adapter.NewTransactionManager(&rdb)
-		passwordManager = adapter.NewPasswordManager(hashGen)
+		passwordManager = adapter.NewPasswordManager(hashGens[0], hashGens[1:]...)
+		switch {
+		case pwnedCorpus != nil:
+			breachedPasswords = adapter.NewBreachedPasswordChecker(
+				pwnedCorpus,
+				pwnedPasswordsConfig.MinCount,
+			)
+		case pwnedStorage != nil:
+			breachedPasswords, err = adapter.NewBreachedPasswordCheckerFromStorage(
+				ctx,
+				pwnedStorage,
+				pwnedPasswordsConfig.File,
+				pwnedPasswordsConfig.MinCount,
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if breachedPasswords != nil {
+			if pwnedPasswordsConfig.FlagOnLogin {
+				loginBreachedPasswords = breachedPasswords
+			}
+		}
 		userGateway = adapter.NewUserGateway(
 			idAdapter.NewULIDGenerator(),
 			rdbAdapter.NewUserAccess(),
+			emailConfig.GetLocalPartPolicy(),
+		)
+		roleGateway = adapter.NewRoleGateway(
+			rdbAdapter.NewRoleAccess(),
+		)
+		groupGateway = adapter.NewGroupGateway(
+			idAdapter.NewULIDGenerator(),
+			rdbAdapter.NewGroupAccess(),
 		)
 		userCredentialGateway = adapter.NewUserCredentialGateway(
 			idAdapter.NewULIDGenerator(),
 			passwordManager,
 			rdbAdapter.NewUserAccess(),
 			rdbAdapter.NewUserCredential(),
+			rdbAdapter.NewPasswordHistoryAccess(),
+			passwordPepper,
+			emailConfig.GetLocalPartPolicy(),
+			loginBreachedPasswords,
+		)
+		passwordHistoryGateway = adapter.NewPasswordHistoryGateway(
+			rdbAdapter.NewPasswordHistoryAccess(),
+			passwordPepper,
+		)
+		verificationTokens = adapter.NewEmailVerificationTokenManager(
+			crypto.NewHMACGenerator(emailVerificationConfig.Secret),
+			emailVerificationConfig.TTL,
+		)
+		resetTokens = adapter.NewPasswordResetTokenManager(
+			crypto.NewHMACGenerator(passwordResetConfig.Secret),
+			passwordResetConfig.TTL,
+		)
+		if emailLoginConfig.Enabled {
+			emailLoginCodes = adapter.NewEmailLoginCodeGateway(
+				idAdapter.NewULIDGenerator(),
+				rdbAdapter.NewEmailLoginCodeAccess(),
+				emailLoginConfig.TTL,
+				emailLoginConfig.MaxAttempts,
+			)
+			emailLoginTokens = adapter.NewEmailLoginTokenManager(
+				crypto.NewHMACGenerator(emailLoginConfig.Secret),
+			)
+		}
+		if totpKeyring != nil {
+			totpGateway = adapter.NewTOTPGateway(
+				rdbAdapter.NewUserTOTPAccess(),
+				totpKeyring,
+			)
+			totpManager = adapter.NewTOTPManager(
+				totpConfig.GetTOTP(),
+				totpConfig.Issuer,
+			)
+			recoveryCodeGateway = adapter.NewRecoveryCodeGateway(
+				idAdapter.NewULIDGenerator(),
+				rdbAdapter.NewRecoveryCodeAccess(),
+			)
+		}
+		if webAuthnConfig.Enabled {
+			webAuthnCredentials = adapter.NewWebAuthnCredentialGateway(
+				idAdapter.NewULIDGenerator(),
+				rdbAdapter.NewWebAuthnCredentialAccess(),
+			)
+			webAuthnChallenges = adapter.NewWebAuthnChallengeGateway(
+				idAdapter.NewULIDGenerator(),
+				rdbAdapter.NewWebAuthnChallengeAccess(),
+				webAuthnConfig.ChallengeTTL,
+			)
+			webAuthnVerifier = adapter.NewWebAuthnVerifier(
+				webAuthnConfig.GetRelyingParty(),
+			)
+		}
+		if sessionConfig.Store == "memory" {
+			sessionStore = adapter.NewMemorySessionStore(
+				idAdapter.NewULIDGenerator(),
+			)
+		} else {
+			sessionStore = adapter.NewSessionStore(
+				idAdapter.NewULIDGenerator(),
+				rdbAdapter.NewSessionAccess(),
+			)
+		}
+		if loginLockoutConfig.Enabled {
+			loginFailures = adapter.NewLoginFailureGateway(
+				rdbAdapter.NewLoginFailureAccess(),
+			)
+		}
+		if rateLimitConfig.Enabled {
+			if rateLimitConfig.Store == "memory" {
+				rateLimitStore = adapter.NewMemoryRateLimitStore(
+					rateLimitConfig.Shards,
+				)
+			} else {
+				rateLimitStore = adapter.NewRateLimitStore(
+					rdbAdapter.NewRateLimitBucketAccess(),
+				)
+			}
+		}
+		if accessPolicy != nil {
+			accessPolicyTable := adapter.NewAccessPolicyTable(accessPolicy)
+			go infrastructure.WatchFile(
+				ctx,
+				accessPolicyConfig.File,
+				accessPolicyFile,
+				accessPolicyConfig.ReloadInterval,
+				func(b []byte) {
+					policy, pErr := entity.ParseAccessPolicy(string(b))
+					if pErr != nil {
+						logger.Error(pErr, "failed to reload access policy")
+						return
+					}
+					accessPolicyTable.Set(policy)
+					logger.Info("access policy reloaded")
+				},
+				func(rErr error) {
+					logger.Error(rErr, "failed to read access policy")
+				},
+			)
+			accessPolicies = accessPolicyTable
+		}
+		csrfTokens = adapter.NewCSRFTokenManager(
+			crypto.NewHMACGenerator(csrfConfig.Secret),
+		)
+		mailer = adapter.NewMailer(
+			mailClient,
+			emailVerificationConfig.URL,
+			passwordResetConfig.URL,
+			emailLoginConfig.URL,
 		)
 	}
 	// interactors
 	var (
-		userInteractor interactor.UserInteractor
+		userInteractor              interactor.UserInteractor
+		authInteractor              interactor.AuthInteractor
+		emailVerificationInteractor interactor.EmailVerificationInteractor
+		passwordInteractor          interactor.PasswordInteractor
+		totpInteractor              interactor.TOTPInteractor
+		webAuthnInteractor          interactor.WebAuthnInteractor
+		emailLoginInteractor        interactor.EmailLoginInteractor
+		sessionInteractor           interactor.SessionInteractor
+		rateLimitInteractor         interactor.RateLimitInteractor
+		roleInteractor              interactor.RoleInteractor
+		groupInteractor             interactor.GroupInteractor
+		accessInteractor            interactor.AccessInteractor
 	)
 	{
+		backgroundJobs := interactor.NewBackgroundJobs(
+			backgroundJobsConfig.Workers,
+			backgroundJobsConfig.QueueSize,
+		)
 		userInteractor = interactor.NewUserInteractor(
 			userGateway,
 			userCredentialGateway,
+			verificationTokens,
+			mailer,
+			passwordManager,
+			*passwordPolicy,
+			breachedPasswords,
+			interactor.UserPolicy{
+				AllowPasswordless:     emailLoginConfig.Enabled,
+				ConcealExistingEmails: signupConfig.ConcealExistingEmails,
+			},
+		)
+		authInteractor = interactor.NewAuthInteractor(
+			userGateway,
+			userCredentialGateway,
+			loginFailures,
+			totpGateway,
+			totpManager,
+			recoveryCodeGateway,
+			webAuthnCredentials,
+			webAuthnChallenges,
+			webAuthnVerifier,
+			interactor.AuthPolicy{
+				RequireVerifiedEmail: emailVerificationConfig.Required,
+				Lockout:              loginLockoutConfig.GetPolicy(),
+			},
+		)
+		sessionInteractor = interactor.NewSessionInteractor(
+			userGateway,
+			sessionStore,
+			csrfTokens,
+			interactor.SessionPolicy{
+				IdleTimeout:     sessionConfig.IdleTimeout,
+				AbsoluteTimeout: sessionConfig.AbsoluteTimeout,
+			},
+		)
+		roleInteractor = interactor.NewRoleInteractor(
+			userGateway,
+			roleGateway,
+		)
+		groupInteractor = interactor.NewGroupInteractor(
+			userGateway,
+			groupGateway,
+		)
+		emailVerificationInteractor = interactor.NewEmailVerificationInteractor(
+			userGateway,
+			verificationTokens,
+		)
+		passwordInteractor = interactor.NewPasswordInteractor(
+			userGateway,
+			userCredentialGateway,
+			resetTokens,
+			mailer,
+			sessionStore,
+			passwordManager,
+			*passwordPolicy,
+			breachedPasswords,
+			passwordHistoryGateway,
+			loginFailures,
+			loginLockoutConfig.GetPolicy(),
+			backgroundJobs,
 		)
+		if totpGateway != nil {
+			totpInteractor = interactor.NewTOTPInteractor(
+				userGateway,
+				totpGateway,
+				totpManager,
+				recoveryCodeGateway,
+				interactor.TOTPPolicy{
+					RecoveryCodeCount: totpConfig.RecoveryCodeCount,
+				},
+			)
+		}
+		if emailLoginCodes != nil {
+			emailLoginInteractor = interactor.NewEmailLoginInteractor(
+				idAdapter.NewULIDGenerator(),
+				userGateway,
+				emailLoginCodes,
+				emailLoginTokens,
+				mailer,
+				totpGateway,
+				totpManager,
+				recoveryCodeGateway,
+				loginFailures,
+				interactor.EmailLoginPolicy{
+					RequireVerifiedEmail: emailVerificationConfig.Required,
+					Lockout:              loginLockoutConfig.GetPolicy(),
+				},
+				backgroundJobs,
+			)
+		}
+		if accessPolicies != nil {
+			accessInteractor = interactor.NewAccessInteractor(
+				userGateway,
+				roleGateway,
+				groupGateway,
+				accessPolicies,
+			)
+		}
+		if rateLimitStore != nil {
+			rateLimitInteractor = interactor.NewRateLimitInteractor(
+				rateLimitStore,
+			)
+		}
+		if webAuthnCredentials != nil {
+			webAuthnInteractor = interactor.NewWebAuthnInteractor(
+				userGateway,
+				webAuthnCredentials,
+				webAuthnChallenges,
+				webAuthnVerifier,
+				interactor.WebAuthnPolicy{
+					RequireVerifiedEmail: emailVerificationConfig.Required,
+				},
+			)
+		}
 	}
 
 	// routes
 	var r routes.Routes
-	users := routes.NewUserRoutes(
+	sessionCookie := handlers.SessionCookie{
+		Name:     sessionConfig.CookieName,
+		CSRFName: sessionConfig.CSRFCookieName,
+		Domain:   sessionConfig.CookieDomain,
+		Secure:   sessionConfig.CookieSecure,
+		SameSite: sessionConfig.GetCookieSameSite(),
+	}
+	checkAuth := middlewares.CheckAuth(
 		txm,
-		userCredentialGateway,
+		authInteractor,
+		sessionInteractor,
+		roleInteractor,
+		sessionCookie,
+		middlewares.CSRFPolicy{
+			TrustedOrigins: csrfConfig.TrustedOrigins,
+		},
+	)
+	stepUp := middlewares.AuthRequirement{
+		Level:  stepUpConfig.GetLevel(),
+		MaxAge: stepUpConfig.MaxAge,
+	}
+	users := routes.NewUserRoutes(
+		checkAuth,
+		stepUp,
 		handlers.NewUserFindHandler(txm, userInteractor),
-		handlers.NewUserCreateHandler(txm, passwordManager, userInteractor),
+		handlers.NewUserCreateHandler(txm, userInteractor, signupConfig.ConcealExistingEmails),
 		handlers.NewUserGetHandler(txm, userInteractor),
 		handlers.NewUserUpdateHandler(txm, userInteractor),
 	)
 	r = append(r, users...)
+	sessions := routes.NewSessionRoutes(
+		checkAuth,
+		handlers.NewSessionCreateHandler(txm, authInteractor, sessionInteractor, groupInteractor, sessionCookie),
+		handlers.NewSessionDeleteHandler(txm, sessionInteractor, sessionCookie),
+		handlers.NewSessionListHandler(txm, sessionInteractor),
+		handlers.NewSessionRevokeHandler(txm, sessionInteractor, sessionCookie),
+		handlers.NewSessionRevokeOthersHandler(txm, sessionInteractor),
+	)
+	r = append(r, sessions...)
+	roles := routes.NewRoleRoutes(
+		checkAuth,
+		stepUp,
+		handlers.NewRoleListHandler(txm, roleInteractor),
+		handlers.NewRoleSaveHandler(txm, roleInteractor),
+		handlers.NewRoleDeleteHandler(txm, roleInteractor),
+		handlers.NewUserRoleListHandler(txm, roleInteractor),
+		handlers.NewUserRoleAssignHandler(txm, roleInteractor),
+		handlers.NewUserRoleUnassignHandler(txm, roleInteractor),
+	)
+	r = append(r, roles...)
+	groups := routes.NewGroupRoutes(
+		checkAuth,
+		stepUp,
+		handlers.NewGroupListHandler(txm, groupInteractor),
+		handlers.NewGroupCreateHandler(txm, groupInteractor),
+		handlers.NewGroupGetHandler(txm, groupInteractor),
+		handlers.NewGroupUpdateHandler(txm, groupInteractor),
+		handlers.NewGroupDeleteHandler(txm, groupInteractor),
+		handlers.NewGroupMemberListHandler(txm, groupInteractor),
+		handlers.NewGroupUserAddHandler(txm, groupInteractor),
+		handlers.NewGroupUserRemoveHandler(txm, groupInteractor),
+		handlers.NewGroupSubgroupAddHandler(txm, groupInteractor),
+		handlers.NewGroupSubgroupRemoveHandler(txm, groupInteractor),
+		handlers.NewUserGroupListHandler(txm, groupInteractor),
+		handlers.NewUserInfoGetHandler(txm, groupInteractor),
+	)
+	r = append(r, groups...)
+	emailVerifications := routes.NewEmailVerificationRoutes(
+		handlers.NewEmailVerificationCreateHandler(txm, emailVerificationInteractor),
+	)
+	r = append(r, emailVerifications...)
+	passwords := routes.NewPasswordRoutes(
+		checkAuth,
+		stepUp,
+		handlers.NewPasswordChangeHandler(txm, passwordInteractor),
+		handlers.NewPasswordResetCreateHandler(txm, passwordInteractor),
+		handlers.NewPasswordResetUpdateHandler(txm, passwordInteractor),
+	)
+	r = append(r, passwords...)
+	if totpInteractor != nil {
+		totps := routes.NewTOTPRoutes(
+			checkAuth,
+			stepUp,
+			handlers.NewTOTPCreateHandler(txm, totpInteractor),
+			handlers.NewTOTPUpdateHandler(txm, totpInteractor),
+			handlers.NewTOTPDeleteHandler(txm, totpInteractor),
+			handlers.NewRecoveryCodeCreateHandler(txm, totpInteractor),
+		)
+		r = append(r, totps...)
+	}
+	if emailLoginInteractor != nil {
+		emailLogins := routes.NewEmailLoginRoutes(
+			handlers.NewEmailLoginCreateHandler(txm, emailLoginInteractor),
+			handlers.NewEmailLoginUpdateHandler(txm, emailLoginInteractor, sessionInteractor, groupInteractor, sessionCookie),
+			handlers.NewEmailLoginLinkCreateHandler(txm, emailLoginInteractor, sessionInteractor, groupInteractor, sessionCookie),
+		)
+		r = append(r, emailLogins...)
+	}
+	if webAuthnInteractor != nil {
+		webAuthn := routes.NewWebAuthnRoutes(
+			checkAuth,
+			stepUp,
+			handlers.NewWebAuthnCredentialOptionsCreateHandler(txm, webAuthnInteractor),
+			handlers.NewWebAuthnCredentialCreateHandler(txm, webAuthnInteractor),
+			handlers.NewWebAuthnAssertionOptionsCreateHandler(txm, webAuthnInteractor),
+			handlers.NewWebAuthnAssertionCreateHandler(txm, webAuthnInteractor, sessionInteractor, groupInteractor, sessionCookie),
+		)
+		r = append(r, webAuthn...)
+	}
+	if accessInteractor != nil {
+		access := routes.NewAccessRoutes(
+			checkAuth,
+			handlers.NewAccessDecisionCreateHandler(txm, accessInteractor),
+		)
+		r = append(r, access...)
+	}
 	health := routes.NewHealthRoutes(
 		handlers.NewHealthGetHandler(),
 	)
 	r = append(r, health...)
 
-	return web.NewGinServer(r...), nil
+	var middleware []handlers.Handler
+	if ipPolicyConfig.Enabled() {
+		ipPolicies := middlewares.NewIPPolicyTable(ipPolicyRules)
+		go infrastructure.WatchFile(
+			ctx,
+			ipPolicyConfig.File,
+			ipPolicyFile,
+			ipPolicyConfig.ReloadInterval,
+			func(b []byte) {
+				rules, pErr := middlewares.ParseIPPolicyRules(string(b))
+				if pErr != nil {
+					logger.Error(pErr, "failed to reload ip policies")
+					return
+				}
+				ipPolicies.Set(rules)
+				logger.Info("ip policies reloaded")
+			},
+			func(rErr error) {
+				logger.Error(rErr, "failed to read ip policies")
+			},
+		)
+		middleware = append(middleware, middlewares.IPPolicy(ipPolicies))
+	}
+	if rateLimitInteractor != nil {
+		middleware = append(middleware, middlewares.RateLimit(
+			txm,
+			rateLimitInteractor,
+			rateLimitRules,
+			emailConfig.GetLocalPartPolicy(),
+		))
+	}
+
+	server := web.NewGinServer(r, middleware...)
+	if err = server.SetTrustedProxies(serverConfig.TrustedProxies); err != nil {
+		return nil, err
+	}
+
+	return server, nil
 }
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/mkaiho/go-auth-api/adapter"
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	idAdapter "github.com/mkaiho/go-auth-api/adapter/id"
+	rdbAdapter "github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/infrastructure"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initErr error
+	command *cobra.Command
+)
+
+func init() {
+	util.InitGLogger(
+		util.OptionLoggerLevel(util.LoggerLevelInfo),
+		util.OptionLoggerFormat(util.LoggerFormatJSON),
+	)
+	command = newCommand()
+}
+
+func main() {
+	var err error
+	logger := util.GLogger()
+	defer func() {
+		if p := recover(); p != nil {
+			msg := "panic has occured"
+			if pErr, ok := p.(error); ok {
+				logger.Error(pErr, msg)
+			} else {
+				logger.Error(fmt.Errorf("%v", p), msg)
+			}
+			os.Exit(1)
+		}
+		if err != nil {
+			logger.Error(err, "error has occured")
+			os.Exit(1)
+		}
+		logger.Info("completed")
+	}()
+	if err = command.Execute(); err != nil {
+		return
+	}
+}
+
+func newCommand() *cobra.Command {
+	command := cobra.Command{
+		Use:   "import-users --file users.csv",
+		Short: "import users with password hashes from other systems",
+		Long: `import users with password hashes from other systems.
+
+Records are read from CSV with a header row or from JSON lines, with the
+fields name, email, email_verified, password_hash, hash_algorithm, salt and
+salt_position. hash_algorithm is detected from password_hash when empty,
+except for salted_sha256 whose hex digest needs salt and salt_position
+(prefix or suffix). Imported hashes are replaced with the configured
+algorithm on each user's first successful login.`,
+		RunE:          handle,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	command.Flags().StringP("file", "f", "", "file to import")
+	command.Flags().StringP("format", "", "", "csv or jsonl, detected from the file extension when empty")
+	command.MarkFlagRequired("file")
+
+	return &command
+}
+
+func handle(cmd *cobra.Command, args []string) (err error) {
+	ctx := util.NewContextWithLogger(context.Background(), util.GLogger())
+	logger := util.FromContext(ctx)
+	if initErr != nil {
+		return initErr
+	}
+
+	filename, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	if len(format) == 0 {
+		format = filepath.Ext(filename)
+	}
+	recordFormat := parseRecordFormat(format)
+	if recordFormat == recordFormatUnsupported {
+		return fmt.Errorf("unsupported format: %s", format)
+	}
+
+	txm, importInteractor, hashImporter, err := newImporter()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var imported, skipped, failed int
+	err = readRecords(f, recordFormat, func(line int, record importRecord) error {
+		recordLogger := logger.WithValues("line", line).WithValues("email", record.Email)
+		err := importRecordInTx(ctx, txm, importInteractor, hashImporter, record)
+		switch {
+		case err == nil:
+			imported++
+		case errors.Is(err, usecase.ErrAlreadyExistsEntity):
+			recordLogger.Info("skip existing user")
+			skipped++
+		default:
+			recordLogger.Error(err, "failed to import user")
+			failed++
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.
+		WithValues("imported", imported).
+		WithValues("skipped", skipped).
+		WithValues("failed", failed).
+		Info("import finished")
+	if failed > 0 {
+		return fmt.Errorf("failed to import %d users", failed)
+	}
+	return nil
+}
+
+func importRecordInTx(
+	ctx context.Context,
+	txm port.TransactionManager,
+	importInteractor interactor.UserImportInteractor,
+	hashImporter *crypto.HashImporter,
+	record importRecord,
+) (err error) {
+	email, err := entity.ParseEmail(record.Email)
+	if err != nil {
+		return err
+	}
+	hashed, err := hashImporter.Import(
+		crypto.ParseHashAlgorithm(record.HashAlgorithm),
+		record.PasswordHash,
+		record.Salt,
+		crypto.ParseSaltPosition(record.SaltPosition),
+	)
+	if err != nil {
+		return err
+	}
+	password, err := entity.ParseHashedPassword(string(hashed))
+	if err != nil {
+		return err
+	}
+
+	ctx, err = txm.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			txm.Rollback(ctx)
+			return
+		}
+		err = txm.End(ctx)
+	}()
+
+	_, err = importInteractor.ImportUser(ctx, interactor.ImportUserInput{
+		Name:          record.Name,
+		Email:         email,
+		EmailVerified: record.EmailVerified,
+		Password:      password,
+	})
+	return err
+}
+
+func newImporter() (port.TransactionManager, interactor.UserImportInteractor, *crypto.HashImporter, error) {
+	var err error
+	// infra
+	var (
+		rdb            rdbAdapter.DB
+		hashGens       []crypto.PasswordHashGenerator
+		passwordPepper *adapter.PasswordPepper
+		emailConfig    *infrastructure.EmailConfig
+	)
+	{
+		// RDB
+		var rdbConfig *infrastructure.MySQLConfig
+		rdbConfig, err = infrastructure.LoadMySQLConfig()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		rdb, err = infrastructure.OpenRDB(rdbConfig)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// Password hash
+		var passwordHashConfig *infrastructure.PasswordHashConfig
+		passwordHashConfig, err = infrastructure.LoadPasswordHashConfig()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		hashGens, err = passwordHashConfig.GetHashGenerators()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// Password pepper
+		var passwordPepperConfig *infrastructure.PasswordPepperConfig
+		passwordPepperConfig, err = infrastructure.LoadPasswordPepperConfig()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var pepperKeyring *crypto.Keyring
+		if passwordPepperConfig.Enabled {
+			var keyringConfig *infrastructure.KeyringConfig
+			keyringConfig, err = infrastructure.LoadKeyringConfig()
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			if !keyringConfig.Enabled() {
+				return nil, nil, nil, fmt.Errorf("password pepper requires CRYPTO_KEYRING_KEYS")
+			}
+			pepperKeyring, err = keyringConfig.GetKeyring()
+			if err != nil {
+				return nil, nil, nil, err
+			}
+		}
+		var pepperCryptors map[string]crypto.Cryptor
+		pepperCryptors, err = passwordPepperConfig.GetLegacyCryptors()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		passwordPepper, err = adapter.NewPasswordPepper(pepperKeyring, pepperCryptors)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// Email
+		emailConfig, err = infrastructure.LoadEmailConfig()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	// ports
+	var (
+		txm                   port.TransactionManager
+		userGateway           port.UserGateway
+		userCredentialGateway port.UserCredentialGateway
+	)
+	{
+		txm = adapter.NewTransactionManager(&rdb)
+		userGateway = adapter.NewUserGateway(
+			idAdapter.NewULIDGenerator(),
+			rdbAdapter.NewUserAccess(),
+			emailConfig.GetLocalPartPolicy(),
+		)
+		userCredentialGateway = adapter.NewUserCredentialGateway(
+			idAdapter.NewULIDGenerator(),
+			adapter.NewPasswordManager(hashGens[0], hashGens[1:]...),
+			rdbAdapter.NewUserAccess(),
+			rdbAdapter.NewUserCredential(),
+			rdbAdapter.NewPasswordHistoryAccess(),
+			passwordPepper,
+			emailConfig.GetLocalPartPolicy(),
+			nil,
+		)
+	}
+
+	return txm, interactor.NewUserImportInteractor(userGateway, userCredentialGateway), crypto.NewHashImporter(hashGens...), nil
+}
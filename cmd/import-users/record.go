@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type recordFormat int
+
+const (
+	recordFormatUnsupported recordFormat = iota
+	recordFormatCSV
+	recordFormatJSONLines
+)
+
+func parseRecordFormat(v string) recordFormat {
+	s := strings.ToLower(strings.TrimPrefix(v, "."))
+	switch s {
+	default:
+		return recordFormatUnsupported
+	case "csv":
+		return recordFormatCSV
+	case "jsonl", "ndjson":
+		return recordFormatJSONLines
+	}
+}
+
+type importRecord struct {
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	PasswordHash  string `json:"password_hash"`
+	HashAlgorithm string `json:"hash_algorithm"`
+	Salt          string `json:"salt"`
+	SaltPosition  string `json:"salt_position"`
+}
+
+// readRecords calls fn for each record with its line number. A record that
+// can not be decoded stops the import since the rest of the file is likely
+// malformed too.
+func readRecords(r io.Reader, format recordFormat, fn func(line int, record importRecord) error) error {
+	switch format {
+	case recordFormatCSV:
+		return readCSVRecords(r, fn)
+	case recordFormatJSONLines:
+		return readJSONLinesRecords(r, fn)
+	default:
+		return errors.New("unsupported format")
+	}
+}
+
+func readCSVRecords(r io.Reader, fn func(line int, record importRecord) error) error {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return err
+	}
+	columns := make(map[string]int, len(header))
+	for i, h := range header {
+		columns[strings.TrimSpace(h)] = i
+	}
+	for _, required := range []string{"email", "password_hash"} {
+		if _, ok := columns[required]; !ok {
+			return fmt.Errorf("missing column: %s", required)
+		}
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := cr.FieldPos(0)
+		var verified bool
+		if v := field(row, "email_verified"); len(v) > 0 {
+			verified, err = strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("line %d: invalid email_verified: %w", line, err)
+			}
+		}
+		err = fn(line, importRecord{
+			Name:          field(row, "name"),
+			Email:         field(row, "email"),
+			EmailVerified: verified,
+			PasswordHash:  field(row, "password_hash"),
+			HashAlgorithm: field(row, "hash_algorithm"),
+			Salt:          field(row, "salt"),
+			SaltPosition:  field(row, "salt_position"),
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONLinesRecords(r io.Reader, fn func(line int, record importRecord) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		b := scanner.Bytes()
+		if len(strings.TrimSpace(string(b))) == 0 {
+			continue
+		}
+		var record importRecord
+		if err := json.Unmarshal(b, &record); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := fn(line, record); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mkaiho/go-auth-api/adapter"
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+	rdbAdapter "github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/infrastructure"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initErr error
+	command *cobra.Command
+)
+
+func init() {
+	util.InitGLogger(
+		util.OptionLoggerLevel(util.LoggerLevelInfo),
+		util.OptionLoggerFormat(util.LoggerFormatJSON),
+	)
+	command = newCommand()
+}
+
+func main() {
+	var err error
+	logger := util.GLogger()
+	defer func() {
+		if p := recover(); p != nil {
+			msg := "panic has occured"
+			if pErr, ok := p.(error); ok {
+				logger.Error(pErr, msg)
+			} else {
+				logger.Error(fmt.Errorf("%v", p), msg)
+			}
+			os.Exit(1)
+		}
+		if err != nil {
+			logger.Error(err, "error has occured")
+			os.Exit(1)
+		}
+		logger.Info("completed")
+	}()
+	if err = command.Execute(); err != nil {
+		return
+	}
+}
+
+func newCommand() *cobra.Command {
+	command := cobra.Command{
+		Use:   "rewrap-passwords",
+		Short: "re-wrap stored password hashes with the current pepper key",
+		Long: `re-wrap stored password hashes with the current pepper key.
+
+Hashes wrapped with legacy AES-CFB keys are re-encrypted with the
+CRYPTO_KEYRING current key. Every key that hashes are currently wrapped with
+must still be configured in CRYPTO_KEYRING_KEYS or PASSWORD_PEPPER_LEGACY_KEYS.
+Once this completes, retired keys can be removed.`,
+		RunE:          handle,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	command.Flags().IntP("batch-size", "", 100, "number of hashes re-wrapped per transaction")
+
+	return &command
+}
+
+func handle(cmd *cobra.Command, args []string) (err error) {
+	ctx := util.NewContextWithLogger(context.Background(), util.GLogger())
+	logger := util.FromContext(ctx)
+	if initErr != nil {
+		return initErr
+	}
+
+	batchSize, err := cmd.Flags().GetInt("batch-size")
+	if err != nil {
+		return err
+	}
+	if batchSize < 1 {
+		return fmt.Errorf("invalid batch size: %d", batchSize)
+	}
+
+	txm, rewrapper, err := newRewrapper()
+	if err != nil {
+		return err
+	}
+
+	var total int
+	for {
+		n, err := rewrapBatch(ctx, txm, rewrapper, batchSize)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+		logger.WithValues("rewrapped", total).Info("rewrapped batch")
+	}
+
+	logger.WithValues("rewrapped", total).Info("rewrap finished")
+	return nil
+}
+
+func rewrapBatch(
+	ctx context.Context,
+	txm port.TransactionManager,
+	rewrapper *adapter.PasswordRewrapper,
+	batchSize int,
+) (n int, err error) {
+	ctx, err = txm.BeginContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			txm.Rollback(ctx)
+			return
+		}
+		err = txm.End(ctx)
+	}()
+
+	return rewrapper.Rewrap(ctx, batchSize)
+}
+
+func newRewrapper() (port.TransactionManager, *adapter.PasswordRewrapper, error) {
+	var err error
+	// infra
+	var (
+		rdb            rdbAdapter.DB
+		passwordPepper *adapter.PasswordPepper
+	)
+	{
+		// RDB
+		var rdbConfig *infrastructure.MySQLConfig
+		rdbConfig, err = infrastructure.LoadMySQLConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		rdb, err = infrastructure.OpenRDB(rdbConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Password pepper
+		var passwordPepperConfig *infrastructure.PasswordPepperConfig
+		passwordPepperConfig, err = infrastructure.LoadPasswordPepperConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		var pepperKeyring *crypto.Keyring
+		if passwordPepperConfig.Enabled {
+			var keyringConfig *infrastructure.KeyringConfig
+			keyringConfig, err = infrastructure.LoadKeyringConfig()
+			if err != nil {
+				return nil, nil, err
+			}
+			if !keyringConfig.Enabled() {
+				return nil, nil, fmt.Errorf("password pepper requires CRYPTO_KEYRING_KEYS")
+			}
+			pepperKeyring, err = keyringConfig.GetKeyring()
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		var pepperCryptors map[string]crypto.Cryptor
+		pepperCryptors, err = passwordPepperConfig.GetLegacyCryptors()
+		if err != nil {
+			return nil, nil, err
+		}
+		passwordPepper, err = adapter.NewPasswordPepper(pepperKeyring, pepperCryptors)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// ports
+	txm := adapter.NewTransactionManager(&rdb)
+	rewrapper := adapter.NewPasswordRewrapper(
+		passwordPepper,
+		rdbAdapter.NewUserCredential(),
+		rdbAdapter.NewPasswordHistoryAccess(),
+	)
+
+	return txm, rewrapper, nil
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/mkaiho/go-auth-api/adapter"
+	idAdapter "github.com/mkaiho/go-auth-api/adapter/id"
+	rdbAdapter "github.com/mkaiho/go-auth-api/adapter/rdb"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/infrastructure"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initErr error
+	command *cobra.Command
+)
+
+func init() {
+	util.InitGLogger(
+		util.OptionLoggerLevel(util.LoggerLevelInfo),
+		util.OptionLoggerFormat(util.LoggerFormatJSON),
+	)
+	command = newCommand()
+}
+
+func main() {
+	var err error
+	logger := util.GLogger()
+	defer func() {
+		if p := recover(); p != nil {
+			msg := "panic has occured"
+			if pErr, ok := p.(error); ok {
+				logger.Error(pErr, msg)
+			} else {
+				logger.Error(fmt.Errorf("%v", p), msg)
+			}
+			os.Exit(1)
+		}
+		if err != nil {
+			logger.Error(err, "error has occured")
+			os.Exit(1)
+		}
+		logger.Info("completed")
+	}()
+	if err = command.Execute(); err != nil {
+		return
+	}
+}
+
+func newCommand() *cobra.Command {
+	command := cobra.Command{
+		Use:   "unlock-user --email user@example.com",
+		Short: "unlock a user locked out by failed logins",
+		Long: `unlock a user locked out by failed logins.
+
+The user's failed logins are forgotten, so that they can log in again
+without waiting for LOGIN_LOCKOUT_DURATION to pass.`,
+		RunE:          handle,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	command.Flags().StringP("email", "e", "", "email of the user to unlock")
+	command.MarkFlagRequired("email")
+
+	return &command
+}
+
+func handle(cmd *cobra.Command, args []string) (err error) {
+	ctx := util.NewContextWithLogger(context.Background(), util.GLogger())
+	logger := util.FromContext(ctx)
+	if initErr != nil {
+		return initErr
+	}
+
+	value, err := cmd.Flags().GetString("email")
+	if err != nil {
+		return err
+	}
+	email, err := entity.ParseEmail(value)
+	if err != nil {
+		return err
+	}
+
+	txm, lockoutInteractor, err := newUnlocker()
+	if err != nil {
+		return err
+	}
+
+	ctx, err = txm.BeginContext(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			txm.Rollback(ctx)
+			return
+		}
+		err = txm.End(ctx)
+	}()
+
+	user, err := lockoutInteractor.Unlock(ctx, interactor.UnlockLoginInput{
+		Email: email,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.WithValues("userID", user.ID).Info("unlocked user")
+	return nil
+}
+
+func newUnlocker() (port.TransactionManager, interactor.LoginLockoutInteractor, error) {
+	var err error
+	// infra
+	var (
+		rdb         rdbAdapter.DB
+		emailConfig *infrastructure.EmailConfig
+	)
+	{
+		// RDB
+		var rdbConfig *infrastructure.MySQLConfig
+		rdbConfig, err = infrastructure.LoadMySQLConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		rdb, err = infrastructure.OpenRDB(rdbConfig)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Email
+		emailConfig, err = infrastructure.LoadEmailConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// ports
+	var (
+		txm           port.TransactionManager
+		userGateway   port.UserGateway
+		loginFailures port.LoginFailureGateway
+	)
+	{
+		txm = adapter.NewTransactionManager(&rdb)
+		userGateway = adapter.NewUserGateway(
+			idAdapter.NewULIDGenerator(),
+			rdbAdapter.NewUserAccess(),
+			emailConfig.GetLocalPartPolicy(),
+		)
+		loginFailures = adapter.NewLoginFailureGateway(
+			rdbAdapter.NewLoginFailureAccess(),
+		)
+	}
+
+	return txm, interactor.NewLoginLockoutInteractor(userGateway, loginFailures), nil
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// Decide access
+type (
+	AccessDecisionCreateRequestSubject struct {
+		ID         string            `json:"id" binding:"required"`
+		Attributes entity.Attributes `json:"attributes"`
+	}
+	AccessDecisionCreateRequest struct {
+		Subject  AccessDecisionCreateRequestSubject `json:"subject" binding:"required"`
+		Action   string                             `json:"action" binding:"required"`
+		Resource entity.Attributes                  `json:"resource"`
+		Context  entity.Attributes                  `json:"context"`
+	}
+	AccessDecisionCreateResponse struct {
+		Allowed bool   `json:"allowed"`
+		Rule    string `json:"rule,omitempty"`
+	}
+	AccessDecisionCreateHandler struct {
+		txm              port.TransactionManager
+		accessInteractor interactor.AccessInteractor
+	}
+)
+
+func NewAccessDecisionCreateHandler(
+	txm port.TransactionManager,
+	accessInteractor interactor.AccessInteractor,
+) *AccessDecisionCreateHandler {
+	return &AccessDecisionCreateHandler{
+		txm:              txm,
+		accessInteractor: accessInteractor,
+	}
+}
+
+func (h *AccessDecisionCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(AccessDecisionCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var decision *entity.AccessDecision
+	decision, err = h.accessInteractor.Decide(ctx, interactor.DecideAccessInput{
+		SubjectID:         entity.ID(request.Subject.ID),
+		SubjectAttributes: request.Subject.Attributes,
+		Action:            request.Action,
+		Resource:          request.Resource,
+		Context:           request.Context,
+	})
+	if err != nil {
+		setAccessErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, AccessDecisionCreateResponse{
+		Allowed: decision.Allowed,
+		Rule:    decision.Rule,
+	})
+}
+
+func setAccessErrorType(gErr *gin.Error, err error) {
+	if errors.Is(err, usecase.ErrNotFoundEntity) {
+		gErr.SetType(gin.ErrorTypePublic)
+	}
+}
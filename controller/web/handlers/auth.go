@@ -2,11 +2,16 @@ package handlers
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
 	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
 	"github.com/mkaiho/go-auth-api/util"
 )
 
@@ -14,6 +19,53 @@ var ErrNoAuthValue = errors.New("no auth value")
 var ErrInvalidAuthValue = errors.New("invalid auth header value")
 var ErrNotSupportedAuthType = errors.New("not supported auth type")
 
+// SecondFactorHeader carries a TOTP code along with the credentials of
+// users who have enabled TOTP. Each code is accepted once, so clients
+// sending credentials with every request need a new code for each time
+// step. Recovery codes are only accepted when logging in.
+const SecondFactorHeader = "X-OTP"
+
+const authRealm = "go-auth-api"
+
+// WebAuthnAssertionHeader carries a passkey assertion as base64url encoded
+// JSON, in place of SecondFactorHeader.
+const WebAuthnAssertionHeader = "X-WebAuthn-Assertion"
+
+const (
+	authUserKey       = "auth_user"
+	authenticationKey = "authentication"
+)
+
+// StepUpError tells clients which authentication a route needs. They step
+// up by repeating the request with a second factor, or with fresh
+// credentials once sessions are older than MaxAge.
+type StepUpError struct {
+	Level  entity.AuthLevel
+	MaxAge time.Duration
+}
+
+func (e *StepUpError) Error() string {
+	return usecase.ErrInsufficientAuthentication.Error()
+}
+
+func (e *StepUpError) Unwrap() error {
+	return usecase.ErrInsufficientAuthentication
+}
+
+// Challenge is the WWW-Authenticate value, with the parameters RFC 9470
+// defines for step-up.
+func (e *StepUpError) Challenge() string {
+	challenge := fmt.Sprintf(
+		`Basic realm="%s", error="insufficient_user_authentication", acr_values="%s"`,
+		authRealm,
+		e.Level,
+	)
+	if e.MaxAge > 0 {
+		challenge += fmt.Sprintf(", max_age=%d", int64(e.MaxAge.Seconds()))
+	}
+	return challenge
+}
+
 type Auth struct {
 	User     string `json:"user"`
 	Password string `json:"password"`
@@ -39,23 +91,104 @@ func GetAuthInfo(gc *gin.Context) (*Auth, error) {
 	}
 }
 
+func GetSecondFactor(gc *gin.Context) string {
+	return strings.TrimSpace(gc.Request.Header.Get(SecondFactorHeader))
+}
+
+// GetWebAuthnAssertion returns nil when the request has no assertion.
+func GetWebAuthnAssertion(gc *gin.Context) (*interactor.WebAuthnAssertionInput, error) {
+	hValue := strings.TrimSpace(gc.Request.Header.Get(WebAuthnAssertionHeader))
+	if len(hValue) == 0 {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(hValue, "="))
+	if err != nil {
+		return nil, ErrInvalidAuthValue
+	}
+	var request WebAuthnAssertionRequest
+	if err := json.Unmarshal(b, &request); err != nil {
+		return nil, ErrInvalidAuthValue
+	}
+	if len(request.ChallengeID) == 0 || len(request.CredentialID) == 0 {
+		return nil, ErrInvalidAuthValue
+	}
+	input := request.input()
+	return &input, nil
+}
+
+// SetAuthUser records the user CheckAuth authenticated for later handlers.
+func SetAuthUser(gc *gin.Context, user *entity.User) {
+	gc.Set(authUserKey, user)
+}
+
+// SetAuthentication records how the user was authenticated, along with the
+// user.
+func SetAuthentication(gc *gin.Context, auth *entity.Authentication) {
+	gc.Set(authenticationKey, auth)
+	SetAuthUser(gc, auth.User)
+}
+
+func GetAuthentication(gc *gin.Context) (*entity.Authentication, bool) {
+	v, ok := gc.Get(authenticationKey)
+	if !ok {
+		return nil, false
+	}
+	auth, ok := v.(*entity.Authentication)
+	return auth, ok
+}
+
+func GetAuthUser(gc *gin.Context) (*entity.User, bool) {
+	v, ok := gc.Get(authUserKey)
+	if !ok {
+		return nil, false
+	}
+	user, ok := v.(*entity.User)
+	return user, ok
+}
+
 func IsAuthError(e error) bool {
+	return PublicAuthError(e) != nil
+}
+
+// PublicAuthError returns the error clients are told about auth error e,
+// or nil when e is not one. Details wrapped in e are dropped, and unknown
+// users look like wrong passwords, so that callers can not probe for
+// accounts.
+func PublicAuthError(e error) error {
 	if errors.Is(e, ErrNoAuthValue) {
-		return true
+		return ErrNoAuthValue
 	}
 	if errors.Is(e, ErrInvalidAuthValue) {
-		return true
+		return ErrInvalidAuthValue
 	}
 	if errors.Is(e, ErrNotSupportedAuthType) {
-		return true
+		return ErrNotSupportedAuthType
+	}
+	if errors.Is(e, ErrInvalidSession) {
+		return ErrInvalidSession
 	}
 	if errors.Is(e, usecase.ErrNoAuthUser) {
-		return true
+		return usecase.ErrInvalidCredential
 	}
 	if errors.Is(e, usecase.ErrInvalidCredential) {
-		return true
+		return usecase.ErrInvalidCredential
+	}
+	if errors.Is(e, usecase.ErrEmailNotVerified) {
+		return usecase.ErrEmailNotVerified
+	}
+	if errors.Is(e, usecase.ErrSecondFactorRequired) {
+		return usecase.ErrSecondFactorRequired
+	}
+	if errors.Is(e, usecase.ErrInvalidSecondFactor) {
+		return usecase.ErrInvalidSecondFactor
+	}
+	if errors.Is(e, usecase.ErrSecondFactorUsed) {
+		return usecase.ErrSecondFactorUsed
+	}
+	if errors.Is(e, usecase.ErrInsufficientAuthentication) {
+		return usecase.ErrInsufficientAuthentication
 	}
-	return false
+	return nil
 }
 
 func getBasicAuthInfo(authValue string) (*Auth, error) {
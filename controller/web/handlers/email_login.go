@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// Request email login
+type (
+	EmailLoginCreateRequest struct {
+		Email string `json:"email" form:"email" binding:"required"`
+	}
+	EmailLoginCreateResponse struct {
+		ID string `json:"id"`
+	}
+	EmailLoginCreateHandler struct {
+		txm                  port.TransactionManager
+		emailLoginInteractor interactor.EmailLoginInteractor
+	}
+)
+
+func NewEmailLoginCreateHandler(
+	txm port.TransactionManager,
+	emailLoginInteractor interactor.EmailLoginInteractor,
+) *EmailLoginCreateHandler {
+	return &EmailLoginCreateHandler{
+		txm:                  txm,
+		emailLoginInteractor: emailLoginInteractor,
+	}
+}
+
+func (h *EmailLoginCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(EmailLoginCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	email, err := entity.ParseEmail(request.Email)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var id entity.ID
+	id, err = h.emailLoginInteractor.RequestLogin(ctx, interactor.RequestEmailLoginInput{
+		Email: email,
+	})
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	response := EmailLoginCreateResponse{
+		ID: id.String(),
+	}
+	gc.JSON(http.StatusAccepted, response)
+}
+
+// Log in with email code
+type (
+	EmailLoginUpdateRequest struct {
+		ID   string `json:"id" uri:"id" binding:"required"`
+		Code string `json:"code" form:"code" binding:"required"`
+	}
+	EmailLoginUpdateHandler struct {
+		txm                  port.TransactionManager
+		emailLoginInteractor interactor.EmailLoginInteractor
+		sessionInteractor    interactor.SessionInteractor
+		groupInteractor      interactor.GroupInteractor
+		cookie               SessionCookie
+	}
+)
+
+func NewEmailLoginUpdateHandler(
+	txm port.TransactionManager,
+	emailLoginInteractor interactor.EmailLoginInteractor,
+	sessionInteractor interactor.SessionInteractor,
+	groupInteractor interactor.GroupInteractor,
+	cookie SessionCookie,
+) *EmailLoginUpdateHandler {
+	return &EmailLoginUpdateHandler{
+		txm:                  txm,
+		emailLoginInteractor: emailLoginInteractor,
+		sessionInteractor:    sessionInteractor,
+		groupInteractor:      groupInteractor,
+		cookie:               cookie,
+	}
+}
+
+func (h *EmailLoginUpdateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(EmailLoginUpdateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		// Wrong codes and second factors are committed too, so that they
+		// count against the attempt limit and towards lockout.
+		if err != nil && !interactor.ShouldCommitLogin(err) {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var authentication *entity.Authentication
+	authentication, err = h.emailLoginInteractor.VerifyCode(ctx, interactor.VerifyEmailLoginCodeInput{
+		ID:           entity.ID(request.ID),
+		Code:         request.Code,
+		SecondFactor: GetSecondFactor(gc),
+	})
+	if err != nil {
+		setEmailLoginErrorType(gc.Error(err), err)
+		return
+	}
+	err = startSession(ctx, gc, h.sessionInteractor, h.groupInteractor, h.cookie, authentication)
+}
+
+// Log in with magic link
+type (
+	EmailLoginLinkCreateRequest struct {
+		Token string `json:"token" uri:"token" binding:"required"`
+	}
+	EmailLoginLinkCreateHandler struct {
+		txm                  port.TransactionManager
+		emailLoginInteractor interactor.EmailLoginInteractor
+		sessionInteractor    interactor.SessionInteractor
+		groupInteractor      interactor.GroupInteractor
+		cookie               SessionCookie
+	}
+)
+
+func NewEmailLoginLinkCreateHandler(
+	txm port.TransactionManager,
+	emailLoginInteractor interactor.EmailLoginInteractor,
+	sessionInteractor interactor.SessionInteractor,
+	groupInteractor interactor.GroupInteractor,
+	cookie SessionCookie,
+) *EmailLoginLinkCreateHandler {
+	return &EmailLoginLinkCreateHandler{
+		txm:                  txm,
+		emailLoginInteractor: emailLoginInteractor,
+		sessionInteractor:    sessionInteractor,
+		groupInteractor:      groupInteractor,
+		cookie:               cookie,
+	}
+}
+
+func (h *EmailLoginLinkCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(EmailLoginLinkCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		// Wrong second factors are committed too, so that they count
+		// towards lockout.
+		if err != nil && !interactor.ShouldCommitLogin(err) {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var authentication *entity.Authentication
+	authentication, err = h.emailLoginInteractor.VerifyLink(ctx, interactor.VerifyEmailLoginLinkInput{
+		Token:        request.Token,
+		SecondFactor: GetSecondFactor(gc),
+	})
+	if err != nil {
+		setEmailLoginErrorType(gc.Error(err), err)
+		return
+	}
+	err = startSession(ctx, gc, h.sessionInteractor, h.groupInteractor, h.cookie, authentication)
+}
+
+func setEmailLoginErrorType(gErr *gin.Error, err error) {
+	if IsAuthError(err) ||
+		errors.Is(err, usecase.ErrInvalidToken) {
+		gErr.SetType(gin.ErrorTypePublic)
+	}
+}
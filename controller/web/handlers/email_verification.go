@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// Verify email
+type (
+	EmailVerificationCreateRequest struct {
+		Token string `json:"token" uri:"token" binding:"required"`
+	}
+	EmailVerificationCreateResponse struct {
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	EmailVerificationCreateHandler struct {
+		txm                         port.TransactionManager
+		emailVerificationInteractor interactor.EmailVerificationInteractor
+	}
+)
+
+func NewEmailVerificationCreateHandler(
+	txm port.TransactionManager,
+	emailVerificationInteractor interactor.EmailVerificationInteractor,
+) *EmailVerificationCreateHandler {
+	return &EmailVerificationCreateHandler{
+		txm:                         txm,
+		emailVerificationInteractor: emailVerificationInteractor,
+	}
+}
+
+func (h *EmailVerificationCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(EmailVerificationCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var user *entity.User
+	user, err = h.emailVerificationInteractor.VerifyEmail(ctx, interactor.VerifyEmailInput{
+		Token: request.Token,
+	})
+	if err != nil {
+		gErr := gc.Error(err)
+		if errors.Is(err, usecase.ErrInvalidToken) || errors.Is(err, usecase.ErrNotFoundEntity) {
+			gErr.SetType(gin.ErrorTypePublic)
+		}
+		return
+	}
+
+	response := EmailVerificationCreateResponse{
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Email:         user.Email.String(),
+		EmailVerified: user.EmailVerified,
+	}
+	gc.JSON(http.StatusOK, response)
+}
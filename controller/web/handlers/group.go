@@ -0,0 +1,712 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+type GroupResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func newGroupResponse(group *entity.Group) *GroupResponse {
+	return &GroupResponse{
+		ID:          group.ID.String(),
+		Name:        group.Name,
+		Description: group.Description,
+	}
+}
+
+type GroupListResponse struct {
+	Groups []*GroupResponse `json:"groups"`
+}
+
+func newGroupListResponse(groups entity.Groups) *GroupListResponse {
+	response := &GroupListResponse{
+		Groups: []*GroupResponse{},
+	}
+	for _, group := range groups {
+		response.Groups = append(response.Groups, newGroupResponse(group))
+	}
+	return response
+}
+
+// List groups
+type (
+	GroupListHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupListHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupListHandler {
+	return &GroupListHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupListHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var groups entity.Groups
+	groups, err = h.groupInteractor.ListGroups(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, newGroupListResponse(groups))
+}
+
+// Create group
+type (
+	GroupCreateRequest struct {
+		Name        string `json:"name" form:"name" binding:"required"`
+		Description string `json:"description" form:"description" binding:"max=255"`
+	}
+	GroupCreateHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupCreateHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupCreateHandler {
+	return &GroupCreateHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	name, err := entity.ParseGroupName(request.Name)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var group *entity.Group
+	group, err = h.groupInteractor.CreateGroup(ctx, interactor.CreateGroupInput{
+		Name:        name,
+		Description: request.Description,
+	})
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.JSON(http.StatusCreated, newGroupResponse(group))
+}
+
+// Get group
+type (
+	GroupGetRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	GroupGetHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupGetHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupGetHandler {
+	return &GroupGetHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupGetHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupGetRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var group *entity.Group
+	group, err = h.groupInteractor.GetGroup(ctx, entity.ID(request.ID))
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, newGroupResponse(group))
+}
+
+// Update group
+type (
+	GroupUpdateRequest struct {
+		ID          string `json:"id" uri:"id" binding:"required"`
+		Name        string `json:"name" form:"name" binding:"required"`
+		Description string `json:"description" form:"description" binding:"max=255"`
+	}
+	GroupUpdateHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupUpdateHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupUpdateHandler {
+	return &GroupUpdateHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupUpdateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupUpdateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	name, err := entity.ParseGroupName(request.Name)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var group *entity.Group
+	group, err = h.groupInteractor.UpdateGroup(ctx, interactor.UpdateGroupInput{
+		ID:          entity.ID(request.ID),
+		Name:        name,
+		Description: request.Description,
+	})
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, newGroupResponse(group))
+}
+
+// Delete group
+type (
+	GroupDeleteRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	GroupDeleteHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupDeleteHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupDeleteHandler {
+	return &GroupDeleteHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupDeleteHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupDeleteRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.groupInteractor.DeleteGroup(ctx, entity.ID(request.ID))
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// List group members
+type (
+	GroupMemberListRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	GroupMemberListResponse struct {
+		UserIDs []string         `json:"user_ids"`
+		Groups  []*GroupResponse `json:"groups"`
+	}
+	GroupMemberListHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupMemberListHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupMemberListHandler {
+	return &GroupMemberListHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupMemberListHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupMemberListRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var members *entity.GroupMembers
+	members, err = h.groupInteractor.ListGroupMembers(ctx, entity.ID(request.ID))
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	response := GroupMemberListResponse{
+		UserIDs: []string{},
+		Groups:  newGroupListResponse(members.Subgroups).Groups,
+	}
+	for _, userID := range members.UserIDs {
+		response.UserIDs = append(response.UserIDs, userID.String())
+	}
+	gc.JSON(http.StatusOK, response)
+}
+
+// Add group user
+type (
+	GroupUserAddRequest struct {
+		ID     string `json:"id" uri:"id" binding:"required"`
+		UserID string `json:"user_id" uri:"user_id" binding:"required"`
+	}
+	GroupUserAddHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupUserAddHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupUserAddHandler {
+	return &GroupUserAddHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupUserAddHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupUserAddRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.groupInteractor.AddGroupUser(ctx, interactor.GroupUserInput{
+		GroupID: entity.ID(request.ID),
+		UserID:  entity.ID(request.UserID),
+	})
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// Remove group user
+type (
+	GroupUserRemoveRequest struct {
+		ID     string `json:"id" uri:"id" binding:"required"`
+		UserID string `json:"user_id" uri:"user_id" binding:"required"`
+	}
+	GroupUserRemoveHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupUserRemoveHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupUserRemoveHandler {
+	return &GroupUserRemoveHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupUserRemoveHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupUserRemoveRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.groupInteractor.RemoveGroupUser(ctx, interactor.GroupUserInput{
+		GroupID: entity.ID(request.ID),
+		UserID:  entity.ID(request.UserID),
+	})
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// Add subgroup
+type (
+	GroupSubgroupAddRequest struct {
+		ID         string `json:"id" uri:"id" binding:"required"`
+		SubgroupID string `json:"subgroup_id" uri:"subgroup_id" binding:"required"`
+	}
+	GroupSubgroupAddHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupSubgroupAddHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupSubgroupAddHandler {
+	return &GroupSubgroupAddHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupSubgroupAddHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupSubgroupAddRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.groupInteractor.AddSubgroup(ctx, interactor.SubgroupInput{
+		GroupID:    entity.ID(request.ID),
+		SubgroupID: entity.ID(request.SubgroupID),
+	})
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// Remove subgroup
+type (
+	GroupSubgroupRemoveRequest struct {
+		ID         string `json:"id" uri:"id" binding:"required"`
+		SubgroupID string `json:"subgroup_id" uri:"subgroup_id" binding:"required"`
+	}
+	GroupSubgroupRemoveHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewGroupSubgroupRemoveHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *GroupSubgroupRemoveHandler {
+	return &GroupSubgroupRemoveHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *GroupSubgroupRemoveHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(GroupSubgroupRemoveRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.groupInteractor.RemoveSubgroup(ctx, interactor.SubgroupInput{
+		GroupID:    entity.ID(request.ID),
+		SubgroupID: entity.ID(request.SubgroupID),
+	})
+	if err != nil {
+		setGroupErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// List user groups
+type (
+	UserGroupListRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	UserGroupListHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewUserGroupListHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *UserGroupListHandler {
+	return &UserGroupListHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *UserGroupListHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(UserGroupListRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var groups entity.Groups
+	groups, err = h.groupInteractor.ListUserGroups(ctx, entity.ID(request.ID))
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, newGroupListResponse(groups))
+}
+
+func setGroupErrorType(gErr *gin.Error, err error) {
+	if errors.Is(err, entity.ErrGroupCycle) {
+		gErr.SetType(gin.ErrorTypeBind)
+		return
+	}
+	if errors.Is(err, usecase.ErrNotFoundEntity) ||
+		errors.Is(err, usecase.ErrAlreadyExistsEntity) {
+		gErr.SetType(gin.ErrorTypePublic)
+	}
+}
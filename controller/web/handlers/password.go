@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// Change password
+type (
+	PasswordChangeRequest struct {
+		ID              string `json:"id" uri:"id" binding:"required"`
+		CurrentPassword string `json:"current_password" form:"current_password" binding:"required"`
+		Password        string `json:"password" form:"password" binding:"required"`
+	}
+	PasswordChangeHandler struct {
+		txm                port.TransactionManager
+		passwordInteractor interactor.PasswordInteractor
+	}
+)
+
+func NewPasswordChangeHandler(
+	txm port.TransactionManager,
+	passwordInteractor interactor.PasswordInteractor,
+) *PasswordChangeHandler {
+	return &PasswordChangeHandler{
+		txm:                txm,
+		passwordInteractor: passwordInteractor,
+	}
+}
+
+func (h *PasswordChangeHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(PasswordChangeRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	currentPassword, err := entity.ParsePassword(request.CurrentPassword)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	newPassword, err := entity.ParsePassword(request.Password)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		// Wrong current passwords are committed too, so that they count
+		// towards lockout.
+		if err != nil && !interactor.IsLoginFailure(err) {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	input := interactor.ChangePasswordInput{
+		UserID:          entity.ID(request.ID),
+		CurrentPassword: currentPassword,
+		NewPassword:     newPassword,
+	}
+	if current, ok := GetSession(gc); ok && current.UserID == input.UserID {
+		input.CurrentSessionID = &current.ID
+	}
+	err = h.passwordInteractor.ChangePassword(ctx, input)
+	if err != nil {
+		setPasswordErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// Request password reset
+type (
+	PasswordResetCreateRequest struct {
+		Email string `json:"email" form:"email" binding:"required"`
+	}
+	PasswordResetCreateHandler struct {
+		txm                port.TransactionManager
+		passwordInteractor interactor.PasswordInteractor
+	}
+)
+
+func NewPasswordResetCreateHandler(
+	txm port.TransactionManager,
+	passwordInteractor interactor.PasswordInteractor,
+) *PasswordResetCreateHandler {
+	return &PasswordResetCreateHandler{
+		txm:                txm,
+		passwordInteractor: passwordInteractor,
+	}
+}
+
+func (h *PasswordResetCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(PasswordResetCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	email, err := entity.ParseEmail(request.Email)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.passwordInteractor.RequestPasswordReset(ctx, interactor.RequestPasswordResetInput{
+		Email: email,
+	})
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	gc.Status(http.StatusAccepted)
+}
+
+// Reset password
+type (
+	PasswordResetUpdateRequest struct {
+		Token    string `json:"token" uri:"token" binding:"required"`
+		Password string `json:"password" form:"password" binding:"required"`
+	}
+	PasswordResetUpdateHandler struct {
+		txm                port.TransactionManager
+		passwordInteractor interactor.PasswordInteractor
+	}
+)
+
+func NewPasswordResetUpdateHandler(
+	txm port.TransactionManager,
+	passwordInteractor interactor.PasswordInteractor,
+) *PasswordResetUpdateHandler {
+	return &PasswordResetUpdateHandler{
+		txm:                txm,
+		passwordInteractor: passwordInteractor,
+	}
+}
+
+func (h *PasswordResetUpdateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(PasswordResetUpdateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	newPassword, err := entity.ParsePassword(request.Password)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.passwordInteractor.ResetPassword(ctx, interactor.ResetPasswordInput{
+		Token:       request.Token,
+		NewPassword: newPassword,
+	})
+	if err != nil {
+		setPasswordErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+func setPasswordErrorType(gErr *gin.Error, err error) {
+	var policyErr *entity.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		gErr.SetType(gin.ErrorTypeBind)
+		return
+	}
+	if IsAuthError(err) ||
+		errors.Is(err, usecase.ErrInvalidToken) ||
+		errors.Is(err, usecase.ErrNotFoundEntity) {
+		gErr.SetType(gin.ErrorTypePublic)
+	}
+}
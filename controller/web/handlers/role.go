@@ -0,0 +1,408 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+type RoleResponse struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+func newRoleResponse(role *entity.Role) *RoleResponse {
+	response := &RoleResponse{
+		Name:        role.Name,
+		Permissions: []string{},
+	}
+	for _, p := range role.Permissions {
+		response.Permissions = append(response.Permissions, p.String())
+	}
+	return response
+}
+
+type RoleListResponse struct {
+	Roles []*RoleResponse `json:"roles"`
+}
+
+func newRoleListResponse(roles entity.Roles) *RoleListResponse {
+	response := &RoleListResponse{
+		Roles: []*RoleResponse{},
+	}
+	for _, role := range roles {
+		response.Roles = append(response.Roles, newRoleResponse(role))
+	}
+	return response
+}
+
+// List roles
+type RoleListHandler struct {
+	txm            port.TransactionManager
+	roleInteractor interactor.RoleInteractor
+}
+
+func NewRoleListHandler(
+	txm port.TransactionManager,
+	roleInteractor interactor.RoleInteractor,
+) *RoleListHandler {
+	return &RoleListHandler{
+		txm:            txm,
+		roleInteractor: roleInteractor,
+	}
+}
+
+func (h *RoleListHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var roles entity.Roles
+	roles, err = h.roleInteractor.ListRoles(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, newRoleListResponse(roles))
+}
+
+// Save role
+type (
+	RoleSaveRequest struct {
+		Name        string   `json:"name" uri:"name" binding:"required"`
+		Permissions []string `json:"permissions" binding:"required"`
+	}
+	RoleSaveHandler struct {
+		txm            port.TransactionManager
+		roleInteractor interactor.RoleInteractor
+	}
+)
+
+func NewRoleSaveHandler(
+	txm port.TransactionManager,
+	roleInteractor interactor.RoleInteractor,
+) *RoleSaveHandler {
+	return &RoleSaveHandler{
+		txm:            txm,
+		roleInteractor: roleInteractor,
+	}
+}
+
+func (h *RoleSaveHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(RoleSaveRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	name, err := entity.ParseRoleName(request.Name)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	var permissions []entity.Permission
+	for _, v := range request.Permissions {
+		var p entity.Permission
+		if p, err = entity.ParsePermission(v); err != nil {
+			gc.Error(err).SetType(gin.ErrorTypeBind)
+			return
+		}
+		permissions = append(permissions, p)
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var role *entity.Role
+	role, err = h.roleInteractor.SaveRole(ctx, interactor.SaveRoleInput{
+		Name:        name,
+		Permissions: permissions,
+	})
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, newRoleResponse(role))
+}
+
+// Delete role
+type (
+	RoleDeleteRequest struct {
+		Name string `json:"name" uri:"name" binding:"required"`
+	}
+	RoleDeleteHandler struct {
+		txm            port.TransactionManager
+		roleInteractor interactor.RoleInteractor
+	}
+)
+
+func NewRoleDeleteHandler(
+	txm port.TransactionManager,
+	roleInteractor interactor.RoleInteractor,
+) *RoleDeleteHandler {
+	return &RoleDeleteHandler{
+		txm:            txm,
+		roleInteractor: roleInteractor,
+	}
+}
+
+func (h *RoleDeleteHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(RoleDeleteRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.roleInteractor.DeleteRole(ctx, interactor.DeleteRoleInput{
+		Name: request.Name,
+	})
+	if err != nil {
+		setRoleErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// List user roles
+type (
+	UserRoleListRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	UserRoleListHandler struct {
+		txm            port.TransactionManager
+		roleInteractor interactor.RoleInteractor
+	}
+)
+
+func NewUserRoleListHandler(
+	txm port.TransactionManager,
+	roleInteractor interactor.RoleInteractor,
+) *UserRoleListHandler {
+	return &UserRoleListHandler{
+		txm:            txm,
+		roleInteractor: roleInteractor,
+	}
+}
+
+func (h *UserRoleListHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(UserRoleListRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var roles entity.Roles
+	roles, err = h.roleInteractor.ListUserRoles(ctx, interactor.ListUserRolesInput{
+		UserID: entity.ID(request.ID),
+	})
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, newRoleListResponse(roles))
+}
+
+// Assign role
+type (
+	UserRoleAssignRequest struct {
+		ID       string `json:"id" uri:"id" binding:"required"`
+		RoleName string `json:"name" uri:"name" binding:"required"`
+	}
+	UserRoleAssignHandler struct {
+		txm            port.TransactionManager
+		roleInteractor interactor.RoleInteractor
+	}
+)
+
+func NewUserRoleAssignHandler(
+	txm port.TransactionManager,
+	roleInteractor interactor.RoleInteractor,
+) *UserRoleAssignHandler {
+	return &UserRoleAssignHandler{
+		txm:            txm,
+		roleInteractor: roleInteractor,
+	}
+}
+
+func (h *UserRoleAssignHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(UserRoleAssignRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var role *entity.Role
+	role, err = h.roleInteractor.AssignRole(ctx, interactor.AssignRoleInput{
+		UserID:   entity.ID(request.ID),
+		RoleName: request.RoleName,
+	})
+	if err != nil {
+		setRoleErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, newRoleResponse(role))
+}
+
+// Unassign role
+type (
+	UserRoleUnassignRequest struct {
+		ID       string `json:"id" uri:"id" binding:"required"`
+		RoleName string `json:"name" uri:"name" binding:"required"`
+	}
+	UserRoleUnassignHandler struct {
+		txm            port.TransactionManager
+		roleInteractor interactor.RoleInteractor
+	}
+)
+
+func NewUserRoleUnassignHandler(
+	txm port.TransactionManager,
+	roleInteractor interactor.RoleInteractor,
+) *UserRoleUnassignHandler {
+	return &UserRoleUnassignHandler{
+		txm:            txm,
+		roleInteractor: roleInteractor,
+	}
+}
+
+func (h *UserRoleUnassignHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(UserRoleUnassignRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.roleInteractor.UnassignRole(ctx, interactor.UnassignRoleInput{
+		UserID:   entity.ID(request.ID),
+		RoleName: request.RoleName,
+	})
+	if err != nil {
+		setRoleErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+func setRoleErrorType(gErr *gin.Error, err error) {
+	if errors.Is(err, usecase.ErrNotFoundEntity) {
+		gErr.SetType(gin.ErrorTypePublic)
+	}
+}
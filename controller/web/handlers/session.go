@@ -0,0 +1,567 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+var ErrInvalidSession = errors.New("invalid session")
+
+const sessionKey = "session"
+
+// maxUserAgentLength is the length stored for recognizing devices.
+const maxUserAgentLength = 512
+
+// CSRFTokenHeader carries the CSRF token of the session in requests that
+// change state.
+const CSRFTokenHeader = "X-CSRF-Token"
+
+// SessionCookie carries session tokens. The cookie is always HttpOnly, so
+// that scripts cannot read the token. The CSRF token is set in a second
+// cookie that scripts of the same site read to fill CSRFTokenHeader.
+type SessionCookie struct {
+	Name     string
+	CSRFName string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// Token returns the session token sent by the client, if any.
+func (c SessionCookie) Token(gc *gin.Context) (string, bool) {
+	cookie, err := gc.Request.Cookie(c.Name)
+	if err != nil || len(cookie.Value) == 0 {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+func (c SessionCookie) Set(gc *gin.Context, token string, csrfToken string, expiresAt time.Time) {
+	http.SetCookie(gc.Writer, &http.Cookie{
+		Name:     c.Name,
+		Value:    token,
+		Path:     "/",
+		Domain:   c.Domain,
+		Expires:  expiresAt,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	})
+	http.SetCookie(gc.Writer, &http.Cookie{
+		Name:     c.CSRFName,
+		Value:    csrfToken,
+		Path:     "/",
+		Domain:   c.Domain,
+		Expires:  expiresAt,
+		Secure:   c.Secure,
+		SameSite: c.SameSite,
+	})
+}
+
+func (c SessionCookie) Clear(gc *gin.Context) {
+	http.SetCookie(gc.Writer, &http.Cookie{
+		Name:     c.Name,
+		Path:     "/",
+		Domain:   c.Domain,
+		MaxAge:   -1,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	})
+	http.SetCookie(gc.Writer, &http.Cookie{
+		Name:     c.CSRFName,
+		Path:     "/",
+		Domain:   c.Domain,
+		MaxAge:   -1,
+		Secure:   c.Secure,
+		SameSite: c.SameSite,
+	})
+}
+
+// SetSession records the session a request was authenticated by.
+func SetSession(gc *gin.Context, session *entity.Session) {
+	gc.Set(sessionKey, session)
+}
+
+// GetSession returns false for requests authenticated by credentials.
+func GetSession(gc *gin.Context) (*entity.Session, bool) {
+	v, ok := gc.Get(sessionKey)
+	if !ok {
+		return nil, false
+	}
+	session, ok := v.(*entity.Session)
+	return session, ok
+}
+
+// Log in
+type (
+	SessionCreateRequest struct {
+		Email    string `json:"email" form:"email" binding:"required"`
+		Password string `json:"password" form:"password" binding:"required"`
+		// OTP is a TOTP or recovery code, as in SecondFactorHeader.
+		OTP               string                    `json:"otp" form:"otp"`
+		WebAuthnAssertion *WebAuthnAssertionRequest `json:"webauthn_assertion"`
+	}
+	SessionCreateResponse struct {
+		ID        string    `json:"id"`
+		UserID    string    `json:"user_id"`
+		Level     string    `json:"acr"`
+		Methods   []string  `json:"amr"`
+		Groups    []string  `json:"groups"`
+		CSRFToken string    `json:"csrf_token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	SessionCreateHandler struct {
+		txm               port.TransactionManager
+		authInteractor    interactor.AuthInteractor
+		sessionInteractor interactor.SessionInteractor
+		groupInteractor   interactor.GroupInteractor
+		cookie            SessionCookie
+	}
+)
+
+func NewSessionCreateHandler(
+	txm port.TransactionManager,
+	authInteractor interactor.AuthInteractor,
+	sessionInteractor interactor.SessionInteractor,
+	groupInteractor interactor.GroupInteractor,
+	cookie SessionCookie,
+) *SessionCreateHandler {
+	return &SessionCreateHandler{
+		txm:               txm,
+		authInteractor:    authInteractor,
+		sessionInteractor: sessionInteractor,
+		groupInteractor:   groupInteractor,
+		cookie:            cookie,
+	}
+}
+
+func (h *SessionCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(SessionCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	email, err := entity.ParseEmail(request.Email)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	password, err := entity.ParsePassword(request.Password)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+	input := interactor.AuthenticateInput{
+		Email:        email,
+		Password:     password,
+		SecondFactor: request.OTP,
+	}
+	if request.WebAuthnAssertion != nil {
+		assertion := request.WebAuthnAssertion.input()
+		input.WebAuthnAssertion = &assertion
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		// Failed logins are committed too, so that they count towards
+		// lockout.
+		if err != nil && !interactor.ShouldCommitLogin(err) {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var authentication *entity.Authentication
+	authentication, err = h.authInteractor.Authenticate(ctx, input)
+	if err != nil {
+		setSessionErrorType(gc.Error(err), err)
+		return
+	}
+	err = startSession(ctx, gc, h.sessionInteractor, h.groupInteractor, h.cookie, authentication)
+}
+
+// startSession starts a session for a user authenticated by any login
+// flow, sets its cookies and answers with it.
+func startSession(
+	ctx context.Context,
+	gc *gin.Context,
+	sessionInteractor interactor.SessionInteractor,
+	groupInteractor interactor.GroupInteractor,
+	cookie SessionCookie,
+	authentication *entity.Authentication,
+) error {
+	created, err := sessionInteractor.Create(ctx, interactor.CreateSessionInput{
+		Authentication: authentication,
+		UserAgent:      truncate(gc.Request.UserAgent(), maxUserAgentLength),
+		IPAddress:      gc.ClientIP(),
+	})
+	if err != nil {
+		gc.Error(err)
+		return err
+	}
+	groups, err := groupInteractor.ListUserGroups(ctx, created.Session.UserID)
+	if err != nil {
+		gc.Error(err)
+		return err
+	}
+
+	cookie.Set(gc, created.Token, created.CSRFToken, created.Session.ExpiresAt)
+	response := SessionCreateResponse{
+		ID:        created.Session.ID.String(),
+		UserID:    created.Session.UserID.String(),
+		Level:     created.Session.Level.String(),
+		Methods:   created.Session.Methods,
+		Groups:    groups.Names(),
+		CSRFToken: created.CSRFToken,
+		ExpiresAt: created.Session.ExpiresAt,
+	}
+	gc.JSON(http.StatusCreated, response)
+	return nil
+}
+
+// Log out
+type (
+	SessionDeleteHandler struct {
+		txm               port.TransactionManager
+		sessionInteractor interactor.SessionInteractor
+		cookie            SessionCookie
+	}
+)
+
+func NewSessionDeleteHandler(
+	txm port.TransactionManager,
+	sessionInteractor interactor.SessionInteractor,
+	cookie SessionCookie,
+) *SessionDeleteHandler {
+	return &SessionDeleteHandler{
+		txm:               txm,
+		sessionInteractor: sessionInteractor,
+		cookie:            cookie,
+	}
+}
+
+func (h *SessionDeleteHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	session, ok := GetSession(gc)
+	if !ok {
+		gc.Error(ErrInvalidSession).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.sessionInteractor.Delete(ctx, session.ID)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	h.cookie.Clear(gc)
+	gc.Status(http.StatusNoContent)
+}
+
+// List sessions
+type (
+	SessionListRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	SessionListResponseSession struct {
+		ID         string    `json:"id"`
+		Device     string    `json:"device"`
+		UserAgent  string    `json:"user_agent"`
+		IPAddress  string    `json:"ip_address"`
+		Current    bool      `json:"current"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastSeenAt time.Time `json:"last_seen_at"`
+	}
+	SessionListResponse struct {
+		Sessions []*SessionListResponseSession `json:"sessions"`
+	}
+	SessionListHandler struct {
+		txm               port.TransactionManager
+		sessionInteractor interactor.SessionInteractor
+	}
+)
+
+func NewSessionListHandler(
+	txm port.TransactionManager,
+	sessionInteractor interactor.SessionInteractor,
+) *SessionListHandler {
+	return &SessionListHandler{
+		txm:               txm,
+		sessionInteractor: sessionInteractor,
+	}
+}
+
+func (h *SessionListHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(SessionListRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var sessions entity.Sessions
+	sessions, err = h.sessionInteractor.List(ctx, entity.ID(request.ID))
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	current, _ := GetSession(gc)
+	response := SessionListResponse{
+		Sessions: []*SessionListResponseSession{},
+	}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, &SessionListResponseSession{
+			ID:         session.ID.String(),
+			Device:     describeDevice(session.UserAgent),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Current:    current != nil && current.ID == session.ID,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+		})
+	}
+	gc.JSON(http.StatusOK, response)
+}
+
+// Revoke session
+type (
+	SessionRevokeRequest struct {
+		ID        string `json:"id" uri:"id" binding:"required"`
+		SessionID string `json:"sid" uri:"sid" binding:"required"`
+	}
+	SessionRevokeHandler struct {
+		txm               port.TransactionManager
+		sessionInteractor interactor.SessionInteractor
+		cookie            SessionCookie
+	}
+)
+
+func NewSessionRevokeHandler(
+	txm port.TransactionManager,
+	sessionInteractor interactor.SessionInteractor,
+	cookie SessionCookie,
+) *SessionRevokeHandler {
+	return &SessionRevokeHandler{
+		txm:               txm,
+		sessionInteractor: sessionInteractor,
+		cookie:            cookie,
+	}
+}
+
+func (h *SessionRevokeHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(SessionRevokeRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.sessionInteractor.Revoke(ctx, interactor.RevokeSessionInput{
+		UserID: entity.ID(request.ID),
+		ID:     entity.ID(request.SessionID),
+	})
+	if err != nil {
+		setSessionErrorType(gc.Error(err), err)
+		return
+	}
+
+	if current, ok := GetSession(gc); ok && current.ID.String() == request.SessionID {
+		h.cookie.Clear(gc)
+	}
+	gc.Status(http.StatusNoContent)
+}
+
+// Log out everywhere else
+type (
+	SessionRevokeOthersRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	SessionRevokeOthersHandler struct {
+		txm               port.TransactionManager
+		sessionInteractor interactor.SessionInteractor
+	}
+)
+
+func NewSessionRevokeOthersHandler(
+	txm port.TransactionManager,
+	sessionInteractor interactor.SessionInteractor,
+) *SessionRevokeOthersHandler {
+	return &SessionRevokeOthersHandler{
+		txm:               txm,
+		sessionInteractor: sessionInteractor,
+	}
+}
+
+func (h *SessionRevokeOthersHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(SessionRevokeOthersRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	// Requests authenticated by credentials have no session to keep.
+	input := interactor.RevokeOtherSessionsInput{
+		UserID: entity.ID(request.ID),
+	}
+	if current, ok := GetSession(gc); ok && current.UserID == input.UserID {
+		input.CurrentID = &current.ID
+	}
+	err = h.sessionInteractor.RevokeOthers(ctx, input)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// describeDevice names the browser and OS of a user agent, such as "Firefox
+// on Windows", or returns an empty string for unknown agents.
+func describeDevice(userAgent string) string {
+	var browser, os string
+	for _, b := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	} {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+	for _, o := range []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"CrOS", "ChromeOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, o.token) {
+			os = o.name
+			break
+		}
+	}
+	switch {
+	case len(browser) > 0 && len(os) > 0:
+		return browser + " on " + os
+	case len(browser) > 0:
+		return browser
+	default:
+		return os
+	}
+}
+
+func truncate(v string, length int) string {
+	if len(v) <= length {
+		return v
+	}
+	return strings.ToValidUTF8(v[:length], "")
+}
+
+func setSessionErrorType(gErr *gin.Error, err error) {
+	if IsAuthError(err) ||
+		errors.Is(err, usecase.ErrInvalidToken) ||
+		errors.Is(err, usecase.ErrPermissionDenied) ||
+		errors.Is(err, usecase.ErrNotFoundEntity) {
+		gErr.SetType(gin.ErrorTypePublic)
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// Enroll TOTP
+type (
+	TOTPCreateRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	TOTPCreateResponse struct {
+		Secret string `json:"secret"`
+		URI    string `json:"uri"`
+		// QRCode is a base64 encoded PNG of URI.
+		QRCode []byte `json:"qr_code"`
+	}
+	TOTPCreateHandler struct {
+		txm            port.TransactionManager
+		totpInteractor interactor.TOTPInteractor
+	}
+)
+
+func NewTOTPCreateHandler(
+	txm port.TransactionManager,
+	totpInteractor interactor.TOTPInteractor,
+) *TOTPCreateHandler {
+	return &TOTPCreateHandler{
+		txm:            txm,
+		totpInteractor: totpInteractor,
+	}
+}
+
+func (h *TOTPCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(TOTPCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var output *interactor.EnrollTOTPOutput
+	output, err = h.totpInteractor.Enroll(ctx, interactor.EnrollTOTPInput{
+		UserID: entity.ID(request.ID),
+	})
+	if err != nil {
+		setTOTPErrorType(gc.Error(err), err)
+		return
+	}
+
+	response := TOTPCreateResponse{
+		Secret: output.Secret,
+		URI:    output.URI,
+		QRCode: output.QRCode,
+	}
+	gc.JSON(http.StatusCreated, response)
+}
+
+// Confirm TOTP
+type (
+	TOTPUpdateRequest struct {
+		ID   string `json:"id" uri:"id" binding:"required"`
+		Code string `json:"code" form:"code" binding:"required"`
+	}
+	TOTPUpdateResponse struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	TOTPUpdateHandler struct {
+		txm            port.TransactionManager
+		totpInteractor interactor.TOTPInteractor
+	}
+)
+
+func NewTOTPUpdateHandler(
+	txm port.TransactionManager,
+	totpInteractor interactor.TOTPInteractor,
+) *TOTPUpdateHandler {
+	return &TOTPUpdateHandler{
+		txm:            txm,
+		totpInteractor: totpInteractor,
+	}
+}
+
+func (h *TOTPUpdateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(TOTPUpdateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var codes []string
+	codes, err = h.totpInteractor.Confirm(ctx, interactor.ConfirmTOTPInput{
+		UserID: entity.ID(request.ID),
+		Code:   request.Code,
+	})
+	if err != nil {
+		setTOTPErrorType(gc.Error(err), err)
+		return
+	}
+
+	response := TOTPUpdateResponse{
+		RecoveryCodes: codes,
+	}
+	gc.JSON(http.StatusOK, response)
+}
+
+// Disable TOTP
+type (
+	TOTPDeleteRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	TOTPDeleteHandler struct {
+		txm            port.TransactionManager
+		totpInteractor interactor.TOTPInteractor
+	}
+)
+
+func NewTOTPDeleteHandler(
+	txm port.TransactionManager,
+	totpInteractor interactor.TOTPInteractor,
+) *TOTPDeleteHandler {
+	return &TOTPDeleteHandler{
+		txm:            txm,
+		totpInteractor: totpInteractor,
+	}
+}
+
+func (h *TOTPDeleteHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(TOTPDeleteRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	err = h.totpInteractor.Disable(ctx, interactor.DisableTOTPInput{
+		UserID: entity.ID(request.ID),
+	})
+	if err != nil {
+		setTOTPErrorType(gc.Error(err), err)
+		return
+	}
+
+	gc.Status(http.StatusNoContent)
+}
+
+// Regenerate recovery codes
+type (
+	RecoveryCodeCreateRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	RecoveryCodeCreateResponse struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+	RecoveryCodeCreateHandler struct {
+		txm            port.TransactionManager
+		totpInteractor interactor.TOTPInteractor
+	}
+)
+
+func NewRecoveryCodeCreateHandler(
+	txm port.TransactionManager,
+	totpInteractor interactor.TOTPInteractor,
+) *RecoveryCodeCreateHandler {
+	return &RecoveryCodeCreateHandler{
+		txm:            txm,
+		totpInteractor: totpInteractor,
+	}
+}
+
+func (h *RecoveryCodeCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(RecoveryCodeCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var codes []string
+	codes, err = h.totpInteractor.RegenerateRecoveryCodes(ctx, interactor.RegenerateRecoveryCodesInput{
+		UserID: entity.ID(request.ID),
+	})
+	if err != nil {
+		setTOTPErrorType(gc.Error(err), err)
+		return
+	}
+
+	response := RecoveryCodeCreateResponse{
+		RecoveryCodes: codes,
+	}
+	gc.JSON(http.StatusCreated, response)
+}
+
+func setTOTPErrorType(gErr *gin.Error, err error) {
+	if IsAuthError(err) ||
+		errors.Is(err, usecase.ErrPermissionDenied) ||
+		errors.Is(err, usecase.ErrNotFoundEntity) ||
+		errors.Is(err, usecase.ErrAlreadyExistsEntity) {
+		gErr.SetType(gin.ErrorTypePublic)
+	}
+}
@@ -11,6 +11,10 @@ import (
 	"github.com/mkaiho/go-auth-api/usecase/port"
 )
 
+var userCreateAccepted = UserCreateAcceptedResponse{
+	Message: "check your email to continue",
+}
+
 // Create user
 type (
 	UserCreateRequest struct {
@@ -18,26 +22,32 @@ type (
 		Email string `json:"email" form:"email" binding:"required"`
 	}
 	UserCreateResponse struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Email string `json:"email"`
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	// UserCreateAcceptedResponse answers sign-ups when existing emails are
+	// concealed, the same for new and existing ones.
+	UserCreateAcceptedResponse struct {
+		Message string `json:"message"`
 	}
 	UserCreateHandler struct {
-		txm             port.TransactionManager
-		passwordManager port.PasswordManager
-		userInteractor  interactor.UserInteractor
+		txm                   port.TransactionManager
+		userInteractor        interactor.UserInteractor
+		concealExistingEmails bool
 	}
 )
 
 func NewUserCreateHandler(
 	txm port.TransactionManager,
-	passwordManager port.PasswordManager,
 	userInteractor interactor.UserInteractor,
+	concealExistingEmails bool,
 ) *UserCreateHandler {
 	return &UserCreateHandler{
-		txm:             txm,
-		passwordManager: passwordManager,
-		userInteractor:  userInteractor,
+		txm:                   txm,
+		userInteractor:        userInteractor,
+		concealExistingEmails: concealExistingEmails,
 	}
 }
 
@@ -49,16 +59,25 @@ func (h *UserCreateHandler) Handle(gc *gin.Context) {
 		gc.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
-	auth, err := GetAuthInfo(gc)
+	email, err := entity.ParseEmail(request.Email)
 	if err != nil {
-		gc.Error(err)
+		gc.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
-	password, err := h.passwordManager.Hash(ctx, auth.Password)
-	if err != nil {
+	// Requests without credentials create accounts that log in by email.
+	var password entity.Password
+	auth, err := GetAuthInfo(gc)
+	if err != nil && !errors.Is(err, ErrNoAuthValue) {
 		gc.Error(err)
 		return
 	}
+	if auth != nil {
+		password, err = entity.ParsePassword(auth.Password)
+		if err != nil {
+			gc.Error(err).SetType(gin.ErrorTypeBind)
+			return
+		}
+	}
 
 	ctx, err = h.txm.BeginContext(ctx)
 	if err != nil {
@@ -80,21 +99,35 @@ func (h *UserCreateHandler) Handle(gc *gin.Context) {
 	var user *entity.User
 	user, err = h.userInteractor.CreateUser(ctx, interactor.CreateUserInput{
 		Name:     request.Name,
-		Email:    entity.Email(request.Email),
+		Email:    email,
 		Password: password,
 	})
 	if err != nil {
+		// The owner has been mailed, and nothing else changed.
+		if h.concealExistingEmails && errors.Is(err, usecase.ErrAlreadyExistsEntity) {
+			err = nil
+			gc.JSON(http.StatusAccepted, userCreateAccepted)
+			return
+		}
 		gErr := gc.Error(err)
-		if errors.Is(err, usecase.ErrAlreadyExistsEntity) {
+		var policyErr *entity.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			gErr.SetType(gin.ErrorTypeBind)
+		} else if errors.Is(err, usecase.ErrAlreadyExistsEntity) {
 			gErr.SetType(gin.ErrorTypePublic)
 		}
 		return
 	}
+	if h.concealExistingEmails {
+		gc.JSON(http.StatusAccepted, userCreateAccepted)
+		return
+	}
 
 	response := UserCreateResponse{
-		ID:    user.ID.String(),
-		Name:  user.Name,
-		Email: user.Email.String(),
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Email:         user.Email.String(),
+		EmailVerified: user.EmailVerified,
 	}
 	gc.JSON(http.StatusCreated, response)
 }
@@ -105,9 +138,10 @@ type (
 		Email *string `json:"email" form:"email"`
 	}
 	UserFindResponseUser struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Email string `json:"email"`
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
 	}
 	UserFindResponse struct {
 		Users []*UserFindResponseUser `json:"users"`
@@ -136,6 +170,16 @@ func (h *UserFindHandler) Handle(gc *gin.Context) {
 		gc.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
+	var email *entity.Email
+	if request.Email != nil {
+		var parsed entity.Email
+		parsed, err = entity.ParseEmail(*request.Email)
+		if err != nil {
+			gc.Error(err).SetType(gin.ErrorTypeBind)
+			return
+		}
+		email = &parsed
+	}
 
 	ctx, err = h.txm.BeginContext(ctx)
 	if err != nil {
@@ -155,7 +199,7 @@ func (h *UserFindHandler) Handle(gc *gin.Context) {
 	}()
 
 	users, err := h.userInteractor.FindUsers(ctx, interactor.FindUserInput{
-		Email: (*entity.Email)(request.Email),
+		Email: email,
 	})
 	if err != nil {
 		gc.Error(err)
@@ -165,9 +209,10 @@ func (h *UserFindHandler) Handle(gc *gin.Context) {
 	var response UserFindResponse
 	for _, user := range users {
 		response.Users = append(response.Users, &UserFindResponseUser{
-			ID:    user.ID.String(),
-			Name:  user.Name,
-			Email: user.Email.String(),
+			ID:            user.ID.String(),
+			Name:          user.Name,
+			Email:         user.Email.String(),
+			EmailVerified: user.EmailVerified,
 		})
 	}
 
@@ -180,9 +225,10 @@ type (
 		ID string `json:"id" uri:"id" binding:"required"`
 	}
 	UserGetResponse struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Email string `json:"email"`
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
 	}
 	UserGetHandler struct {
 		txm            port.TransactionManager
@@ -239,9 +285,10 @@ func (h *UserGetHandler) Handle(gc *gin.Context) {
 	}
 
 	response := UserGetResponse{
-		ID:    user.ID.String(),
-		Name:  user.Name,
-		Email: user.Email.String(),
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Email:         user.Email.String(),
+		EmailVerified: user.EmailVerified,
 	}
 	gc.JSON(http.StatusOK, response)
 
@@ -255,9 +302,10 @@ type (
 		Email string `json:"email" form:"email" binding:"required"`
 	}
 	UserUpdateResponse struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Email string `json:"email"`
+		ID            string `json:"id"`
+		Name          string `json:"name"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
 	}
 	UserUpdateHandler struct {
 		txm            port.TransactionManager
@@ -283,6 +331,11 @@ func (h *UserUpdateHandler) Handle(gc *gin.Context) {
 		gc.Error(err).SetType(gin.ErrorTypeBind)
 		return
 	}
+	email, err := entity.ParseEmail(request.Email)
+	if err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
 
 	ctx, err = h.txm.BeginContext(ctx)
 	if err != nil {
@@ -305,20 +358,21 @@ func (h *UserUpdateHandler) Handle(gc *gin.Context) {
 	user, err = h.userInteractor.UpdateUser(ctx, interactor.UpdateUserInput{
 		ID:    entity.ID(request.ID),
 		Name:  request.Name,
-		Email: entity.Email(request.Email),
+		Email: email,
 	})
 	if err != nil {
 		gErr := gc.Error(err)
-		if errors.Is(err, usecase.ErrNotFoundEntity) {
+		if errors.Is(err, usecase.ErrNotFoundEntity) || errors.Is(err, usecase.ErrAlreadyExistsEntity) {
 			gErr.SetType(gin.ErrorTypePublic)
 		}
 		return
 	}
 
 	response := UserUpdateResponse{
-		ID:    user.ID.String(),
-		Name:  user.Name,
-		Email: user.Email.String(),
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Email:         user.Email.String(),
+		EmailVerified: user.EmailVerified,
 	}
 	gc.JSON(http.StatusOK, response)
 
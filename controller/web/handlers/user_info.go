@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// Get user info
+type (
+	// UserInfoGetResponse names its fields after the standard OpenID
+	// Connect claims, with groups as many identity providers report them.
+	UserInfoGetResponse struct {
+		Subject       string   `json:"sub"`
+		Name          string   `json:"name"`
+		Email         string   `json:"email"`
+		EmailVerified bool     `json:"email_verified"`
+		Groups        []string `json:"groups"`
+	}
+	UserInfoGetHandler struct {
+		txm             port.TransactionManager
+		groupInteractor interactor.GroupInteractor
+	}
+)
+
+func NewUserInfoGetHandler(
+	txm port.TransactionManager,
+	groupInteractor interactor.GroupInteractor,
+) *UserInfoGetHandler {
+	return &UserInfoGetHandler{
+		txm:             txm,
+		groupInteractor: groupInteractor,
+	}
+}
+
+func (h *UserInfoGetHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	auth, ok := GetAuthentication(gc)
+	if !ok {
+		gc.Error(ErrNoAuthValue).SetType(gin.ErrorTypePublic)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var groups entity.Groups
+	groups, err = h.groupInteractor.ListUserGroups(ctx, auth.User.ID)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+
+	gc.JSON(http.StatusOK, UserInfoGetResponse{
+		Subject:       auth.User.ID.String(),
+		Name:          auth.User.Name,
+		Email:         auth.User.Email.String(),
+		EmailVerified: auth.User.EmailVerified,
+		Groups:        groups.Names(),
+	})
+}
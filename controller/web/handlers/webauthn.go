@@ -0,0 +1,409 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+)
+
+// Base64URL is binary WebAuthn data, which browsers and the WebAuthn JSON
+// serialization encode as unpadded base64url.
+type Base64URL []byte
+
+func (b Base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *Base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// The options are laid out as PublicKeyCredentialCreationOptions and
+// PublicKeyCredentialRequestOptions, so that clients can pass them to the
+// WebAuthn API after decoding the binary fields.
+type (
+	WebAuthnRelyingPartyEntity struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	WebAuthnUserEntity struct {
+		ID          Base64URL `json:"id"`
+		Name        string    `json:"name"`
+		DisplayName string    `json:"displayName"`
+	}
+	WebAuthnCredentialParameter struct {
+		Type      string `json:"type"`
+		Algorithm int    `json:"alg"`
+	}
+	WebAuthnCredentialDescriptor struct {
+		Type string    `json:"type"`
+		ID   Base64URL `json:"id"`
+	}
+	WebAuthnAuthenticatorSelection struct {
+		ResidentKey string `json:"residentKey"`
+		// RequireResidentKey is ResidentKey for WebAuthn Level 1 clients.
+		RequireResidentKey bool   `json:"requireResidentKey"`
+		UserVerification   string `json:"userVerification"`
+	}
+	WebAuthnCreationOptions struct {
+		Challenge              Base64URL                      `json:"challenge"`
+		RelyingParty           WebAuthnRelyingPartyEntity     `json:"rp"`
+		User                   WebAuthnUserEntity             `json:"user"`
+		Parameters             []WebAuthnCredentialParameter  `json:"pubKeyCredParams"`
+		ExcludeCredentials     []WebAuthnCredentialDescriptor `json:"excludeCredentials"`
+		AuthenticatorSelection WebAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+	}
+	WebAuthnRequestOptions struct {
+		Challenge        Base64URL                      `json:"challenge"`
+		RelyingPartyID   string                         `json:"rpId"`
+		AllowCredentials []WebAuthnCredentialDescriptor `json:"allowCredentials"`
+		UserVerification string                         `json:"userVerification"`
+	}
+	// WebAuthnAssertionRequest is also the JSON form of the
+	// WebAuthnAssertionHeader value.
+	WebAuthnAssertionRequest struct {
+		ChallengeID       string    `json:"challenge_id" binding:"required"`
+		CredentialID      Base64URL `json:"credential_id" binding:"required"`
+		ClientDataJSON    Base64URL `json:"client_data_json" binding:"required"`
+		AuthenticatorData Base64URL `json:"authenticator_data" binding:"required"`
+		Signature         Base64URL `json:"signature" binding:"required"`
+	}
+)
+
+func (r *WebAuthnAssertionRequest) input() interactor.WebAuthnAssertionInput {
+	return interactor.WebAuthnAssertionInput{
+		ChallengeID:       entity.ID(r.ChallengeID),
+		CredentialID:      r.CredentialID,
+		ClientDataJSON:    r.ClientDataJSON,
+		AuthenticatorData: r.AuthenticatorData,
+		Signature:         r.Signature,
+	}
+}
+
+// Begin passkey registration
+type (
+	WebAuthnCredentialOptionsCreateRequest struct {
+		ID string `json:"id" uri:"id" binding:"required"`
+	}
+	WebAuthnCredentialOptionsCreateResponse struct {
+		ChallengeID string                  `json:"challenge_id"`
+		PublicKey   WebAuthnCreationOptions `json:"public_key"`
+	}
+	WebAuthnCredentialOptionsCreateHandler struct {
+		txm                port.TransactionManager
+		webAuthnInteractor interactor.WebAuthnInteractor
+	}
+)
+
+func NewWebAuthnCredentialOptionsCreateHandler(
+	txm port.TransactionManager,
+	webAuthnInteractor interactor.WebAuthnInteractor,
+) *WebAuthnCredentialOptionsCreateHandler {
+	return &WebAuthnCredentialOptionsCreateHandler{
+		txm:                txm,
+		webAuthnInteractor: webAuthnInteractor,
+	}
+}
+
+func (h *WebAuthnCredentialOptionsCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(WebAuthnCredentialOptionsCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var output *interactor.WebAuthnRegistrationOptions
+	output, err = h.webAuthnInteractor.BeginRegistration(ctx, interactor.BeginWebAuthnRegistrationInput{
+		UserID: entity.ID(request.ID),
+	})
+	if err != nil {
+		setWebAuthnErrorType(gc.Error(err), err)
+		return
+	}
+
+	parameters := make([]WebAuthnCredentialParameter, 0, len(output.RelyingParty.Algorithms))
+	for _, alg := range output.RelyingParty.Algorithms {
+		parameters = append(parameters, WebAuthnCredentialParameter{
+			Type:      "public-key",
+			Algorithm: alg,
+		})
+	}
+	response := WebAuthnCredentialOptionsCreateResponse{
+		ChallengeID: output.ChallengeID.String(),
+		PublicKey: WebAuthnCreationOptions{
+			Challenge: output.Challenge,
+			RelyingParty: WebAuthnRelyingPartyEntity{
+				ID:   output.RelyingParty.ID,
+				Name: output.RelyingParty.Name,
+			},
+			User: WebAuthnUserEntity{
+				ID:          Base64URL(output.User.ID),
+				Name:        output.User.Email.String(),
+				DisplayName: output.User.Name,
+			},
+			Parameters:         parameters,
+			ExcludeCredentials: newWebAuthnCredentialDescriptors(output.ExcludeCredentials),
+			// Logins list no credentials, so only discoverable ones can be
+			// used.
+			AuthenticatorSelection: WebAuthnAuthenticatorSelection{
+				ResidentKey:        "required",
+				RequireResidentKey: true,
+				UserVerification:   "preferred",
+			},
+		},
+	}
+	gc.JSON(http.StatusCreated, response)
+}
+
+// Finish passkey registration
+type (
+	WebAuthnCredentialCreateRequest struct {
+		ID                string    `json:"id" uri:"id" binding:"required"`
+		ChallengeID       string    `json:"challenge_id" binding:"required"`
+		ClientDataJSON    Base64URL `json:"client_data_json" binding:"required"`
+		AttestationObject Base64URL `json:"attestation_object" binding:"required"`
+	}
+	WebAuthnCredentialCreateResponse struct {
+		ID           string    `json:"id"`
+		CredentialID Base64URL `json:"credential_id"`
+	}
+	WebAuthnCredentialCreateHandler struct {
+		txm                port.TransactionManager
+		webAuthnInteractor interactor.WebAuthnInteractor
+	}
+)
+
+func NewWebAuthnCredentialCreateHandler(
+	txm port.TransactionManager,
+	webAuthnInteractor interactor.WebAuthnInteractor,
+) *WebAuthnCredentialCreateHandler {
+	return &WebAuthnCredentialCreateHandler{
+		txm:                txm,
+		webAuthnInteractor: webAuthnInteractor,
+	}
+}
+
+func (h *WebAuthnCredentialCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(WebAuthnCredentialCreateRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var credential *entity.WebAuthnCredential
+	credential, err = h.webAuthnInteractor.FinishRegistration(ctx, interactor.FinishWebAuthnRegistrationInput{
+		UserID:            entity.ID(request.ID),
+		ChallengeID:       entity.ID(request.ChallengeID),
+		ClientDataJSON:    request.ClientDataJSON,
+		AttestationObject: request.AttestationObject,
+	})
+	if err != nil {
+		setWebAuthnErrorType(gc.Error(err), err)
+		return
+	}
+
+	response := WebAuthnCredentialCreateResponse{
+		ID:           credential.ID.String(),
+		CredentialID: credential.CredentialID,
+	}
+	gc.JSON(http.StatusCreated, response)
+}
+
+// Begin passkey login
+type (
+	WebAuthnAssertionOptionsCreateResponse struct {
+		ChallengeID string                 `json:"challenge_id"`
+		PublicKey   WebAuthnRequestOptions `json:"public_key"`
+	}
+	WebAuthnAssertionOptionsCreateHandler struct {
+		txm                port.TransactionManager
+		webAuthnInteractor interactor.WebAuthnInteractor
+	}
+)
+
+func NewWebAuthnAssertionOptionsCreateHandler(
+	txm port.TransactionManager,
+	webAuthnInteractor interactor.WebAuthnInteractor,
+) *WebAuthnAssertionOptionsCreateHandler {
+	return &WebAuthnAssertionOptionsCreateHandler{
+		txm:                txm,
+		webAuthnInteractor: webAuthnInteractor,
+	}
+}
+
+func (h *WebAuthnAssertionOptionsCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var output *interactor.WebAuthnLoginOptions
+	output, err = h.webAuthnInteractor.BeginLogin(ctx)
+	if err != nil {
+		setWebAuthnErrorType(gc.Error(err), err)
+		return
+	}
+
+	response := WebAuthnAssertionOptionsCreateResponse{
+		ChallengeID: output.ChallengeID.String(),
+		PublicKey: WebAuthnRequestOptions{
+			Challenge:        output.Challenge,
+			RelyingPartyID:   output.RelyingParty.ID,
+			AllowCredentials: []WebAuthnCredentialDescriptor{},
+			UserVerification: "preferred",
+		},
+	}
+	gc.JSON(http.StatusCreated, response)
+}
+
+// Finish passkey login
+type (
+	WebAuthnAssertionCreateHandler struct {
+		txm                port.TransactionManager
+		webAuthnInteractor interactor.WebAuthnInteractor
+		sessionInteractor  interactor.SessionInteractor
+		groupInteractor    interactor.GroupInteractor
+		cookie             SessionCookie
+	}
+)
+
+func NewWebAuthnAssertionCreateHandler(
+	txm port.TransactionManager,
+	webAuthnInteractor interactor.WebAuthnInteractor,
+	sessionInteractor interactor.SessionInteractor,
+	groupInteractor interactor.GroupInteractor,
+	cookie SessionCookie,
+) *WebAuthnAssertionCreateHandler {
+	return &WebAuthnAssertionCreateHandler{
+		txm:                txm,
+		webAuthnInteractor: webAuthnInteractor,
+		sessionInteractor:  sessionInteractor,
+		groupInteractor:    groupInteractor,
+		cookie:             cookie,
+	}
+}
+
+func (h *WebAuthnAssertionCreateHandler) Handle(gc *gin.Context) {
+	var err error
+	ctx := gc.Request.Context()
+	request := new(WebAuthnAssertionRequest)
+	if err = ShouldBind(gc, request); err != nil {
+		gc.Error(err).SetType(gin.ErrorTypeBind)
+		return
+	}
+
+	ctx, err = h.txm.BeginContext(ctx)
+	if err != nil {
+		gc.Error(err)
+		return
+	}
+	defer func() {
+		if err != nil {
+			if rErr := h.txm.Rollback(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		} else {
+			if rErr := h.txm.End(ctx); rErr != nil {
+				gc.Error(rErr)
+			}
+		}
+	}()
+
+	var authentication *entity.Authentication
+	authentication, err = h.webAuthnInteractor.FinishLogin(ctx, request.input())
+	if err != nil {
+		setWebAuthnErrorType(gc.Error(err), err)
+		return
+	}
+	err = startSession(ctx, gc, h.sessionInteractor, h.groupInteractor, h.cookie, authentication)
+}
+
+func newWebAuthnCredentialDescriptors(ids [][]byte) []WebAuthnCredentialDescriptor {
+	descriptors := make([]WebAuthnCredentialDescriptor, 0, len(ids))
+	for _, id := range ids {
+		descriptors = append(descriptors, WebAuthnCredentialDescriptor{
+			Type: "public-key",
+			ID:   id,
+		})
+	}
+	return descriptors
+}
+
+func setWebAuthnErrorType(gErr *gin.Error, err error) {
+	if IsAuthError(err) ||
+		errors.Is(err, usecase.ErrInvalidToken) ||
+		errors.Is(err, usecase.ErrPermissionDenied) ||
+		errors.Is(err, usecase.ErrNotFoundEntity) ||
+		errors.Is(err, usecase.ErrAlreadyExistsEntity) {
+		gErr.SetType(gin.ErrorTypePublic)
+	}
+}
@@ -1,49 +1,175 @@
 package middlewares
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/mkaiho/go-auth-api/controller/web/handlers"
 	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
 	"github.com/mkaiho/go-auth-api/usecase/port"
 	"github.com/mkaiho/go-auth-api/util"
 )
 
-func CheckAuth(txm port.TransactionManager, credGateway port.UserCredentialGateway) handlers.Handler {
+// CheckAuth authenticates requests by the credentials in the Authorization
+// header or, without one, by the session cookie. Requests authenticated by
+// the cookie are checked against CSRF too. The user's current roles are
+// recorded along with the authentication.
+func CheckAuth(
+	txm port.TransactionManager,
+	authInteractor interactor.AuthInteractor,
+	sessionInteractor interactor.SessionInteractor,
+	roleInteractor interactor.RoleInteractor,
+	cookie handlers.SessionCookie,
+	csrf CSRFPolicy,
+) handlers.Handler {
 	return func(gc *gin.Context) {
 		var err error
-		var auth *handlers.Auth
 		ctx := gc.Request.Context()
 		logger := util.GLogger()
 		defer func() {
 			if err != nil {
 				logger.Error(err, "failed to check auth")
 				gErr := gc.Error(err)
-				if handlers.IsAuthError(err) {
+				if handlers.IsAuthError(err) || errors.Is(err, usecase.ErrPermissionDenied) {
 					gErr.SetType(gin.ErrorTypePublic)
 				}
 				gc.Abort()
 			}
 		}()
 		ctx, err = txm.BeginContext(ctx)
-		defer txm.Rollback(ctx)
 		if err != nil {
 			return
 		}
-		auth, err = handlers.GetAuthInfo(gc)
-		if err != nil {
-			return
+		defer func() {
+			// Failed logins are committed too, so that they count towards
+			// lockout.
+			if err != nil && !interactor.ShouldCommitLogin(err) {
+				txm.Rollback(ctx)
+				return
+			}
+			// Authentication may upgrade the stored password hash, and
+			// sessions record when they were last used.
+			if eErr := txm.End(ctx); eErr != nil {
+				err = eErr
+			}
+		}()
+		if token, ok := cookie.Token(gc); ok && len(gc.GetHeader("Authorization")) == 0 {
+			err = checkSession(ctx, gc, sessionInteractor, cookie, csrf, token)
+		} else {
+			err = checkCredentials(ctx, gc, authInteractor)
 		}
-		email, err := entity.ParseEmail(auth.User)
 		if err != nil {
 			return
 		}
-		password, err := entity.ParsePassword(auth.Password)
-		if err != nil {
+		err = setRoles(ctx, gc, roleInteractor)
+	}
+}
+
+// setRoles looks roles up on every request, so that changes apply at once
+// even to sessions.
+func setRoles(ctx context.Context, gc *gin.Context, roleInteractor interactor.RoleInteractor) error {
+	auth, ok := handlers.GetAuthentication(gc)
+	if !ok {
+		return handlers.ErrNoAuthValue
+	}
+	roles, err := roleInteractor.ListUserRoles(ctx, interactor.ListUserRolesInput{
+		UserID: auth.User.ID,
+	})
+	if err != nil {
+		return err
+	}
+	auth.Roles = roles
+	return nil
+}
+
+func checkCredentials(ctx context.Context, gc *gin.Context, authInteractor interactor.AuthInteractor) error {
+	auth, err := handlers.GetAuthInfo(gc)
+	if err != nil {
+		return err
+	}
+	email, err := entity.ParseEmail(auth.User)
+	if err != nil {
+		return err
+	}
+	password, err := entity.ParsePassword(auth.Password)
+	if err != nil {
+		return err
+	}
+	assertion, err := handlers.GetWebAuthnAssertion(gc)
+	if err != nil {
+		return err
+	}
+	authentication, err := authInteractor.Authenticate(ctx, interactor.AuthenticateInput{
+		Email:             email,
+		Password:          password,
+		SecondFactor:      handlers.GetSecondFactor(gc),
+		WebAuthnAssertion: assertion,
+		PerRequest:        true,
+	})
+	if err != nil {
+		return err
+	}
+	handlers.SetAuthentication(gc, authentication)
+	return nil
+}
+
+func checkSession(
+	ctx context.Context,
+	gc *gin.Context,
+	sessionInteractor interactor.SessionInteractor,
+	cookie handlers.SessionCookie,
+	csrf CSRFPolicy,
+	token string,
+) error {
+	authenticated, err := sessionInteractor.Authenticate(ctx, token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			// The client keeps sending the cookie until it is told to
+			// drop it.
+			cookie.Clear(gc)
+			return fmt.Errorf("%w: %v", handlers.ErrInvalidSession, err)
+		}
+		return err
+	}
+	if err := checkCSRF(ctx, gc, sessionInteractor, csrf, authenticated.Session); err != nil {
+		return err
+	}
+	handlers.SetSession(gc, authenticated.Session)
+	handlers.SetAuthentication(gc, authenticated.Authentication)
+	return nil
+}
+
+// AuthRequirement is the authentication level and recency a route needs.
+// A zero MaxAge accepts authentications of any age.
+type AuthRequirement struct {
+	Level  entity.AuthLevel
+	MaxAge time.Duration
+}
+
+// RequireAuth checks the authentication recorded by CheckAuth, and answers
+// with a step-up challenge when it falls short of requirement.
+func RequireAuth(requirement AuthRequirement) handlers.Handler {
+	return func(gc *gin.Context) {
+		auth, ok := handlers.GetAuthentication(gc)
+		if !ok {
+			gc.Error(handlers.ErrNoAuthValue).SetType(gin.ErrorTypePublic)
+			gc.Abort()
 			return
 		}
-		err = credGateway.Check(ctx, email, password)
-		if err != nil {
+		if auth.Satisfies(requirement.Level, requirement.MaxAge, time.Now()) {
 			return
 		}
+		err := &handlers.StepUpError{
+			Level:  requirement.Level,
+			MaxAge: requirement.MaxAge,
+		}
+		gc.Header("WWW-Authenticate", err.Challenge())
+		gc.Error(err).SetType(gin.ErrorTypePublic)
+		gc.Abort()
 	}
 }
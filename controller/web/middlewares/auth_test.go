@@ -0,0 +1,108 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAuth(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	tests := []struct {
+		name           string
+		requirement    AuthRequirement
+		authentication *entity.Authentication
+		wantErr        error
+		wantChallenge  string
+	}{
+		{
+			name:        "let mfa authentication through",
+			requirement: AuthRequirement{Level: entity.AuthLevelMFA},
+			authentication: &entity.Authentication{
+				User:           user,
+				Level:          entity.AuthLevelMFA,
+				AuthTime:       time.Now().Add(-time.Hour),
+				AvailableLevel: entity.AuthLevelMFA,
+			},
+		},
+		{
+			name:        "let password authentication through when user can not step up",
+			requirement: AuthRequirement{Level: entity.AuthLevelMFA},
+			authentication: &entity.Authentication{
+				User:           user,
+				Level:          entity.AuthLevelPassword,
+				AuthTime:       time.Now(),
+				AvailableLevel: entity.AuthLevelPassword,
+			},
+		},
+		{
+			name:        "let recent authentication through",
+			requirement: AuthRequirement{Level: entity.AuthLevelPassword, MaxAge: 5 * time.Minute},
+			authentication: &entity.Authentication{
+				User:     user,
+				Level:    entity.AuthLevelPassword,
+				AuthTime: time.Now().Add(-time.Minute),
+			},
+		},
+		{
+			name:        "challenge password authentication when user can step up",
+			requirement: AuthRequirement{Level: entity.AuthLevelMFA},
+			authentication: &entity.Authentication{
+				User:           user,
+				Level:          entity.AuthLevelPassword,
+				AuthTime:       time.Now(),
+				AvailableLevel: entity.AuthLevelMFA,
+			},
+			wantErr:       usecase.ErrInsufficientAuthentication,
+			wantChallenge: `Basic realm="go-auth-api", error="insufficient_user_authentication", acr_values="mfa"`,
+		},
+		{
+			name:        "challenge old authentication with max age",
+			requirement: AuthRequirement{Level: entity.AuthLevelMFA, MaxAge: 5 * time.Minute},
+			authentication: &entity.Authentication{
+				User:           user,
+				Level:          entity.AuthLevelMFA,
+				AuthTime:       time.Now().Add(-10 * time.Minute),
+				AvailableLevel: entity.AuthLevelMFA,
+			},
+			wantErr:       usecase.ErrInsufficientAuthentication,
+			wantChallenge: `Basic realm="go-auth-api", error="insufficient_user_authentication", acr_values="mfa", max_age=300`,
+		},
+		{
+			name:        "refuse unauthenticated request",
+			requirement: AuthRequirement{Level: entity.AuthLevelPassword},
+			wantErr:     handlers.ErrNoAuthValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			gc, _ := gin.CreateTestContext(w)
+			gc.Request = httptest.NewRequest(http.MethodPut, "/users/test_user_id_001/password", nil)
+			if tt.authentication != nil {
+				handlers.SetAuthentication(gc, tt.authentication)
+			}
+
+			RequireAuth(tt.requirement)(gc)
+			assert.Equal(t, tt.wantChallenge, w.Header().Get("WWW-Authenticate"))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, gc.Errors.Last().Err, tt.wantErr)
+				assert.True(t, gc.IsAborted())
+				return
+			}
+			assert.Empty(t, gc.Errors)
+			assert.False(t, gc.IsAborted())
+		})
+	}
+}
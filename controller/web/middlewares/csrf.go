@@ -0,0 +1,91 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+)
+
+var ErrInvalidCSRFToken = fmt.Errorf("%w: invalid csrf token", usecase.ErrPermissionDenied)
+var ErrUntrustedOrigin = fmt.Errorf("%w: untrusted origin", usecase.ErrPermissionDenied)
+
+// CSRFPolicy protects requests authenticated by the session cookie, which
+// browsers send along with requests of any site. Requests with credentials
+// in the Authorization header need no protection.
+type CSRFPolicy struct {
+	// TrustedOrigins are the origins of the web apps, such as
+	// "https://example.com". Without any, only the API's own host is
+	// trusted.
+	TrustedOrigins []string
+}
+
+// checkCSRF lets requests that change state through only when they come
+// from a trusted origin and echo the session's CSRF token.
+func checkCSRF(
+	ctx context.Context,
+	gc *gin.Context,
+	sessionInteractor interactor.SessionInteractor,
+	policy CSRFPolicy,
+	session *entity.Session,
+) error {
+	switch gc.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return nil
+	}
+	if err := policy.checkOrigin(gc); err != nil {
+		return err
+	}
+	token := strings.TrimSpace(gc.GetHeader(handlers.CSRFTokenHeader))
+	if len(token) == 0 {
+		return ErrInvalidCSRFToken
+	}
+	if err := sessionInteractor.VerifyCSRFToken(ctx, session.ID, token); err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) {
+			return ErrInvalidCSRFToken
+		}
+		return err
+	}
+	return nil
+}
+
+// checkOrigin compares the Origin header or, without one, the Referer.
+// Some clients send neither, which leaves the token to protect them.
+func (p CSRFPolicy) checkOrigin(gc *gin.Context) error {
+	origin := gc.GetHeader("Origin")
+	if len(origin) == 0 {
+		referer := gc.GetHeader("Referer")
+		if len(referer) == 0 {
+			return nil
+		}
+		u, err := url.Parse(referer)
+		if err != nil || len(u.Host) == 0 {
+			return ErrUntrustedOrigin
+		}
+		origin = u.Scheme + "://" + u.Host
+	}
+	// Sandboxed frames and redirects across origins send "null".
+	if origin == "null" {
+		return ErrUntrustedOrigin
+	}
+	for _, trusted := range p.TrustedOrigins {
+		if strings.EqualFold(strings.TrimRight(trusted, "/"), origin) {
+			return nil
+		}
+	}
+	if len(p.TrustedOrigins) == 0 {
+		u, err := url.Parse(origin)
+		if err == nil && strings.EqualFold(u.Host, gc.Request.Host) {
+			return nil
+		}
+	}
+	return ErrUntrustedOrigin
+}
@@ -0,0 +1,171 @@
+package middlewares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/entity"
+	interactormocks "github.com/mkaiho/go-auth-api/mocks/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_checkCSRF(t *testing.T) {
+	session := &entity.Session{
+		ID:     "test_session_id_001",
+		UserID: "test_user_id_001",
+	}
+	tests := []struct {
+		name      string
+		method    string
+		headers   map[string]string
+		policy    CSRFPolicy
+		verifyErr error
+		wantCheck bool
+		wantErr   error
+	}{
+		{
+			name:   "return nil for safe method without token",
+			method: http.MethodGet,
+			headers: map[string]string{
+				"Origin": "https://evil.example.com",
+			},
+		},
+		{
+			name:   "return nil when trusted origin sends valid token",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Origin":                 "https://app.example.com",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			policy:    CSRFPolicy{TrustedOrigins: []string{"https://app.example.com/"}},
+			wantCheck: true,
+		},
+		{
+			name:   "return nil when own host sends valid token without trusted origins",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Origin":                 "https://api.example.com",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			wantCheck: true,
+		},
+		{
+			name:   "return nil when trusted referer sends valid token without origin",
+			method: http.MethodPut,
+			headers: map[string]string{
+				"Referer":                "https://app.example.com/settings?tab=1",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			policy:    CSRFPolicy{TrustedOrigins: []string{"https://app.example.com"}},
+			wantCheck: true,
+		},
+		{
+			name:   "return nil when neither origin nor referer is sent with valid token",
+			method: http.MethodDelete,
+			headers: map[string]string{
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			policy:    CSRFPolicy{TrustedOrigins: []string{"https://app.example.com"}},
+			wantCheck: true,
+		},
+		{
+			name:   "return error for untrusted origin",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Origin":                 "https://evil.example.com",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			policy:  CSRFPolicy{TrustedOrigins: []string{"https://app.example.com"}},
+			wantErr: ErrUntrustedOrigin,
+		},
+		{
+			name:   "return error for other host without trusted origins",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Origin":                 "https://evil.example.com",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			wantErr: ErrUntrustedOrigin,
+		},
+		{
+			name:   "return error for null origin",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Origin":                 "null",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			policy:  CSRFPolicy{TrustedOrigins: []string{"https://app.example.com"}},
+			wantErr: ErrUntrustedOrigin,
+		},
+		{
+			name:   "return error for untrusted referer without origin",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Referer":                "https://evil.example.com/page",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			policy:  CSRFPolicy{TrustedOrigins: []string{"https://app.example.com"}},
+			wantErr: ErrUntrustedOrigin,
+		},
+		{
+			name:   "return error for referer without host",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Referer":                "/settings",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			wantErr: ErrUntrustedOrigin,
+		},
+		{
+			name:   "return error when token is missing",
+			method: http.MethodPost,
+			headers: map[string]string{
+				"Origin": "https://app.example.com",
+			},
+			policy:  CSRFPolicy{TrustedOrigins: []string{"https://app.example.com"}},
+			wantErr: ErrInvalidCSRFToken,
+		},
+		{
+			name:   "return error when token is wrong",
+			method: http.MethodPatch,
+			headers: map[string]string{
+				"Origin":                 "https://app.example.com",
+				handlers.CSRFTokenHeader: "test_csrf_token",
+			},
+			policy:    CSRFPolicy{TrustedOrigins: []string{"https://app.example.com"}},
+			verifyErr: usecase.ErrInvalidToken,
+			wantCheck: true,
+			wantErr:   ErrInvalidCSRFToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+			gc.Request = httptest.NewRequest(tt.method, "https://api.example.com/users/test_user_id_001", nil)
+			for k, v := range tt.headers {
+				gc.Request.Header.Set(k, v)
+			}
+			sessionInteractor := interactormocks.NewSessionInteractor(t)
+			if tt.wantCheck {
+				sessionInteractor.
+					On("VerifyCSRFToken", ctx, session.ID, "test_csrf_token").
+					Return(tt.verifyErr).
+					Times(1)
+			}
+
+			err := checkCSRF(ctx, gc, sessionInteractor, tt.policy, session)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.ErrorIs(t, err, usecase.ErrPermissionDenied)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
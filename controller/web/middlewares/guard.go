@@ -0,0 +1,45 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+)
+
+// RequirePermission lets requests through when one of the user's roles
+// grants permission.
+func RequirePermission(permission entity.Permission) handlers.Handler {
+	return guard(false, permission)
+}
+
+// RequireSelf lets users act only on themselves, as given by the route's
+// ":id" parameter.
+func RequireSelf() handlers.Handler {
+	return guard(true, "")
+}
+
+// RequireSelfOrPermission lets users act on themselves, and users granted
+// permission act on anyone.
+func RequireSelfOrPermission(permission entity.Permission) handlers.Handler {
+	return guard(true, permission)
+}
+
+func guard(self bool, permission entity.Permission) handlers.Handler {
+	return func(gc *gin.Context) {
+		auth, ok := handlers.GetAuthentication(gc)
+		if !ok {
+			gc.Error(handlers.ErrNoAuthValue).SetType(gin.ErrorTypePublic)
+			gc.Abort()
+			return
+		}
+		if self && gc.Param("id") == auth.User.ID.String() {
+			return
+		}
+		if len(permission) > 0 && auth.Roles.Grants(permission) {
+			return
+		}
+		gc.Error(usecase.ErrPermissionDenied).SetType(gin.ErrorTypePublic)
+		gc.Abort()
+	}
+}
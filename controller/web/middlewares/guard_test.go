@@ -0,0 +1,116 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_guard(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	admin := &entity.Role{
+		Name:        entity.RoleAdmin,
+		Permissions: []entity.Permission{entity.PermissionUsersRead, entity.PermissionUsersWrite},
+	}
+	reader := &entity.Role{
+		Name:        "reader",
+		Permissions: []entity.Permission{entity.PermissionUsersRead},
+	}
+	tests := []struct {
+		name    string
+		guard   handlers.Handler
+		id      string
+		roles   entity.Roles
+		noAuth  bool
+		wantErr error
+	}{
+		{
+			name:  "let user with permission through",
+			guard: RequirePermission(entity.PermissionUsersWrite),
+			id:    "test_user_id_002",
+			roles: entity.Roles{reader, admin},
+		},
+		{
+			name:    "refuse user without permission",
+			guard:   RequirePermission(entity.PermissionUsersWrite),
+			id:      "test_user_id_002",
+			roles:   entity.Roles{reader},
+			wantErr: usecase.ErrPermissionDenied,
+		},
+		{
+			name:    "refuse user acting on themselves without permission",
+			guard:   RequirePermission(entity.PermissionUsersWrite),
+			id:      user.ID.String(),
+			wantErr: usecase.ErrPermissionDenied,
+		},
+		{
+			name:  "let user act on themselves",
+			guard: RequireSelf(),
+			id:    user.ID.String(),
+		},
+		{
+			name:    "refuse user acting on others even with permission",
+			guard:   RequireSelf(),
+			id:      "test_user_id_002",
+			roles:   entity.Roles{admin},
+			wantErr: usecase.ErrPermissionDenied,
+		},
+		{
+			name:  "let user act on themselves without permission",
+			guard: RequireSelfOrPermission(entity.PermissionUsersWrite),
+			id:    user.ID.String(),
+		},
+		{
+			name:  "let user with permission act on others",
+			guard: RequireSelfOrPermission(entity.PermissionUsersWrite),
+			id:    "test_user_id_002",
+			roles: entity.Roles{admin},
+		},
+		{
+			name:    "refuse user acting on others without permission",
+			guard:   RequireSelfOrPermission(entity.PermissionUsersWrite),
+			id:      "test_user_id_002",
+			roles:   entity.Roles{reader},
+			wantErr: usecase.ErrPermissionDenied,
+		},
+		{
+			name:    "refuse unauthenticated request",
+			guard:   RequireSelfOrPermission(entity.PermissionUsersWrite),
+			id:      user.ID.String(),
+			noAuth:  true,
+			wantErr: handlers.ErrNoAuthValue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+			gc.Request = httptest.NewRequest(http.MethodPut, "/users/"+tt.id, nil)
+			gc.Params = gin.Params{{Key: "id", Value: tt.id}}
+			if !tt.noAuth {
+				handlers.SetAuthentication(gc, &entity.Authentication{
+					User:  user,
+					Roles: tt.roles,
+				})
+			}
+
+			tt.guard(gc)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, gc.Errors.Last().Err, tt.wantErr)
+				assert.True(t, gc.IsAborted())
+				return
+			}
+			assert.Empty(t, gc.Errors)
+			assert.False(t, gc.IsAborted())
+		})
+	}
+}
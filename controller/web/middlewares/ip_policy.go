@@ -0,0 +1,133 @@
+package middlewares
+
+import (
+	"bufio"
+	"fmt"
+	"net/netip"
+	"path"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+var ErrIPNotAllowed = fmt.Errorf("%w: ip address not allowed", usecase.ErrPermissionDenied)
+
+// IPPolicyRule applies Policy to the routes under PathPrefix, such as
+// "/admin" for "/admin" and "/admin/users". "/" covers every route.
+type IPPolicyRule struct {
+	PathPrefix string
+	Policy     entity.IPPolicy
+}
+
+// ParseIPPolicyRules parses one rule per line, as
+// "<path prefix> allow|deny <cidr>...". Lines of the same prefix are
+// merged, and blank lines and those starting with "#" are skipped.
+func ParseIPPolicyRules(text string) ([]IPPolicyRule, error) {
+	policies := make(map[string]*entity.IPPolicy)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !strings.HasPrefix(fields[0], "/") {
+			return nil, fmt.Errorf("invalid ip policy rule at line %d: %s", n, line)
+		}
+		pathPrefix := path.Clean(fields[0])
+		policy, ok := policies[pathPrefix]
+		if !ok {
+			policy = &entity.IPPolicy{}
+			policies[pathPrefix] = policy
+		}
+		var prefixes []netip.Prefix
+		for _, v := range fields[2:] {
+			prefix, err := entity.ParseIPPrefix(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ip policy rule at line %d: %w", n, err)
+			}
+			prefixes = append(prefixes, prefix)
+		}
+		switch strings.ToLower(fields[1]) {
+		default:
+			return nil, fmt.Errorf("invalid ip policy rule at line %d: %s", n, line)
+		case "allow":
+			policy.Allow = append(policy.Allow, prefixes...)
+		case "deny":
+			policy.Deny = append(policy.Deny, prefixes...)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var rules []IPPolicyRule
+	for pathPrefix, policy := range policies {
+		rules = append(rules, IPPolicyRule{
+			PathPrefix: pathPrefix,
+			Policy:     *policy,
+		})
+	}
+	return rules, nil
+}
+
+func (r IPPolicyRule) covers(requestPath string) bool {
+	if r.PathPrefix == "/" || requestPath == r.PathPrefix {
+		return true
+	}
+	return strings.HasPrefix(requestPath, r.PathPrefix+"/")
+}
+
+// IPPolicyTable holds the rules in force. They may be replaced at any time,
+// such as when their file changes.
+type IPPolicyTable struct {
+	rules atomic.Pointer[[]IPPolicyRule]
+}
+
+func NewIPPolicyTable(rules []IPPolicyRule) *IPPolicyTable {
+	t := &IPPolicyTable{}
+	t.Set(rules)
+	return t
+}
+
+func (t *IPPolicyTable) Set(rules []IPPolicyRule) {
+	t.rules.Store(&rules)
+}
+
+// refusing returns the first rule covering requestPath that does not
+// permit addr.
+func (t *IPPolicyTable) refusing(requestPath string, addr netip.Addr) (*IPPolicyRule, bool) {
+	for _, rule := range *t.rules.Load() {
+		if rule.covers(requestPath) && !rule.Policy.Permits(addr) {
+			return &rule, true
+		}
+	}
+	return nil, false
+}
+
+// IPPolicy refuses requests unless every rule covering their path permits
+// the client address, so rules for "/" apply under "/admin" too. Client
+// addresses are as gin.Context.ClientIP reports them, so proxies in front
+// of the server must be trusted for their forwarded addresses to count.
+func IPPolicy(table *IPPolicyTable) handlers.Handler {
+	return func(gc *gin.Context) {
+		// Unparsable addresses match no prefix, so rules allowing any refuse them.
+		addr, _ := netip.ParseAddr(gc.ClientIP())
+		rule, refused := table.refusing(path.Clean("/"+gc.Request.URL.Path), addr)
+		if !refused {
+			return
+		}
+		util.GLogger().Info(
+			"request refused by ip policy",
+			"clientIP", gc.ClientIP(),
+			"pathPrefix", rule.PathPrefix,
+		)
+		gc.Error(ErrIPNotAllowed).SetType(gin.ErrorTypePublic)
+		gc.Abort()
+	}
+}
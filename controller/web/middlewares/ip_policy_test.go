@@ -0,0 +1,207 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIPPolicyRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []IPPolicyRule
+		wantErr bool
+	}{
+		{
+			name: "return rules merged by path prefix",
+			text: "# admins only from the office\n" +
+				"/admin/ allow 192.0.2.0/24 2001:db8::/32\n" +
+				"\n" +
+				"/admin deny 192.0.2.10\n",
+			want: []IPPolicyRule{
+				{
+					PathPrefix: "/admin",
+					Policy: entity.IPPolicy{
+						Allow: []netip.Prefix{
+							netip.MustParsePrefix("192.0.2.0/24"),
+							netip.MustParsePrefix("2001:db8::/32"),
+						},
+						Deny: []netip.Prefix{
+							netip.MustParsePrefix("192.0.2.10/32"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name:    "return error for relative path",
+			text:    "admin allow 192.0.2.0/24",
+			wantErr: true,
+		},
+		{
+			name:    "return error without prefix",
+			text:    "/admin allow",
+			wantErr: true,
+		},
+		{
+			name:    "return error for unknown action",
+			text:    "/admin permit 192.0.2.0/24",
+			wantErr: true,
+		},
+		{
+			name:    "return error for invalid prefix",
+			text:    "/admin allow 192.0.2.0/33",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIPPolicyRules(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIPPolicyRule_covers(t *testing.T) {
+	tests := []struct {
+		name       string
+		pathPrefix string
+		path       string
+		want       bool
+	}{
+		{
+			name:       "cover every path with root",
+			pathPrefix: "/",
+			path:       "/users/test_user_id_001",
+			want:       true,
+		},
+		{
+			name:       "cover prefix itself",
+			pathPrefix: "/admin",
+			path:       "/admin",
+			want:       true,
+		},
+		{
+			name:       "cover path under prefix",
+			pathPrefix: "/admin",
+			path:       "/admin/users",
+			want:       true,
+		},
+		{
+			name:       "not cover path sharing prefix letters",
+			pathPrefix: "/admin",
+			path:       "/administrators",
+			want:       false,
+		},
+		{
+			name:       "not cover parent path",
+			pathPrefix: "/admin/users",
+			path:       "/admin",
+			want:       false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := IPPolicyRule{PathPrefix: tt.pathPrefix}
+			assert.Equal(t, tt.want, r.covers(tt.path))
+		})
+	}
+}
+
+func TestIPPolicy(t *testing.T) {
+	table := NewIPPolicyTable([]IPPolicyRule{
+		{
+			PathPrefix: "/",
+			Policy: entity.IPPolicy{
+				Deny: []netip.Prefix{netip.MustParsePrefix("203.0.113.0/24")},
+			},
+		},
+		{
+			PathPrefix: "/admin",
+			Policy: entity.IPPolicy{
+				Allow: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+			},
+		},
+	})
+	tests := []struct {
+		name       string
+		path       string
+		remoteAddr string
+		wantErr    error
+	}{
+		{
+			name:       "let allowed address through",
+			path:       "/admin/users",
+			remoteAddr: "192.0.2.1:12345",
+		},
+		{
+			name:       "let address through where no allow rule applies",
+			path:       "/users",
+			remoteAddr: "198.51.100.1:12345",
+		},
+		{
+			name:       "refuse address not allowed under prefix",
+			path:       "/admin/users",
+			remoteAddr: "198.51.100.1:12345",
+			wantErr:    ErrIPNotAllowed,
+		},
+		{
+			name:       "refuse address denied by root rule under prefix",
+			path:       "/admin",
+			remoteAddr: "203.0.113.1:12345",
+			wantErr:    ErrIPNotAllowed,
+		},
+		{
+			name:       "refuse address not allowed on path escaping prefix",
+			path:       "/users/../admin/users",
+			remoteAddr: "198.51.100.1:12345",
+			wantErr:    ErrIPNotAllowed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+			gc.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			gc.Request.URL.Path = tt.path
+			gc.Request.RemoteAddr = tt.remoteAddr
+
+			IPPolicy(table)(gc)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, gc.Errors.Last().Err, tt.wantErr)
+				assert.True(t, gc.IsAborted())
+				return
+			}
+			assert.Empty(t, gc.Errors)
+			assert.False(t, gc.IsAborted())
+		})
+	}
+}
+
+func TestIPPolicyTable_Set(t *testing.T) {
+	gc, _ := gin.CreateTestContext(httptest.NewRecorder())
+	gc.Request = httptest.NewRequest(http.MethodGet, "/admin", nil)
+	gc.Request.RemoteAddr = "198.51.100.1:12345"
+	table := NewIPPolicyTable(nil)
+
+	table.Set([]IPPolicyRule{
+		{
+			PathPrefix: "/admin",
+			Policy: entity.IPPolicy{
+				Allow: []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+			},
+		},
+	})
+	IPPolicy(table)(gc)
+	assert.ErrorIs(t, gc.Errors.Last().Err, ErrIPNotAllowed)
+}
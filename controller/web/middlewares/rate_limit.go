@@ -0,0 +1,242 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/interactor"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+var ErrTooManyRequests = errors.New("too many requests")
+
+// RateLimitKey is what requests are counted by.
+type RateLimitKey int
+
+const (
+	// RateLimitKeyIP counts requests per client IP address.
+	RateLimitKeyIP RateLimitKey = iota
+	// RateLimitKeyAccount counts requests per email address, taken from
+	// the Basic credentials or the "email" field of the body. Requests
+	// without one are not counted.
+	RateLimitKeyAccount
+	// RateLimitKeyAuthorization counts requests per client IP address, as
+	// RateLimitKeyIP does, but only those with an Authorization header.
+	RateLimitKeyAuthorization
+)
+
+func (k RateLimitKey) String() string {
+	return [...]string{"ip", "account", "authorization"}[k]
+}
+
+func ParseRateLimitKey(v string) (RateLimitKey, error) {
+	switch strings.ToLower(v) {
+	default:
+		return 0, fmt.Errorf("invalid rate limit key: %s", v)
+	case "ip":
+		return RateLimitKeyIP, nil
+	case "account":
+		return RateLimitKeyAccount, nil
+	case "authorization":
+		return RateLimitKeyAuthorization, nil
+	}
+}
+
+// RateLimitRule limits requests to a route. Method and Path are those the
+// route is registered with, such as "POST" and "/users/:id/password", or
+// "*" to match any.
+type RateLimitRule struct {
+	Method string
+	Path   string
+	Key    RateLimitKey
+	Limit  entity.RateLimit
+}
+
+// ParseRateLimitRule parses "<method> <path> <key> <limit>", such as
+// "POST /sessions ip 10/1m".
+func ParseRateLimitRule(v string) (RateLimitRule, error) {
+	fields := strings.Fields(v)
+	if len(fields) != 4 {
+		return RateLimitRule{}, fmt.Errorf("invalid rate limit rule: %s", v)
+	}
+	key, err := ParseRateLimitKey(fields[2])
+	if err != nil {
+		return RateLimitRule{}, err
+	}
+	limit, err := entity.ParseRateLimit(fields[3])
+	if err != nil {
+		return RateLimitRule{}, err
+	}
+	return RateLimitRule{
+		Method: strings.ToUpper(fields[0]),
+		Path:   fields[1],
+		Key:    key,
+		Limit:  limit,
+	}, nil
+}
+
+func (r RateLimitRule) String() string {
+	return fmt.Sprintf("%s %s %s %s", r.Method, r.Path, r.Key, r.Limit)
+}
+
+func (r RateLimitRule) matches(method string, path string) bool {
+	return (r.Method == "*" || r.Method == method) && (r.Path == "*" || r.Path == path)
+}
+
+// RateLimit refuses requests with 429 once any matching rule's limit is
+// reached. Responses to limited routes carry the RateLimit-Limit,
+// RateLimit-Remaining and RateLimit-Reset headers of the most restrictive
+// rule, and refusals Retry-After as well. Requests are let through when the
+// store fails, so that logins do not depend on it. Account keys are
+// normalized with emailPolicy, as accounts are looked up.
+func RateLimit(
+	txm port.TransactionManager,
+	rateLimitInteractor interactor.RateLimitInteractor,
+	rules []RateLimitRule,
+	emailPolicy entity.EmailLocalPartPolicy,
+) handlers.Handler {
+	return func(gc *gin.Context) {
+		path := gc.FullPath()
+		if len(path) == 0 {
+			return
+		}
+		var buckets []interactor.RateLimitBucket
+		var account string
+		for _, rule := range rules {
+			if !rule.matches(gc.Request.Method, path) {
+				continue
+			}
+			var value string
+			switch rule.Key {
+			case RateLimitKeyIP:
+				value = gc.ClientIP()
+			case RateLimitKeyAuthorization:
+				if len(gc.GetHeader("Authorization")) > 0 {
+					value = gc.ClientIP()
+				}
+			case RateLimitKeyAccount:
+				if len(account) == 0 {
+					account = rateLimitAccount(gc, emailPolicy)
+				}
+				value = account
+			}
+			if len(value) == 0 {
+				continue
+			}
+			buckets = append(buckets, interactor.RateLimitBucket{
+				Key:   fmt.Sprintf("%s %s %s:%s", rule.Method, rule.Path, rule.Key, value),
+				Limit: rule.Limit,
+			})
+		}
+		if len(buckets) == 0 {
+			return
+		}
+
+		result, err := takeRateLimit(gc.Request.Context(), txm, rateLimitInteractor, buckets)
+		if err != nil {
+			util.GLogger().Error(err, "failed to check rate limit")
+			return
+		}
+		gc.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		gc.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		gc.Header("RateLimit-Reset", ceilSeconds(result.ResetAfter))
+		if !result.Allowed {
+			gc.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			gc.Error(ErrTooManyRequests).SetType(gin.ErrorTypePublic)
+			gc.Abort()
+		}
+	}
+}
+
+func takeRateLimit(
+	ctx context.Context,
+	txm port.TransactionManager,
+	rateLimitInteractor interactor.RateLimitInteractor,
+	buckets []interactor.RateLimitBucket,
+) (result *entity.RateLimitResult, err error) {
+	ctx, err = txm.BeginContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			txm.Rollback(ctx)
+			return
+		}
+		if eErr := txm.End(ctx); eErr != nil {
+			err = eErr
+		}
+	}()
+	return rateLimitInteractor.Take(ctx, interactor.TakeRateLimitInput{
+		Buckets: buckets,
+	})
+}
+
+// rateLimitAccount returns the account key of the Basic credentials' or the
+// body's "email" field. The body is put back for the handler to read.
+func rateLimitAccount(gc *gin.Context, emailPolicy entity.EmailLocalPartPolicy) string {
+	if auth, err := handlers.GetAuthInfo(gc); err == nil {
+		return rateLimitAccountKey(auth.User, emailPolicy)
+	}
+	if gc.Request.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(gc.Request.Body)
+	gc.Request.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var email string
+	switch gc.ContentType() {
+	case gin.MIMEJSON:
+		var fields struct {
+			Email string `json:"email"`
+		}
+		if err := json.Unmarshal(body, &fields); err == nil {
+			email = fields.Email
+		}
+	case gin.MIMEPOSTForm:
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			email = values.Get("email")
+		}
+	}
+	return rateLimitAccountKey(email, emailPolicy)
+}
+
+// rateLimitAccountKey normalizes email and also drops "+" subaddresses and
+// dots from unquoted local parts, so that variants delivered to one mailbox
+// share a bucket. Addresses that do not parse are only trimmed and
+// lower-cased.
+func rateLimitAccountKey(email string, emailPolicy entity.EmailLocalPartPolicy) string {
+	email = strings.TrimSpace(email)
+	if len(email) == 0 {
+		return ""
+	}
+	normalized, err := entity.Email(email).Normalize(emailPolicy)
+	if err != nil {
+		return strings.ToLower(email)
+	}
+	local := normalized.LocalPart()
+	if !strings.HasPrefix(local, `"`) {
+		local, _, _ = strings.Cut(local, "+")
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	return local + "@" + normalized.Domain()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/entity"
 	"github.com/mkaiho/go-auth-api/usecase"
 	"github.com/mkaiho/go-auth-api/util"
 )
@@ -33,7 +34,7 @@ func Recovery() handlers.Handler {
 				headers := strings.Split(string(httpRequest), "\r\n")
 				for idx, header := range headers {
 					current := strings.Split(header, ":")
-					if current[0] == "Authorization" {
+					if current[0] == "Authorization" || current[0] == "Cookie" {
 						headers[idx] = current[0] + ": *"
 					}
 				}
@@ -63,9 +64,21 @@ func Recovery() handlers.Handler {
 				}
 			}
 			if errMsgs := c.Errors.ByType(gin.ErrorTypeBind); len(errMsgs) > 0 {
-				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				body := gin.H{
 					"message": errMsgs[0].Err.Error(),
-				})
+				}
+				var policyErr *entity.PasswordPolicyError
+				if errors.As(errMsgs[0].Err, &policyErr) {
+					var violations []gin.H
+					for _, v := range policyErr.Violations {
+						violations = append(violations, gin.H{
+							"rule":    v.Rule.String(),
+							"message": v.Message,
+						})
+					}
+					body["violations"] = violations
+				}
+				c.AbortWithStatusJSON(http.StatusBadRequest, body)
 			} else if errMsgs := c.Errors.ByType(gin.ErrorTypePublic); len(errMsgs) > 0 {
 				var msg string
 				code := http.StatusBadRequest
@@ -73,17 +86,34 @@ func Recovery() handlers.Handler {
 					code = http.StatusNotFound
 				} else if errors.Is(errMsgs[0].Err, usecase.ErrAlreadyExistsEntity) {
 					code = http.StatusConflict
-				} else if handlers.IsAuthError(errMsgs[0].Err) {
-					code = http.StatusUnauthorized
+				} else if errors.Is(errMsgs[0].Err, usecase.ErrEmailNotVerified) {
+					code = http.StatusForbidden
+					msg = errMsgs[0].Err.Error()
+				} else if errors.Is(errMsgs[0].Err, usecase.ErrPermissionDenied) {
+					code = http.StatusForbidden
 					msg = errMsgs[0].Err.Error()
+				} else if errors.Is(errMsgs[0].Err, ErrTooManyRequests) {
+					code = http.StatusTooManyRequests
+				} else if authErr := handlers.PublicAuthError(errMsgs[0].Err); authErr != nil {
+					code = http.StatusUnauthorized
+					msg = authErr.Error()
 				}
 
 				if len(msg) == 0 {
 					msg = http.StatusText(code)
 				}
-				c.AbortWithStatusJSON(code, gin.H{
+				body := gin.H{
 					"message": msg,
-				})
+				}
+				var stepUpErr *handlers.StepUpError
+				if errors.As(errMsgs[0].Err, &stepUpErr) {
+					body["error"] = "insufficient_user_authentication"
+					body["acr_values"] = stepUpErr.Level.String()
+					if stepUpErr.MaxAge > 0 {
+						body["max_age"] = int64(stepUpErr.MaxAge.Seconds())
+					}
+				}
+				c.AbortWithStatusJSON(code, body)
 			}
 		}()
 		c.Next()
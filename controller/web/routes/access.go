@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+func NewAccessRoutes(
+	checkAuth handlers.Handler,
+	accessDecisionCreate *handlers.AccessDecisionCreateHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPost,
+			path:     "/authorize-decision",
+			guard:    middlewares.RequirePermission(entity.PermissionAccessDecide),
+			handlers: handlers.Handlers{checkAuth, accessDecisionCreate.Handle},
+		},
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+)
+
+func NewEmailLoginRoutes(
+	emailLoginCreate *handlers.EmailLoginCreateHandler,
+	emailLoginUpdate *handlers.EmailLoginUpdateHandler,
+	emailLoginLinkCreate *handlers.EmailLoginLinkCreateHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPost,
+			path:     "/email-logins",
+			handlers: handlers.Handlers{emailLoginCreate.Handle},
+		},
+		{
+			method:   http.MethodPut,
+			path:     "/email-logins/:id",
+			handlers: handlers.Handlers{emailLoginUpdate.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/email-login-links/:token",
+			handlers: handlers.Handlers{emailLoginLinkCreate.Handle},
+		},
+	}
+}
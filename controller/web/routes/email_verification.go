@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+)
+
+func NewEmailVerificationRoutes(
+	emailVerificationCreate *handlers.EmailVerificationCreateHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPost,
+			path:     "/email-verifications/:token",
+			handlers: handlers.Handlers{emailVerificationCreate.Handle},
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+func NewGroupRoutes(
+	checkAuth handlers.Handler,
+	stepUp middlewares.AuthRequirement,
+	groupList *handlers.GroupListHandler,
+	groupCreate *handlers.GroupCreateHandler,
+	groupGet *handlers.GroupGetHandler,
+	groupUpdate *handlers.GroupUpdateHandler,
+	groupDelete *handlers.GroupDeleteHandler,
+	groupMemberList *handlers.GroupMemberListHandler,
+	groupUserAdd *handlers.GroupUserAddHandler,
+	groupUserRemove *handlers.GroupUserRemoveHandler,
+	groupSubgroupAdd *handlers.GroupSubgroupAddHandler,
+	groupSubgroupRemove *handlers.GroupSubgroupRemoveHandler,
+	userGroupList *handlers.UserGroupListHandler,
+	userInfoGet *handlers.UserInfoGetHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodGet,
+			path:     "/groups",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsRead),
+			handlers: handlers.Handlers{checkAuth, groupList.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/groups",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), groupCreate.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/groups/:id",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsRead),
+			handlers: handlers.Handlers{checkAuth, groupGet.Handle},
+		},
+		{
+			method:   http.MethodPut,
+			path:     "/groups/:id",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), groupUpdate.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/groups/:id",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), groupDelete.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/groups/:id/members",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsRead),
+			handlers: handlers.Handlers{checkAuth, groupMemberList.Handle},
+		},
+		{
+			method:   http.MethodPut,
+			path:     "/groups/:id/users/:user_id",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), groupUserAdd.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/groups/:id/users/:user_id",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), groupUserRemove.Handle},
+		},
+		{
+			method:   http.MethodPut,
+			path:     "/groups/:id/groups/:subgroup_id",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), groupSubgroupAdd.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/groups/:id/groups/:subgroup_id",
+			guard:    middlewares.RequirePermission(entity.PermissionGroupsWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), groupSubgroupRemove.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/users/:id/groups",
+			guard:    middlewares.RequireSelfOrPermission(entity.PermissionGroupsRead),
+			handlers: handlers.Handlers{checkAuth, userGroupList.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/userinfo",
+			handlers: handlers.Handlers{checkAuth, userInfoGet.Handle},
+		},
+	}
+}
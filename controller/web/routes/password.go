@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
+)
+
+func NewPasswordRoutes(
+	checkAuth handlers.Handler,
+	stepUp middlewares.AuthRequirement,
+	passwordChange *handlers.PasswordChangeHandler,
+	passwordResetCreate *handlers.PasswordResetCreateHandler,
+	passwordResetUpdate *handlers.PasswordResetUpdateHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPut,
+			path:     "/users/:id/password",
+			guard:    middlewares.RequireSelf(),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), passwordChange.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/password-resets",
+			handlers: handlers.Handlers{passwordResetCreate.Handle},
+		},
+		{
+			method:   http.MethodPut,
+			path:     "/password-resets/:token",
+			handlers: handlers.Handlers{passwordResetUpdate.Handle},
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+func NewRoleRoutes(
+	checkAuth handlers.Handler,
+	stepUp middlewares.AuthRequirement,
+	roleList *handlers.RoleListHandler,
+	roleSave *handlers.RoleSaveHandler,
+	roleDelete *handlers.RoleDeleteHandler,
+	userRoleList *handlers.UserRoleListHandler,
+	userRoleAssign *handlers.UserRoleAssignHandler,
+	userRoleUnassign *handlers.UserRoleUnassignHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodGet,
+			path:     "/roles",
+			guard:    middlewares.RequirePermission(entity.PermissionRolesRead),
+			handlers: handlers.Handlers{checkAuth, roleList.Handle},
+		},
+		{
+			method:   http.MethodPut,
+			path:     "/roles/:name",
+			guard:    middlewares.RequirePermission(entity.PermissionRolesWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), roleSave.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/roles/:name",
+			guard:    middlewares.RequirePermission(entity.PermissionRolesWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), roleDelete.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/users/:id/roles",
+			guard:    middlewares.RequireSelfOrPermission(entity.PermissionRolesRead),
+			handlers: handlers.Handlers{checkAuth, userRoleList.Handle},
+		},
+		{
+			method:   http.MethodPut,
+			path:     "/users/:id/roles/:name",
+			guard:    middlewares.RequirePermission(entity.PermissionRolesWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), userRoleAssign.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/users/:id/roles/:name",
+			guard:    middlewares.RequirePermission(entity.PermissionRolesWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), userRoleUnassign.Handle},
+		},
+	}
+}
@@ -5,8 +5,12 @@ import (
 )
 
 type Route struct {
-	method   string
-	path     string
+	method string
+	path   string
+	// guard authorizes requests, such as middlewares.RequirePermission,
+	// after the other handlers but the last have authenticated them. Nil
+	// leaves routes open to any request that gets through.
+	guard    handlers.Handler
 	handlers handlers.Handlers
 }
 
@@ -18,8 +22,15 @@ func (r *Route) Path() string {
 	return r.path
 }
 
+// Handlers returns the handlers with the guard just before the last one.
 func (r *Route) Handlers() handlers.Handlers {
-	return r.handlers
+	if r.guard == nil || len(r.handlers) == 0 {
+		return r.handlers
+	}
+	last := len(r.handlers) - 1
+	hs := make(handlers.Handlers, 0, len(r.handlers)+1)
+	hs = append(hs, r.handlers[:last]...)
+	return append(hs, r.guard, r.handlers[last])
 }
 
 type Routes []*Route
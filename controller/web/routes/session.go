@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+func NewSessionRoutes(
+	checkAuth handlers.Handler,
+	sessionCreate *handlers.SessionCreateHandler,
+	sessionDelete *handlers.SessionDeleteHandler,
+	sessionList *handlers.SessionListHandler,
+	sessionRevoke *handlers.SessionRevokeHandler,
+	sessionRevokeOthers *handlers.SessionRevokeOthersHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPost,
+			path:     "/sessions",
+			handlers: handlers.Handlers{sessionCreate.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/sessions/current",
+			handlers: handlers.Handlers{checkAuth, sessionDelete.Handle},
+		},
+		{
+			method:   http.MethodGet,
+			path:     "/users/:id/sessions",
+			guard:    middlewares.RequireSelfOrPermission(entity.PermissionUsersRead),
+			handlers: handlers.Handlers{checkAuth, sessionList.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/users/:id/sessions",
+			guard:    middlewares.RequireSelfOrPermission(entity.PermissionUsersWrite),
+			handlers: handlers.Handlers{checkAuth, sessionRevokeOthers.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/users/:id/sessions/:sid",
+			guard:    middlewares.RequireSelfOrPermission(entity.PermissionUsersWrite),
+			handlers: handlers.Handlers{checkAuth, sessionRevoke.Handle},
+		},
+	}
+}
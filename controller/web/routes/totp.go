@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
+)
+
+func NewTOTPRoutes(
+	checkAuth handlers.Handler,
+	stepUp middlewares.AuthRequirement,
+	totpCreate *handlers.TOTPCreateHandler,
+	totpUpdate *handlers.TOTPUpdateHandler,
+	totpDelete *handlers.TOTPDeleteHandler,
+	recoveryCodeCreate *handlers.RecoveryCodeCreateHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPost,
+			path:     "/users/:id/totp",
+			guard:    middlewares.RequireSelf(),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), totpCreate.Handle},
+		},
+		{
+			method:   http.MethodPut,
+			path:     "/users/:id/totp",
+			guard:    middlewares.RequireSelf(),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), totpUpdate.Handle},
+		},
+		{
+			method:   http.MethodDelete,
+			path:     "/users/:id/totp",
+			guard:    middlewares.RequireSelf(),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), totpDelete.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/users/:id/recovery-codes",
+			guard:    middlewares.RequireSelf(),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), recoveryCodeCreate.Handle},
+		},
+	}
+}
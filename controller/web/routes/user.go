@@ -5,12 +5,12 @@ import (
 
 	"github.com/mkaiho/go-auth-api/controller/web/handlers"
 	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
-	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/entity"
 )
 
 func NewUserRoutes(
-	txm port.TransactionManager,
-	credGateway port.UserCredentialGateway,
+	checkAuth handlers.Handler,
+	stepUp middlewares.AuthRequirement,
 	userFind *handlers.UserFindHandler,
 	userCreate *handlers.UserCreateHandler,
 	userGet *handlers.UserGetHandler,
@@ -20,7 +20,8 @@ func NewUserRoutes(
 		{
 			method:   http.MethodGet,
 			path:     "/users",
-			handlers: handlers.Handlers{middlewares.CheckAuth(txm, credGateway), userFind.Handle},
+			guard:    middlewares.RequirePermission(entity.PermissionUsersRead),
+			handlers: handlers.Handlers{checkAuth, userFind.Handle},
 		},
 		{
 			method:   http.MethodPost,
@@ -30,12 +31,14 @@ func NewUserRoutes(
 		{
 			method:   http.MethodGet,
 			path:     "/users/:id",
-			handlers: handlers.Handlers{middlewares.CheckAuth(txm, credGateway), userGet.Handle},
+			guard:    middlewares.RequireSelfOrPermission(entity.PermissionUsersRead),
+			handlers: handlers.Handlers{checkAuth, userGet.Handle},
 		},
 		{
 			method:   http.MethodPut,
 			path:     "/users/:id",
-			handlers: handlers.Handlers{middlewares.CheckAuth(txm, credGateway), userUpdate.Handle},
+			guard:    middlewares.RequireSelfOrPermission(entity.PermissionUsersWrite),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), userUpdate.Handle},
 		},
 	}
 }
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/mkaiho/go-auth-api/controller/web/handlers"
+	"github.com/mkaiho/go-auth-api/controller/web/middlewares"
+)
+
+func NewWebAuthnRoutes(
+	checkAuth handlers.Handler,
+	stepUp middlewares.AuthRequirement,
+	credentialOptionsCreate *handlers.WebAuthnCredentialOptionsCreateHandler,
+	credentialCreate *handlers.WebAuthnCredentialCreateHandler,
+	assertionOptionsCreate *handlers.WebAuthnAssertionOptionsCreateHandler,
+	assertionCreate *handlers.WebAuthnAssertionCreateHandler,
+) Routes {
+	return Routes{
+		{
+			method:   http.MethodPost,
+			path:     "/users/:id/webauthn-credentials/options",
+			guard:    middlewares.RequireSelf(),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), credentialOptionsCreate.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/users/:id/webauthn-credentials",
+			guard:    middlewares.RequireSelf(),
+			handlers: handlers.Handlers{checkAuth, middlewares.RequireAuth(stepUp), credentialCreate.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/webauthn-assertions/options",
+			handlers: handlers.Handlers{assertionOptionsCreate.Handle},
+		},
+		{
+			method:   http.MethodPost,
+			path:     "/webauthn-assertions",
+			handlers: handlers.Handlers{assertionCreate.Handle},
+		},
+	}
+}
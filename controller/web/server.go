@@ -25,15 +25,26 @@ func (s *Server) Handle(httpMethod string, relativePath string, h ...handlers.Ha
 	s.e.Handle(httpMethod, relativePath, hs...)
 }
 
+// SetTrustedProxies trusts the forwarded client addresses of requests
+// from proxies, given as addresses or CIDRs.
+func (s *Server) SetTrustedProxies(proxies []string) error {
+	return s.e.SetTrustedProxies(proxies)
+}
+
 func (s *Server) Run(addr ...string) error {
 	return s.e.Run(addr...)
 }
 
-func NewGinServer(r ...*routes.Route) *Server {
+// NewGinServer runs middleware on every request, after logging and
+// recovery. No proxies are trusted until SetTrustedProxies.
+func NewGinServer(r routes.Routes, middleware ...handlers.Handler) *Server {
 	server := &Server{
 		e: gin.New(),
 	}
+	// Trusting no proxies never fails.
+	_ = server.e.SetTrustedProxies(nil)
 	server.Use(middlewares.NewGinLogger(), middlewares.Recovery())
+	server.Use(middleware...)
 	for _, route := range r {
 		server.Handle(route.Method(), route.Path(), route.Handlers()...)
 	}
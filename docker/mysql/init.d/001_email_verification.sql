@@ -0,0 +1,2 @@
+ALTER TABLE `users`
+  ADD COLUMN `email_verified` TINYINT(1) NOT NULL DEFAULT 0 AFTER `email`;
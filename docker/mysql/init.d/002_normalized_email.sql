@@ -0,0 +1,8 @@
+ALTER TABLE `users`
+  ADD COLUMN `normalized_email` VARCHAR(255) COLLATE utf8mb4_bin NULL AFTER `email_verified`;
+-- Best effort backfill for rows created before normalization existed.
+-- Internationalized domains are re-normalized by the application on the next update.
+UPDATE `users` SET `normalized_email` = LOWER(`email`) WHERE `normalized_email` IS NULL;
+ALTER TABLE `users`
+  MODIFY COLUMN `normalized_email` VARCHAR(255) COLLATE utf8mb4_bin NOT NULL,
+  ADD UNIQUE KEY `uk_users_normalized_email` (`normalized_email`);
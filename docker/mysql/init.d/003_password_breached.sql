@@ -0,0 +1,2 @@
+ALTER TABLE `user_credentials`
+  ADD COLUMN `password_breached` TINYINT(1) NOT NULL DEFAULT 0 AFTER `password`;
@@ -0,0 +1,11 @@
+CREATE TABLE `password_history` (
+  `id` VARCHAR(40) NOT NULL,
+  `user_id` VARCHAR(40) NOT NULL,
+  `password` VARCHAR(255) COLLATE utf8mb4_unicode_ci NOT NULL,
+  `created_at` TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6),
+  PRIMARY KEY (`id`),
+  KEY `idx_password_history_user_id_created_at` (`user_id`, `created_at`)
+);
+-- Seed the history with passwords set before it existed.
+INSERT INTO `password_history` (`id`, `user_id`, `password`, `created_at`)
+SELECT `id`, `user_id`, `password`, `updated_at` FROM `user_credentials`;
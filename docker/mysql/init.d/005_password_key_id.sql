@@ -0,0 +1,7 @@
+-- An empty key ID means the password hash is stored unwrapped.
+ALTER TABLE `user_credentials`
+  ADD COLUMN `password_key_id` VARCHAR(64) NOT NULL DEFAULT '' AFTER `password_breached`,
+  ADD KEY `idx_user_credentials_password_key_id` (`password_key_id`);
+ALTER TABLE `password_history`
+  ADD COLUMN `password_key_id` VARCHAR(64) NOT NULL DEFAULT '' AFTER `password`,
+  ADD KEY `idx_password_history_password_key_id` (`password_key_id`);
@@ -0,0 +1,19 @@
+-- `secret` is encrypted with the CRYPTO_KEYRING keys.
+CREATE TABLE `user_totps` (
+  `user_id` VARCHAR(40) NOT NULL,
+  `secret` VARCHAR(255) NOT NULL,
+  `confirmed` BOOLEAN NOT NULL DEFAULT FALSE,
+  `last_used_step` BIGINT NOT NULL DEFAULT 0,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`)
+);
+CREATE TABLE `recovery_codes` (
+  `id` VARCHAR(40) NOT NULL,
+  `user_id` VARCHAR(40) NOT NULL,
+  `code_hash` CHAR(64) NOT NULL,
+  `used_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_recovery_codes_user_id_code_hash` (`user_id`, `code_hash`)
+);
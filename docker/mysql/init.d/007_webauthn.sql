@@ -0,0 +1,25 @@
+CREATE TABLE `webauthn_credentials` (
+  `id` VARCHAR(40) NOT NULL,
+  `user_id` VARCHAR(40) NOT NULL,
+  `credential_id` VARBINARY(1023) NOT NULL,
+  `public_key` BLOB NOT NULL,
+  `sign_count` INT UNSIGNED NOT NULL DEFAULT 0,
+  `last_used_at` TIMESTAMP NULL DEFAULT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_webauthn_credentials_credential_id` (`credential_id`),
+  KEY `idx_webauthn_credentials_user_id` (`user_id`)
+);
+-- Challenges are deleted when used. `user_id` is NULL for logins with
+-- discoverable credentials.
+CREATE TABLE `webauthn_challenges` (
+  `id` VARCHAR(40) NOT NULL,
+  `user_id` VARCHAR(40) NULL DEFAULT NULL,
+  `ceremony` VARCHAR(20) NOT NULL,
+  `challenge` VARBINARY(64) NOT NULL,
+  `expires_at` TIMESTAMP NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_webauthn_challenges_expires_at` (`expires_at`)
+);
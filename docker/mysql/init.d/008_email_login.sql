@@ -0,0 +1,11 @@
+-- A user has at most one pending code; requesting another replaces it.
+CREATE TABLE `email_login_codes` (
+  `id` VARCHAR(40) NOT NULL,
+  `user_id` VARCHAR(40) NOT NULL,
+  `code_hash` CHAR(64) NOT NULL,
+  `attempts` INT NOT NULL DEFAULT 0,
+  `expires_at` TIMESTAMP NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_email_login_codes_user_id` (`user_id`)
+);
@@ -0,0 +1,18 @@
+-- Sessions are looked up by a hash of their cookie token. Idle sessions are
+-- rejected by `last_seen_at`, and rows are deleted after `expires_at`.
+CREATE TABLE `sessions` (
+  `id` VARCHAR(40) NOT NULL,
+  `user_id` VARCHAR(40) NOT NULL,
+  `token_hash` CHAR(64) NOT NULL,
+  `level` VARCHAR(10) NOT NULL,
+  `available_level` VARCHAR(10) NOT NULL,
+  `methods` VARCHAR(255) NOT NULL,
+  `auth_time` TIMESTAMP NOT NULL,
+  `last_seen_at` TIMESTAMP NOT NULL,
+  `expires_at` TIMESTAMP NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_sessions_token_hash` (`token_hash`),
+  KEY `idx_sessions_user_id` (`user_id`),
+  KEY `idx_sessions_expires_at` (`expires_at`)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE `sessions`
+  ADD COLUMN `user_agent` VARCHAR(512) NOT NULL DEFAULT '' AFTER `auth_time`,
+  ADD COLUMN `ip_address` VARCHAR(45) NOT NULL DEFAULT '' AFTER `user_agent`;
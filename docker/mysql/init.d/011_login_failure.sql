@@ -0,0 +1,7 @@
+-- Failed logins per user, deleted on success or unlock.
+CREATE TABLE `login_failures` (
+  `user_id` VARCHAR(40) NOT NULL,
+  `failed_count` INT NOT NULL DEFAULT 0,
+  `last_failed_at` TIMESTAMP NOT NULL,
+  PRIMARY KEY (`user_id`)
+);
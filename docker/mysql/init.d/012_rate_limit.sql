@@ -0,0 +1,11 @@
+-- Token buckets of rate limits shared by all instances. Rows are deleted
+-- once their bucket is full again. Keys are SHA-256 hashes, as they hold
+-- email and IP addresses.
+CREATE TABLE `rate_limit_buckets` (
+  `bucket_key` CHAR(64) NOT NULL,
+  `tokens` DOUBLE NOT NULL,
+  `updated_at` TIMESTAMP(6) NOT NULL,
+  `full_at` TIMESTAMP(6) NOT NULL,
+  PRIMARY KEY (`bucket_key`),
+  INDEX `idx_rate_limit_buckets_full_at` (`full_at`)
+);
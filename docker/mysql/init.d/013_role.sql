@@ -0,0 +1,28 @@
+-- Roles grant permissions, such as "users:read", to the users assigned
+-- them. Rows of a deleted role are deleted along with it.
+CREATE TABLE `roles` (
+  `name` VARCHAR(64) NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`name`)
+);
+CREATE TABLE `role_permissions` (
+  `role_name` VARCHAR(64) NOT NULL,
+  `permission` VARCHAR(64) NOT NULL,
+  PRIMARY KEY (`role_name`, `permission`)
+);
+CREATE TABLE `user_roles` (
+  `user_id` VARCHAR(40) NOT NULL,
+  `role_name` VARCHAR(64) NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`user_id`, `role_name`),
+  KEY `idx_user_roles_role_name` (`role_name`)
+);
+
+-- Administrators are assigned with cmd/assign-role.
+INSERT INTO `roles` (`name`) VALUES ('admin');
+INSERT INTO `role_permissions` (`role_name`, `permission`) VALUES
+  ('admin', 'users:read'),
+  ('admin', 'users:write'),
+  ('admin', 'roles:read'),
+  ('admin', 'roles:write');
@@ -0,0 +1,3 @@
+-- Services granted "access:decide" may ask for access decisions.
+INSERT INTO `role_permissions` (`role_name`, `permission`) VALUES
+  ('admin', 'access:decide');
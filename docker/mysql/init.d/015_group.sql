@@ -0,0 +1,29 @@
+-- Groups gather users and other groups, whose members are then members of
+-- the group too. GROUPS is a reserved word, so the table is always quoted.
+CREATE TABLE `groups` (
+  `id` VARCHAR(40) NOT NULL,
+  `name` VARCHAR(64) NOT NULL,
+  `description` VARCHAR(255) NOT NULL DEFAULT '',
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_groups_name` (`name`)
+);
+CREATE TABLE `group_users` (
+  `group_id` VARCHAR(40) NOT NULL,
+  `user_id` VARCHAR(40) NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`group_id`, `user_id`),
+  KEY `idx_group_users_user_id` (`user_id`)
+);
+CREATE TABLE `group_subgroups` (
+  `group_id` VARCHAR(40) NOT NULL,
+  `subgroup_id` VARCHAR(40) NOT NULL,
+  `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`group_id`, `subgroup_id`),
+  KEY `idx_group_subgroups_subgroup_id` (`subgroup_id`)
+);
+
+INSERT INTO `role_permissions` (`role_name`, `permission`) VALUES
+  ('admin', 'groups:read'),
+  ('admin', 'groups:write');
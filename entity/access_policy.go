@@ -0,0 +1,748 @@
+package entity
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AccessEffect is what an access rule decides when it matches.
+type AccessEffect int
+
+const (
+	AccessEffectAllow AccessEffect = iota
+	AccessEffectDeny
+)
+
+func (e AccessEffect) String() string {
+	return [...]string{
+		"allow",
+		"deny",
+	}[e]
+}
+
+func ParseAccessEffect(v string) (AccessEffect, error) {
+	switch v {
+	case "allow":
+		return AccessEffectAllow, nil
+	case "deny":
+		return AccessEffectDeny, nil
+	default:
+		return 0, fmt.Errorf("invalid access effect: %s", v)
+	}
+}
+
+// Attributes describe the subject, resource or context of an access
+// request. Values are the ones JSON decodes to: strings, float64 numbers,
+// bools, nil, []any and map[string]any. Other integers and []string are
+// accepted for attributes set in code.
+type Attributes map[string]any
+
+// AccessRequest asks whether Subject may perform Action on Resource.
+type AccessRequest struct {
+	Subject  Attributes
+	Action   string
+	Resource Attributes
+	Context  Attributes
+}
+
+type AccessDecision struct {
+	Allowed bool
+	// Rule names the rule that decided, or is empty when no rule matched
+	// and access was denied by default.
+	Rule string
+	// Errors are the conditions that could not be evaluated. Those of deny
+	// rules are taken to hold, and those of allow rules not to.
+	Errors []error
+}
+
+// AccessRule applies Effect to the actions it names when Condition holds.
+// A nil Condition always holds.
+type AccessRule struct {
+	Name      string
+	Effect    AccessEffect
+	Actions   []string
+	Condition *AccessCondition
+}
+
+// Covers reports whether the rule names action, either exactly, by a
+// pattern such as "users:*", or by "*".
+func (r *AccessRule) Covers(action string) bool {
+	for _, pattern := range r.Actions {
+		if pattern == action {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(action, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessPolicy decides access requests by its rules. A matching deny rule
+// overrides any allow rule, and requests no rule matches are denied.
+type AccessPolicy struct {
+	Rules []*AccessRule
+}
+
+func (p *AccessPolicy) Decide(req AccessRequest) AccessDecision {
+	var decision AccessDecision
+	for _, rule := range p.Rules {
+		if !rule.Covers(req.Action) {
+			continue
+		}
+		holds := true
+		if rule.Condition != nil {
+			var err error
+			holds, err = rule.Condition.Holds(req)
+			if err != nil {
+				decision.Errors = append(decision.Errors, fmt.Errorf("rule %s: %w", rule.Name, err))
+				holds = rule.Effect == AccessEffectDeny
+			}
+		}
+		if !holds {
+			continue
+		}
+		if rule.Effect == AccessEffectDeny {
+			decision.Allowed = false
+			decision.Rule = rule.Name
+			return decision
+		}
+		if !decision.Allowed {
+			decision.Allowed = true
+			decision.Rule = rule.Name
+		}
+	}
+	return decision
+}
+
+var accessRuleNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ParseAccessPolicy parses one rule per line, as
+//
+//	<name>: allow|deny <action>[,<action>...] [when <condition>]
+//
+// Lines starting with a space or tab continue the rule above them, and
+// blank lines and those starting with "#" are skipped. See
+// ParseAccessCondition for conditions.
+func ParseAccessPolicy(text string) (*AccessPolicy, error) {
+	type ruleText struct {
+		line int
+		text string
+	}
+	var texts []*ruleText
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for n := 1; scanner.Scan(); n++ {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if raw[0] == ' ' || raw[0] == '\t' {
+			if len(texts) == 0 {
+				return nil, fmt.Errorf("invalid access rule at line %d: continues no rule", n)
+			}
+			last := texts[len(texts)-1]
+			last.text += " " + line
+			continue
+		}
+		texts = append(texts, &ruleText{line: n, text: line})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	policy := &AccessPolicy{}
+	names := make(map[string]bool)
+	for _, t := range texts {
+		rule, err := parseAccessRule(t.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid access rule at line %d: %w", t.line, err)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("invalid access rule at line %d: duplicate name %s", t.line, rule.Name)
+		}
+		names[rule.Name] = true
+		policy.Rules = append(policy.Rules, rule)
+	}
+	return policy, nil
+}
+
+func parseAccessRule(text string) (*AccessRule, error) {
+	name, rest, ok := strings.Cut(text, ":")
+	if !ok || !accessRuleNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid name: %s", text)
+	}
+	effectText, rest := cutField(rest)
+	effect, err := ParseAccessEffect(effectText)
+	if err != nil {
+		return nil, err
+	}
+	actionsText, rest := cutField(rest)
+	if len(actionsText) == 0 {
+		return nil, errors.New("no actions")
+	}
+	rule := &AccessRule{
+		Name:    name,
+		Effect:  effect,
+		Actions: strings.Split(actionsText, ","),
+	}
+	for _, action := range rule.Actions {
+		if len(action) == 0 {
+			return nil, fmt.Errorf("invalid actions: %s", actionsText)
+		}
+	}
+	if len(rest) == 0 {
+		return rule, nil
+	}
+	keyword, rest := cutField(rest)
+	if keyword != "when" || len(rest) == 0 {
+		return nil, fmt.Errorf("expected \"when <condition>\": %s", strings.TrimSpace(keyword+" "+rest))
+	}
+	rule.Condition, err = ParseAccessCondition(rest)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// cutField cuts the first space separated field off v.
+func cutField(v string) (field string, rest string) {
+	v = strings.TrimSpace(v)
+	if i := strings.IndexAny(v, " \t"); i >= 0 {
+		return v[:i], strings.TrimSpace(v[i:])
+	}
+	return v, ""
+}
+
+// AccessCondition is a boolean expression over an access request, such as
+//
+//	"manager" in subject.roles and resource.org == subject.org
+//
+// Attributes are read as subject.<name>, resource.<name> and
+// context.<name>, with further ".<name>" reading into objects, and action
+// is the requested action. Missing attributes are null. Values are
+// compared with ==, !=, <, <=, > and >=, and "x in y" tests membership of
+// a list or a substring of a string. Conditions combine with and, or, not
+// and parentheses, and literals are "strings", numbers, true, false, null
+// and [lists].
+type AccessCondition struct {
+	source string
+	root   accessNode
+}
+
+func ParseAccessCondition(v string) (*AccessCondition, error) {
+	tokens, err := tokenizeAccessCondition(v)
+	if err != nil {
+		return nil, err
+	}
+	p := &accessParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != accessTokenEOF {
+		return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+	}
+	return &AccessCondition{
+		source: v,
+		root:   root,
+	}, nil
+}
+
+func (c *AccessCondition) String() string {
+	return c.source
+}
+
+// Holds evaluates the condition for req. It fails when values are
+// compared that can not be, or when the condition is not a boolean.
+func (c *AccessCondition) Holds(req AccessRequest) (bool, error) {
+	v, err := c.root.eval(&req)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("condition is not a boolean: %v", v)
+	}
+	return b, nil
+}
+
+type accessTokenKind int
+
+const (
+	accessTokenEOF accessTokenKind = iota
+	accessTokenIdent
+	accessTokenString
+	accessTokenNumber
+	accessTokenSymbol
+)
+
+type accessToken struct {
+	kind  accessTokenKind
+	text  string
+	value any
+	pos   int
+}
+
+func tokenizeAccessCondition(src string) ([]accessToken, error) {
+	var tokens []accessToken
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && (isIdentStart(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, accessToken{kind: accessTokenIdent, text: src[start:i], pos: start})
+		case isDigit(c) || (c == '-' && i+1 < len(src) && isDigit(src[i+1])):
+			start := i
+			i++
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			n, err := strconv.ParseFloat(src[start:i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at %d", src[start:i], start)
+			}
+			tokens = append(tokens, accessToken{kind: accessTokenNumber, text: src[start:i], value: n, pos: start})
+		case c == '"':
+			start := i
+			for i++; i < len(src) && src[i] != '"'; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(src) {
+				return nil, fmt.Errorf("unterminated string at %d", start)
+			}
+			i++
+			s, err := strconv.Unquote(src[start:i])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string %s at %d", src[start:i], start)
+			}
+			tokens = append(tokens, accessToken{kind: accessTokenString, text: src[start:i], value: s, pos: start})
+		default:
+			start := i
+			switch op := src[i:min(i+2, len(src))]; op {
+			case "==", "!=", "<=", ">=":
+				i += 2
+			default:
+				if !strings.ContainsRune("<>()[],.", rune(c)) {
+					return nil, fmt.Errorf("unexpected %q at %d", c, i)
+				}
+				i++
+			}
+			tokens = append(tokens, accessToken{kind: accessTokenSymbol, text: src[start:i], pos: start})
+		}
+	}
+	return append(tokens, accessToken{kind: accessTokenEOF, text: "end", pos: len(src)}), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+type accessParser struct {
+	tokens []accessToken
+	pos    int
+}
+
+func (p *accessParser) peek() accessToken {
+	return p.tokens[p.pos]
+}
+
+func (p *accessParser) next() accessToken {
+	t := p.tokens[p.pos]
+	if t.kind != accessTokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token when it is the symbol or keyword text.
+func (p *accessParser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == accessTokenSymbol || t.kind == accessTokenIdent) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *accessParser) expect(text string) error {
+	if !p.accept(text) {
+		t := p.peek()
+		return fmt.Errorf("expected %q but got %q at %d", text, t.text, t.pos)
+	}
+	return nil
+}
+
+func (p *accessParser) parseOr() (accessNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &accessLogical{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *accessParser) parseAnd() (accessNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &accessLogical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *accessParser) parseNot() (accessNode, error) {
+	if p.accept("not") {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &accessNot{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *accessParser) parseComparison() (accessNode, error) {
+	left, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">", "in"} {
+		if p.accept(op) {
+			right, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			return &accessComparison{op: op, left: left, right: right}, nil
+		}
+	}
+	return left, nil
+}
+
+func (p *accessParser) parseValue() (accessNode, error) {
+	t := p.next()
+	switch t.kind {
+	case accessTokenString, accessTokenNumber:
+		return &accessLiteral{value: t.value}, nil
+	case accessTokenSymbol:
+		switch t.text {
+		case "(":
+			node, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return node, nil
+		case "[":
+			list := &accessList{}
+			if p.accept("]") {
+				return list, nil
+			}
+			for {
+				item, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				list.items = append(list.items, item)
+				if p.accept("]") {
+					return list, nil
+				}
+				if err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case accessTokenIdent:
+		switch t.text {
+		case "true":
+			return &accessLiteral{value: true}, nil
+		case "false":
+			return &accessLiteral{value: false}, nil
+		case "null":
+			return &accessLiteral{value: nil}, nil
+		case "action":
+			return &accessAttribute{root: t.text}, nil
+		case "subject", "resource", "context":
+			attr := &accessAttribute{root: t.text}
+			for p.accept(".") {
+				name := p.next()
+				if name.kind != accessTokenIdent {
+					return nil, fmt.Errorf("expected attribute name but got %q at %d", name.text, name.pos)
+				}
+				attr.path = append(attr.path, name.text)
+			}
+			if len(attr.path) == 0 {
+				return nil, fmt.Errorf("expected attribute of %s at %d", t.text, t.pos)
+			}
+			return attr, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q at %d", t.text, t.pos)
+}
+
+type accessNode interface {
+	eval(req *AccessRequest) (any, error)
+}
+
+type accessLiteral struct {
+	value any
+}
+
+func (n *accessLiteral) eval(*AccessRequest) (any, error) {
+	return n.value, nil
+}
+
+type accessList struct {
+	items []accessNode
+}
+
+func (n *accessList) eval(req *AccessRequest) (any, error) {
+	values := make([]any, 0, len(n.items))
+	for _, item := range n.items {
+		v, err := item.eval(req)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+type accessAttribute struct {
+	root string
+	path []string
+}
+
+func (n *accessAttribute) eval(req *AccessRequest) (any, error) {
+	var v any
+	switch n.root {
+	case "action":
+		return req.Action, nil
+	case "subject":
+		v = map[string]any(req.Subject)
+	case "resource":
+		v = map[string]any(req.Resource)
+	case "context":
+		v = map[string]any(req.Context)
+	}
+	for _, name := range n.path {
+		switch m := v.(type) {
+		case map[string]any:
+			v = m[name]
+		case Attributes:
+			v = m[name]
+		default:
+			return nil, nil
+		}
+	}
+	return v, nil
+}
+
+type accessNot struct {
+	operand accessNode
+}
+
+func (n *accessNot) eval(req *AccessRequest) (any, error) {
+	b, err := evalAccessBool(n.operand, req)
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type accessLogical struct {
+	or    bool
+	left  accessNode
+	right accessNode
+}
+
+func (n *accessLogical) eval(req *AccessRequest) (any, error) {
+	left, err := evalAccessBool(n.left, req)
+	if err != nil {
+		return nil, err
+	}
+	if left == n.or {
+		return left, nil
+	}
+	return evalAccessBool(n.right, req)
+}
+
+func evalAccessBool(node accessNode, req *AccessRequest) (bool, error) {
+	v, err := node.eval(req)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("not a boolean: %v", v)
+	}
+	return b, nil
+}
+
+type accessComparison struct {
+	op    string
+	left  accessNode
+	right accessNode
+}
+
+func (n *accessComparison) eval(req *AccessRequest) (any, error) {
+	left, err := n.left.eval(req)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(req)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "==":
+		return accessValuesEqual(left, right), nil
+	case "!=":
+		return !accessValuesEqual(left, right), nil
+	case "in":
+		if s, ok := right.(string); ok {
+			sub, ok := left.(string)
+			if !ok {
+				return nil, fmt.Errorf("can not find %v in a string", left)
+			}
+			return strings.Contains(s, sub), nil
+		}
+		if right == nil {
+			return false, nil
+		}
+		list, ok := asAccessList(right)
+		if !ok {
+			return nil, fmt.Errorf("can not find %v in %v", left, right)
+		}
+		for _, item := range list {
+			if accessValuesEqual(left, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	var c int
+	if l, ok := asAccessNumber(left); ok {
+		r, ok := asAccessNumber(right)
+		if !ok {
+			return nil, fmt.Errorf("can not compare %v and %v", left, right)
+		}
+		c = compareOrdered(l, r)
+	} else if l, ok := left.(string); ok {
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("can not compare %v and %v", left, right)
+		}
+		c = compareOrdered(l, r)
+	} else {
+		return nil, fmt.Errorf("can not compare %v and %v", left, right)
+	}
+	switch n.op {
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	default:
+		return c >= 0, nil
+	}
+}
+
+func compareOrdered[T float64 | string](l, r T) int {
+	switch {
+	case l < r:
+		return -1
+	case l > r:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func accessValuesEqual(left, right any) bool {
+	if l, ok := asAccessNumber(left); ok {
+		r, ok := asAccessNumber(right)
+		return ok && l == r
+	}
+	if l, ok := asAccessList(left); ok {
+		r, ok := asAccessList(right)
+		if !ok || len(l) != len(r) {
+			return false
+		}
+		for i := range l {
+			if !accessValuesEqual(l[i], r[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	switch left.(type) {
+	case nil, string, bool:
+		return left == right
+	default:
+		return false
+	}
+}
+
+func asAccessNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func asAccessList(v any) ([]any, bool) {
+	switch l := v.(type) {
+	case []any:
+		return l, true
+	case []string:
+		values := make([]any, 0, len(l))
+		for _, s := range l {
+			values = append(values, s)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,244 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAccessPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantRules int
+		wantErr   bool
+	}{
+		{
+			name: "return rules",
+			text: `
+# managers may update users in their own org
+manager-org-update: allow users:read,users:update
+  when "manager" in subject.roles
+    and resource.org == subject.org
+self-read: allow users:read when resource.id == subject.id
+no-self-delete: deny users:delete when resource.id == subject.id
+admins: allow *
+`,
+			wantRules: 4,
+		},
+		{
+			name:    "return error when name is invalid",
+			text:    "Bad Name: allow users:read",
+			wantErr: true,
+		},
+		{
+			name:    "return error when effect is invalid",
+			text:    "rule: permit users:read",
+			wantErr: true,
+		},
+		{
+			name:    "return error when names are duplicated",
+			text:    "rule: allow users:read\nrule: deny users:read",
+			wantErr: true,
+		},
+		{
+			name:    "return error when condition is missing",
+			text:    "rule: allow users:read when",
+			wantErr: true,
+		},
+		{
+			name:    "return error when first line continues",
+			text:    "  rule: allow users:read",
+			wantErr: true,
+		},
+		{
+			name:    "return error when condition is invalid",
+			text:    "rule: allow users:read when subject.org ==",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAccessPolicy(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, got.Rules, tt.wantRules)
+		})
+	}
+}
+
+func TestAccessCondition_Holds(t *testing.T) {
+	var resource Attributes
+	if err := json.Unmarshal([]byte(`{
+		"id": "user_002",
+		"org": "acme",
+		"level": 3,
+		"tags": ["internal"],
+		"owner": {"org": "acme"}
+	}`), &resource); err != nil {
+		t.Fatal(err)
+	}
+	req := AccessRequest{
+		Subject: Attributes{
+			"id":             "user_001",
+			"org":            "acme",
+			"roles":          []string{"manager"},
+			"clearance":      5,
+			"email_verified": true,
+		},
+		Action:   "users:update",
+		Resource: resource,
+	}
+	tests := []struct {
+		name      string
+		condition string
+		want      bool
+		wantErr   bool
+	}{
+		{
+			name:      "compare attributes",
+			condition: `resource.org == subject.org`,
+			want:      true,
+		},
+		{
+			name:      "find value in list",
+			condition: `"manager" in subject.roles and "internal" in resource.tags`,
+			want:      true,
+		},
+		{
+			name:      "find attribute in list literal",
+			condition: `action in ["users:read", "users:update"]`,
+			want:      true,
+		},
+		{
+			name:      "find substring",
+			condition: `"users:" in action`,
+			want:      true,
+		},
+		{
+			name:      "compare numbers of different types",
+			condition: `subject.clearance >= resource.level and resource.level == 3`,
+			want:      true,
+		},
+		{
+			name:      "read nested attribute",
+			condition: `resource.owner.org == "acme"`,
+			want:      true,
+		},
+		{
+			name:      "read missing attribute as null",
+			condition: `subject.team == null and not ("a" in subject.team)`,
+			want:      true,
+		},
+		{
+			name:      "use boolean attribute",
+			condition: `subject.email_verified and not subject.id == resource.id`,
+			want:      true,
+		},
+		{
+			name:      "prefer and over or",
+			condition: `true or false and false`,
+			want:      true,
+		},
+		{
+			name:      "group with parentheses",
+			condition: `(true or false) and false`,
+			want:      false,
+		},
+		{
+			name:      "return error when ordering different types",
+			condition: `subject.clearance > "3"`,
+			wantErr:   true,
+		},
+		{
+			name:      "return error when condition is not a boolean",
+			condition: `subject.org`,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseAccessCondition(tt.condition)
+			if !assert.NoError(t, err) {
+				return
+			}
+			got, err := c.Holds(req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAccessPolicy_Decide(t *testing.T) {
+	policy, err := ParseAccessPolicy(`
+manager-org-update: allow users:update
+  when "manager" in subject.roles and resource.org == subject.org
+self: allow users:* when resource.id == subject.id
+no-self-delete: deny users:delete when resource.id == subject.id
+broken: deny users:read when subject.level > "high"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	manager := Attributes{"id": "user_001", "org": "acme", "roles": []string{"manager"}}
+	tests := []struct {
+		name       string
+		req        AccessRequest
+		want       AccessDecision
+		wantErrors int
+	}{
+		{
+			name: "allow by matching rule",
+			req: AccessRequest{
+				Subject:  manager,
+				Action:   "users:update",
+				Resource: Attributes{"id": "user_002", "org": "acme"},
+			},
+			want: AccessDecision{Allowed: true, Rule: "manager-org-update"},
+		},
+		{
+			name: "deny when no rule matches",
+			req: AccessRequest{
+				Subject:  manager,
+				Action:   "users:update",
+				Resource: Attributes{"id": "user_002", "org": "other"},
+			},
+			want: AccessDecision{Allowed: false},
+		},
+		{
+			name: "deny overrides allow",
+			req: AccessRequest{
+				Subject:  manager,
+				Action:   "users:delete",
+				Resource: Attributes{"id": "user_001"},
+			},
+			want: AccessDecision{Allowed: false, Rule: "no-self-delete"},
+		},
+		{
+			name: "deny when deny condition fails",
+			req: AccessRequest{
+				Subject:  manager,
+				Action:   "users:read",
+				Resource: Attributes{"id": "user_001"},
+			},
+			want:       AccessDecision{Allowed: false, Rule: "broken"},
+			wantErrors: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.Decide(tt.req)
+			assert.Equal(t, tt.want.Allowed, got.Allowed)
+			assert.Equal(t, tt.want.Rule, got.Rule)
+			assert.Len(t, got.Errors, tt.wantErrors)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuthLevel is the strength of an authentication, reported as the acr
+// claim.
+type AuthLevel int
+
+const (
+	// AuthLevelPassword is a single factor, such as a password or an
+	// emailed code.
+	AuthLevelPassword AuthLevel = iota
+	// AuthLevelMFA is a password together with a second factor, or a
+	// user-verified passkey.
+	AuthLevelMFA
+)
+
+func (l AuthLevel) String() string {
+	return [...]string{
+		"pwd",
+		"mfa",
+	}[l]
+}
+
+func ParseAuthLevel(v string) (AuthLevel, error) {
+	switch strings.ToLower(v) {
+	default:
+		return 0, fmt.Errorf("invalid auth level: %s", v)
+	case "pwd":
+		return AuthLevelPassword, nil
+	case "mfa":
+		return AuthLevelMFA, nil
+	}
+}
+
+// Authentication methods as registered for the amr claim by RFC 8176,
+// except AuthMethodEmail, which RFC 8176 has no value for.
+const (
+	AuthMethodPassword    = "pwd"
+	AuthMethodOTP         = "otp"
+	AuthMethodHardwareKey = "hwk"
+	AuthMethodMFA         = "mfa"
+	// AuthMethodEmail is a code or link sent to the user's email address.
+	AuthMethodEmail = "email"
+)
+
+// Authentication is how and when a user proved their identity.
+type Authentication struct {
+	User     *User
+	Level    AuthLevel
+	Methods  []string
+	AuthTime time.Time
+	// AvailableLevel is the highest level the user's enrolled factors can
+	// reach. Requirements above it are relaxed to it, so that users
+	// without a second factor are not locked out.
+	AvailableLevel AuthLevel
+	// Roles are the user's roles when the request was authenticated.
+	Roles Roles
+}
+
+// NewAuthentication derives the level from methods, which are MFA when
+// more than one was used.
+func NewAuthentication(user *User, authTime time.Time, methods ...string) *Authentication {
+	level := AuthLevelPassword
+	if len(methods) > 1 {
+		level = AuthLevelMFA
+		methods = append(methods, AuthMethodMFA)
+	}
+	return &Authentication{
+		User:           user,
+		Level:          level,
+		Methods:        methods,
+		AuthTime:       authTime,
+		AvailableLevel: level,
+	}
+}
+
+// Satisfies reports whether the authentication is at least level, as far as
+// available, and, when maxAge is not zero, happened within maxAge before
+// now.
+func (a *Authentication) Satisfies(level AuthLevel, maxAge time.Duration, now time.Time) bool {
+	if level > a.AvailableLevel {
+		level = a.AvailableLevel
+	}
+	if a.Level < level {
+		return false
+	}
+	if maxAge > 0 && now.Sub(a.AuthTime) > maxAge {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAuthLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    AuthLevel
+		wantErr bool
+	}{
+		{name: `return password when value is "pwd"`, v: "pwd", want: AuthLevelPassword},
+		{name: `return mfa when value is "mfa"`, v: "mfa", want: AuthLevelMFA},
+		{name: `return mfa when value is "MFA"`, v: "MFA", want: AuthLevelMFA},
+		{name: "return error when value is unknown", v: "mfa2", wantErr: true},
+		{name: "return error when value is empty", v: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAuthLevel(tt.v)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "ParseAuthLevel() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func TestNewAuthentication(t *testing.T) {
+	user := &User{ID: "test_user_id_001"}
+	now := time.Unix(1700000000, 0)
+
+	got := NewAuthentication(user, now, AuthMethodPassword)
+	assert.Equal(t, AuthLevelPassword, got.Level)
+	assert.Equal(t, []string{AuthMethodPassword}, got.Methods)
+
+	got = NewAuthentication(user, now, AuthMethodPassword, AuthMethodOTP)
+	assert.Equal(t, AuthLevelMFA, got.Level)
+	assert.Equal(t, []string{AuthMethodPassword, AuthMethodOTP, AuthMethodMFA}, got.Methods)
+}
+
+func TestAuthentication_Satisfies(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name   string
+		auth   *Authentication
+		level  AuthLevel
+		maxAge time.Duration
+		want   bool
+	}{
+		{
+			name:  "return true when level is enough",
+			auth:  &Authentication{Level: AuthLevelMFA, AvailableLevel: AuthLevelMFA, AuthTime: now.Add(-time.Hour)},
+			level: AuthLevelMFA,
+			want:  true,
+		},
+		{
+			name:  "return false when level is too low",
+			auth:  &Authentication{Level: AuthLevelPassword, AvailableLevel: AuthLevelMFA, AuthTime: now},
+			level: AuthLevelMFA,
+			want:  false,
+		},
+		{
+			name:  "return true when level is the highest available",
+			auth:  &Authentication{Level: AuthLevelPassword, AvailableLevel: AuthLevelPassword, AuthTime: now},
+			level: AuthLevelMFA,
+			want:  true,
+		},
+		{
+			name:   "return true when authenticated within max age",
+			auth:   &Authentication{Level: AuthLevelMFA, AvailableLevel: AuthLevelMFA, AuthTime: now.Add(-5 * time.Minute)},
+			level:  AuthLevelMFA,
+			maxAge: 5 * time.Minute,
+			want:   true,
+		},
+		{
+			name:   "return false when authenticated before max age",
+			auth:   &Authentication{Level: AuthLevelMFA, AvailableLevel: AuthLevelMFA, AuthTime: now.Add(-6 * time.Minute)},
+			level:  AuthLevelPassword,
+			maxAge: 5 * time.Minute,
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.auth.Satisfies(tt.level, tt.maxAge, now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
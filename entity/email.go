@@ -3,8 +3,62 @@ package entity
 import (
 	"errors"
 	"fmt"
+	"net/netip"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	maxEmailLength     = 254
+	maxLocalPartLength = 64
+	maxDomainLength    = 253
+)
+
+var (
+	ErrEmailNoAtSign         = errors.New("missing @")
+	ErrEmailTooLong          = errors.New("too long")
+	ErrEmailInvalidLocalPart = errors.New("invalid local part")
+	ErrEmailInvalidDomain    = errors.New("invalid domain")
+)
+
+var emailIDNA = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.StrictDomainName(true),
+	idna.VerifyDNSLength(true),
+)
+
+type EmailLocalPartPolicy int
+
+const (
+	EmailLocalPartPolicyCaseInsensitive EmailLocalPartPolicy = iota
+	EmailLocalPartPolicyCaseSensitive
 )
 
+func (p EmailLocalPartPolicy) String() string {
+	return [...]string{
+		"case_insensitive",
+		"case_sensitive",
+	}[p]
+}
+
+func ParseEmailLocalPartPolicy(v string) (EmailLocalPartPolicy, error) {
+	s := strings.ToLower(strings.ReplaceAll(v, "-", "_"))
+	switch s {
+	default:
+		return 0, fmt.Errorf("invalid email local part policy: %s", v)
+	case "case_insensitive":
+		return EmailLocalPartPolicyCaseInsensitive, nil
+	case "case_sensitive":
+		return EmailLocalPartPolicyCaseSensitive, nil
+	}
+}
+
+// Email is an addr-spec as defined by RFC 5322, extended with UTF-8
+// local parts and domains by RFC 6531.
 type Email string
 
 func ParseEmail(v string) (Email, error) {
@@ -19,9 +73,176 @@ func (e Email) String() string {
 	return string(e)
 }
 
+func (e Email) LocalPart() string {
+	local, _, _ := splitEmail(string(e))
+	return local
+}
+
+func (e Email) Domain() string {
+	_, domain, _ := splitEmail(string(e))
+	return domain
+}
+
 func (e Email) Validate() error {
 	if len(e) == 0 {
 		return errors.New("empty")
 	}
+	if !utf8.ValidString(string(e)) {
+		return errors.New("invalid utf-8")
+	}
+	local, domain, ok := splitEmail(string(e))
+	if !ok {
+		return ErrEmailNoAtSign
+	}
+	if err := validateEmailLocalPart(local); err != nil {
+		return err
+	}
+	asciiDomain, err := toASCIIEmailDomain(domain)
+	if err != nil {
+		return err
+	}
+	if len(local)+1+len(asciiDomain) > maxEmailLength {
+		return ErrEmailTooLong
+	}
+	return nil
+}
+
+// Normalize returns the form used to decide whether two addresses belong
+// to the same mailbox. The domain is always case folded and converted to
+// its ASCII (punycode) form, and the local part is NFC normalized and case
+// folded according to policy.
+func (e Email) Normalize(policy EmailLocalPartPolicy) (Email, error) {
+	if err := e.Validate(); err != nil {
+		return "", fmt.Errorf("invalid email: %w", err)
+	}
+	local, domain, _ := splitEmail(string(e))
+	asciiDomain, err := toASCIIEmailDomain(domain)
+	if err != nil {
+		return "", err
+	}
+	local = norm.NFC.String(local)
+	if policy == EmailLocalPartPolicyCaseInsensitive {
+		local = strings.ToLower(local)
+	}
+	return Email(local + "@" + asciiDomain), nil
+}
+
+// splitEmail splits at the last "@" since a quoted local part may itself
+// contain one.
+func splitEmail(v string) (local string, domain string, ok bool) {
+	i := strings.LastIndexByte(v, '@')
+	if i < 0 {
+		return "", "", false
+	}
+	return v[:i], v[i+1:], true
+}
+
+func validateEmailLocalPart(local string) error {
+	if len(local) == 0 || len(local) > maxLocalPartLength {
+		return ErrEmailInvalidLocalPart
+	}
+	if strings.HasPrefix(local, `"`) {
+		return validateEmailQuotedString(local)
+	}
+	return validateEmailDotAtom(local)
+}
+
+func validateEmailDotAtom(v string) error {
+	for _, atom := range strings.Split(v, ".") {
+		if len(atom) == 0 {
+			return ErrEmailInvalidLocalPart
+		}
+		for _, r := range atom {
+			if !isEmailAtext(r) {
+				return ErrEmailInvalidLocalPart
+			}
+		}
+	}
+	return nil
+}
+
+func validateEmailQuotedString(v string) error {
+	if len(v) < 2 || !strings.HasSuffix(v, `"`) {
+		return ErrEmailInvalidLocalPart
+	}
+	inner := v[1 : len(v)-1]
+	escaped := false
+	for _, r := range inner {
+		switch {
+		case escaped:
+			// quoted-pair = "\" (VCHAR / WSP)
+			if !(r == ' ' || r == '\t' || (r >= 0x21 && r <= 0x7e) || r >= utf8.RuneSelf) {
+				return ErrEmailInvalidLocalPart
+			}
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			return ErrEmailInvalidLocalPart
+		case r == ' ' || r == '\t' || r >= utf8.RuneSelf:
+		case r >= 0x21 && r <= 0x7e:
+		default:
+			return ErrEmailInvalidLocalPart
+		}
+	}
+	if escaped {
+		return ErrEmailInvalidLocalPart
+	}
+	return nil
+}
+
+func isEmailAtext(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	case strings.ContainsRune("!#$%&'*+-/=?^_`{|}~", r):
+		return true
+	case r >= utf8.RuneSelf:
+		// UTF8-non-ascii (RFC 6531)
+		return r != utf8.RuneError
+	default:
+		return false
+	}
+}
+
+// toASCIIEmailDomain validates the domain and returns its lower case ASCII
+// form. Domain literals such as "[192.0.2.1]" or "[IPv6:2001:db8::1]" are
+// returned as is.
+func toASCIIEmailDomain(domain string) (string, error) {
+	if len(domain) == 0 {
+		return "", ErrEmailInvalidDomain
+	}
+	if strings.HasPrefix(domain, "[") {
+		if err := validateEmailDomainLiteral(domain); err != nil {
+			return "", err
+		}
+		return strings.ToLower(domain), nil
+	}
+	ascii, err := emailIDNA.ToASCII(domain)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrEmailInvalidDomain, err)
+	}
+	if len(ascii) > maxDomainLength || strings.HasSuffix(ascii, ".") {
+		return "", ErrEmailInvalidDomain
+	}
+	return strings.ToLower(ascii), nil
+}
+
+func validateEmailDomainLiteral(domain string) error {
+	if !strings.HasSuffix(domain, "]") {
+		return ErrEmailInvalidDomain
+	}
+	literal := domain[1 : len(domain)-1]
+	if v6, ok := strings.CutPrefix(literal, "IPv6:"); ok {
+		addr, err := netip.ParseAddr(v6)
+		if err != nil || !addr.Is6() {
+			return ErrEmailInvalidDomain
+		}
+		return nil
+	}
+	addr, err := netip.ParseAddr(literal)
+	if err != nil || !addr.Is4() {
+		return ErrEmailInvalidDomain
+	}
 	return nil
 }
@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEmail(t *testing.T) {
+	tests := []struct {
+		name      string
+		v         string
+		assertion assert.ErrorAssertionFunc
+	}{
+		{name: "simple address", v: "foo@example.com", assertion: assert.NoError},
+		{name: "dot-atom with special characters", v: "a.b+c!#$%&'*/=?^_`{|}~-@example.com", assertion: assert.NoError},
+		{name: "quoted local part", v: `"john doe"@example.com`, assertion: assert.NoError},
+		{name: "quoted local part containing @", v: `"a@b"@example.com`, assertion: assert.NoError},
+		{name: "quoted local part with quoted-pair", v: `"a\"b"@example.com`, assertion: assert.NoError},
+		{name: "internationalized local part", v: "用户@example.com", assertion: assert.NoError},
+		{name: "internationalized domain", v: "foo@bücher.de", assertion: assert.NoError},
+		{name: "punycode domain", v: "foo@xn--bcher-kva.de", assertion: assert.NoError},
+		{name: "IPv4 domain literal", v: "foo@[192.0.2.1]", assertion: assert.NoError},
+		{name: "IPv6 domain literal", v: "foo@[IPv6:2001:db8::1]", assertion: assert.NoError},
+		{name: "empty", v: "", assertion: assert.Error},
+		{name: "no at sign", v: "foo", assertion: assert.Error},
+		{name: "empty local part", v: "@example.com", assertion: assert.Error},
+		{name: "empty domain", v: "foo@", assertion: assert.Error},
+		{name: "leading dot", v: ".foo@example.com", assertion: assert.Error},
+		{name: "consecutive dots", v: "foo..bar@example.com", assertion: assert.Error},
+		{name: "unquoted space", v: "foo bar@example.com", assertion: assert.Error},
+		{name: "unterminated quote", v: `"foo@example.com`, assertion: assert.Error},
+		{name: "domain with underscore", v: "foo@exa_mple.com", assertion: assert.Error},
+		{name: "domain with empty label", v: "foo@example..com", assertion: assert.Error},
+		{name: "domain label starting with hyphen", v: "foo@-example.com", assertion: assert.Error},
+		{name: "invalid domain literal", v: "foo@[300.0.0.1]", assertion: assert.Error},
+		{name: "too long local part", v: strings.Repeat("a", 65) + "@example.com", assertion: assert.Error},
+		{name: "too long address", v: strings.Repeat("a", 64) + "@" + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 63) + ".com", assertion: assert.Error},
+		{name: "invalid utf-8", v: "foo\xff@example.com", assertion: assert.Error},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseEmail(tt.v)
+			tt.assertion(t, err)
+		})
+	}
+}
+
+func TestEmail_Normalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		e      Email
+		policy EmailLocalPartPolicy
+		want   Email
+	}{
+		{
+			name:   "fold whole address when case insensitive",
+			e:      "Foo@Example.COM",
+			policy: EmailLocalPartPolicyCaseInsensitive,
+			want:   "foo@example.com",
+		},
+		{
+			name:   "fold only domain when case sensitive",
+			e:      "Foo@Example.COM",
+			policy: EmailLocalPartPolicyCaseSensitive,
+			want:   "Foo@example.com",
+		},
+		{
+			name:   "convert internationalized domain to punycode",
+			e:      "foo@BÜCHER.de",
+			policy: EmailLocalPartPolicyCaseInsensitive,
+			want:   "foo@xn--bcher-kva.de",
+		},
+		{
+			name:   "compose local part to NFC",
+			e:      "café@example.com",
+			policy: EmailLocalPartPolicyCaseSensitive,
+			want:   "café@example.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.e.Normalize(tt.policy)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "Email.Normalize() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func TestParseEmailLocalPartPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		v       string
+		want    EmailLocalPartPolicy
+		wantErr bool
+	}{
+		{name: `return case sensitive when value is "case_sensitive"`, v: "case_sensitive", want: EmailLocalPartPolicyCaseSensitive},
+		{name: `return case sensitive when value is "case-sensitive"`, v: "case-sensitive", want: EmailLocalPartPolicyCaseSensitive},
+		{name: `return case insensitive when value is "case_insensitive"`, v: "case_insensitive", want: EmailLocalPartPolicyCaseInsensitive},
+		{name: `return case insensitive when value is "Case-Insensitive"`, v: "Case-Insensitive", want: EmailLocalPartPolicyCaseInsensitive},
+		{name: "return error when value is unknown", v: "unknown", wantErr: true},
+		{name: "return error when value is empty", v: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseEmailLocalPartPolicy(tt.v)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "ParseEmailLocalPartPolicy() = %v, want %v", got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// ErrGroupCycle refuses nesting a group in itself, directly or not.
+var ErrGroupCycle = errors.New("group would be nested in itself")
+
+// Group gathers users and other groups, whose members are then members of
+// the group too.
+type Group struct {
+	ID          ID
+	Name        string
+	Description string
+}
+
+var groupNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ParseGroupName accepts lower-case letters, digits, "_" and "-", up to 64
+// characters, as group names are reported as claims.
+func ParseGroupName(v string) (string, error) {
+	if !groupNamePattern.MatchString(v) {
+		return "", fmt.Errorf("invalid group name: %s", v)
+	}
+	return v, nil
+}
+
+type Groups []*Group
+
+func (gs Groups) Names() []string {
+	names := make([]string, 0, len(gs))
+	for _, g := range gs {
+		names = append(names, g.Name)
+	}
+	return names
+}
+
+func (gs Groups) Contains(id ID) bool {
+	for _, g := range gs {
+		if g.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// GroupMembers are the direct members of a group.
+type GroupMembers struct {
+	UserIDs   []ID
+	Subgroups Groups
+}
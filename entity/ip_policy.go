@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// IPPolicy decides which client addresses may make requests. Denied
+// addresses are refused even when allowed. Without any allowed prefix,
+// every address not denied is permitted.
+type IPPolicy struct {
+	Allow []netip.Prefix
+	Deny  []netip.Prefix
+}
+
+// ParseIPPrefix parses a CIDR such as "192.0.2.0/24", or a single address
+// as a prefix of its full length.
+func ParseIPPrefix(v string) (netip.Prefix, error) {
+	v = strings.TrimSpace(v)
+	if strings.Contains(v, "/") {
+		prefix, err := netip.ParsePrefix(v)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid ip prefix: %s", v)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(v)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid ip prefix: %s", v)
+	}
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func (p IPPolicy) Permits(addr netip.Addr) bool {
+	// IPv4 clients may come as IPv4-mapped IPv6 addresses.
+	addr = addr.Unmap()
+	for _, prefix := range p.Deny {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, prefix := range p.Allow {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
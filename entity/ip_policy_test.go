@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIPPrefix(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    netip.Prefix
+		wantErr bool
+	}{
+		{
+			name:  "return cidr",
+			value: "192.0.2.0/24",
+			want:  netip.MustParsePrefix("192.0.2.0/24"),
+		},
+		{
+			name:  "return masked cidr",
+			value: "192.0.2.10/24",
+			want:  netip.MustParsePrefix("192.0.2.0/24"),
+		},
+		{
+			name:  "return ipv4 address as prefix",
+			value: "192.0.2.10",
+			want:  netip.MustParsePrefix("192.0.2.10/32"),
+		},
+		{
+			name:  "return ipv6 address as prefix",
+			value: "2001:db8::1",
+			want:  netip.MustParsePrefix("2001:db8::1/128"),
+		},
+		{
+			name:    "return error when value is invalid",
+			value:   "192.0.2.0/33",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIPPrefix(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIPPolicy_Permits(t *testing.T) {
+	policy := IPPolicy{
+		Allow: []netip.Prefix{
+			netip.MustParsePrefix("192.0.2.0/24"),
+			netip.MustParsePrefix("2001:db8::/32"),
+		},
+		Deny: []netip.Prefix{
+			netip.MustParsePrefix("192.0.2.128/25"),
+		},
+	}
+	tests := []struct {
+		name   string
+		policy IPPolicy
+		addr   string
+		want   bool
+	}{
+		{
+			name:   "permit allowed address",
+			policy: policy,
+			addr:   "192.0.2.1",
+			want:   true,
+		},
+		{
+			name:   "permit allowed ipv4-mapped address",
+			policy: policy,
+			addr:   "::ffff:192.0.2.1",
+			want:   true,
+		},
+		{
+			name:   "permit allowed ipv6 address",
+			policy: policy,
+			addr:   "2001:db8::1",
+			want:   true,
+		},
+		{
+			name:   "refuse denied address even when allowed",
+			policy: policy,
+			addr:   "192.0.2.200",
+			want:   false,
+		},
+		{
+			name:   "refuse address not allowed",
+			policy: policy,
+			addr:   "198.51.100.1",
+			want:   false,
+		},
+		{
+			name: "permit address not denied without allowed prefixes",
+			policy: IPPolicy{
+				Deny: policy.Deny,
+			},
+			addr: "198.51.100.1",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.policy.Permits(netip.MustParseAddr(tt.addr)))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package entity
+
+import "time"
+
+// LoginFailures are the failed logins of a user not yet forgotten.
+type LoginFailures struct {
+	Count        int
+	LastFailedAt time.Time
+}
+
+// LoginLockoutPolicy slows down guessing against an account, and then
+// locks it for a while.
+type LoginLockoutPolicy struct {
+	// BaseDelay is the wait after the first failure. Each further failure
+	// doubles it, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Threshold is the number of failures that locks the account for
+	// Duration. Failures are forgotten once Duration passes without
+	// another.
+	Threshold int
+	Duration  time.Duration
+}
+
+// Count returns the failures still counted at now.
+func (p *LoginLockoutPolicy) Count(failures *LoginFailures, now time.Time) int {
+	if failures == nil || failures.Count == 0 {
+		return 0
+	}
+	if !now.Before(failures.LastFailedAt.Add(p.Duration)) {
+		return 0
+	}
+	return failures.Count
+}
+
+// NextAttemptAt returns the time before which logins are refused, or the
+// zero time when they are not.
+func (p *LoginLockoutPolicy) NextAttemptAt(failures *LoginFailures, now time.Time) time.Time {
+	count := p.Count(failures, now)
+	if count == 0 {
+		return time.Time{}
+	}
+	if p.Threshold > 0 && count >= p.Threshold {
+		return failures.LastFailedAt.Add(p.Duration)
+	}
+	delay := p.BaseDelay
+	for i := 1; i < count && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return failures.LastFailedAt.Add(delay)
+}
+
+// Refuses reports whether a login at now is refused.
+func (p *LoginLockoutPolicy) Refuses(failures *LoginFailures, now time.Time) bool {
+	return now.Before(p.NextAttemptAt(failures, now))
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginLockoutPolicy_NextAttemptAt(t *testing.T) {
+	policy := &LoginLockoutPolicy{
+		BaseDelay: time.Second,
+		MaxDelay:  30 * time.Second,
+		Threshold: 10,
+		Duration:  15 * time.Minute,
+	}
+	lastFailedAt := time.Unix(1700000000, 0)
+	tests := []struct {
+		name     string
+		failures *LoginFailures
+		now      time.Time
+		want     time.Time
+	}{
+		{
+			name: "return zero time without failures",
+			now:  lastFailedAt,
+			want: time.Time{},
+		},
+		{
+			name:     "return base delay after first failure",
+			failures: &LoginFailures{Count: 1, LastFailedAt: lastFailedAt},
+			now:      lastFailedAt,
+			want:     lastFailedAt.Add(time.Second),
+		},
+		{
+			name:     "return doubled delay after each failure",
+			failures: &LoginFailures{Count: 4, LastFailedAt: lastFailedAt},
+			now:      lastFailedAt,
+			want:     lastFailedAt.Add(8 * time.Second),
+		},
+		{
+			name:     "return max delay when doubled delay exceeds it",
+			failures: &LoginFailures{Count: 9, LastFailedAt: lastFailedAt},
+			now:      lastFailedAt,
+			want:     lastFailedAt.Add(30 * time.Second),
+		},
+		{
+			name:     "return end of lockout at threshold",
+			failures: &LoginFailures{Count: 10, LastFailedAt: lastFailedAt},
+			now:      lastFailedAt,
+			want:     lastFailedAt.Add(15 * time.Minute),
+		},
+		{
+			name:     "return zero time once failures are forgotten",
+			failures: &LoginFailures{Count: 10, LastFailedAt: lastFailedAt},
+			now:      lastFailedAt.Add(15 * time.Minute),
+			want:     time.Time{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := policy.NextAttemptAt(tt.failures, tt.now)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// bcrypt ignores every byte after the 72nd.
+const BcryptMaxPasswordBytes = 72
+
+const minPersonalInfoLength = 3
+
+type PasswordRule int
+
+const (
+	PasswordRuleMinLength PasswordRule = iota
+	PasswordRuleMaxLength
+	PasswordRuleCharacterClasses
+	PasswordRuleForbiddenSubstring
+	PasswordRuleCommonPassword
+	PasswordRuleBreachedPassword
+	PasswordRuleReusedPassword
+)
+
+func (r PasswordRule) String() string {
+	return [...]string{
+		"min_length",
+		"max_length",
+		"character_classes",
+		"forbidden_substring",
+		"common_password",
+		"breached_password",
+		"reused_password",
+	}[r]
+}
+
+type PasswordPolicyViolation struct {
+	Rule    PasswordRule
+	Message string
+}
+
+type PasswordPolicyError struct {
+	Violations []PasswordPolicyViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	msgs := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		msgs = append(msgs, v.Message)
+	}
+	return "password policy violation: " + strings.Join(msgs, ", ")
+}
+
+// PasswordPolicySubject is the account a password is being set for. Its
+// attributes must not appear in the password.
+type PasswordPolicySubject struct {
+	Email Email
+	Name  string
+}
+
+type PasswordPolicy struct {
+	// MinLength is counted in characters.
+	MinLength int
+	// MaxBytes is counted in bytes since that is what hashers limit.
+	MaxBytes int
+	// MinCharacterClasses is the number of distinct classes among lower
+	// case, upper case, digits and symbols that must be present.
+	MinCharacterClasses int
+	ForbidPersonalInfo  bool
+	ForbiddenSubstrings []string
+	CommonPasswords     map[string]struct{}
+	// HistorySize is how many of the most recent passwords, the current one
+	// included, may not be reused. It needs the stored hashes so it is
+	// enforced when hashing rather than by Validate.
+	HistorySize int
+}
+
+func NewCommonPasswords(passwords []string) map[string]struct{} {
+	m := make(map[string]struct{}, len(passwords))
+	for _, p := range passwords {
+		p = strings.TrimSpace(p)
+		if len(p) == 0 {
+			continue
+		}
+		m[strings.ToLower(p)] = struct{}{}
+	}
+	return m
+}
+
+func (p *PasswordPolicy) Validate(password Password, subject PasswordPolicySubject) error {
+	var violations []PasswordPolicyViolation
+	v := password.String()
+
+	if p.MinLength > 0 && utf8.RuneCountInString(v) < p.MinLength {
+		violations = append(violations, PasswordPolicyViolation{
+			Rule:    PasswordRuleMinLength,
+			Message: fmt.Sprintf("must be at least %d characters", p.MinLength),
+		})
+	}
+	if p.MaxBytes > 0 && len(v) > p.MaxBytes {
+		violations = append(violations, PasswordPolicyViolation{
+			Rule:    PasswordRuleMaxLength,
+			Message: fmt.Sprintf("must be at most %d bytes", p.MaxBytes),
+		})
+	}
+	if p.MinCharacterClasses > 0 && countCharacterClasses(v) < p.MinCharacterClasses {
+		violations = append(violations, PasswordPolicyViolation{
+			Rule:    PasswordRuleCharacterClasses,
+			Message: fmt.Sprintf("must contain at least %d of lower case, upper case, digits and symbols", p.MinCharacterClasses),
+		})
+	}
+	lower := strings.ToLower(v)
+	for _, s := range p.forbiddenSubstrings(subject) {
+		if strings.Contains(lower, s) {
+			violations = append(violations, PasswordPolicyViolation{
+				Rule:    PasswordRuleForbiddenSubstring,
+				Message: "must not contain personal information or forbidden words",
+			})
+			break
+		}
+	}
+	if _, ok := p.CommonPasswords[lower]; ok {
+		violations = append(violations, PasswordPolicyViolation{
+			Rule:    PasswordRuleCommonPassword,
+			Message: "must not be a commonly used password",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{
+			Violations: violations,
+		}
+	}
+	return nil
+}
+
+func (p *PasswordPolicy) forbiddenSubstrings(subject PasswordPolicySubject) []string {
+	var subs []string
+	for _, s := range p.ForbiddenSubstrings {
+		if len(s) > 0 {
+			subs = append(subs, strings.ToLower(s))
+		}
+	}
+	if !p.ForbidPersonalInfo {
+		return subs
+	}
+	candidates := []string{subject.Email.LocalPart()}
+	candidates = append(candidates, strings.FieldsFunc(subject.Name, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})...)
+	for _, c := range candidates {
+		if utf8.RuneCountInString(c) >= minPersonalInfoLength {
+			subs = append(subs, strings.ToLower(c))
+		}
+	}
+	return subs
+}
+
+func countCharacterClasses(v string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range v {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
@@ -0,0 +1,92 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := &PasswordPolicy{
+		MinLength:           8,
+		MaxBytes:            BcryptMaxPasswordBytes,
+		MinCharacterClasses: 2,
+		ForbidPersonalInfo:  true,
+		ForbiddenSubstrings: []string{"acme"},
+		CommonPasswords:     NewCommonPasswords([]string{"password1", "qwerty123"}),
+	}
+	subject := PasswordPolicySubject{
+		Email: "alice.smith@example.com",
+		Name:  "Alice Smith",
+	}
+	tests := []struct {
+		name     string
+		password Password
+		want     []PasswordRule
+	}{
+		{
+			name:     "return no error when password satisfies policy",
+			password: "correct horse battery staple",
+			want:     nil,
+		},
+		{
+			name:     "return min length violation",
+			password: "ab1",
+			want:     []PasswordRule{PasswordRuleMinLength},
+		},
+		{
+			name:     "return max length violation",
+			password: Password(strings.Repeat("a1", 37)),
+			want:     []PasswordRule{PasswordRuleMaxLength},
+		},
+		{
+			name:     "return character classes violation",
+			password: "abcdefghij",
+			want:     []PasswordRule{PasswordRuleCharacterClasses},
+		},
+		{
+			name:     "return forbidden substring violation for name",
+			password: "iamSMITH-2024",
+			want:     []PasswordRule{PasswordRuleForbiddenSubstring},
+		},
+		{
+			name:     "return forbidden substring violation for email local part",
+			password: "xalice.smithx1",
+			want:     []PasswordRule{PasswordRuleForbiddenSubstring},
+		},
+		{
+			name:     "return forbidden substring violation for configured word",
+			password: "Acme-rocks-42",
+			want:     []PasswordRule{PasswordRuleForbiddenSubstring},
+		},
+		{
+			name:     "return common password violation case insensitively",
+			password: "PassWord1",
+			want:     []PasswordRule{PasswordRuleCommonPassword},
+		},
+		{
+			name:     "return every violated rule",
+			password: "alice",
+			want:     []PasswordRule{PasswordRuleMinLength, PasswordRuleCharacterClasses, PasswordRuleForbiddenSubstring},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password, subject)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var pErr *PasswordPolicyError
+			if !assert.ErrorAs(t, err, &pErr) {
+				return
+			}
+			var got []PasswordRule
+			for _, v := range pErr.Violations {
+				got = append(got, v.Rule)
+			}
+			assert.Equal(t, tt.want, got, "PasswordPolicy.Validate() = %v, want %v", got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimit allows Limit requests per Period. It is a token bucket of Limit
+// tokens, refilled evenly over Period, so bursts up to Limit are allowed.
+type RateLimit struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRateLimit parses "<limit>/<period>", such as "10/1m".
+func ParseRateLimit(v string) (RateLimit, error) {
+	limitValue, periodValue, ok := strings.Cut(strings.TrimSpace(v), "/")
+	if !ok {
+		return RateLimit{}, fmt.Errorf("invalid rate limit: %s", v)
+	}
+	limit, err := strconv.Atoi(limitValue)
+	if err != nil || limit < 1 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit: %s", v)
+	}
+	period, err := time.ParseDuration(periodValue)
+	if err != nil || period <= 0 {
+		return RateLimit{}, fmt.Errorf("invalid rate limit: %s", v)
+	}
+	return RateLimit{
+		Limit:  limit,
+		Period: period,
+	}, nil
+}
+
+func (l RateLimit) String() string {
+	return fmt.Sprintf("%d/%s", l.Limit, l.Period)
+}
+
+// TokenBucket is the state of a RateLimit for one key.
+type TokenBucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is the time until the bucket is full again.
+	ResetAfter time.Duration
+	// RetryAfter is the time until a token is available, when none is.
+	RetryAfter time.Duration
+}
+
+// Take refills bucket up to now and takes a token from it if one is
+// available. A nil bucket is full.
+func (l RateLimit) Take(bucket *TokenBucket, now time.Time) (*TokenBucket, *RateLimitResult) {
+	limit := float64(l.Limit)
+	perToken := l.Period / time.Duration(l.Limit)
+	tokens := limit
+	if bucket != nil {
+		tokens = bucket.Tokens
+		if elapsed := now.Sub(bucket.UpdatedAt); elapsed > 0 {
+			tokens += float64(elapsed) / float64(perToken)
+		}
+		tokens = math.Min(tokens, limit)
+	}
+	result := &RateLimitResult{
+		Limit: l.Limit,
+	}
+	if tokens >= 1 {
+		tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+	result.Remaining = int(tokens)
+	result.ResetAfter = time.Duration((limit - tokens) * float64(perToken))
+
+	return &TokenBucket{
+		Tokens:    tokens,
+		UpdatedAt: now,
+	}, result
+}
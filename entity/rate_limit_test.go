@@ -0,0 +1,111 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRateLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    RateLimit
+		wantErr bool
+	}{
+		{
+			name:  "return rate limit",
+			value: "10/1m",
+			want:  RateLimit{Limit: 10, Period: time.Minute},
+		},
+		{
+			name:    "return error without period",
+			value:   "10",
+			wantErr: true,
+		},
+		{
+			name:    "return error when limit is not positive",
+			value:   "0/1m",
+			wantErr: true,
+		},
+		{
+			name:    "return error when period is invalid",
+			value:   "10/minute",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRateLimit(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestRateLimit_Take(t *testing.T) {
+	limit := RateLimit{Limit: 10, Period: 10 * time.Second}
+	now := time.Unix(1700000000, 0)
+	tests := []struct {
+		name       string
+		bucket     *TokenBucket
+		wantBucket *TokenBucket
+		want       *RateLimitResult
+	}{
+		{
+			name:       "take token from new bucket",
+			wantBucket: &TokenBucket{Tokens: 9, UpdatedAt: now},
+			want: &RateLimitResult{
+				Allowed:    true,
+				Limit:      10,
+				Remaining:  9,
+				ResetAfter: time.Second,
+			},
+		},
+		{
+			name:       "take refilled token",
+			bucket:     &TokenBucket{Tokens: 0, UpdatedAt: now.Add(-2 * time.Second)},
+			wantBucket: &TokenBucket{Tokens: 1, UpdatedAt: now},
+			want: &RateLimitResult{
+				Allowed:    true,
+				Limit:      10,
+				Remaining:  1,
+				ResetAfter: 9 * time.Second,
+			},
+		},
+		{
+			name:       "refill up to limit",
+			bucket:     &TokenBucket{Tokens: 5, UpdatedAt: now.Add(-time.Hour)},
+			wantBucket: &TokenBucket{Tokens: 9, UpdatedAt: now},
+			want: &RateLimitResult{
+				Allowed:    true,
+				Limit:      10,
+				Remaining:  9,
+				ResetAfter: time.Second,
+			},
+		},
+		{
+			name:       "deny when bucket is empty",
+			bucket:     &TokenBucket{Tokens: 0.5, UpdatedAt: now},
+			wantBucket: &TokenBucket{Tokens: 0.5, UpdatedAt: now},
+			want: &RateLimitResult{
+				Limit:      10,
+				Remaining:  0,
+				ResetAfter: 9500 * time.Millisecond,
+				RetryAfter: 500 * time.Millisecond,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotBucket, got := limit.Take(tt.bucket, now)
+			assert.Equal(t, tt.wantBucket, gotBucket)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
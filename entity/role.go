@@ -0,0 +1,94 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Permission allows an action on a kind of resource, as
+// "<resource>:<action>".
+type Permission string
+
+const (
+	PermissionUsersRead   Permission = "users:read"
+	PermissionUsersWrite  Permission = "users:write"
+	PermissionRolesRead   Permission = "roles:read"
+	PermissionRolesWrite  Permission = "roles:write"
+	PermissionGroupsRead  Permission = "groups:read"
+	PermissionGroupsWrite Permission = "groups:write"
+	// PermissionAccessDecide lets services ask for access decisions.
+	PermissionAccessDecide Permission = "access:decide"
+)
+
+// Permissions are all the permissions routes are guarded by.
+var Permissions = []Permission{
+	PermissionUsersRead,
+	PermissionUsersWrite,
+	PermissionRolesRead,
+	PermissionRolesWrite,
+	PermissionGroupsRead,
+	PermissionGroupsWrite,
+	PermissionAccessDecide,
+}
+
+func ParsePermission(v string) (Permission, error) {
+	for _, p := range Permissions {
+		if string(p) == v {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown permission: %s", v)
+}
+
+func (p Permission) String() string {
+	return string(p)
+}
+
+// RoleAdmin is granted every permission by the initial schema.
+const RoleAdmin = "admin"
+
+var roleNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ParseRoleName accepts lower-case letters, digits, "_" and "-", up to 64
+// characters.
+func ParseRoleName(v string) (string, error) {
+	if !roleNamePattern.MatchString(v) {
+		return "", fmt.Errorf("invalid role name: %s", v)
+	}
+	return v, nil
+}
+
+// Role is a named set of permissions assigned to users.
+type Role struct {
+	Name        string
+	Permissions []Permission
+}
+
+func (r *Role) Grants(permission Permission) bool {
+	for _, p := range r.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type Roles []*Role
+
+// Grants reports whether any of the roles grants permission.
+func (rs Roles) Grants(permission Permission) bool {
+	for _, r := range rs {
+		if r.Grants(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rs Roles) Names() []string {
+	names := make([]string, 0, len(rs))
+	for _, r := range rs {
+		names = append(names, r.Name)
+	}
+	return names
+}
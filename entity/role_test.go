@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRoleName(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:  "return role name",
+			value: "support-staff_2",
+		},
+		{
+			name:    "return error when name has upper-case letters",
+			value:   "Admin",
+			wantErr: true,
+		},
+		{
+			name:    "return error when name is empty",
+			value:   "",
+			wantErr: true,
+		},
+		{
+			name:    "return error when name starts with symbol",
+			value:   "-admin",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRoleName(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.value, got)
+		})
+	}
+}
+
+func TestRoles_Grants(t *testing.T) {
+	roles := Roles{
+		{Name: "viewer", Permissions: []Permission{PermissionUsersRead}},
+		{Name: "role-admin", Permissions: []Permission{PermissionRolesRead, PermissionRolesWrite}},
+	}
+	assert.True(t, roles.Grants(PermissionUsersRead))
+	assert.True(t, roles.Grants(PermissionRolesWrite))
+	assert.False(t, roles.Grants(PermissionUsersWrite))
+	assert.False(t, Roles(nil).Grants(PermissionUsersRead))
+}
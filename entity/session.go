@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+// Session keeps a browser logged in between requests. It records the
+// authentication that created it, so that step-up requirements still apply
+// to its requests.
+type Session struct {
+	ID             ID
+	UserID         ID
+	Level          AuthLevel
+	AvailableLevel AuthLevel
+	Methods        []string
+	AuthTime       time.Time
+	// UserAgent and IPAddress are those of the login, shown to the user to
+	// recognize their devices.
+	UserAgent  string
+	IPAddress  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	// ExpiresAt is the absolute end of the session, however active it is.
+	ExpiresAt time.Time
+}
+
+type Sessions []*Session
+
+// Authentication returns the authentication of the session's user.
+func (s *Session) Authentication(user *User) *Authentication {
+	return &Authentication{
+		User:           user,
+		Level:          s.Level,
+		Methods:        s.Methods,
+		AuthTime:       s.AuthTime,
+		AvailableLevel: s.AvailableLevel,
+	}
+}
@@ -0,0 +1,12 @@
+package entity
+
+// TOTP is a user's authenticator app enrollment. It only protects logins
+// once Confirmed, which proves the user's device produces matching codes.
+type TOTP struct {
+	UserID    ID
+	Secret    []byte
+	Confirmed bool
+	// LastUsedStep is the time step of the last accepted code. Codes for it
+	// and earlier steps are rejected so that a code can only be used once.
+	LastUsedStep int64
+}
@@ -1,9 +1,10 @@
 package entity
 
 type User struct {
-	ID    ID
-	Name  string
-	Email Email
+	ID            ID
+	Name          string
+	Email         Email
+	EmailVerified bool
 }
 
 type Users []*User
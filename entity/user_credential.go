@@ -52,6 +52,9 @@ type UserCredential struct {
 	UserID   ID
 	Email    Email
 	Password HashedPassword
+	// PasswordBreached is set at login when the password is found in a
+	// breach corpus, and cleared when the password changes.
+	PasswordBreached bool
 }
 
 type UserCredentials []*UserCredential
@@ -0,0 +1,49 @@
+package entity
+
+import "time"
+
+type WebAuthnCeremony int
+
+const (
+	WebAuthnCeremonyRegistration WebAuthnCeremony = iota
+	WebAuthnCeremonyAuthentication
+)
+
+func (c WebAuthnCeremony) String() string {
+	return [...]string{
+		"registration",
+		"authentication",
+	}[c]
+}
+
+func ParseWebAuthnCeremony(v string) WebAuthnCeremony {
+	switch v {
+	default:
+		return WebAuthnCeremonyRegistration
+	case "authentication":
+		return WebAuthnCeremonyAuthentication
+	}
+}
+
+// WebAuthnCredential is a passkey or security key registered by a user.
+type WebAuthnCredential struct {
+	ID           ID
+	UserID       ID
+	CredentialID []byte
+	// PublicKey is the COSE encoded credential public key.
+	PublicKey []byte
+	SignCount uint32
+}
+
+type WebAuthnCredentials []*WebAuthnCredential
+
+// WebAuthnChallenge is issued at the start of a ceremony and can be used to
+// finish it only once. UserID is nil for logins that let the authenticator
+// pick a discoverable credential.
+type WebAuthnChallenge struct {
+	ID        ID
+	UserID    *ID
+	Ceremony  WebAuthnCeremony
+	Challenge []byte
+	ExpiresAt time.Time
+}
@@ -0,0 +1,27 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type AccessPolicyConfig struct {
+	// File holds the access rules, as entity.ParseAccessPolicy reads them.
+	// Access decisions are disabled when empty.
+	File string `envconfig:"FILE"`
+	// ReloadInterval is how often File is checked for changes.
+	ReloadInterval time.Duration `envconfig:"RELOAD_INTERVAL" default:"30s"`
+}
+
+func LoadAccessPolicyConfig() (*AccessPolicyConfig, error) {
+	var c AccessPolicyConfig
+	if err := envconfig.Process("ACCESS_POLICY", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *AccessPolicyConfig) Enabled() bool {
+	return len(c.File) > 0
+}
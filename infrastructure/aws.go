@@ -0,0 +1,44 @@
+package infrastructure
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/kelseyhightower/envconfig"
+)
+
+type AWSConfig struct {
+	Region string `envconfig:"REGION" required:"true"`
+	// Requests are sent unsigned when no access key is set, which only
+	// suits public buckets.
+	AccessKeyID     string `envconfig:"ACCESS_KEY_ID"`
+	SecretAccessKey string `envconfig:"SECRET_ACCESS_KEY"`
+	SessionToken    string `envconfig:"SESSION_TOKEN"`
+}
+
+func LoadAWSConfig() (*AWSConfig, error) {
+	var c AWSConfig
+	if err := envconfig.Process("AWS", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *AWSConfig) GetConfig() aws.Config {
+	conf := aws.Config{
+		Region: c.Region,
+	}
+	if len(c.AccessKeyID) > 0 {
+		credentials := aws.Credentials{
+			AccessKeyID:     c.AccessKeyID,
+			SecretAccessKey: c.SecretAccessKey,
+			SessionToken:    c.SessionToken,
+		}
+		conf.Credentials = aws.NewCredentialsCache(aws.CredentialsProviderFunc(
+			func(ctx context.Context) (aws.Credentials, error) {
+				return credentials, nil
+			},
+		))
+	}
+	return conf
+}
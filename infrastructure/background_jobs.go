@@ -0,0 +1,20 @@
+package infrastructure
+
+import (
+	"github.com/kelseyhightower/envconfig"
+)
+
+type BackgroundJobsConfig struct {
+	// Workers run jobs such as mail sends. Jobs beyond QueueSize waiting
+	// ones are dropped and logged.
+	Workers   int `envconfig:"WORKERS" default:"4"`
+	QueueSize int `envconfig:"QUEUE_SIZE" default:"1000"`
+}
+
+func LoadBackgroundJobsConfig() (*BackgroundJobsConfig, error) {
+	var c BackgroundJobsConfig
+	if err := envconfig.Process("BACKGROUND_JOBS", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,21 @@
+package infrastructure
+
+import (
+	"github.com/kelseyhightower/envconfig"
+)
+
+type CSRFConfig struct {
+	Secret string `envconfig:"SECRET" required:"true"`
+	// TrustedOrigins are the origins of the web apps using sessions, such
+	// as "https://example.com". Without any, only the API's own host is
+	// trusted.
+	TrustedOrigins []string `envconfig:"TRUSTED_ORIGINS"`
+}
+
+func LoadCSRFConfig() (*CSRFConfig, error) {
+	var c CSRFConfig
+	if err := envconfig.Process("CSRF", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,27 @@
+package infrastructure
+
+import (
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type EmailConfig struct {
+	LocalPartPolicy string `envconfig:"LOCAL_PART_POLICY" default:"case_insensitive"`
+}
+
+func LoadEmailConfig() (*EmailConfig, error) {
+	var c EmailConfig
+	if err := envconfig.Process("EMAIL", &c); err != nil {
+		return nil, err
+	}
+	if _, err := entity.ParseEmailLocalPartPolicy(c.LocalPartPolicy); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetLocalPartPolicy returns the policy LoadEmailConfig has validated.
+func (c *EmailConfig) GetLocalPartPolicy() entity.EmailLocalPartPolicy {
+	policy, _ := entity.ParseEmailLocalPartPolicy(c.LocalPartPolicy)
+	return policy
+}
@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type EmailLoginConfig struct {
+	// Enabled also allows creating users without a password.
+	Enabled     bool          `envconfig:"ENABLED" default:"false"`
+	Secret      string        `envconfig:"SECRET"`
+	TTL         time.Duration `envconfig:"TTL" default:"10m"`
+	MaxAttempts int           `envconfig:"MAX_ATTEMPTS" default:"5"`
+	URL         string        `envconfig:"URL"`
+}
+
+func LoadEmailLoginConfig() (*EmailLoginConfig, error) {
+	var c EmailLoginConfig
+	if err := envconfig.Process("EMAIL_LOGIN", &c); err != nil {
+		return nil, err
+	}
+	if c.Enabled && (len(c.Secret) == 0 || len(c.URL) == 0) {
+		return nil, errors.New("email login requires EMAIL_LOGIN_SECRET and EMAIL_LOGIN_URL")
+	}
+	return &c, nil
+}
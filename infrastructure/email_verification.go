@@ -0,0 +1,22 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type EmailVerificationConfig struct {
+	Secret   string        `envconfig:"SECRET" required:"true"`
+	TTL      time.Duration `envconfig:"TTL" default:"24h"`
+	URL      string        `envconfig:"URL" required:"true"`
+	Required bool          `envconfig:"REQUIRED" default:"false"`
+}
+
+func LoadEmailVerificationConfig() (*EmailVerificationConfig, error) {
+	var c EmailVerificationConfig
+	if err := envconfig.Process("EMAIL_VERIFICATION", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
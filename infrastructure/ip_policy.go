@@ -0,0 +1,64 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type IPPolicyConfig struct {
+	// File holds the rules, one "<path prefix> allow|deny <cidr>..." per
+	// line. IP policies are disabled when empty.
+	File string `envconfig:"FILE"`
+	// ReloadInterval is how often File is checked for changes.
+	ReloadInterval time.Duration `envconfig:"RELOAD_INTERVAL" default:"30s"`
+}
+
+func LoadIPPolicyConfig() (*IPPolicyConfig, error) {
+	var c IPPolicyConfig
+	if err := envconfig.Process("IP_POLICY", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *IPPolicyConfig) Enabled() bool {
+	return len(c.File) > 0
+}
+
+// WatchFile reads filename every interval until ctx is done, and calls
+// onChange with its content whenever it differs from initial. Files that
+// can not be read are reported to onError and checked again later.
+func WatchFile(
+	ctx context.Context,
+	filename string,
+	initial []byte,
+	interval time.Duration,
+	onChange func(b []byte),
+	onError func(err error),
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	current := initial
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			onError(err)
+			continue
+		}
+		if bytes.Equal(b, current) {
+			continue
+		}
+		current = b
+		onChange(b)
+	}
+}
@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+)
+
+type KeyringConfig struct {
+	Algorithm string `envconfig:"ALGORITHM" default:"aes-256-gcm"`
+	// KeyID selects the key new data is encrypted with.
+	KeyID string `envconfig:"KEY_ID"`
+	// Keys are "<id>:<base64 key>" pairs separated by commas. Keys must be
+	// 32 bytes and retired keys have to stay until everything encrypted
+	// with them has been re-encrypted.
+	Keys map[string]string `envconfig:"KEYS"`
+}
+
+func LoadKeyringConfig() (*KeyringConfig, error) {
+	var c KeyringConfig
+	if err := envconfig.Process("CRYPTO_KEYRING", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *KeyringConfig) Enabled() bool {
+	return len(c.Keys) > 0
+}
+
+// GetKeyring returns nil when no keys are configured.
+func (c *KeyringConfig) GetKeyring() (*crypto.Keyring, error) {
+	if !c.Enabled() {
+		return nil, nil
+	}
+	algorithm := crypto.ParseAEADAlgorithm(c.Algorithm)
+	if algorithm == crypto.AEADAlgorithmUnsupported {
+		return nil, fmt.Errorf("unsupported keyring algorithm: %s", c.Algorithm)
+	}
+	keys := make(map[string][]byte, len(c.Keys))
+	for id, encoded := range c.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keyring key %s: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return crypto.NewKeyring(algorithm, c.KeyID, keys)
+}
@@ -0,0 +1,76 @@
+package infrastructure
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/adapter/kms"
+)
+
+type LocalKMSConfig struct {
+	// KeyID selects the master key new data keys are wrapped with.
+	KeyID string `envconfig:"KEY_ID" default:"local"`
+	// MasterKeys are "<id>:<base64 key>" pairs separated by commas. Keys
+	// must be 32 bytes and retired keys have to stay until every data key
+	// wrapped with them has been re-encrypted. MasterKeysFile is read
+	// instead when set, and holds the same pairs separated by commas or
+	// newlines.
+	MasterKeys     map[string]string `envconfig:"MASTER_KEYS"`
+	MasterKeysFile string            `envconfig:"MASTER_KEYS_FILE"`
+}
+
+func LoadLocalKMSConfig() (*LocalKMSConfig, error) {
+	var c LocalKMSConfig
+	if err := envconfig.Process("KMS_LOCAL", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *LocalKMSConfig) GetLocalKMS() (*kms.LocalKMS, error) {
+	encodedKeys := c.MasterKeys
+	if len(c.MasterKeysFile) > 0 {
+		b, err := os.ReadFile(c.MasterKeysFile)
+		if err != nil {
+			return nil, err
+		}
+		encodedKeys, err = parseMasterKeys(string(b))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(encodedKeys) == 0 {
+		return nil, errors.New("local KMS master keys are not configured")
+	}
+	keys := make(map[string][]byte, len(encodedKeys))
+	for id, encoded := range encodedKeys {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid local KMS master key %s: %w", id, err)
+		}
+		keys[id] = key
+	}
+	return kms.NewLocalKMS(c.KeyID, keys)
+}
+
+func parseMasterKeys(text string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, pair := range strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '\n'
+	}) {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+		id, key, ok := strings.Cut(pair, ":")
+		if !ok || len(id) == 0 {
+			return nil, errors.New("invalid local KMS master key: expected <id>:<key>")
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
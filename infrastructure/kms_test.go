@@ -0,0 +1,116 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalKMSConfig_GetLocalKMS(t *testing.T) {
+	k1 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	k2 := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	ctx := context.Background()
+
+	old, err := (&LocalKMSConfig{
+		KeyID:      "k1",
+		MasterKeys: map[string]string{"k1": k1},
+	}).GetLocalKMS()
+	if !assert.NoError(t, err) {
+		return
+	}
+	dataKey, err := old.GenerateDataKey(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	file := filepath.Join(t.TempDir(), "master_keys")
+	if err := os.WriteFile(file, []byte("k1:"+k1+"\nk2:"+k2+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		config  LocalKMSConfig
+		wantErr bool
+	}{
+		{
+			name: "decrypt data key under old master key from environment",
+			config: LocalKMSConfig{
+				KeyID:      "k2",
+				MasterKeys: map[string]string{"k1": k1, "k2": k2},
+			},
+		},
+		{
+			name: "decrypt data key under old master key from file",
+			config: LocalKMSConfig{
+				KeyID:          "k2",
+				MasterKeysFile: file,
+			},
+		},
+		{
+			name: "return error when current key is not configured",
+			config: LocalKMSConfig{
+				KeyID:      "k3",
+				MasterKeys: map[string]string{"k1": k1, "k2": k2},
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error when no key is configured",
+			config: LocalKMSConfig{
+				KeyID: "k1",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.GetLocalKMS()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if !assert.NoError(t, err) {
+				return
+			}
+			plaintext, err := got.DecryptDataKey(ctx, dataKey.EncryptedKey)
+			assert.NoError(t, err)
+			assert.Equal(t, dataKey.Plaintext, plaintext)
+		})
+	}
+}
+
+func Test_parseMasterKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name: "return keys separated by commas and newlines",
+			text: "k1:a2V5MQ==, k2:a2V5Mg==\nk3:a2V5Mw==\n",
+			want: map[string]string{"k1": "a2V5MQ==", "k2": "a2V5Mg==", "k3": "a2V5Mw=="},
+		},
+		{
+			name:    "return error without key ID",
+			text:    "a2V5MQ==",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseMasterKeys(tt.text)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
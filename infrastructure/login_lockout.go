@@ -0,0 +1,33 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type LoginLockoutConfig struct {
+	Enabled   bool          `envconfig:"ENABLED" default:"true"`
+	BaseDelay time.Duration `envconfig:"BASE_DELAY" default:"1s"`
+	MaxDelay  time.Duration `envconfig:"MAX_DELAY" default:"1m"`
+	Threshold int           `envconfig:"THRESHOLD" default:"10"`
+	Duration  time.Duration `envconfig:"DURATION" default:"15m"`
+}
+
+func LoadLoginLockoutConfig() (*LoginLockoutConfig, error) {
+	var c LoginLockoutConfig
+	if err := envconfig.Process("LOGIN_LOCKOUT", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *LoginLockoutConfig) GetPolicy() entity.LoginLockoutPolicy {
+	return entity.LoginLockoutPolicy{
+		BaseDelay: c.BaseDelay,
+		MaxDelay:  c.MaxDelay,
+		Threshold: c.Threshold,
+		Duration:  c.Duration,
+	}
+}
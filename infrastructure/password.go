@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"os"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+//go:embed data/common_passwords.txt
+var defaultCommonPasswords []byte
+
+type PasswordPolicyConfig struct {
+	MinLength           int      `envconfig:"MIN_LENGTH" default:"8"`
+	MaxBytes            int      `envconfig:"MAX_BYTES" default:"72"`
+	MinCharacterClasses int      `envconfig:"MIN_CHARACTER_CLASSES" default:"0"`
+	ForbidPersonalInfo  bool     `envconfig:"FORBID_PERSONAL_INFO" default:"true"`
+	ForbiddenSubstrings []string `envconfig:"FORBIDDEN_SUBSTRINGS"`
+	// CommonPasswordsFile replaces the built-in list with a newline
+	// separated file when set.
+	CommonPasswordsFile string `envconfig:"COMMON_PASSWORDS_FILE"`
+	HistorySize         int    `envconfig:"HISTORY_SIZE" default:"5"`
+}
+
+func LoadPasswordPolicyConfig() (*PasswordPolicyConfig, error) {
+	var c PasswordPolicyConfig
+	if err := envconfig.Process("PASSWORD_POLICY", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *PasswordPolicyConfig) GetPasswordPolicy() (*entity.PasswordPolicy, error) {
+	b := defaultCommonPasswords
+	if len(c.CommonPasswordsFile) > 0 {
+		var err error
+		b, err = os.ReadFile(c.CommonPasswordsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var passwords []string
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		passwords = append(passwords, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &entity.PasswordPolicy{
+		MinLength:           c.MinLength,
+		MaxBytes:            c.MaxBytes,
+		MinCharacterClasses: c.MinCharacterClasses,
+		ForbidPersonalInfo:  c.ForbidPersonalInfo,
+		ForbiddenSubstrings: c.ForbiddenSubstrings,
+		CommonPasswords:     entity.NewCommonPasswords(passwords),
+		HistorySize:         c.HistorySize,
+	}, nil
+}
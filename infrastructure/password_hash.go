@@ -0,0 +1,90 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+)
+
+type PasswordHashConfig struct {
+	// Algorithm hashes new passwords. Hashes of the other algorithms are
+	// still verified and upgraded on the next successful login.
+	Algorithm       string `envconfig:"ALGORITHM" default:"bcrypt"`
+	BcryptCost      int    `envconfig:"BCRYPT_COST" default:"10"`
+	Argon2idMemory  uint32 `envconfig:"ARGON2ID_MEMORY" default:"65536"`
+	Argon2idTime    uint32 `envconfig:"ARGON2ID_TIME" default:"3"`
+	Argon2idThreads uint8  `envconfig:"ARGON2ID_THREADS" default:"4"`
+	// Argon2id hashes with higher parameters are refused.
+	Argon2idMaxMemory  uint32 `envconfig:"ARGON2ID_MAX_MEMORY" default:"262144"`
+	Argon2idMaxTime    uint32 `envconfig:"ARGON2ID_MAX_TIME" default:"10"`
+	Argon2idMaxThreads uint8  `envconfig:"ARGON2ID_MAX_THREADS" default:"16"`
+	ScryptLogN         uint8  `envconfig:"SCRYPT_LOG_N" default:"15"`
+	ScryptR            int    `envconfig:"SCRYPT_R" default:"8"`
+	ScryptP            int    `envconfig:"SCRYPT_P" default:"1"`
+	// Scrypt hashes with higher parameters are refused.
+	ScryptMaxLogN uint8 `envconfig:"SCRYPT_MAX_LOG_N" default:"17"`
+	ScryptMaxR    int   `envconfig:"SCRYPT_MAX_R" default:"16"`
+	ScryptMaxP    int   `envconfig:"SCRYPT_MAX_P" default:"4"`
+}
+
+func LoadPasswordHashConfig() (*PasswordHashConfig, error) {
+	var c PasswordHashConfig
+	if err := envconfig.Process("PASSWORD_HASH", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetHashGenerators returns the configured algorithm first followed by the
+// others, including the legacy algorithms of imported users.
+func (c *PasswordHashConfig) GetHashGenerators() ([]crypto.PasswordHashGenerator, error) {
+	bcrypt, err := crypto.NewBcryptoHashGeneratorWithCost(c.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+	argon2idParams := crypto.DefaultArgon2idParams
+	argon2idParams.Memory = c.Argon2idMemory
+	argon2idParams.Time = c.Argon2idTime
+	argon2idParams.Threads = c.Argon2idThreads
+	argon2idParams.MaxMemory = c.Argon2idMaxMemory
+	argon2idParams.MaxTime = c.Argon2idMaxTime
+	argon2idParams.MaxThreads = c.Argon2idMaxThreads
+	argon2id, err := crypto.NewArgon2idHashGenerator(argon2idParams)
+	if err != nil {
+		return nil, err
+	}
+	scryptParams := crypto.DefaultScryptParams
+	scryptParams.LogN = c.ScryptLogN
+	scryptParams.R = c.ScryptR
+	scryptParams.P = c.ScryptP
+	scryptParams.MaxLogN = c.ScryptMaxLogN
+	scryptParams.MaxR = c.ScryptMaxR
+	scryptParams.MaxP = c.ScryptMaxP
+	scrypt, err := crypto.NewScryptHashGenerator(scryptParams)
+	if err != nil {
+		return nil, err
+	}
+
+	pbkdf2, err := crypto.NewPBKDF2HashGenerator(crypto.DefaultPBKDF2Iterations)
+	if err != nil {
+		return nil, err
+	}
+	phpass, err := crypto.NewPHPassHashGenerator(crypto.DefaultPHPassLog2Rounds)
+	if err != nil {
+		return nil, err
+	}
+	// Legacy algorithms only verify imported hashes, which are replaced on
+	// the next successful login.
+	legacy := []crypto.PasswordHashGenerator{pbkdf2, phpass, crypto.NewSaltedSHA256HashGenerator()}
+
+	all := []crypto.PasswordHashGenerator{bcrypt, argon2id, scrypt}
+	algorithm := crypto.ParseHashAlgorithm(c.Algorithm)
+	for i, g := range all {
+		if g.Algorithm() == algorithm {
+			all[0], all[i] = all[i], all[0]
+			return append(all, legacy...), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported password hash algorithm: %s", c.Algorithm)
+}
@@ -0,0 +1,39 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/adapter/crypto"
+)
+
+type PasswordPepperConfig struct {
+	// Enabled wraps new hashes with the CRYPTO_KEYRING keys. Hashes are
+	// stored unwrapped when false.
+	Enabled bool `envconfig:"ENABLED" default:"false"`
+	// LegacyKeys are the "<id>:<key>" AES-CFB pairs hashes were wrapped with
+	// before the keyring. They are only used to read those hashes until
+	// rewrap-passwords has moved them to the keyring.
+	LegacyKeys map[string]string `envconfig:"LEGACY_KEYS"`
+}
+
+func LoadPasswordPepperConfig() (*PasswordPepperConfig, error) {
+	var c PasswordPepperConfig
+	if err := envconfig.Process("PASSWORD_PEPPER", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *PasswordPepperConfig) GetLegacyCryptors() (map[string]crypto.Cryptor, error) {
+	cryptors := make(map[string]crypto.Cryptor, len(c.LegacyKeys))
+	for id, key := range c.LegacyKeys {
+		switch len(key) {
+		case 16, 24, 32:
+		default:
+			return nil, fmt.Errorf("invalid password pepper key length: %s", id)
+		}
+		cryptors[id] = crypto.NewAESCryptor(key)
+	}
+	return cryptors, nil
+}
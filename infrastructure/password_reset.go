@@ -0,0 +1,21 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type PasswordResetConfig struct {
+	Secret string        `envconfig:"SECRET" required:"true"`
+	TTL    time.Duration `envconfig:"TTL" default:"1h"`
+	URL    string        `envconfig:"URL" required:"true"`
+}
+
+func LoadPasswordResetConfig() (*PasswordResetConfig, error) {
+	var c PasswordResetConfig
+	if err := envconfig.Process("PASSWORD_RESET", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,41 @@
+package infrastructure
+
+import (
+	"errors"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type PwnedPasswordsConfig struct {
+	// File is a Pwned Passwords SHA-1 download sorted by hash. Screening is
+	// disabled when empty.
+	File string `envconfig:"FILE"`
+	// Source is where File is read from, "file" or "s3". S3 objects are
+	// read from Bucket whole into memory, so they suit trimmed down lists
+	// rather than the full download.
+	Source      string `envconfig:"SOURCE" default:"file"`
+	Bucket      string `envconfig:"BUCKET"`
+	MinCount    int    `envconfig:"MIN_COUNT" default:"1"`
+	FlagOnLogin bool   `envconfig:"FLAG_ON_LOGIN" default:"false"`
+}
+
+func LoadPwnedPasswordsConfig() (*PwnedPasswordsConfig, error) {
+	var c PwnedPasswordsConfig
+	if err := envconfig.Process("PWNED_PASSWORDS", &c); err != nil {
+		return nil, err
+	}
+	switch c.Source {
+	case "file":
+	case "s3":
+		if c.Enabled() && len(c.Bucket) == 0 {
+			return nil, errors.New("PWNED_PASSWORDS_BUCKET is required for s3 source")
+		}
+	default:
+		return nil, errors.New("PWNED_PASSWORDS_SOURCE must be file or s3")
+	}
+	return &c, nil
+}
+
+func (c *PwnedPasswordsConfig) Enabled() bool {
+	return len(c.File) > 0
+}
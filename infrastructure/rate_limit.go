@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"errors"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type RateLimitConfig struct {
+	Enabled bool `envconfig:"ENABLED" default:"true"`
+	// Store is where buckets are kept, "rdb" or "memory". Memory buckets
+	// suit a single instance only, since each instance counts on its own
+	// and every added instance multiplies the limits.
+	Store  string `envconfig:"STORE" default:"rdb"`
+	Shards int    `envconfig:"SHARDS" default:"64"`
+	// Rules are "<method> <path> <key> <limit>", where key is "ip",
+	// "account" or "authorization" and limit is such as "10/1m". The
+	// default "* * authorization" rule covers the Basic credentials any
+	// route accepts.
+	Rules []string `envconfig:"RULES" default:"* * authorization 60/1m,POST /sessions ip 20/1m,POST /sessions account 10/1m,POST /webauthn-assertions ip 20/1m,POST /email-logins ip 10/1m,POST /email-logins account 5/15m,PUT /email-logins/:id ip 20/1m,POST /password-resets ip 10/1m,POST /password-resets account 5/1h,PUT /users/:id/password ip 10/1m,POST /users ip 10/1h"`
+}
+
+func LoadRateLimitConfig() (*RateLimitConfig, error) {
+	var c RateLimitConfig
+	if err := envconfig.Process("RATE_LIMIT", &c); err != nil {
+		return nil, err
+	}
+	if c.Store != "rdb" && c.Store != "memory" {
+		return nil, errors.New("RATE_LIMIT_STORE must be rdb or memory")
+	}
+	return &c, nil
+}
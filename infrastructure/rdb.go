@@ -4,12 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
 	"github.com/mkaiho/go-auth-api/adapter/rdb"
 	"github.com/mkaiho/go-auth-api/usecase"
 )
 
+const mysqlErrDupEntry = 1062
+
 const (
 	DriverNameUnknown rdb.DriverName = ""
 	DriverNameMySQL   rdb.DriverName = "mysql"
@@ -81,11 +85,13 @@ func (rt *RDBTransaction) Select(ctx context.Context, dest interface{}, query st
 }
 
 func (rt *RDBTransaction) NamedExec(ctx context.Context, query string, arg interface{}) (sql.Result, error) {
-	return rt.tx.NamedExecContext(ctx, query, arg)
+	result, err := rt.tx.NamedExecContext(ctx, query, arg)
+	return result, translateExecError(err)
 }
 
 func (rt *RDBTransaction) Exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
-	return rt.tx.ExecContext(ctx, query, args...)
+	result, err := rt.tx.ExecContext(ctx, query, args...)
+	return result, translateExecError(err)
 }
 
 func (rt *RDBTransaction) Commit() error {
@@ -95,3 +101,11 @@ func (rt *RDBTransaction) Commit() error {
 func (rt *RDBTransaction) Rollback() error {
 	return rt.tx.Rollback()
 }
+
+func translateExecError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry {
+		return fmt.Errorf("%w: %s", usecase.ErrAlreadyExistsEntity, mysqlErr.Message)
+	}
+	return err
+}
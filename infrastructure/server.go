@@ -0,0 +1,20 @@
+package infrastructure
+
+import (
+	"github.com/kelseyhightower/envconfig"
+)
+
+type ServerConfig struct {
+	// TrustedProxies are the addresses or CIDRs of the load balancers in
+	// front of the server, whose X-Forwarded-For headers are believed.
+	// Without any, client addresses are those of the connections.
+	TrustedProxies []string `envconfig:"TRUSTED_PROXIES"`
+}
+
+func LoadServerConfig() (*ServerConfig, error) {
+	var c ServerConfig
+	if err := envconfig.Process("SERVER", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type SessionConfig struct {
+	// Store is where sessions are kept, "rdb" or "memory". Memory sessions
+	// suit a single instance only and are lost on restart.
+	Store           string        `envconfig:"STORE" default:"rdb"`
+	IdleTimeout     time.Duration `envconfig:"IDLE_TIMEOUT" default:"30m"`
+	AbsoluteTimeout time.Duration `envconfig:"ABSOLUTE_TIMEOUT" default:"12h"`
+	// CookieName defaults to a "__Host-" name, which browsers only accept
+	// from secure origins without a domain.
+	CookieName string `envconfig:"COOKIE_NAME" default:"__Host-session"`
+	// CSRFCookieName is readable by scripts, which echo its value in the
+	// X-CSRF-Token header.
+	CSRFCookieName string `envconfig:"CSRF_COOKIE_NAME" default:"__Host-csrf"`
+	CookieDomain   string `envconfig:"COOKIE_DOMAIN"`
+	CookieSecure   bool   `envconfig:"COOKIE_SECURE" default:"true"`
+	CookieSameSite string `envconfig:"COOKIE_SAME_SITE" default:"lax"`
+}
+
+func LoadSessionConfig() (*SessionConfig, error) {
+	var c SessionConfig
+	if err := envconfig.Process("SESSION", &c); err != nil {
+		return nil, err
+	}
+	if c.Store != "rdb" && c.Store != "memory" {
+		return nil, errors.New("SESSION_STORE must be rdb or memory")
+	}
+	if c.AbsoluteTimeout <= 0 {
+		return nil, errors.New("SESSION_ABSOLUTE_TIMEOUT must be positive")
+	}
+	for _, name := range []string{c.CookieName, c.CSRFCookieName} {
+		if strings.HasPrefix(name, "__Host-") && (!c.CookieSecure || len(c.CookieDomain) > 0) {
+			return nil, errors.New("__Host- session cookies require SESSION_COOKIE_SECURE and no SESSION_COOKIE_DOMAIN")
+		}
+	}
+	if c.GetCookieSameSite() == http.SameSiteNoneMode && !c.CookieSecure {
+		return nil, errors.New("SameSite=None session cookies require SESSION_COOKIE_SECURE")
+	}
+	return &c, nil
+}
+
+func (c *SessionConfig) GetCookieSameSite() http.SameSite {
+	switch strings.ToLower(c.CookieSameSite) {
+	default:
+		return http.SameSiteLaxMode
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	}
+}
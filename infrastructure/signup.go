@@ -0,0 +1,19 @@
+package infrastructure
+
+import (
+	"github.com/kelseyhightower/envconfig"
+)
+
+type SignupConfig struct {
+	// ConcealExistingEmails answers sign-ups with 202 whether or not the
+	// email has an account, and mails its owner instead of answering 409.
+	ConcealExistingEmails bool `envconfig:"CONCEAL_EXISTING_EMAILS" default:"false"`
+}
+
+func LoadSignupConfig() (*SignupConfig, error) {
+	var c SignupConfig
+	if err := envconfig.Process("SIGNUP", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,63 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/adapter/mail"
+)
+
+var _ mail.Client = (*SMTPClient)(nil)
+
+type SMTPConfig struct {
+	Host     string `envconfig:"HOST" required:"true"`
+	Port     int    `envconfig:"PORT" default:"587"`
+	User     string `envconfig:"USER"`
+	Password string `envconfig:"PASSWORD"`
+	From     string `envconfig:"FROM" required:"true"`
+}
+
+func LoadSMTPConfig() (*SMTPConfig, error) {
+	var c SMTPConfig
+	if err := envconfig.Process("SMTP", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+type SMTPClient struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPClient(conf *SMTPConfig) *SMTPClient {
+	var auth smtp.Auth
+	if len(conf.User) > 0 {
+		auth = smtp.PlainAuth("", conf.User, conf.Password, conf.Host)
+	}
+	return &SMTPClient{
+		addr: net.JoinHostPort(conf.Host, strconv.Itoa(conf.Port)),
+		from: conf.From,
+		auth: auth,
+	}
+}
+
+func (c *SMTPClient) Send(ctx context.Context, msg *mail.Message) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", c.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	return smtp.SendMail(c.addr, c.auth, c.from, msg.To, b.Bytes())
+}
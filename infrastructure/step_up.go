@@ -0,0 +1,33 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+// StepUpConfig is the authentication required to change a user's email,
+// password or second factors.
+type StepUpConfig struct {
+	// Level is an acr value, "pwd" or "mfa".
+	Level  string        `envconfig:"LEVEL" default:"mfa"`
+	MaxAge time.Duration `envconfig:"MAX_AGE" default:"5m"`
+}
+
+func LoadStepUpConfig() (*StepUpConfig, error) {
+	var c StepUpConfig
+	if err := envconfig.Process("STEP_UP", &c); err != nil {
+		return nil, err
+	}
+	if _, err := entity.ParseAuthLevel(c.Level); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// GetLevel returns the level LoadStepUpConfig has validated.
+func (c *StepUpConfig) GetLevel() entity.AuthLevel {
+	level, _ := entity.ParseAuthLevel(c.Level)
+	return level
+}
@@ -0,0 +1,47 @@
+package infrastructure
+
+import (
+	"testing"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadStepUpConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   string
+		want    entity.AuthLevel
+		wantErr bool
+	}{
+		{
+			name:  "return config with password level",
+			level: "pwd",
+			want:  entity.AuthLevelPassword,
+		},
+		{
+			name:  "return config with mfa level",
+			level: "mfa",
+			want:  entity.AuthLevelMFA,
+		},
+		{
+			name:    "return error when level is unknown",
+			level:   "mfa2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STEP_UP_LEVEL", tt.level)
+
+			got, err := LoadStepUpConfig()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got.GetLevel())
+			}
+		})
+	}
+}
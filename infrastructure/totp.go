@@ -0,0 +1,31 @@
+package infrastructure
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/adapter/otp"
+)
+
+type TOTPConfig struct {
+	// Enabled requires CRYPTO_KEYRING, which secrets are encrypted with.
+	// Disabling TOTP stops asking enrolled users for codes.
+	Enabled           bool          `envconfig:"ENABLED" default:"false"`
+	Issuer            string        `envconfig:"ISSUER" default:"go-auth-api"`
+	Digits            int           `envconfig:"DIGITS" default:"6"`
+	Period            time.Duration `envconfig:"PERIOD" default:"30s"`
+	Skew              int           `envconfig:"SKEW" default:"1"`
+	RecoveryCodeCount int           `envconfig:"RECOVERY_CODE_COUNT" default:"10"`
+}
+
+func LoadTOTPConfig() (*TOTPConfig, error) {
+	var c TOTPConfig
+	if err := envconfig.Process("TOTP", &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (c *TOTPConfig) GetTOTP() *otp.TOTP {
+	return otp.NewTOTP(c.Digits, c.Period, c.Skew)
+}
@@ -0,0 +1,35 @@
+package infrastructure
+
+import (
+	"errors"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/mkaiho/go-auth-api/adapter/webauthn"
+)
+
+type WebAuthnConfig struct {
+	Enabled bool `envconfig:"ENABLED" default:"false"`
+	// RPID is the domain credentials are scoped to, such as "example.com".
+	RPID   string `envconfig:"RP_ID"`
+	RPName string `envconfig:"RP_NAME" default:"go-auth-api"`
+	// Origins are the web origins allowed to run ceremonies, such as
+	// "https://example.com".
+	Origins      []string      `envconfig:"ORIGINS"`
+	ChallengeTTL time.Duration `envconfig:"CHALLENGE_TTL" default:"5m"`
+}
+
+func LoadWebAuthnConfig() (*WebAuthnConfig, error) {
+	var c WebAuthnConfig
+	if err := envconfig.Process("WEBAUTHN", &c); err != nil {
+		return nil, err
+	}
+	if c.Enabled && (len(c.RPID) == 0 || len(c.Origins) == 0) {
+		return nil, errors.New("WebAuthn requires WEBAUTHN_RP_ID and WEBAUTHN_ORIGINS")
+	}
+	return &c, nil
+}
+
+func (c *WebAuthnConfig) GetRelyingParty() *webauthn.RelyingParty {
+	return webauthn.NewRelyingParty(c.RPID, c.RPName, c.Origins)
+}
@@ -0,0 +1,133 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AEADCryptor is an autogenerated mock type for the AEADCryptor type
+type AEADCryptor struct {
+	mock.Mock
+}
+
+// Decrypt provides a mock function with given fields: ctx, ciphertext
+func (_m *AEADCryptor) Decrypt(ctx context.Context, ciphertext []byte) ([]byte, error) {
+	ret := _m.Called(ctx, ciphertext)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) ([]byte, error)); ok {
+		return rf(ctx, ciphertext)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) []byte); ok {
+		r0 = rf(ctx, ciphertext)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, ciphertext)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Encrypt provides a mock function with given fields: ctx, plaintext
+func (_m *AEADCryptor) Encrypt(ctx context.Context, plaintext []byte) ([]byte, error) {
+	ret := _m.Called(ctx, plaintext)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) ([]byte, error)); ok {
+		return rf(ctx, plaintext)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) []byte); ok {
+		r0 = rf(ctx, plaintext)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, plaintext)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Open provides a mock function with given fields: ctx, ciphertext, associatedData
+func (_m *AEADCryptor) Open(ctx context.Context, ciphertext []byte, associatedData []byte) ([]byte, error) {
+	ret := _m.Called(ctx, ciphertext, associatedData)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) ([]byte, error)); ok {
+		return rf(ctx, ciphertext, associatedData)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) []byte); ok {
+		r0 = rf(ctx, ciphertext, associatedData)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []byte) error); ok {
+		r1 = rf(ctx, ciphertext, associatedData)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Seal provides a mock function with given fields: ctx, plaintext, associatedData
+func (_m *AEADCryptor) Seal(ctx context.Context, plaintext []byte, associatedData []byte) ([]byte, error) {
+	ret := _m.Called(ctx, plaintext, associatedData)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) ([]byte, error)); ok {
+		return rf(ctx, plaintext, associatedData)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) []byte); ok {
+		r0 = rf(ctx, plaintext, associatedData)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, []byte) error); ok {
+		r1 = rf(ctx, plaintext, associatedData)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAEADCryptor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAEADCryptor creates a new instance of AEADCryptor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAEADCryptor(t mockConstructorTestingTNewAEADCryptor) *AEADCryptor {
+	mock := &AEADCryptor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
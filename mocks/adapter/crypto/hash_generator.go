@@ -13,13 +13,13 @@ type HashGenerator struct {
 	mock.Mock
 }
 
-// Compare provides a mock function with given fields: ctx, value, hashed
-func (_m *HashGenerator) Compare(ctx context.Context, value []byte, hashed []byte) error {
-	ret := _m.Called(ctx, value, hashed)
+// Compare provides a mock function with given fields: ctx, hashed, value
+func (_m *HashGenerator) Compare(ctx context.Context, hashed []byte, value []byte) error {
+	ret := _m.Called(ctx, hashed, value)
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) error); ok {
-		r0 = rf(ctx, value, hashed)
+		r0 = rf(ctx, hashed, value)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,134 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	crypto "crypto"
+
+	adaptercrypto "github.com/mkaiho/go-auth-api/adapter/crypto"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeyManager is an autogenerated mock type for the KeyManager type
+type KeyManager struct {
+	mock.Mock
+}
+
+// ConvertFormat provides a mock function with given fields: privateKey, format
+func (_m *KeyManager) ConvertFormat(privateKey crypto.Signer, format adaptercrypto.PrivateKeyFormat) ([]byte, error) {
+	ret := _m.Called(privateKey, format)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(crypto.Signer, adaptercrypto.PrivateKeyFormat) ([]byte, error)); ok {
+		return rf(privateKey, format)
+	}
+	if rf, ok := ret.Get(0).(func(crypto.Signer, adaptercrypto.PrivateKeyFormat) []byte); ok {
+		r0 = rf(privateKey, format)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(crypto.Signer, adaptercrypto.PrivateKeyFormat) error); ok {
+		r1 = rf(privateKey, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateKey provides a mock function with given fields: algorithm
+func (_m *KeyManager) GenerateKey(algorithm adaptercrypto.SignatureAlgorithm) (crypto.Signer, error) {
+	ret := _m.Called(algorithm)
+
+	var r0 crypto.Signer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(adaptercrypto.SignatureAlgorithm) (crypto.Signer, error)); ok {
+		return rf(algorithm)
+	}
+	if rf, ok := ret.Get(0).(func(adaptercrypto.SignatureAlgorithm) crypto.Signer); ok {
+		r0 = rf(algorithm)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(crypto.Signer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(adaptercrypto.SignatureAlgorithm) error); ok {
+		r1 = rf(algorithm)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadBytes provides a mock function with given fields: b, format
+func (_m *KeyManager) ReadBytes(b []byte, format adaptercrypto.PrivateKeyFormat) (crypto.Signer, error) {
+	ret := _m.Called(b, format)
+
+	var r0 crypto.Signer
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]byte, adaptercrypto.PrivateKeyFormat) (crypto.Signer, error)); ok {
+		return rf(b, format)
+	}
+	if rf, ok := ret.Get(0).(func([]byte, adaptercrypto.PrivateKeyFormat) crypto.Signer); ok {
+		r0 = rf(b, format)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(crypto.Signer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]byte, adaptercrypto.PrivateKeyFormat) error); ok {
+		r1 = rf(b, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReadFile provides a mock function with given fields: filename, format
+func (_m *KeyManager) ReadFile(filename string, format adaptercrypto.PrivateKeyFormat) (crypto.Signer, error) {
+	ret := _m.Called(filename, format)
+
+	var r0 crypto.Signer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, adaptercrypto.PrivateKeyFormat) (crypto.Signer, error)); ok {
+		return rf(filename, format)
+	}
+	if rf, ok := ret.Get(0).(func(string, adaptercrypto.PrivateKeyFormat) crypto.Signer); ok {
+		r0 = rf(filename, format)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(crypto.Signer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, adaptercrypto.PrivateKeyFormat) error); ok {
+		r1 = rf(filename, format)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewKeyManager interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewKeyManager creates a new instance of KeyManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewKeyManager(t mockConstructorTestingTNewKeyManager) *KeyManager {
+	mock := &KeyManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,69 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MACGenerator is an autogenerated mock type for the MACGenerator type
+type MACGenerator struct {
+	mock.Mock
+}
+
+// Generate provides a mock function with given fields: ctx, value
+func (_m *MACGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
+	ret := _m.Called(ctx, value)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) ([]byte, error)); ok {
+		return rf(ctx, value)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) []byte); ok {
+		r0 = rf(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, value, mac
+func (_m *MACGenerator) Verify(ctx context.Context, value []byte, mac []byte) error {
+	ret := _m.Called(ctx, value, mac)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) error); ok {
+		r0 = rf(ctx, value, mac)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewMACGenerator interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMACGenerator creates a new instance of MACGenerator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMACGenerator(t mockConstructorTestingTNewMACGenerator) *MACGenerator {
+	mock := &MACGenerator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,112 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	crypto "github.com/mkaiho/go-auth-api/adapter/crypto"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordHashGenerator is an autogenerated mock type for the PasswordHashGenerator type
+type PasswordHashGenerator struct {
+	mock.Mock
+}
+
+// Algorithm provides a mock function with given fields:
+func (_m *PasswordHashGenerator) Algorithm() crypto.HashAlgorithm {
+	ret := _m.Called()
+
+	var r0 crypto.HashAlgorithm
+	if rf, ok := ret.Get(0).(func() crypto.HashAlgorithm); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(crypto.HashAlgorithm)
+	}
+
+	return r0
+}
+
+// Compare provides a mock function with given fields: ctx, hashed, value
+func (_m *PasswordHashGenerator) Compare(ctx context.Context, hashed []byte, value []byte) error {
+	ret := _m.Called(ctx, hashed, value)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) error); ok {
+		r0 = rf(ctx, hashed, value)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Generate provides a mock function with given fields: ctx, value
+func (_m *PasswordHashGenerator) Generate(ctx context.Context, value []byte) ([]byte, error) {
+	ret := _m.Called(ctx, value)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) ([]byte, error)); ok {
+		return rf(ctx, value)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) []byte); ok {
+		r0 = rf(ctx, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, value)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Identify provides a mock function with given fields: hashed
+func (_m *PasswordHashGenerator) Identify(hashed []byte) bool {
+	ret := _m.Called(hashed)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func([]byte) bool); ok {
+		r0 = rf(hashed)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NeedsRehash provides a mock function with given fields: hashed
+func (_m *PasswordHashGenerator) NeedsRehash(hashed []byte) bool {
+	ret := _m.Called(hashed)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func([]byte) bool); ok {
+		r0 = rf(hashed)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPasswordHashGenerator interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordHashGenerator creates a new instance of PasswordHashGenerator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordHashGenerator(t mockConstructorTestingTNewPasswordHashGenerator) *PasswordHashGenerator {
+	mock := &PasswordHashGenerator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,101 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	crypto2 "crypto"
+
+	crypto "github.com/mkaiho/go-auth-api/adapter/crypto"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Signer is an autogenerated mock type for the Signer type
+type Signer struct {
+	mock.Mock
+}
+
+// Algorithm provides a mock function with given fields:
+func (_m *Signer) Algorithm() crypto.SignatureAlgorithm {
+	ret := _m.Called()
+
+	var r0 crypto.SignatureAlgorithm
+	if rf, ok := ret.Get(0).(func() crypto.SignatureAlgorithm); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(crypto.SignatureAlgorithm)
+	}
+
+	return r0
+}
+
+// PublicKey provides a mock function with given fields:
+func (_m *Signer) PublicKey() crypto2.PublicKey {
+	ret := _m.Called()
+
+	var r0 crypto2.PublicKey
+	if rf, ok := ret.Get(0).(func() crypto2.PublicKey); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(crypto2.PublicKey)
+		}
+	}
+
+	return r0
+}
+
+// Sign provides a mock function with given fields: ctx, message
+func (_m *Signer) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	ret := _m.Called(ctx, message)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) ([]byte, error)); ok {
+		return rf(ctx, message)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) []byte); ok {
+		r0 = rf(ctx, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, message, signature
+func (_m *Signer) Verify(ctx context.Context, message []byte, signature []byte) error {
+	ret := _m.Called(ctx, message, signature)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) error); ok {
+		r0 = rf(ctx, message, signature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSigner interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSigner creates a new instance of Signer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSigner(t mockConstructorTestingTNewSigner) *Signer {
+	mock := &Signer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	interactor "github.com/mkaiho/go-auth-api/usecase/interactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuthInteractor is an autogenerated mock type for the AuthInteractor type
+type AuthInteractor struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, input
+func (_m *AuthInteractor) Authenticate(ctx context.Context, input interactor.AuthenticateInput) (*entity.Authentication, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.Authentication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.AuthenticateInput) (*entity.Authentication, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.AuthenticateInput) *entity.Authentication); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Authentication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.AuthenticateInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAuthInteractor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAuthInteractor creates a new instance of AuthInteractor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAuthInteractor(t mockConstructorTestingTNewAuthInteractor) *AuthInteractor {
+	mock := &AuthInteractor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	interactor "github.com/mkaiho/go-auth-api/usecase/interactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailVerificationInteractor is an autogenerated mock type for the EmailVerificationInteractor type
+type EmailVerificationInteractor struct {
+	mock.Mock
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, input
+func (_m *EmailVerificationInteractor) VerifyEmail(ctx context.Context, input interactor.VerifyEmailInput) (*entity.User, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.VerifyEmailInput) (*entity.User, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.VerifyEmailInput) *entity.User); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.VerifyEmailInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEmailVerificationInteractor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEmailVerificationInteractor creates a new instance of EmailVerificationInteractor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEmailVerificationInteractor(t mockConstructorTestingTNewEmailVerificationInteractor) *EmailVerificationInteractor {
+	mock := &EmailVerificationInteractor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,72 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	interactor "github.com/mkaiho/go-auth-api/usecase/interactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordInteractor is an autogenerated mock type for the PasswordInteractor type
+type PasswordInteractor struct {
+	mock.Mock
+}
+
+// ChangePassword provides a mock function with given fields: ctx, input
+func (_m *PasswordInteractor) ChangePassword(ctx context.Context, input interactor.ChangePasswordInput) error {
+	ret := _m.Called(ctx, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.ChangePasswordInput) error); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestPasswordReset provides a mock function with given fields: ctx, input
+func (_m *PasswordInteractor) RequestPasswordReset(ctx context.Context, input interactor.RequestPasswordResetInput) error {
+	ret := _m.Called(ctx, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.RequestPasswordResetInput) error); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, input
+func (_m *PasswordInteractor) ResetPassword(ctx context.Context, input interactor.ResetPasswordInput) error {
+	ret := _m.Called(ctx, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.ResetPasswordInput) error); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPasswordInteractor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordInteractor creates a new instance of PasswordInteractor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordInteractor(t mockConstructorTestingTNewPasswordInteractor) *PasswordInteractor {
+	mock := &PasswordInteractor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	interactor "github.com/mkaiho/go-auth-api/usecase/interactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RateLimitInteractor is an autogenerated mock type for the RateLimitInteractor type
+type RateLimitInteractor struct {
+	mock.Mock
+}
+
+// Take provides a mock function with given fields: ctx, input
+func (_m *RateLimitInteractor) Take(ctx context.Context, input interactor.TakeRateLimitInput) (*entity.RateLimitResult, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.RateLimitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.TakeRateLimitInput) (*entity.RateLimitResult, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.TakeRateLimitInput) *entity.RateLimitResult); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RateLimitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.TakeRateLimitInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRateLimitInteractor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRateLimitInteractor creates a new instance of RateLimitInteractor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRateLimitInteractor(t mockConstructorTestingTNewRateLimitInteractor) *RateLimitInteractor {
+	mock := &RateLimitInteractor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,165 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	interactor "github.com/mkaiho/go-auth-api/usecase/interactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SessionInteractor is an autogenerated mock type for the SessionInteractor type
+type SessionInteractor struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: ctx, token
+func (_m *SessionInteractor) Authenticate(ctx context.Context, token string) (*interactor.SessionAuthentication, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *interactor.SessionAuthentication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*interactor.SessionAuthentication, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *interactor.SessionAuthentication); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*interactor.SessionAuthentication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, input
+func (_m *SessionInteractor) Create(ctx context.Context, input interactor.CreateSessionInput) (*interactor.CreatedSession, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *interactor.CreatedSession
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.CreateSessionInput) (*interactor.CreatedSession, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.CreateSessionInput) *interactor.CreatedSession); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*interactor.CreatedSession)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.CreateSessionInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *SessionInteractor) Delete(ctx context.Context, id entity.ID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// List provides a mock function with given fields: ctx, userID
+func (_m *SessionInteractor) List(ctx context.Context, userID entity.ID) (entity.Sessions, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 entity.Sessions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (entity.Sessions, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) entity.Sessions); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entity.Sessions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: ctx, input
+func (_m *SessionInteractor) Revoke(ctx context.Context, input interactor.RevokeSessionInput) error {
+	ret := _m.Called(ctx, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.RevokeSessionInput) error); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeOthers provides a mock function with given fields: ctx, input
+func (_m *SessionInteractor) RevokeOthers(ctx context.Context, input interactor.RevokeOtherSessionsInput) error {
+	ret := _m.Called(ctx, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.RevokeOtherSessionsInput) error); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyCSRFToken provides a mock function with given fields: ctx, sessionID, token
+func (_m *SessionInteractor) VerifyCSRFToken(ctx context.Context, sessionID entity.ID, token string) error {
+	ret := _m.Called(ctx, sessionID, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, string) error); ok {
+		r0 = rf(ctx, sessionID, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSessionInteractor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSessionInteractor creates a new instance of SessionInteractor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionInteractor(t mockConstructorTestingTNewSessionInteractor) *SessionInteractor {
+	mock := &SessionInteractor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,122 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	interactor "github.com/mkaiho/go-auth-api/usecase/interactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TOTPInteractor is an autogenerated mock type for the TOTPInteractor type
+type TOTPInteractor struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, input
+func (_m *TOTPInteractor) Confirm(ctx context.Context, input interactor.ConfirmTOTPInput) ([]string, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.ConfirmTOTPInput) ([]string, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.ConfirmTOTPInput) []string); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.ConfirmTOTPInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Disable provides a mock function with given fields: ctx, input
+func (_m *TOTPInteractor) Disable(ctx context.Context, input interactor.DisableTOTPInput) error {
+	ret := _m.Called(ctx, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.DisableTOTPInput) error); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enroll provides a mock function with given fields: ctx, input
+func (_m *TOTPInteractor) Enroll(ctx context.Context, input interactor.EnrollTOTPInput) (*interactor.EnrollTOTPOutput, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *interactor.EnrollTOTPOutput
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.EnrollTOTPInput) (*interactor.EnrollTOTPOutput, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.EnrollTOTPInput) *interactor.EnrollTOTPOutput); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*interactor.EnrollTOTPOutput)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.EnrollTOTPInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: ctx, input
+func (_m *TOTPInteractor) RegenerateRecoveryCodes(ctx context.Context, input interactor.RegenerateRecoveryCodesInput) ([]string, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.RegenerateRecoveryCodesInput) ([]string, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.RegenerateRecoveryCodesInput) []string); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.RegenerateRecoveryCodesInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTOTPInteractor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTOTPInteractor creates a new instance of TOTPInteractor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTOTPInteractor(t mockConstructorTestingTNewTOTPInteractor) *TOTPInteractor {
+	mock := &TOTPInteractor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,57 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	interactor "github.com/mkaiho/go-auth-api/usecase/interactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserImportInteractor is an autogenerated mock type for the UserImportInteractor type
+type UserImportInteractor struct {
+	mock.Mock
+}
+
+// ImportUser provides a mock function with given fields: ctx, input
+func (_m *UserImportInteractor) ImportUser(ctx context.Context, input interactor.ImportUserInput) (*entity.User, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.ImportUserInput) (*entity.User, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.ImportUserInput) *entity.User); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.ImportUserInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewUserImportInteractor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewUserImportInteractor creates a new instance of UserImportInteractor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewUserImportInteractor(t mockConstructorTestingTNewUserImportInteractor) *UserImportInteractor {
+	mock := &UserImportInteractor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,135 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	interactor "github.com/mkaiho/go-auth-api/usecase/interactor"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebAuthnInteractor is an autogenerated mock type for the WebAuthnInteractor type
+type WebAuthnInteractor struct {
+	mock.Mock
+}
+
+// BeginLogin provides a mock function with given fields: ctx
+func (_m *WebAuthnInteractor) BeginLogin(ctx context.Context) (*interactor.WebAuthnLoginOptions, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *interactor.WebAuthnLoginOptions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*interactor.WebAuthnLoginOptions, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *interactor.WebAuthnLoginOptions); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*interactor.WebAuthnLoginOptions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginRegistration provides a mock function with given fields: ctx, input
+func (_m *WebAuthnInteractor) BeginRegistration(ctx context.Context, input interactor.BeginWebAuthnRegistrationInput) (*interactor.WebAuthnRegistrationOptions, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *interactor.WebAuthnRegistrationOptions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.BeginWebAuthnRegistrationInput) (*interactor.WebAuthnRegistrationOptions, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.BeginWebAuthnRegistrationInput) *interactor.WebAuthnRegistrationOptions); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*interactor.WebAuthnRegistrationOptions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.BeginWebAuthnRegistrationInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishLogin provides a mock function with given fields: ctx, input
+func (_m *WebAuthnInteractor) FinishLogin(ctx context.Context, input interactor.WebAuthnAssertionInput) (*entity.Authentication, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.Authentication
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.WebAuthnAssertionInput) (*entity.Authentication, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.WebAuthnAssertionInput) *entity.Authentication); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Authentication)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.WebAuthnAssertionInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishRegistration provides a mock function with given fields: ctx, input
+func (_m *WebAuthnInteractor) FinishRegistration(ctx context.Context, input interactor.FinishWebAuthnRegistrationInput) (*entity.WebAuthnCredential, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.WebAuthnCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.FinishWebAuthnRegistrationInput) (*entity.WebAuthnCredential, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interactor.FinishWebAuthnRegistrationInput) *entity.WebAuthnCredential); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.WebAuthnCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interactor.FinishWebAuthnRegistrationInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebAuthnInteractor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebAuthnInteractor creates a new instance of WebAuthnInteractor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebAuthnInteractor(t mockConstructorTestingTNewWebAuthnInteractor) *WebAuthnInteractor {
+	mock := &WebAuthnInteractor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,56 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccessPolicySource is an autogenerated mock type for the AccessPolicySource type
+type AccessPolicySource struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx
+func (_m *AccessPolicySource) Get(ctx context.Context) (*entity.AccessPolicy, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *entity.AccessPolicy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*entity.AccessPolicy, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *entity.AccessPolicy); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.AccessPolicy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewAccessPolicySource interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAccessPolicySource creates a new instance of AccessPolicySource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAccessPolicySource(t mockConstructorTestingTNewAccessPolicySource) *AccessPolicySource {
+	mock := &AccessPolicySource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,54 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BreachedPasswordChecker is an autogenerated mock type for the BreachedPasswordChecker type
+type BreachedPasswordChecker struct {
+	mock.Mock
+}
+
+// IsBreached provides a mock function with given fields: ctx, password
+func (_m *BreachedPasswordChecker) IsBreached(ctx context.Context, password entity.Password) (bool, error) {
+	ret := _m.Called(ctx, password)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Password) (bool, error)); ok {
+		return rf(ctx, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.Password) bool); ok {
+		r0 = rf(ctx, password)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.Password) error); ok {
+		r1 = rf(ctx, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewBreachedPasswordChecker interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewBreachedPasswordChecker creates a new instance of BreachedPasswordChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewBreachedPasswordChecker(t mockConstructorTestingTNewBreachedPasswordChecker) *BreachedPasswordChecker {
+	mock := &BreachedPasswordChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,68 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CSRFTokenManager is an autogenerated mock type for the CSRFTokenManager type
+type CSRFTokenManager struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: ctx, sessionID
+func (_m *CSRFTokenManager) Issue(ctx context.Context, sessionID entity.ID) (string, error) {
+	ret := _m.Called(ctx, sessionID)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (string, error)); ok {
+		return rf(ctx, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) string); ok {
+		r0 = rf(ctx, sessionID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, sessionID, token
+func (_m *CSRFTokenManager) Verify(ctx context.Context, sessionID entity.ID, token string) error {
+	ret := _m.Called(ctx, sessionID, token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, string) error); ok {
+		r0 = rf(ctx, sessionID, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewCSRFTokenManager interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewCSRFTokenManager creates a new instance of CSRFTokenManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewCSRFTokenManager(t mockConstructorTestingTNewCSRFTokenManager) *CSRFTokenManager {
+	mock := &CSRFTokenManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
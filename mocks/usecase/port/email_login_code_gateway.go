@@ -0,0 +1,105 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailLoginCodeGateway is an autogenerated mock type for the EmailLoginCodeGateway type
+type EmailLoginCodeGateway struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: ctx, id
+func (_m *EmailLoginCodeGateway) Consume(ctx context.Context, id entity.ID) (entity.ID, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 entity.ID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (entity.ID, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) entity.ID); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(entity.ID)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Issue provides a mock function with given fields: ctx, userID
+func (_m *EmailLoginCodeGateway) Issue(ctx context.Context, userID entity.ID) (*port.EmailLoginCode, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *port.EmailLoginCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (*port.EmailLoginCode, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) *port.EmailLoginCode); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.EmailLoginCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, id, code
+func (_m *EmailLoginCodeGateway) Verify(ctx context.Context, id entity.ID, code string) (entity.ID, error) {
+	ret := _m.Called(ctx, id, code)
+
+	var r0 entity.ID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, string) (entity.ID, error)); ok {
+		return rf(ctx, id, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, string) entity.ID); ok {
+		r0 = rf(ctx, id, code)
+	} else {
+		r0 = ret.Get(0).(entity.ID)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID, string) error); ok {
+		r1 = rf(ctx, id, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEmailLoginCodeGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEmailLoginCodeGateway creates a new instance of EmailLoginCodeGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEmailLoginCodeGateway(t mockConstructorTestingTNewEmailLoginCodeGateway) *EmailLoginCodeGateway {
+	mock := &EmailLoginCodeGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,80 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailLoginTokenManager is an autogenerated mock type for the EmailLoginTokenManager type
+type EmailLoginTokenManager struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: ctx, input
+func (_m *EmailLoginTokenManager) Issue(ctx context.Context, input port.EmailLoginTokenIssueInput) (string, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.EmailLoginTokenIssueInput) (string, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.EmailLoginTokenIssueInput) string); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.EmailLoginTokenIssueInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Parse provides a mock function with given fields: ctx, token
+func (_m *EmailLoginTokenManager) Parse(ctx context.Context, token string) (*port.EmailLoginToken, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *port.EmailLoginToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*port.EmailLoginToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *port.EmailLoginToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.EmailLoginToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEmailLoginTokenManager interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEmailLoginTokenManager creates a new instance of EmailLoginTokenManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEmailLoginTokenManager(t mockConstructorTestingTNewEmailLoginTokenManager) *EmailLoginTokenManager {
+	mock := &EmailLoginTokenManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,80 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailVerificationTokenManager is an autogenerated mock type for the EmailVerificationTokenManager type
+type EmailVerificationTokenManager struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: ctx, input
+func (_m *EmailVerificationTokenManager) Issue(ctx context.Context, input port.EmailVerificationTokenIssueInput) (string, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.EmailVerificationTokenIssueInput) (string, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.EmailVerificationTokenIssueInput) string); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.EmailVerificationTokenIssueInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Parse provides a mock function with given fields: ctx, token
+func (_m *EmailVerificationTokenManager) Parse(ctx context.Context, token string) (*port.EmailVerificationToken, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *port.EmailVerificationToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*port.EmailVerificationToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *port.EmailVerificationToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.EmailVerificationToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEmailVerificationTokenManager interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEmailVerificationTokenManager creates a new instance of EmailVerificationTokenManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEmailVerificationTokenManager(t mockConstructorTestingTNewEmailVerificationTokenManager) *EmailVerificationTokenManager {
+	mock := &EmailVerificationTokenManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,283 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GroupGateway is an autogenerated mock type for the GroupGateway type
+type GroupGateway struct {
+	mock.Mock
+}
+
+// AddSubgroup provides a mock function with given fields: ctx, id, subgroupID
+func (_m *GroupGateway) AddSubgroup(ctx context.Context, id entity.ID, subgroupID entity.ID) error {
+	ret := _m.Called(ctx, id, subgroupID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, entity.ID) error); ok {
+		r0 = rf(ctx, id, subgroupID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddUser provides a mock function with given fields: ctx, id, userID
+func (_m *GroupGateway) AddUser(ctx context.Context, id entity.ID, userID entity.ID) error {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, entity.ID) error); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: ctx, input
+func (_m *GroupGateway) Create(ctx context.Context, input port.GroupCreateInput) (*entity.Group, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.GroupCreateInput) (*entity.Group, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.GroupCreateInput) *entity.Group); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.GroupCreateInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *GroupGateway) Delete(ctx context.Context, id entity.ID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *GroupGateway) Get(ctx context.Context, id entity.ID) (*entity.Group, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entity.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (*entity.Group, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) *entity.Group); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *GroupGateway) List(ctx context.Context) (entity.Groups, error) {
+	ret := _m.Called(ctx)
+
+	var r0 entity.Groups
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (entity.Groups, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) entity.Groups); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entity.Groups)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAncestorIDs provides a mock function with given fields: ctx, id
+func (_m *GroupGateway) ListAncestorIDs(ctx context.Context, id entity.ID) ([]entity.ID, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 []entity.ID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) ([]entity.ID, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) []entity.ID); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.ID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *GroupGateway) ListByUserID(ctx context.Context, userID entity.ID) (entity.Groups, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 entity.Groups
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (entity.Groups, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) entity.Groups); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entity.Groups)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMembers provides a mock function with given fields: ctx, id
+func (_m *GroupGateway) ListMembers(ctx context.Context, id entity.ID) (*entity.GroupMembers, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entity.GroupMembers
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (*entity.GroupMembers, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) *entity.GroupMembers); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.GroupMembers)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveSubgroup provides a mock function with given fields: ctx, id, subgroupID
+func (_m *GroupGateway) RemoveSubgroup(ctx context.Context, id entity.ID, subgroupID entity.ID) error {
+	ret := _m.Called(ctx, id, subgroupID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, entity.ID) error); ok {
+		r0 = rf(ctx, id, subgroupID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveUser provides a mock function with given fields: ctx, id, userID
+func (_m *GroupGateway) RemoveUser(ctx context.Context, id entity.ID, userID entity.ID) error {
+	ret := _m.Called(ctx, id, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, entity.ID) error); ok {
+		r0 = rf(ctx, id, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, input
+func (_m *GroupGateway) Update(ctx context.Context, input port.GroupUpdateInput) (*entity.Group, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.GroupUpdateInput) (*entity.Group, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.GroupUpdateInput) *entity.Group); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.GroupUpdateInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewGroupGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewGroupGateway creates a new instance of GroupGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewGroupGateway(t mockConstructorTestingTNewGroupGateway) *GroupGateway {
+	mock := &GroupGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
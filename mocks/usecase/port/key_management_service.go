@@ -0,0 +1,122 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// KeyManagementService is an autogenerated mock type for the KeyManagementService type
+type KeyManagementService struct {
+	mock.Mock
+}
+
+// DecryptDataKey provides a mock function with given fields: ctx, encryptedKey
+func (_m *KeyManagementService) DecryptDataKey(ctx context.Context, encryptedKey []byte) ([]byte, error) {
+	ret := _m.Called(ctx, encryptedKey)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) ([]byte, error)); ok {
+		return rf(ctx, encryptedKey)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) []byte); ok {
+		r0 = rf(ctx, encryptedKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, encryptedKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GenerateDataKey provides a mock function with given fields: ctx
+func (_m *KeyManagementService) GenerateDataKey(ctx context.Context) (*port.DataKey, error) {
+	ret := _m.Called(ctx)
+
+	var r0 *port.DataKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*port.DataKey, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *port.DataKey); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.DataKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Sign provides a mock function with given fields: ctx, message
+func (_m *KeyManagementService) Sign(ctx context.Context, message []byte) ([]byte, error) {
+	ret := _m.Called(ctx, message)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) ([]byte, error)); ok {
+		return rf(ctx, message)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) []byte); ok {
+		r0 = rf(ctx, message)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, message)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, message, signature
+func (_m *KeyManagementService) Verify(ctx context.Context, message []byte, signature []byte) error {
+	ret := _m.Called(ctx, message, signature)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, []byte) error); ok {
+		r0 = rf(ctx, message, signature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewKeyManagementService interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewKeyManagementService creates a new instance of KeyManagementService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewKeyManagementService(t mockConstructorTestingTNewKeyManagementService) *KeyManagementService {
+	mock := &KeyManagementService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
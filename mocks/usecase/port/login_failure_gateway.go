@@ -0,0 +1,85 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LoginFailureGateway is an autogenerated mock type for the LoginFailureGateway type
+type LoginFailureGateway struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: ctx, userID
+func (_m *LoginFailureGateway) Get(ctx context.Context, userID entity.ID) (*entity.LoginFailures, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *entity.LoginFailures
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (*entity.LoginFailures, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) *entity.LoginFailures); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.LoginFailures)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Record provides a mock function with given fields: ctx, input
+func (_m *LoginFailureGateway) Record(ctx context.Context, input port.LoginFailureRecordInput) error {
+	ret := _m.Called(ctx, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.LoginFailureRecordInput) error); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Reset provides a mock function with given fields: ctx, userID
+func (_m *LoginFailureGateway) Reset(ctx context.Context, userID entity.ID) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLoginFailureGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginFailureGateway creates a new instance of LoginFailureGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginFailureGateway(t mockConstructorTestingTNewLoginFailureGateway) *LoginFailureGateway {
+	mock := &LoginFailureGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
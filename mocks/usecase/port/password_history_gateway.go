@@ -0,0 +1,56 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordHistoryGateway is an autogenerated mock type for the PasswordHistoryGateway type
+type PasswordHistoryGateway struct {
+	mock.Mock
+}
+
+// ListRecent provides a mock function with given fields: ctx, userID, limit
+func (_m *PasswordHistoryGateway) ListRecent(ctx context.Context, userID entity.ID, limit int) ([]entity.HashedPassword, error) {
+	ret := _m.Called(ctx, userID, limit)
+
+	var r0 []entity.HashedPassword
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, int) ([]entity.HashedPassword, error)); ok {
+		return rf(ctx, userID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, int) []entity.HashedPassword); ok {
+		r0 = rf(ctx, userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.HashedPassword)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID, int) error); ok {
+		r1 = rf(ctx, userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewPasswordHistoryGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordHistoryGateway creates a new instance of PasswordHistoryGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordHistoryGateway(t mockConstructorTestingTNewPasswordHistoryGateway) *PasswordHistoryGateway {
+	mock := &PasswordHistoryGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -52,6 +52,20 @@ func (_m *PasswordManager) Hash(ctx context.Context, value string) (entity.Hashe
 	return r0, r1
 }
 
+// NeedsRehash provides a mock function with given fields: ctx, hashedPassword
+func (_m *PasswordManager) NeedsRehash(ctx context.Context, hashedPassword entity.HashedPassword) bool {
+	ret := _m.Called(ctx, hashedPassword)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, entity.HashedPassword) bool); ok {
+		r0 = rf(ctx, hashedPassword)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPasswordManager interface {
 	mock.TestingT
 	Cleanup(func())
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordResetTokenManager is an autogenerated mock type for the PasswordResetTokenManager type
+type PasswordResetTokenManager struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: ctx, input
+func (_m *PasswordResetTokenManager) Issue(ctx context.Context, input port.PasswordResetTokenIssueInput) (string, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.PasswordResetTokenIssueInput) (string, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.PasswordResetTokenIssueInput) string); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.PasswordResetTokenIssueInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Parse provides a mock function with given fields: ctx, token
+func (_m *PasswordResetTokenManager) Parse(ctx context.Context, token string) (*port.PasswordResetToken, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *port.PasswordResetToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*port.PasswordResetToken, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *port.PasswordResetToken); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.PasswordResetToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx, token, password
+func (_m *PasswordResetTokenManager) Verify(ctx context.Context, token string, password entity.HashedPassword) error {
+	ret := _m.Called(ctx, token, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.HashedPassword) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPasswordResetTokenManager interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPasswordResetTokenManager creates a new instance of PasswordResetTokenManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPasswordResetTokenManager(t mockConstructorTestingTNewPasswordResetTokenManager) *PasswordResetTokenManager {
+	mock := &PasswordResetTokenManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
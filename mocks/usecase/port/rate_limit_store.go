@@ -0,0 +1,56 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RateLimitStore is an autogenerated mock type for the RateLimitStore type
+type RateLimitStore struct {
+	mock.Mock
+}
+
+// Take provides a mock function with given fields: ctx, key, limit
+func (_m *RateLimitStore) Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitResult, error) {
+	ret := _m.Called(ctx, key, limit)
+
+	var r0 *entity.RateLimitResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.RateLimit) (*entity.RateLimitResult, error)); ok {
+		return rf(ctx, key, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, entity.RateLimit) *entity.RateLimitResult); ok {
+		r0 = rf(ctx, key, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.RateLimitResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, entity.RateLimit) error); ok {
+		r1 = rf(ctx, key, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewRateLimitStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRateLimitStore creates a new instance of RateLimitStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRateLimitStore(t mockConstructorTestingTNewRateLimitStore) *RateLimitStore {
+	mock := &RateLimitStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RecoveryCodeGateway is an autogenerated mock type for the RecoveryCodeGateway type
+type RecoveryCodeGateway struct {
+	mock.Mock
+}
+
+// DeleteByUserID provides a mock function with given fields: ctx, userID
+func (_m *RecoveryCodeGateway) DeleteByUserID(ctx context.Context, userID entity.ID) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Generate provides a mock function with given fields: ctx, userID, count
+func (_m *RecoveryCodeGateway) Generate(ctx context.Context, userID entity.ID, count int) ([]string, error) {
+	ret := _m.Called(ctx, userID, count)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, int) ([]string, error)); ok {
+		return rf(ctx, userID, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, int) []string); ok {
+		r0 = rf(ctx, userID, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID, int) error); ok {
+		r1 = rf(ctx, userID, count)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Use provides a mock function with given fields: ctx, userID, code
+func (_m *RecoveryCodeGateway) Use(ctx context.Context, userID entity.ID, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewRecoveryCodeGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRecoveryCodeGateway creates a new instance of RecoveryCodeGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRecoveryCodeGateway(t mockConstructorTestingTNewRecoveryCodeGateway) *RecoveryCodeGateway {
+	mock := &RecoveryCodeGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,177 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RoleGateway is an autogenerated mock type for the RoleGateway type
+type RoleGateway struct {
+	mock.Mock
+}
+
+// Assign provides a mock function with given fields: ctx, userID, name
+func (_m *RoleGateway) Assign(ctx context.Context, userID entity.ID, name string) error {
+	ret := _m.Called(ctx, userID, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, string) error); ok {
+		r0 = rf(ctx, userID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, name
+func (_m *RoleGateway) Delete(ctx context.Context, name string) error {
+	ret := _m.Called(ctx, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, name
+func (_m *RoleGateway) Get(ctx context.Context, name string) (*entity.Role, error) {
+	ret := _m.Called(ctx, name)
+
+	var r0 *entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Role, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Role); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx
+func (_m *RoleGateway) List(ctx context.Context) (entity.Roles, error) {
+	ret := _m.Called(ctx)
+
+	var r0 entity.Roles
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (entity.Roles, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) entity.Roles); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entity.Roles)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *RoleGateway) ListByUserID(ctx context.Context, userID entity.ID) (entity.Roles, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 entity.Roles
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (entity.Roles, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) entity.Roles); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entity.Roles)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, input
+func (_m *RoleGateway) Save(ctx context.Context, input port.RoleSaveInput) (*entity.Role, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.RoleSaveInput) (*entity.Role, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.RoleSaveInput) *entity.Role); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.RoleSaveInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unassign provides a mock function with given fields: ctx, userID, name
+func (_m *RoleGateway) Unassign(ctx context.Context, userID entity.ID, name string) error {
+	ret := _m.Called(ctx, userID, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, string) error); ok {
+		r0 = rf(ctx, userID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewRoleGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewRoleGateway creates a new instance of RoleGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewRoleGateway(t mockConstructorTestingTNewRoleGateway) *RoleGateway {
+	mock := &RoleGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
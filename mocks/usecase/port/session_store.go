@@ -0,0 +1,185 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SessionStore is an autogenerated mock type for the SessionStore type
+type SessionStore struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, input
+func (_m *SessionStore) Create(ctx context.Context, input port.SessionCreateInput) (*entity.Session, string, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.Session
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.SessionCreateInput) (*entity.Session, string, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.SessionCreateInput) *entity.Session); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.SessionCreateInput) string); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, port.SessionCreateInput) error); ok {
+		r2 = rf(ctx, input)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *SessionStore) Delete(ctx context.Context, id entity.ID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteByUserID provides a mock function with given fields: ctx, input
+func (_m *SessionStore) DeleteByUserID(ctx context.Context, input port.SessionDeleteByUserIDInput) error {
+	ret := _m.Called(ctx, input)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.SessionDeleteByUserIDInput) error); ok {
+		r0 = rf(ctx, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *SessionStore) Get(ctx context.Context, id entity.ID) (*entity.Session, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (*entity.Session, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) *entity.Session); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByToken provides a mock function with given fields: ctx, token
+func (_m *SessionStore) GetByToken(ctx context.Context, token string) (*entity.Session, error) {
+	ret := _m.Called(ctx, token)
+
+	var r0 *entity.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*entity.Session, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *entity.Session); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *SessionStore) ListByUserID(ctx context.Context, userID entity.ID) (entity.Sessions, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 entity.Sessions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (entity.Sessions, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) entity.Sessions); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entity.Sessions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Touch provides a mock function with given fields: ctx, id, lastSeenAt
+func (_m *SessionStore) Touch(ctx context.Context, id entity.ID, lastSeenAt time.Time) error {
+	ret := _m.Called(ctx, id, lastSeenAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, time.Time) error); ok {
+		r0 = rf(ctx, id, lastSeenAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSessionStore interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSessionStore creates a new instance of SessionStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionStore(t mockConstructorTestingTNewSessionStore) *SessionStore {
+	mock := &SessionStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
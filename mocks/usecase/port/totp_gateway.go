@@ -0,0 +1,112 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TOTPGateway is an autogenerated mock type for the TOTPGateway type
+type TOTPGateway struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, userID, step
+func (_m *TOTPGateway) Confirm(ctx context.Context, userID entity.ID, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, userID
+func (_m *TOTPGateway) Delete(ctx context.Context, userID entity.ID) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, userID
+func (_m *TOTPGateway) Get(ctx context.Context, userID entity.ID) (*entity.TOTP, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 *entity.TOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (*entity.TOTP, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) *entity.TOTP); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.TOTP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, totp
+func (_m *TOTPGateway) Save(ctx context.Context, totp entity.TOTP) error {
+	ret := _m.Called(ctx, totp)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.TOTP) error); ok {
+		r0 = rf(ctx, totp)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseStep provides a mock function with given fields: ctx, userID, step
+func (_m *TOTPGateway) UseStep(ctx context.Context, userID entity.ID, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTOTPGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTOTPGateway creates a new instance of TOTPGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTOTPGateway(t mockConstructorTestingTNewTOTPGateway) *TOTPGateway {
+	mock := &TOTPGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,106 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TOTPManager is an autogenerated mock type for the TOTPManager type
+type TOTPManager struct {
+	mock.Mock
+}
+
+// Generate provides a mock function with given fields: ctx, account
+func (_m *TOTPManager) Generate(ctx context.Context, account string) (*port.TOTPKey, error) {
+	ret := _m.Called(ctx, account)
+
+	var r0 *port.TOTPKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*port.TOTPKey, error)); ok {
+		return rf(ctx, account)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *port.TOTPKey); ok {
+		r0 = rf(ctx, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.TOTPKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QRCode provides a mock function with given fields: ctx, uri
+func (_m *TOTPManager) QRCode(ctx context.Context, uri string) ([]byte, error) {
+	ret := _m.Called(ctx, uri)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, uri)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, uri)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, uri)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Validate provides a mock function with given fields: ctx, secret, code
+func (_m *TOTPManager) Validate(ctx context.Context, secret []byte, code string) (int64, error) {
+	ret := _m.Called(ctx, secret, code)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, string) (int64, error)); ok {
+		return rf(ctx, secret, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, string) int64); ok {
+		r0 = rf(ctx, secret, code)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, string) error); ok {
+		r1 = rf(ctx, secret, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewTOTPManager interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTOTPManager creates a new instance of TOTPManager. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTOTPManager(t mockConstructorTestingTNewTOTPManager) *TOTPManager {
+	mock := &TOTPManager{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,83 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebAuthnChallengeGateway is an autogenerated mock type for the WebAuthnChallengeGateway type
+type WebAuthnChallengeGateway struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: ctx, id, ceremony
+func (_m *WebAuthnChallengeGateway) Consume(ctx context.Context, id entity.ID, ceremony entity.WebAuthnCeremony) (*entity.WebAuthnChallenge, error) {
+	ret := _m.Called(ctx, id, ceremony)
+
+	var r0 *entity.WebAuthnChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, entity.WebAuthnCeremony) (*entity.WebAuthnChallenge, error)); ok {
+		return rf(ctx, id, ceremony)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, entity.WebAuthnCeremony) *entity.WebAuthnChallenge); ok {
+		r0 = rf(ctx, id, ceremony)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.WebAuthnChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID, entity.WebAuthnCeremony) error); ok {
+		r1 = rf(ctx, id, ceremony)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, input
+func (_m *WebAuthnChallengeGateway) Create(ctx context.Context, input port.WebAuthnChallengeCreateInput) (*entity.WebAuthnChallenge, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.WebAuthnChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.WebAuthnChallengeCreateInput) (*entity.WebAuthnChallenge, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.WebAuthnChallengeCreateInput) *entity.WebAuthnChallenge); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.WebAuthnChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.WebAuthnChallengeCreateInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebAuthnChallengeGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebAuthnChallengeGateway creates a new instance of WebAuthnChallengeGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebAuthnChallengeGateway(t mockConstructorTestingTNewWebAuthnChallengeGateway) *WebAuthnChallengeGateway {
+	mock := &WebAuthnChallengeGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,123 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	entity "github.com/mkaiho/go-auth-api/entity"
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebAuthnCredentialGateway is an autogenerated mock type for the WebAuthnCredentialGateway type
+type WebAuthnCredentialGateway struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, input
+func (_m *WebAuthnCredentialGateway) Create(ctx context.Context, input port.WebAuthnCredentialCreateInput) (*entity.WebAuthnCredential, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *entity.WebAuthnCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.WebAuthnCredentialCreateInput) (*entity.WebAuthnCredential, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.WebAuthnCredentialCreateInput) *entity.WebAuthnCredential); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.WebAuthnCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.WebAuthnCredentialCreateInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByCredentialID provides a mock function with given fields: ctx, credentialID
+func (_m *WebAuthnCredentialGateway) GetByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error) {
+	ret := _m.Called(ctx, credentialID)
+
+	var r0 *entity.WebAuthnCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (*entity.WebAuthnCredential, error)); ok {
+		return rf(ctx, credentialID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) *entity.WebAuthnCredential); ok {
+		r0 = rf(ctx, credentialID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.WebAuthnCredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, credentialID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUserID provides a mock function with given fields: ctx, userID
+func (_m *WebAuthnCredentialGateway) ListByUserID(ctx context.Context, userID entity.ID) (entity.WebAuthnCredentials, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 entity.WebAuthnCredentials
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) (entity.WebAuthnCredentials, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID) entity.WebAuthnCredentials); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(entity.WebAuthnCredentials)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, entity.ID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateSignCount provides a mock function with given fields: ctx, id, signCount
+func (_m *WebAuthnCredentialGateway) UpdateSignCount(ctx context.Context, id entity.ID, signCount uint32) error {
+	ret := _m.Called(ctx, id, signCount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, entity.ID, uint32) error); ok {
+		r0 = rf(ctx, id, signCount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewWebAuthnCredentialGateway interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebAuthnCredentialGateway creates a new instance of WebAuthnCredentialGateway. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebAuthnCredentialGateway(t mockConstructorTestingTNewWebAuthnCredentialGateway) *WebAuthnCredentialGateway {
+	mock := &WebAuthnCredentialGateway{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
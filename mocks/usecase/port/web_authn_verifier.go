@@ -0,0 +1,96 @@
+// Code generated by mockery v2.20.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	port "github.com/mkaiho/go-auth-api/usecase/port"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WebAuthnVerifier is an autogenerated mock type for the WebAuthnVerifier type
+type WebAuthnVerifier struct {
+	mock.Mock
+}
+
+// RelyingParty provides a mock function with given fields: ctx
+func (_m *WebAuthnVerifier) RelyingParty(ctx context.Context) port.WebAuthnRelyingParty {
+	ret := _m.Called(ctx)
+
+	var r0 port.WebAuthnRelyingParty
+	if rf, ok := ret.Get(0).(func(context.Context) port.WebAuthnRelyingParty); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(port.WebAuthnRelyingParty)
+	}
+
+	return r0
+}
+
+// VerifyAssertion provides a mock function with given fields: ctx, input
+func (_m *WebAuthnVerifier) VerifyAssertion(ctx context.Context, input port.WebAuthnAssertionInput) (*port.WebAuthnAssertion, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *port.WebAuthnAssertion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.WebAuthnAssertionInput) (*port.WebAuthnAssertion, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.WebAuthnAssertionInput) *port.WebAuthnAssertion); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.WebAuthnAssertion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.WebAuthnAssertionInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyRegistration provides a mock function with given fields: ctx, input
+func (_m *WebAuthnVerifier) VerifyRegistration(ctx context.Context, input port.WebAuthnRegistrationInput) (*port.WebAuthnRegistration, error) {
+	ret := _m.Called(ctx, input)
+
+	var r0 *port.WebAuthnRegistration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, port.WebAuthnRegistrationInput) (*port.WebAuthnRegistration, error)); ok {
+		return rf(ctx, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, port.WebAuthnRegistrationInput) *port.WebAuthnRegistration); ok {
+		r0 = rf(ctx, input)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*port.WebAuthnRegistration)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, port.WebAuthnRegistrationInput) error); ok {
+		r1 = rf(ctx, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewWebAuthnVerifier interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewWebAuthnVerifier creates a new instance of WebAuthnVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewWebAuthnVerifier(t mockConstructorTestingTNewWebAuthnVerifier) *WebAuthnVerifier {
+	mock := &WebAuthnVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,6 +4,14 @@ import "errors"
 
 var ErrNoAuthUser = errors.New("not exist auth user")
 var ErrInvalidCredential = errors.New("invalid credential")
+var ErrEmailNotVerified = errors.New("email not verified")
+var ErrSecondFactorRequired = errors.New("second factor required")
+var ErrInvalidSecondFactor = errors.New("invalid second factor")
+var ErrSecondFactorUsed = errors.New("second factor already used")
+var ErrPermissionDenied = errors.New("permission denied")
+var ErrInsufficientAuthentication = errors.New("insufficient user authentication")
 
 var ErrNotFoundEntity = errors.New("not found entity")
 var ErrAlreadyExistsEntity = errors.New("already exists entity")
+
+var ErrInvalidToken = errors.New("invalid token")
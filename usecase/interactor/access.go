@@ -0,0 +1,127 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type DecideAccessInput struct {
+	SubjectID entity.ID
+	// SubjectAttributes are added to those of the subject user, such as
+	// the org the calling service knows them by. They can not override the
+	// user's own attributes.
+	SubjectAttributes entity.Attributes
+	Action            string
+	Resource          entity.Attributes
+	Context           entity.Attributes
+}
+
+var _ AccessInteractor = (*accessInteractor)(nil)
+
+type AccessInteractor interface {
+	// Decide evaluates the access policy, and logs every decision.
+	Decide(ctx context.Context, input DecideAccessInput) (*entity.AccessDecision, error)
+}
+
+type accessInteractor struct {
+	users    port.UserGateway
+	roles    port.RoleGateway
+	groups   port.GroupGateway
+	policies port.AccessPolicySource
+}
+
+func NewAccessInteractor(
+	users port.UserGateway,
+	roles port.RoleGateway,
+	groups port.GroupGateway,
+	policies port.AccessPolicySource,
+) *accessInteractor {
+	return &accessInteractor{
+		users:    users,
+		roles:    roles,
+		groups:   groups,
+		policies: policies,
+	}
+}
+
+func (it *accessInteractor) Decide(ctx context.Context, input DecideAccessInput) (*entity.AccessDecision, error) {
+	logger := util.FromContext(ctx)
+
+	user, err := it.users.Get(ctx, input.SubjectID)
+	if err != nil {
+		logger.Error(err, "failed get user")
+		return nil, err
+	}
+	roles, err := it.roles.ListByUserID(ctx, user.ID)
+	if err != nil {
+		logger.Error(err, "failed list user roles")
+		return nil, err
+	}
+	groups, err := it.groups.ListByUserID(ctx, user.ID)
+	if err != nil {
+		logger.Error(err, "failed list user groups")
+		return nil, err
+	}
+	policy, err := it.policies.Get(ctx)
+	if err != nil {
+		logger.Error(err, "failed get access policy")
+		return nil, err
+	}
+
+	decision := policy.Decide(entity.AccessRequest{
+		Subject:  subjectAttributes(user, roles, groups, input.SubjectAttributes),
+		Action:   input.Action,
+		Resource: input.Resource,
+		Context:  input.Context,
+	})
+	var errs []string
+	for _, err := range decision.Errors {
+		errs = append(errs, err.Error())
+	}
+	logger.WithName("access").Info(
+		"access decided",
+		"subjectID", user.ID,
+		"action", input.Action,
+		"resource", input.Resource,
+		"allowed", decision.Allowed,
+		"rule", decision.Rule,
+		"errors", errs,
+	)
+
+	return &decision, nil
+}
+
+// subjectAttributes describes user to access policies as id, name, email,
+// email_verified, roles, permissions and groups, over the attributes given.
+func subjectAttributes(
+	user *entity.User,
+	roles entity.Roles,
+	groups entity.Groups,
+	given entity.Attributes,
+) entity.Attributes {
+	attrs := make(entity.Attributes, len(given)+7)
+	for k, v := range given {
+		attrs[k] = v
+	}
+	var permissions []string
+	seen := make(map[entity.Permission]bool)
+	for _, role := range roles {
+		for _, p := range role.Permissions {
+			if !seen[p] {
+				seen[p] = true
+				permissions = append(permissions, p.String())
+			}
+		}
+	}
+	attrs["id"] = user.ID.String()
+	attrs["name"] = user.Name
+	attrs["email"] = user.Email.String()
+	attrs["email_verified"] = user.EmailVerified
+	attrs["roles"] = roles.Names()
+	attrs["permissions"] = permissions
+	attrs["groups"] = groups.Names()
+	return attrs
+}
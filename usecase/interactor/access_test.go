@@ -0,0 +1,130 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_accessInteractor_Decide(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	roles := entity.Roles{
+		{Name: "manager", Permissions: []entity.Permission{entity.PermissionUsersRead}},
+	}
+	groups := entity.Groups{
+		{ID: "test_group_id_001", Name: "platform"},
+	}
+	policy, err := entity.ParseAccessPolicy(`
+manager-org-update: allow users:update
+  when "manager" in subject.roles and resource.org == subject.org
+platform-deploy: allow deployments:create when "platform" in subject.groups
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		input   DecideAccessInput
+		userErr error
+		want    *entity.AccessDecision
+		wantErr error
+	}{
+		{
+			name: "allow with given subject attributes",
+			input: DecideAccessInput{
+				SubjectID:         user.ID,
+				SubjectAttributes: entity.Attributes{"org": "acme"},
+				Action:            "users:update",
+				Resource:          entity.Attributes{"org": "acme"},
+			},
+			want: &entity.AccessDecision{Allowed: true, Rule: "manager-org-update"},
+		},
+		{
+			name: "deny when given attributes claim other roles",
+			input: DecideAccessInput{
+				SubjectID:         user.ID,
+				SubjectAttributes: entity.Attributes{"org": "acme", "roles": []any{}},
+				Action:            "users:update",
+				Resource:          entity.Attributes{"org": "other"},
+			},
+			want: &entity.AccessDecision{Allowed: false},
+		},
+		{
+			name: "allow by group membership",
+			input: DecideAccessInput{
+				SubjectID: user.ID,
+				Action:    "deployments:create",
+			},
+			want: &entity.AccessDecision{Allowed: true, Rule: "platform-deploy"},
+		},
+		{
+			name: "return error when subject is unknown",
+			input: DecideAccessInput{
+				SubjectID: user.ID,
+				Action:    "users:update",
+			},
+			userErr: usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := portmocks.NewUserGateway(t)
+			users.On("Get", ctx, user.ID).Return(user, tt.userErr).Times(1)
+			roleGateway := portmocks.NewRoleGateway(t)
+			groupGateway := portmocks.NewGroupGateway(t)
+			policies := portmocks.NewAccessPolicySource(t)
+			if tt.userErr == nil {
+				roleGateway.On("ListByUserID", ctx, user.ID).Return(roles, nil).Times(1)
+				groupGateway.On("ListByUserID", ctx, user.ID).Return(groups, nil).Times(1)
+				policies.On("Get", ctx).Return(policy, nil).Times(1)
+			}
+			it := NewAccessInteractor(users, roleGateway, groupGateway, policies)
+
+			got, err := it.Decide(ctx, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_subjectAttributes(t *testing.T) {
+	user := &entity.User{
+		ID:            "test_user_id_001",
+		Name:          "test_user_001",
+		Email:         "test_001@example.com",
+		EmailVerified: true,
+	}
+	roles := entity.Roles{
+		{Name: "a", Permissions: []entity.Permission{entity.PermissionUsersRead, entity.PermissionUsersWrite}},
+		{Name: "b", Permissions: []entity.Permission{entity.PermissionUsersRead}},
+	}
+	groups := entity.Groups{
+		{ID: "test_group_id_001", Name: "platform"},
+	}
+	got := subjectAttributes(user, roles, groups, entity.Attributes{"org": "acme", "id": "spoofed"})
+	assert.Equal(t, entity.Attributes{
+		"id":             "test_user_id_001",
+		"name":           "test_user_001",
+		"email":          "test_001@example.com",
+		"email_verified": true,
+		"roles":          []string{"a", "b"},
+		"permissions":    []string{"users:read", "users:write"},
+		"groups":         []string{"platform"},
+		"org":            "acme",
+	}, got)
+}
@@ -0,0 +1,194 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	AuthenticateInput struct {
+		Email    entity.Email
+		Password entity.Password
+		// SecondFactor is a TOTP or recovery code, required from users who
+		// have enabled TOTP.
+		SecondFactor string
+		// WebAuthnAssertion is a passkey assertion, accepted in place of
+		// SecondFactor.
+		WebAuthnAssertion *WebAuthnAssertionInput
+		// PerRequest marks credentials sent along with every request,
+		// rather than once to log in. Recovery codes are refused for them,
+		// since each request would use one up.
+		PerRequest bool
+	}
+	AuthPolicy struct {
+		RequireVerifiedEmail bool
+		// Lockout applies when a LoginFailureGateway is configured.
+		Lockout entity.LoginLockoutPolicy
+	}
+)
+
+var _ AuthInteractor = (*authInteractor)(nil)
+
+type AuthInteractor interface {
+	Authenticate(ctx context.Context, input AuthenticateInput) (*entity.Authentication, error)
+}
+
+type authInteractor struct {
+	users         port.UserGateway
+	userCreds     port.UserCredentialGateway
+	passwords     passwordChecker
+	totps         port.TOTPGateway
+	totpManager   port.TOTPManager
+	recoveryCodes port.RecoveryCodeGateway
+	passkeys      port.WebAuthnCredentialGateway
+	challenges    port.WebAuthnChallengeGateway
+	verifier      port.WebAuthnVerifier
+	policy        AuthPolicy
+	now           func() time.Time
+}
+
+func NewAuthInteractor(
+	users port.UserGateway,
+	userCreds port.UserCredentialGateway,
+	failures port.LoginFailureGateway,
+	totps port.TOTPGateway,
+	totpManager port.TOTPManager,
+	recoveryCodes port.RecoveryCodeGateway,
+	passkeys port.WebAuthnCredentialGateway,
+	challenges port.WebAuthnChallengeGateway,
+	verifier port.WebAuthnVerifier,
+	policy AuthPolicy,
+) *authInteractor {
+	return &authInteractor{
+		users:     users,
+		userCreds: userCreds,
+		passwords: passwordChecker{
+			userCreds: userCreds,
+			lockout: loginLockout{
+				failures: failures,
+				policy:   policy.Lockout,
+			},
+		},
+		totps:         totps,
+		totpManager:   totpManager,
+		recoveryCodes: recoveryCodes,
+		passkeys:      passkeys,
+		challenges:    challenges,
+		verifier:      verifier,
+		policy:        policy,
+		now:           time.Now,
+	}
+}
+
+func (it *authInteractor) Authenticate(
+	ctx context.Context,
+	input AuthenticateInput,
+) (*entity.Authentication, error) {
+	logger := util.FromContext(ctx)
+
+	users, err := it.users.List(ctx, port.UserListInput{
+		Email: &input.Email,
+	})
+	if err != nil {
+		logger.Error(err, "failed find user")
+		return nil, err
+	}
+	// Unknown emails and accounts without passwords fail like a wrong
+	// password, after as long a check, so that callers can not probe for
+	// accounts.
+	if len(users) == 0 {
+		if err := it.userCreds.Check(ctx, input.Email, input.Password); err != nil && !errors.Is(err, usecase.ErrNoAuthUser) {
+			logger.Error(err, "failed check user credentials")
+			return nil, err
+		}
+		logger.Info("login attempted for unknown email")
+		return nil, usecase.ErrInvalidCredential
+	}
+	user := users[0]
+	failures, err := it.passwords.check(ctx, user.ID, input.Email, input.Password, it.now())
+	if err != nil {
+		return nil, err
+	}
+	methods := []string{entity.AuthMethodPassword}
+	if input.WebAuthnAssertion != nil {
+		if err := it.verifyPasskey(ctx, user.ID, *input.WebAuthnAssertion); err != nil {
+			logger.Error(err, "failed verify passkey")
+			return nil, it.recordLoginFailure(ctx, user.ID, err)
+		}
+		methods = append(methods, entity.AuthMethodHardwareKey)
+	} else if it.totps != nil {
+		// TOTP is disabled when no gateway is configured.
+		recoveryCodes := it.recoveryCodes
+		if input.PerRequest {
+			recoveryCodes = nil
+		}
+		verified, err := verifySecondFactor(ctx, it.totps, it.totpManager, recoveryCodes, user.ID, input.SecondFactor)
+		if err != nil {
+			logger.Error(err, "failed verify second factor")
+			return nil, it.recordLoginFailure(ctx, user.ID, err)
+		}
+		if verified {
+			methods = append(methods, entity.AuthMethodOTP)
+		}
+	}
+	if err := it.resetLoginFailures(ctx, user.ID, failures); err != nil {
+		logger.Error(err, "failed reset login failures")
+		return nil, err
+	}
+	// Only users who passed every factor learn that their email is not
+	// verified, which tells that the password was right.
+	if it.policy.RequireVerifiedEmail && !user.EmailVerified {
+		return nil, usecase.ErrEmailNotVerified
+	}
+	auth := entity.NewAuthentication(user, it.now(), methods...)
+	// Passkeys are optional as a second factor, so their users may have
+	// logged in with a password alone while able to step up.
+	if auth.Level < entity.AuthLevelMFA && it.passkeys != nil {
+		credentials, err := it.passkeys.ListByUserID(ctx, user.ID)
+		if err != nil {
+			logger.Error(err, "failed list webauthn credentials")
+			return nil, err
+		}
+		if len(credentials) > 0 {
+			auth.AvailableLevel = entity.AuthLevelMFA
+		}
+	}
+
+	return auth, nil
+}
+
+func (it *authInteractor) recordLoginFailure(ctx context.Context, userID entity.ID, err error) error {
+	return it.passwords.lockout.recordFailure(ctx, userID, it.now(), err)
+}
+
+func (it *authInteractor) resetLoginFailures(ctx context.Context, userID entity.ID, failures *entity.LoginFailures) error {
+	return it.passwords.lockout.resetFailures(ctx, userID, failures)
+}
+
+// verifyPasskey checks a passkey used as a second factor. The password has
+// already been checked, so user verification is not required.
+func (it *authInteractor) verifyPasskey(ctx context.Context, userID entity.ID, input WebAuthnAssertionInput) error {
+	// WebAuthn is disabled when no gateway is configured.
+	if it.passkeys == nil {
+		return usecase.ErrInvalidSecondFactor
+	}
+	credential, err := verifyWebAuthnAssertion(ctx, it.passkeys, it.challenges, it.verifier, input, false)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidCredential) || errors.Is(err, usecase.ErrInvalidToken) {
+			return fmt.Errorf("%w: %v", usecase.ErrInvalidSecondFactor, err)
+		}
+		return err
+	}
+	if credential.UserID != userID {
+		return usecase.ErrInvalidSecondFactor
+	}
+
+	return nil
+}
@@ -0,0 +1,392 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_authInteractor_Authenticate_lockout(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	policy := AuthPolicy{
+		Lockout: entity.LoginLockoutPolicy{
+			BaseDelay: time.Second,
+			MaxDelay:  time.Minute,
+			Threshold: 3,
+			Duration:  15 * time.Minute,
+		},
+	}
+	tests := []struct {
+		name       string
+		failures   *entity.LoginFailures
+		checkErr   error
+		wantCheck  bool
+		wantRecord bool
+		wantReset  bool
+		wantErr    error
+	}{
+		{
+			name:      "return authentication and reset failures",
+			failures:  &entity.LoginFailures{Count: 2, LastFailedAt: now.Add(-time.Minute)},
+			wantCheck: true,
+			wantReset: true,
+		},
+		{
+			name:      "return authentication without failures",
+			failures:  &entity.LoginFailures{},
+			wantCheck: true,
+		},
+		{
+			name:       "return error and record failure when password is wrong",
+			failures:   &entity.LoginFailures{},
+			checkErr:   usecase.ErrInvalidCredential,
+			wantCheck:  true,
+			wantRecord: true,
+			wantErr:    usecase.ErrInvalidCredential,
+		},
+		{
+			name:      "return error after checking right password during backoff",
+			failures:  &entity.LoginFailures{Count: 2, LastFailedAt: now.Add(-time.Second)},
+			wantCheck: true,
+			wantErr:   usecase.ErrInvalidCredential,
+		},
+		{
+			name:      "return error after checking right password when locked",
+			failures:  &entity.LoginFailures{Count: 3, LastFailedAt: now.Add(-10 * time.Minute)},
+			wantCheck: true,
+			wantErr:   usecase.ErrInvalidCredential,
+		},
+		{
+			name:      "return error without recording failure when locked and password is wrong",
+			failures:  &entity.LoginFailures{Count: 3, LastFailedAt: now.Add(-10 * time.Minute)},
+			checkErr:  usecase.ErrInvalidCredential,
+			wantCheck: true,
+			wantErr:   usecase.ErrInvalidCredential,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := portmocks.NewUserGateway(t)
+			users.
+				On("List", ctx, port.UserListInput{Email: &user.Email}).
+				Return(entity.Users{user}, nil).
+				Times(1)
+			userCreds := portmocks.NewUserCredentialGateway(t)
+			if tt.wantCheck {
+				userCreds.
+					On("Check", ctx, user.Email, entity.Password("test_password")).
+					Return(tt.checkErr).
+					Times(1)
+			}
+			failures := portmocks.NewLoginFailureGateway(t)
+			failures.On("Get", ctx, user.ID).Return(tt.failures, nil).Times(1)
+			if tt.wantRecord {
+				failures.
+					On("Record", ctx, port.LoginFailureRecordInput{
+						UserID:       user.ID,
+						FailedAt:     now,
+						ForgetBefore: now.Add(-15 * time.Minute),
+					}).
+					Return(nil).
+					Times(1)
+			}
+			if tt.wantReset {
+				failures.On("Reset", ctx, user.ID).Return(nil).Times(1)
+			}
+			it := NewAuthInteractor(users, userCreds, failures, nil, nil, nil, nil, nil, nil, policy)
+			it.now = func() time.Time { return now }
+
+			got, err := it.Authenticate(ctx, AuthenticateInput{
+				Email:    user.Email,
+				Password: "test_password",
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, entity.NewAuthentication(user, now, entity.AuthMethodPassword), got)
+		})
+	}
+}
+
+func Test_authInteractor_Authenticate_unknownUser(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	tests := []struct {
+		name       string
+		users      entity.Users
+		wantRecord bool
+	}{
+		{
+			name: "return invalid credential after checking password of unknown email",
+		},
+		{
+			name:       "return invalid credential and record failure for user without password",
+			users:      entity.Users{user},
+			wantRecord: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := portmocks.NewUserGateway(t)
+			users.
+				On("List", ctx, port.UserListInput{Email: &user.Email}).
+				Return(tt.users, nil).
+				Times(1)
+			userCreds := portmocks.NewUserCredentialGateway(t)
+			userCreds.
+				On("Check", ctx, user.Email, entity.Password("test_password")).
+				Return(usecase.ErrNoAuthUser).
+				Times(1)
+			failures := portmocks.NewLoginFailureGateway(t)
+			if len(tt.users) > 0 {
+				failures.On("Get", ctx, user.ID).Return(&entity.LoginFailures{}, nil).Times(1)
+			}
+			if tt.wantRecord {
+				failures.
+					On("Record", ctx, port.LoginFailureRecordInput{
+						UserID:       user.ID,
+						FailedAt:     now,
+						ForgetBefore: now.Add(-15 * time.Minute),
+					}).
+					Return(nil).
+					Times(1)
+			}
+			it := NewAuthInteractor(users, userCreds, failures, nil, nil, nil, nil, nil, nil, AuthPolicy{
+				Lockout: entity.LoginLockoutPolicy{
+					Threshold: 3,
+					Duration:  15 * time.Minute,
+				},
+			})
+			it.now = func() time.Time { return now }
+
+			got, err := it.Authenticate(ctx, AuthenticateInput{
+				Email:    user.Email,
+				Password: "test_password",
+			})
+			assert.Equal(t, usecase.ErrInvalidCredential, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func Test_authInteractor_Authenticate_secondFactor(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	totp := &entity.TOTP{
+		UserID:    user.ID,
+		Secret:    []byte("test_secret"),
+		Confirmed: true,
+	}
+	tests := []struct {
+		name         string
+		perRequest   bool
+		validateErr  error
+		useStepErr   error
+		wantRecovery bool
+		wantRecord   bool
+		wantErr      error
+	}{
+		{
+			name: "return mfa authentication when totp code is valid",
+		},
+		{
+			name:       "return error without recording failure when totp code is used again",
+			perRequest: true,
+			useStepErr: usecase.ErrSecondFactorUsed,
+			wantErr:    usecase.ErrSecondFactorUsed,
+		},
+		{
+			name:         "return mfa authentication when logging in with recovery code",
+			validateErr:  usecase.ErrInvalidSecondFactor,
+			wantRecovery: true,
+		},
+		{
+			name:        "return error and record failure when recovery code is sent per request",
+			perRequest:  true,
+			validateErr: usecase.ErrInvalidSecondFactor,
+			wantRecord:  true,
+			wantErr:     usecase.ErrInvalidSecondFactor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := portmocks.NewUserGateway(t)
+			users.
+				On("List", ctx, port.UserListInput{Email: &user.Email}).
+				Return(entity.Users{user}, nil).
+				Times(1)
+			userCreds := portmocks.NewUserCredentialGateway(t)
+			userCreds.
+				On("Check", ctx, user.Email, entity.Password("test_password")).
+				Return(nil).
+				Times(1)
+			failures := portmocks.NewLoginFailureGateway(t)
+			failures.On("Get", ctx, user.ID).Return(&entity.LoginFailures{}, nil).Times(1)
+			if tt.wantRecord {
+				failures.
+					On("Record", ctx, port.LoginFailureRecordInput{
+						UserID:       user.ID,
+						FailedAt:     now,
+						ForgetBefore: now.Add(-15 * time.Minute),
+					}).
+					Return(nil).
+					Times(1)
+			}
+			totps := portmocks.NewTOTPGateway(t)
+			totps.On("Get", ctx, user.ID).Return(totp, nil).Times(1)
+			totpManager := portmocks.NewTOTPManager(t)
+			totpManager.
+				On("Validate", ctx, totp.Secret, "123456").
+				Return(int64(100), tt.validateErr).
+				Times(1)
+			if tt.validateErr == nil {
+				totps.On("UseStep", ctx, user.ID, int64(100)).Return(tt.useStepErr).Times(1)
+			}
+			recoveryCodes := portmocks.NewRecoveryCodeGateway(t)
+			if tt.wantRecovery {
+				recoveryCodes.On("Use", ctx, user.ID, "123456").Return(nil).Times(1)
+			}
+			it := NewAuthInteractor(users, userCreds, failures, totps, totpManager, recoveryCodes, nil, nil, nil, AuthPolicy{
+				Lockout: entity.LoginLockoutPolicy{
+					Threshold: 3,
+					Duration:  15 * time.Minute,
+				},
+			})
+			it.now = func() time.Time { return now }
+
+			got, err := it.Authenticate(ctx, AuthenticateInput{
+				Email:        user.Email,
+				Password:     "test_password",
+				SecondFactor: "123456",
+				PerRequest:   tt.perRequest,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.False(t, IsLoginFailure(err) && !tt.wantRecord, "error must be counted only when recorded")
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, entity.NewAuthentication(user, now, entity.AuthMethodPassword, entity.AuthMethodOTP), got)
+		})
+	}
+}
+
+func Test_authInteractor_Authenticate_unverifiedEmail(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	totp := &entity.TOTP{
+		UserID:    user.ID,
+		Secret:    []byte("test_secret"),
+		Confirmed: true,
+	}
+	tests := []struct {
+		name        string
+		validateErr error
+		wantRecord  bool
+		wantReset   bool
+		wantErr     error
+	}{
+		{
+			name:      "return unverified email after second factor and reset failures",
+			wantReset: true,
+			wantErr:   usecase.ErrEmailNotVerified,
+		},
+		{
+			name:        "return invalid second factor and record failure before checking email",
+			validateErr: usecase.ErrInvalidSecondFactor,
+			wantRecord:  true,
+			wantErr:     usecase.ErrInvalidSecondFactor,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := portmocks.NewUserGateway(t)
+			users.
+				On("List", ctx, port.UserListInput{Email: &user.Email}).
+				Return(entity.Users{user}, nil).
+				Times(1)
+			userCreds := portmocks.NewUserCredentialGateway(t)
+			userCreds.
+				On("Check", ctx, user.Email, entity.Password("test_password")).
+				Return(nil).
+				Times(1)
+			failures := portmocks.NewLoginFailureGateway(t)
+			failures.On("Get", ctx, user.ID).Return(&entity.LoginFailures{Count: 1, LastFailedAt: now.Add(-time.Hour)}, nil).Times(1)
+			if tt.wantRecord {
+				failures.
+					On("Record", ctx, port.LoginFailureRecordInput{
+						UserID:       user.ID,
+						FailedAt:     now,
+						ForgetBefore: now.Add(-15 * time.Minute),
+					}).
+					Return(nil).
+					Times(1)
+			}
+			if tt.wantReset {
+				failures.On("Reset", ctx, user.ID).Return(nil).Times(1)
+			}
+			totps := portmocks.NewTOTPGateway(t)
+			totps.On("Get", ctx, user.ID).Return(totp, nil).Times(1)
+			totpManager := portmocks.NewTOTPManager(t)
+			totpManager.
+				On("Validate", ctx, totp.Secret, "123456").
+				Return(int64(100), tt.validateErr).
+				Times(1)
+			if tt.validateErr == nil {
+				totps.On("UseStep", ctx, user.ID, int64(100)).Return(nil).Times(1)
+			}
+			recoveryCodes := portmocks.NewRecoveryCodeGateway(t)
+			if tt.validateErr != nil {
+				recoveryCodes.On("Use", ctx, user.ID, "123456").Return(usecase.ErrInvalidSecondFactor).Times(1)
+			}
+			it := NewAuthInteractor(users, userCreds, failures, totps, totpManager, recoveryCodes, nil, nil, nil, AuthPolicy{
+				RequireVerifiedEmail: true,
+				Lockout: entity.LoginLockoutPolicy{
+					Threshold: 3,
+					Duration:  15 * time.Minute,
+				},
+			})
+			it.now = func() time.Time { return now }
+
+			got, err := it.Authenticate(ctx, AuthenticateInput{
+				Email:        user.Email,
+				Password:     "test_password",
+				SecondFactor: "123456",
+			})
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.True(t, ShouldCommitLogin(err))
+			assert.Nil(t, got)
+		})
+	}
+}
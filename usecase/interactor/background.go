@@ -0,0 +1,58 @@
+package interactor
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrBackgroundQueueFull = errors.New("background queue is full")
+
+// BackgroundJobs runs work that outlives its request, such as sending mail,
+// on a fixed number of workers. Bursts of requests queue up rather than
+// each starting a goroutine.
+type BackgroundJobs struct {
+	jobs chan func()
+	wg   sync.WaitGroup
+}
+
+// NewBackgroundJobs starts workers that take jobs from a queue holding up
+// to queueSize waiting ones.
+func NewBackgroundJobs(workers int, queueSize int) *BackgroundJobs {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	b := &BackgroundJobs{
+		jobs: make(chan func(), queueSize),
+	}
+	b.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer b.wg.Done()
+			for job := range b.jobs {
+				job()
+			}
+		}()
+	}
+	return b
+}
+
+// Enqueue never waits for a worker, so that callers answer as fast with a
+// job as without. Jobs are dropped with ErrBackgroundQueueFull when the
+// queue is full.
+func (b *BackgroundJobs) Enqueue(job func()) error {
+	select {
+	case b.jobs <- job:
+		return nil
+	default:
+		return ErrBackgroundQueueFull
+	}
+}
+
+// Close waits for queued jobs to finish. Enqueue must not be called after.
+func (b *BackgroundJobs) Close() {
+	close(b.jobs)
+	b.wg.Wait()
+}
@@ -0,0 +1,35 @@
+package interactor
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundJobs(t *testing.T) {
+	b := NewBackgroundJobs(2, 4)
+	var done atomic.Int32
+	for i := 0; i < 4; i++ {
+		assert.NoError(t, b.Enqueue(func() { done.Add(1) }))
+	}
+	b.Close()
+	assert.Equal(t, int32(4), done.Load())
+}
+
+func TestBackgroundJobs_Enqueue_full(t *testing.T) {
+	b := NewBackgroundJobs(1, 1)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	assert.NoError(t, b.Enqueue(func() {
+		close(started)
+		<-release
+	}))
+	<-started
+
+	// The worker is busy, so one job waits and the next is dropped.
+	assert.NoError(t, b.Enqueue(func() {}))
+	assert.ErrorIs(t, b.Enqueue(func() {}), ErrBackgroundQueueFull)
+	close(release)
+	b.Close()
+}
@@ -0,0 +1,243 @@
+package interactor
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	RequestEmailLoginInput struct {
+		Email entity.Email
+	}
+	VerifyEmailLoginCodeInput struct {
+		ID   entity.ID
+		Code string
+		// SecondFactor is a TOTP or recovery code, required from users who
+		// have enabled TOTP.
+		SecondFactor string
+	}
+	VerifyEmailLoginLinkInput struct {
+		Token        string
+		SecondFactor string
+	}
+	EmailLoginPolicy struct {
+		RequireVerifiedEmail bool
+		// Lockout applies when a LoginFailureGateway is configured.
+		Lockout entity.LoginLockoutPolicy
+	}
+)
+
+var _ EmailLoginInteractor = (*emailLoginInteractor)(nil)
+
+// EmailLoginInteractor logs users in with a code or link sent to their
+// email address, which works for accounts without a password. The code or
+// link stands in for the password, so the second factor and lockout apply
+// as they do to password login.
+type EmailLoginInteractor interface {
+	// RequestLogin returns the ID the code has to be verified with.
+	RequestLogin(ctx context.Context, input RequestEmailLoginInput) (entity.ID, error)
+	VerifyCode(ctx context.Context, input VerifyEmailLoginCodeInput) (*entity.Authentication, error)
+	VerifyLink(ctx context.Context, input VerifyEmailLoginLinkInput) (*entity.Authentication, error)
+}
+
+type emailLoginInteractor struct {
+	idgen         port.IDGenerator
+	users         port.UserGateway
+	codes         port.EmailLoginCodeGateway
+	tokens        port.EmailLoginTokenManager
+	mailer        port.Mailer
+	totps         port.TOTPGateway
+	totpManager   port.TOTPManager
+	recoveryCodes port.RecoveryCodeGateway
+	lockout       loginLockout
+	policy        EmailLoginPolicy
+	now           func() time.Time
+	// enqueue runs mail sends in the background.
+	enqueue func(func()) error
+}
+
+func NewEmailLoginInteractor(
+	idgen port.IDGenerator,
+	users port.UserGateway,
+	codes port.EmailLoginCodeGateway,
+	tokens port.EmailLoginTokenManager,
+	mailer port.Mailer,
+	totps port.TOTPGateway,
+	totpManager port.TOTPManager,
+	recoveryCodes port.RecoveryCodeGateway,
+	failures port.LoginFailureGateway,
+	policy EmailLoginPolicy,
+	jobs *BackgroundJobs,
+) *emailLoginInteractor {
+	return &emailLoginInteractor{
+		idgen:         idgen,
+		users:         users,
+		codes:         codes,
+		tokens:        tokens,
+		mailer:        mailer,
+		totps:         totps,
+		totpManager:   totpManager,
+		recoveryCodes: recoveryCodes,
+		lockout: loginLockout{
+			failures: failures,
+			policy:   policy.Lockout,
+		},
+		policy:  policy,
+		now:     time.Now,
+		enqueue: jobs.Enqueue,
+	}
+}
+
+// RequestLogin issues a code for unknown emails as well, bound to a user ID
+// nobody has and never sent, so callers can not probe for accounts by what
+// is returned or how long it takes. Failed sends are only logged.
+func (it *emailLoginInteractor) RequestLogin(
+	ctx context.Context,
+	input RequestEmailLoginInput,
+) (entity.ID, error) {
+	logger := util.FromContext(ctx)
+
+	users, err := it.users.List(ctx, port.UserListInput{
+		Email: &input.Email,
+	})
+	if err != nil {
+		logger.Error(err, "failed find user")
+		return "", err
+	}
+	var user *entity.User
+	var userID entity.ID
+	if len(users) == 0 {
+		logger.Info("email login requested for unknown email")
+		userID, err = it.idgen.Generate()
+		if err != nil {
+			logger.Error(err, "failed generate id")
+			return "", err
+		}
+	} else {
+		user = users[0]
+		userID = user.ID
+	}
+	code, err := it.codes.Issue(ctx, userID)
+	if err != nil {
+		logger.Error(err, "failed issue email login code")
+		return "", err
+	}
+	if user == nil {
+		return code.ID, nil
+	}
+	// The link is signed and the mail sent in the background, so that known
+	// emails are not answered slower than unknown ones. Neither touches the
+	// request's transaction.
+	jobCtx := context.WithoutCancel(ctx)
+	err = it.enqueue(func() {
+		token, err := it.tokens.Issue(jobCtx, port.EmailLoginTokenIssueInput{
+			ID:        code.ID,
+			ExpiresAt: code.ExpiresAt,
+		})
+		if err != nil {
+			logger.Error(err, "failed issue email login token")
+			return
+		}
+		err = it.mailer.SendEmailLogin(jobCtx, port.EmailLoginMailInput{
+			To:    user.Email,
+			Name:  user.Name,
+			Code:  code.Code,
+			Token: token,
+		})
+		if err != nil {
+			logger.Error(err, "failed send email login")
+		}
+	})
+	if err != nil {
+		logger.Warn(err, "failed queue email login")
+	}
+
+	return code.ID, nil
+}
+
+func (it *emailLoginInteractor) VerifyCode(
+	ctx context.Context,
+	input VerifyEmailLoginCodeInput,
+) (*entity.Authentication, error) {
+	logger := util.FromContext(ctx)
+
+	userID, err := it.codes.Verify(ctx, input.ID, input.Code)
+	if err != nil {
+		logger.Error(err, "failed verify email login code")
+		return nil, err
+	}
+	auth, err := it.authenticate(ctx, userID, input.SecondFactor)
+	if err != nil {
+		logger.Error(err, "failed authenticate email login")
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+func (it *emailLoginInteractor) VerifyLink(
+	ctx context.Context,
+	input VerifyEmailLoginLinkInput,
+) (*entity.Authentication, error) {
+	logger := util.FromContext(ctx)
+
+	token, err := it.tokens.Parse(ctx, input.Token)
+	if err != nil {
+		logger.Error(err, "failed parse email login token")
+		return nil, err
+	}
+	userID, err := it.codes.Consume(ctx, token.ID)
+	if err != nil {
+		logger.Error(err, "failed consume email login code")
+		return nil, err
+	}
+	auth, err := it.authenticate(ctx, userID, input.SecondFactor)
+	if err != nil {
+		logger.Error(err, "failed authenticate email login")
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+// authenticate applies the checks password login makes once the password
+// matched.
+func (it *emailLoginInteractor) authenticate(ctx context.Context, userID entity.ID, secondFactor string) (*entity.Authentication, error) {
+	user, err := it.users.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	failures, err := it.lockout.get(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := it.now()
+	if it.lockout.policy.Refuses(failures, now) {
+		util.FromContext(ctx).Info("login refused by lockout", "userID", user.ID)
+		return nil, usecase.ErrInvalidCredential
+	}
+	methods := []string{entity.AuthMethodEmail}
+	// TOTP is disabled when no gateway is configured.
+	if it.totps != nil {
+		verified, err := verifySecondFactor(ctx, it.totps, it.totpManager, it.recoveryCodes, user.ID, secondFactor)
+		if err != nil {
+			return nil, it.lockout.recordFailure(ctx, user.ID, now, err)
+		}
+		if verified {
+			methods = append(methods, entity.AuthMethodOTP)
+		}
+	}
+	if err := it.lockout.resetFailures(ctx, user.ID, failures); err != nil {
+		return nil, err
+	}
+	if it.policy.RequireVerifiedEmail && !user.EmailVerified {
+		return nil, usecase.ErrEmailNotVerified
+	}
+
+	return entity.NewAuthentication(user, now, methods...), nil
+}
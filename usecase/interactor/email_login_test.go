@@ -0,0 +1,324 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_emailLoginInteractor_RequestLogin(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	code := &port.EmailLoginCode{
+		ID:        "test_code_id_001",
+		Code:      "123456",
+		ExpiresAt: time.Unix(1700000600, 0),
+	}
+	tests := []struct {
+		name        string
+		users       entity.Users
+		sendErr     error
+		enqueueErr  error
+		wantUserID  entity.ID
+		wantEnqueue bool
+	}{
+		{
+			name:        "return code id and send email in background",
+			users:       entity.Users{user},
+			wantUserID:  user.ID,
+			wantEnqueue: true,
+		},
+		{
+			name:        "return code id when email fails",
+			users:       entity.Users{user},
+			sendErr:     errors.New("test_error"),
+			wantUserID:  user.ID,
+			wantEnqueue: true,
+		},
+		{
+			name:       "return code id when background queue is full",
+			users:      entity.Users{user},
+			enqueueErr: ErrBackgroundQueueFull,
+			wantUserID: user.ID,
+		},
+		{
+			name:       "return code id issued for generated user id without email when email is unknown",
+			wantUserID: "test_generated_id_001",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			users := portmocks.NewUserGateway(t)
+			users.
+				On("List", ctx, port.UserListInput{Email: &user.Email}).
+				Return(tt.users, nil).
+				Times(1)
+			idgen := portmocks.NewIDGenerator(t)
+			codes := portmocks.NewEmailLoginCodeGateway(t)
+			tokens := portmocks.NewEmailLoginTokenManager(t)
+			mailer := portmocks.NewMailer(t)
+			if len(tt.users) == 0 {
+				idgen.On("Generate").Return(entity.ID("test_generated_id_001"), nil).Times(1)
+			}
+			// Unknown emails get a code too, so that they make the same
+			// queries as known ones.
+			codes.On("Issue", ctx, tt.wantUserID).Return(code, nil).Times(1)
+			if tt.wantEnqueue {
+				notCanceled := mock.MatchedBy(func(ctx context.Context) bool {
+					return ctx.Err() == nil
+				})
+				tokens.
+					On("Issue", notCanceled, port.EmailLoginTokenIssueInput{
+						ID:        code.ID,
+						ExpiresAt: code.ExpiresAt,
+					}).
+					Return("test_token", nil).
+					Times(1)
+				mailer.
+					On("SendEmailLogin", notCanceled, port.EmailLoginMailInput{
+						To:    user.Email,
+						Name:  user.Name,
+						Code:  code.Code,
+						Token: "test_token",
+					}).
+					Return(tt.sendErr).
+					Times(1)
+			}
+			var enqueued []func()
+			it := &emailLoginInteractor{
+				idgen:  idgen,
+				users:  users,
+				codes:  codes,
+				tokens: tokens,
+				mailer: mailer,
+				enqueue: func(f func()) error {
+					if tt.enqueueErr != nil {
+						return tt.enqueueErr
+					}
+					enqueued = append(enqueued, f)
+					return nil
+				},
+			}
+			got, err := it.RequestLogin(ctx, RequestEmailLoginInput{Email: user.Email})
+			assert.NoError(t, err)
+			assert.Equal(t, code.ID, got, "emailLoginInteractor.RequestLogin() = %v, want %v", got, code.ID)
+			if !tt.wantEnqueue {
+				assert.Empty(t, enqueued)
+				return
+			}
+			// The link is signed and the email sent after the request is done.
+			assert.Len(t, enqueued, 1)
+			tokens.AssertNotCalled(t, "Issue")
+			mailer.AssertNotCalled(t, "SendEmailLogin")
+			cancel()
+			enqueued[0]()
+		})
+	}
+}
+
+func Test_emailLoginInteractor_VerifyCode(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	totp := &entity.TOTP{
+		UserID:    user.ID,
+		Secret:    []byte("test_secret"),
+		Confirmed: true,
+	}
+	input := VerifyEmailLoginCodeInput{
+		ID:           "test_code_id_001",
+		Code:         "123456",
+		SecondFactor: "654321",
+	}
+	lockout := entity.LoginLockoutPolicy{
+		Threshold: 3,
+		Duration:  15 * time.Minute,
+	}
+	tests := []struct {
+		name        string
+		policy      EmailLoginPolicy
+		verifyErr   error
+		failures    *entity.LoginFailures
+		totp        *entity.TOTP
+		validateErr error
+		wantRecord  bool
+		wantReset   bool
+		want        *entity.Authentication
+		wantErr     error
+	}{
+		{
+			name:     "return authentication by email",
+			failures: &entity.LoginFailures{},
+			want:     entity.NewAuthentication(user, now, entity.AuthMethodEmail),
+		},
+		{
+			name:      "return mfa authentication and reset failures when second factor is valid",
+			failures:  &entity.LoginFailures{Count: 1, LastFailedAt: now.Add(-time.Minute)},
+			totp:      totp,
+			wantReset: true,
+			want:      entity.NewAuthentication(user, now, entity.AuthMethodEmail, entity.AuthMethodOTP),
+		},
+		{
+			name:        "return error and record failure when second factor is wrong",
+			failures:    &entity.LoginFailures{},
+			totp:        totp,
+			validateErr: usecase.ErrInvalidSecondFactor,
+			wantRecord:  true,
+			wantErr:     usecase.ErrInvalidSecondFactor,
+		},
+		{
+			name:     "return error when locked out",
+			failures: &entity.LoginFailures{Count: 3, LastFailedAt: now.Add(-time.Minute)},
+			wantErr:  usecase.ErrInvalidCredential,
+		},
+		{
+			name:      "return error when code is wrong",
+			verifyErr: usecase.ErrInvalidCredential,
+			wantErr:   usecase.ErrInvalidCredential,
+		},
+		{
+			name:      "return error when code is exhausted",
+			verifyErr: usecase.ErrInvalidToken,
+			wantErr:   usecase.ErrInvalidToken,
+		},
+		{
+			name: "return error when email is not verified",
+			policy: EmailLoginPolicy{
+				RequireVerifiedEmail: true,
+			},
+			failures: &entity.LoginFailures{},
+			wantErr:  usecase.ErrEmailNotVerified,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			codes := portmocks.NewEmailLoginCodeGateway(t)
+			users := portmocks.NewUserGateway(t)
+			failures := portmocks.NewLoginFailureGateway(t)
+			totps := portmocks.NewTOTPGateway(t)
+			totpManager := portmocks.NewTOTPManager(t)
+			recoveryCodes := portmocks.NewRecoveryCodeGateway(t)
+			if tt.verifyErr != nil {
+				codes.On("Verify", ctx, input.ID, input.Code).Return(entity.ID(""), tt.verifyErr).Times(1)
+			} else {
+				codes.On("Verify", ctx, input.ID, input.Code).Return(user.ID, nil).Times(1)
+				users.On("Get", ctx, user.ID).Return(user, nil).Times(1)
+				failures.On("Get", ctx, user.ID).Return(tt.failures, nil).Times(1)
+			}
+			if !lockout.Refuses(tt.failures, now) && tt.verifyErr == nil {
+				if tt.totp != nil {
+					totps.On("Get", ctx, user.ID).Return(tt.totp, nil).Times(1)
+					totpManager.
+						On("Validate", ctx, tt.totp.Secret, input.SecondFactor).
+						Return(int64(1), tt.validateErr).
+						Times(1)
+					if tt.validateErr == nil {
+						totps.On("UseStep", ctx, user.ID, int64(1)).Return(nil).Times(1)
+					} else {
+						recoveryCodes.On("Use", ctx, user.ID, input.SecondFactor).Return(tt.validateErr).Times(1)
+					}
+				} else {
+					totps.On("Get", ctx, user.ID).Return(nil, usecase.ErrNotFoundEntity).Times(1)
+				}
+			}
+			if tt.wantRecord {
+				failures.
+					On("Record", ctx, port.LoginFailureRecordInput{
+						UserID:       user.ID,
+						FailedAt:     now,
+						ForgetBefore: now.Add(-15 * time.Minute),
+					}).
+					Return(nil).
+					Times(1)
+			}
+			if tt.wantReset {
+				failures.On("Reset", ctx, user.ID).Return(nil).Times(1)
+			}
+			tt.policy.Lockout = lockout
+			it := NewEmailLoginInteractor(nil, users, codes, nil, nil, totps, totpManager, recoveryCodes, failures, tt.policy, nil)
+			it.now = func() time.Time { return now }
+
+			got, err := it.VerifyCode(ctx, input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "emailLoginInteractor.VerifyCode() error = %v, wantErr %v", err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "emailLoginInteractor.VerifyCode() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func Test_emailLoginInteractor_VerifyLink(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	tests := []struct {
+		name     string
+		parseErr error
+		want     *entity.Authentication
+		wantErr  error
+	}{
+		{
+			name: "return authentication by email",
+			want: entity.NewAuthentication(user, now, entity.AuthMethodEmail),
+		},
+		{
+			name:     "return error when token is invalid",
+			parseErr: usecase.ErrInvalidToken,
+			wantErr:  usecase.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			tokens := portmocks.NewEmailLoginTokenManager(t)
+			codes := portmocks.NewEmailLoginCodeGateway(t)
+			users := portmocks.NewUserGateway(t)
+			if tt.parseErr != nil {
+				tokens.On("Parse", ctx, "test_token").Return(nil, tt.parseErr).Times(1)
+			} else {
+				tokens.
+					On("Parse", ctx, "test_token").
+					Return(&port.EmailLoginToken{ID: "test_code_id_001"}, nil).
+					Times(1)
+				codes.On("Consume", ctx, entity.ID("test_code_id_001")).Return(user.ID, nil).Times(1)
+				users.On("Get", ctx, user.ID).Return(user, nil).Times(1)
+			}
+			it := &emailLoginInteractor{
+				users:  users,
+				codes:  codes,
+				tokens: tokens,
+				now:    func() time.Time { return now },
+			}
+			got, err := it.VerifyLink(ctx, VerifyEmailLoginLinkInput{Token: "test_token"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "emailLoginInteractor.VerifyLink() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "emailLoginInteractor.VerifyLink() = %v, want %v", got, tt.want)
+		})
+	}
+}
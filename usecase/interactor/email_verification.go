@@ -0,0 +1,97 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	VerifyEmailInput struct {
+		Token string
+	}
+)
+
+var _ EmailVerificationInteractor = (*emailVerificationInteractor)(nil)
+
+type EmailVerificationInteractor interface {
+	VerifyEmail(ctx context.Context, input VerifyEmailInput) (*entity.User, error)
+}
+
+type emailVerificationInteractor struct {
+	users              port.UserGateway
+	verificationTokens port.EmailVerificationTokenManager
+}
+
+func NewEmailVerificationInteractor(
+	users port.UserGateway,
+	verificationTokens port.EmailVerificationTokenManager,
+) *emailVerificationInteractor {
+	return &emailVerificationInteractor{
+		users:              users,
+		verificationTokens: verificationTokens,
+	}
+}
+
+func (it *emailVerificationInteractor) VerifyEmail(
+	ctx context.Context,
+	input VerifyEmailInput,
+) (*entity.User, error) {
+	logger := util.FromContext(ctx)
+
+	token, err := it.verificationTokens.Parse(ctx, input.Token)
+	if err != nil {
+		logger.Error(err, "failed parse email verification token")
+		return nil, err
+	}
+	user, err := it.users.Get(ctx, token.UserID)
+	if err != nil {
+		logger.Error(err, "failed get user")
+		return nil, err
+	}
+	// The token is bound to the address it was sent to, so a link for a
+	// previous address must not verify the current one.
+	if user.Email != token.Email {
+		return nil, usecase.ErrInvalidToken
+	}
+	if user.EmailVerified {
+		return user, nil
+	}
+
+	user, err = it.users.Update(ctx, port.UserUpdateInput{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: true,
+	})
+	if err != nil {
+		logger.Error(err, "failed update user")
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func sendEmailVerification(
+	ctx context.Context,
+	verificationTokens port.EmailVerificationTokenManager,
+	mailer port.Mailer,
+	user *entity.User,
+) error {
+	token, err := verificationTokens.Issue(ctx, port.EmailVerificationTokenIssueInput{
+		UserID: user.ID,
+		Email:  user.Email,
+	})
+	if err != nil {
+		return err
+	}
+
+	return mailer.SendEmailVerification(ctx, port.EmailVerificationMailInput{
+		To:    user.Email,
+		Name:  user.Name,
+		Token: token,
+	})
+}
@@ -0,0 +1,239 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_emailVerificationInteractor_VerifyEmail(t *testing.T) {
+	type mockTokenParseReturn struct {
+		token *port.EmailVerificationToken
+		err   error
+	}
+	type mockUserGetReturn struct {
+		user *entity.User
+		err  error
+	}
+	type mockUserUpdateReturn struct {
+		user *entity.User
+		err  error
+	}
+	type mockReturn struct {
+		tokenParse *mockTokenParseReturn
+		userGet    *mockUserGetReturn
+		userUpdate *mockUserUpdateReturn
+	}
+	type args struct {
+		ctx   context.Context
+		input VerifyEmailInput
+	}
+	tests := []struct {
+		name       string
+		args       args
+		mockReturn mockReturn
+		want       *entity.User
+		wantErr    error
+	}{
+		{
+			name: "return verified user",
+			args: args{
+				ctx: context.Background(),
+				input: VerifyEmailInput{
+					Token: "test_token",
+				},
+			},
+			mockReturn: mockReturn{
+				tokenParse: &mockTokenParseReturn{
+					token: &port.EmailVerificationToken{
+						UserID:    "test_user_id_001",
+						Email:     "test_001@example.com",
+						ExpiresAt: time.Now().Add(time.Hour),
+					},
+				},
+				userGet: &mockUserGetReturn{
+					user: &entity.User{
+						ID:    "test_user_id_001",
+						Name:  "test_user_001",
+						Email: "test_001@example.com",
+					},
+				},
+				userUpdate: &mockUserUpdateReturn{
+					user: &entity.User{
+						ID:            "test_user_id_001",
+						Name:          "test_user_001",
+						Email:         "test_001@example.com",
+						EmailVerified: true,
+					},
+				},
+			},
+			want: &entity.User{
+				ID:            "test_user_id_001",
+				Name:          "test_user_001",
+				Email:         "test_001@example.com",
+				EmailVerified: true,
+			},
+		},
+		{
+			name: "return user without update when already verified",
+			args: args{
+				ctx: context.Background(),
+				input: VerifyEmailInput{
+					Token: "test_token",
+				},
+			},
+			mockReturn: mockReturn{
+				tokenParse: &mockTokenParseReturn{
+					token: &port.EmailVerificationToken{
+						UserID:    "test_user_id_001",
+						Email:     "test_001@example.com",
+						ExpiresAt: time.Now().Add(time.Hour),
+					},
+				},
+				userGet: &mockUserGetReturn{
+					user: &entity.User{
+						ID:            "test_user_id_001",
+						Name:          "test_user_001",
+						Email:         "test_001@example.com",
+						EmailVerified: true,
+					},
+				},
+			},
+			want: &entity.User{
+				ID:            "test_user_id_001",
+				Name:          "test_user_001",
+				Email:         "test_001@example.com",
+				EmailVerified: true,
+			},
+		},
+		{
+			name: "return error when token was issued for previous email",
+			args: args{
+				ctx: context.Background(),
+				input: VerifyEmailInput{
+					Token: "test_token",
+				},
+			},
+			mockReturn: mockReturn{
+				tokenParse: &mockTokenParseReturn{
+					token: &port.EmailVerificationToken{
+						UserID:    "test_user_id_001",
+						Email:     "old@example.com",
+						ExpiresAt: time.Now().Add(time.Hour),
+					},
+				},
+				userGet: &mockUserGetReturn{
+					user: &entity.User{
+						ID:    "test_user_id_001",
+						Name:  "test_user_001",
+						Email: "test_001@example.com",
+					},
+				},
+			},
+			want:    nil,
+			wantErr: usecase.ErrInvalidToken,
+		},
+		{
+			name: "return error when token is invalid",
+			args: args{
+				ctx: context.Background(),
+				input: VerifyEmailInput{
+					Token: "test_token",
+				},
+			},
+			mockReturn: mockReturn{
+				tokenParse: &mockTokenParseReturn{
+					err: usecase.ErrInvalidToken,
+				},
+			},
+			want:    nil,
+			wantErr: usecase.ErrInvalidToken,
+		},
+		{
+			name: "return error when user update failed",
+			args: args{
+				ctx: context.Background(),
+				input: VerifyEmailInput{
+					Token: "test_token",
+				},
+			},
+			mockReturn: mockReturn{
+				tokenParse: &mockTokenParseReturn{
+					token: &port.EmailVerificationToken{
+						UserID:    "test_user_id_001",
+						Email:     "test_001@example.com",
+						ExpiresAt: time.Now().Add(time.Hour),
+					},
+				},
+				userGet: &mockUserGetReturn{
+					user: &entity.User{
+						ID:    "test_user_id_001",
+						Name:  "test_user_001",
+						Email: "test_001@example.com",
+					},
+				},
+				userUpdate: &mockUserUpdateReturn{
+					err: errors.New("failed to update user"),
+				},
+			},
+			want:    nil,
+			wantErr: errors.New("failed to update user"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verificationTokens := portmocks.NewEmailVerificationTokenManager(t)
+			if tt.mockReturn.tokenParse != nil {
+				verificationTokens.
+					On("Parse", tt.args.ctx, tt.args.input.Token).
+					Return(
+						tt.mockReturn.tokenParse.token,
+						tt.mockReturn.tokenParse.err,
+					).
+					Times(1)
+			}
+			users := portmocks.NewUserGateway(t)
+			if tt.mockReturn.userGet != nil {
+				users.
+					On("Get", tt.args.ctx, tt.mockReturn.tokenParse.token.UserID).
+					Return(
+						tt.mockReturn.userGet.user,
+						tt.mockReturn.userGet.err,
+					).
+					Times(1)
+			}
+			if tt.mockReturn.userUpdate != nil {
+				users.
+					On("Update", tt.args.ctx, port.UserUpdateInput{
+						ID:            tt.mockReturn.userGet.user.ID,
+						Name:          tt.mockReturn.userGet.user.Name,
+						Email:         tt.mockReturn.userGet.user.Email,
+						EmailVerified: true,
+					}).
+					Return(
+						tt.mockReturn.userUpdate.user,
+						tt.mockReturn.userUpdate.err,
+					).
+					Times(1)
+			}
+			it := &emailVerificationInteractor{
+				users:              users,
+				verificationTokens: verificationTokens,
+			}
+			got, err := it.VerifyEmail(tt.args.ctx, tt.args.input)
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error(), "emailVerificationInteractor.VerifyEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "emailVerificationInteractor.VerifyEmail() = %v, want %v", got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	CreateGroupInput struct {
+		Name        string
+		Description string
+	}
+	UpdateGroupInput struct {
+		ID          entity.ID
+		Name        string
+		Description string
+	}
+	GroupUserInput struct {
+		GroupID entity.ID
+		UserID  entity.ID
+	}
+	SubgroupInput struct {
+		GroupID    entity.ID
+		SubgroupID entity.ID
+	}
+)
+
+var _ GroupInteractor = (*groupInteractor)(nil)
+
+type GroupInteractor interface {
+	ListGroups(ctx context.Context) (entity.Groups, error)
+	GetGroup(ctx context.Context, id entity.ID) (*entity.Group, error)
+	CreateGroup(ctx context.Context, input CreateGroupInput) (*entity.Group, error)
+	UpdateGroup(ctx context.Context, input UpdateGroupInput) (*entity.Group, error)
+	DeleteGroup(ctx context.Context, id entity.ID) error
+	ListGroupMembers(ctx context.Context, id entity.ID) (*entity.GroupMembers, error)
+	AddGroupUser(ctx context.Context, input GroupUserInput) error
+	RemoveGroupUser(ctx context.Context, input GroupUserInput) error
+	// AddSubgroup makes the subgroup's members members of the group, and
+	// returns entity.ErrGroupCycle when the group is the subgroup or is
+	// nested in it.
+	AddSubgroup(ctx context.Context, input SubgroupInput) error
+	RemoveSubgroup(ctx context.Context, input SubgroupInput) error
+	// ListUserGroups returns the groups the user is a member of, directly
+	// or through subgroups.
+	ListUserGroups(ctx context.Context, userID entity.ID) (entity.Groups, error)
+}
+
+type groupInteractor struct {
+	users  port.UserGateway
+	groups port.GroupGateway
+}
+
+func NewGroupInteractor(
+	users port.UserGateway,
+	groups port.GroupGateway,
+) *groupInteractor {
+	return &groupInteractor{
+		users:  users,
+		groups: groups,
+	}
+}
+
+func (it *groupInteractor) ListGroups(ctx context.Context) (entity.Groups, error) {
+	logger := util.FromContext(ctx)
+
+	groups, err := it.groups.List(ctx)
+	if err != nil {
+		logger.Error(err, "failed list groups")
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+func (it *groupInteractor) GetGroup(ctx context.Context, id entity.ID) (*entity.Group, error) {
+	logger := util.FromContext(ctx)
+
+	group, err := it.groups.Get(ctx, id)
+	if err != nil {
+		logger.Error(err, "failed get group")
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (it *groupInteractor) CreateGroup(ctx context.Context, input CreateGroupInput) (*entity.Group, error) {
+	logger := util.FromContext(ctx)
+
+	group, err := it.groups.Create(ctx, port.GroupCreateInput{
+		Name:        input.Name,
+		Description: input.Description,
+	})
+	if err != nil {
+		logger.Error(err, "failed create group")
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (it *groupInteractor) UpdateGroup(ctx context.Context, input UpdateGroupInput) (*entity.Group, error) {
+	logger := util.FromContext(ctx)
+
+	group, err := it.groups.Update(ctx, port.GroupUpdateInput{
+		ID:          input.ID,
+		Name:        input.Name,
+		Description: input.Description,
+	})
+	if err != nil {
+		logger.Error(err, "failed update group")
+		return nil, err
+	}
+
+	return group, nil
+}
+
+func (it *groupInteractor) DeleteGroup(ctx context.Context, id entity.ID) error {
+	logger := util.FromContext(ctx)
+
+	if err := it.groups.Delete(ctx, id); err != nil {
+		logger.Error(err, "failed delete group")
+		return err
+	}
+
+	return nil
+}
+
+func (it *groupInteractor) ListGroupMembers(ctx context.Context, id entity.ID) (*entity.GroupMembers, error) {
+	logger := util.FromContext(ctx)
+
+	if _, err := it.groups.Get(ctx, id); err != nil {
+		logger.Error(err, "failed get group")
+		return nil, err
+	}
+	members, err := it.groups.ListMembers(ctx, id)
+	if err != nil {
+		logger.Error(err, "failed list group members")
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (it *groupInteractor) AddGroupUser(ctx context.Context, input GroupUserInput) error {
+	logger := util.FromContext(ctx)
+
+	if _, err := it.groups.Get(ctx, input.GroupID); err != nil {
+		logger.Error(err, "failed get group")
+		return err
+	}
+	if _, err := it.users.Get(ctx, input.UserID); err != nil {
+		logger.Error(err, "failed get user")
+		return err
+	}
+	if err := it.groups.AddUser(ctx, input.GroupID, input.UserID); err != nil {
+		logger.Error(err, "failed add group user")
+		return err
+	}
+
+	return nil
+}
+
+func (it *groupInteractor) RemoveGroupUser(ctx context.Context, input GroupUserInput) error {
+	logger := util.FromContext(ctx)
+
+	if err := it.groups.RemoveUser(ctx, input.GroupID, input.UserID); err != nil {
+		logger.Error(err, "failed remove group user")
+		return err
+	}
+
+	return nil
+}
+
+func (it *groupInteractor) AddSubgroup(ctx context.Context, input SubgroupInput) error {
+	logger := util.FromContext(ctx)
+
+	if _, err := it.groups.Get(ctx, input.GroupID); err != nil {
+		logger.Error(err, "failed get group")
+		return err
+	}
+	if _, err := it.groups.Get(ctx, input.SubgroupID); err != nil {
+		logger.Error(err, "failed get subgroup")
+		return err
+	}
+	ancestorIDs, err := it.groups.ListAncestorIDs(ctx, input.GroupID)
+	if err != nil {
+		logger.Error(err, "failed list group ancestors")
+		return err
+	}
+	for _, id := range ancestorIDs {
+		if id == input.SubgroupID {
+			return entity.ErrGroupCycle
+		}
+	}
+	if err := it.groups.AddSubgroup(ctx, input.GroupID, input.SubgroupID); err != nil {
+		logger.Error(err, "failed add subgroup")
+		return err
+	}
+
+	return nil
+}
+
+func (it *groupInteractor) RemoveSubgroup(ctx context.Context, input SubgroupInput) error {
+	logger := util.FromContext(ctx)
+
+	if err := it.groups.RemoveSubgroup(ctx, input.GroupID, input.SubgroupID); err != nil {
+		logger.Error(err, "failed remove subgroup")
+		return err
+	}
+
+	return nil
+}
+
+func (it *groupInteractor) ListUserGroups(ctx context.Context, userID entity.ID) (entity.Groups, error) {
+	logger := util.FromContext(ctx)
+
+	groups, err := it.groups.ListByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(err, "failed list user groups")
+		return nil, err
+	}
+
+	return groups, nil
+}
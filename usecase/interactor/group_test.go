@@ -0,0 +1,133 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_groupInteractor_AddSubgroup(t *testing.T) {
+	parent := &entity.Group{ID: "test_group_id_001", Name: "engineering"}
+	child := &entity.Group{ID: "test_group_id_002", Name: "platform"}
+	tests := []struct {
+		name        string
+		input       SubgroupInput
+		subgroupErr error
+		ancestorIDs []entity.ID
+		wantAdd     bool
+		wantErr     error
+	}{
+		{
+			name: "add subgroup",
+			input: SubgroupInput{
+				GroupID:    parent.ID,
+				SubgroupID: child.ID,
+			},
+			ancestorIDs: []entity.ID{parent.ID},
+			wantAdd:     true,
+		},
+		{
+			name: "return error when group is nested in subgroup",
+			input: SubgroupInput{
+				GroupID:    parent.ID,
+				SubgroupID: child.ID,
+			},
+			ancestorIDs: []entity.ID{parent.ID, "test_group_id_003", child.ID},
+			wantErr:     entity.ErrGroupCycle,
+		},
+		{
+			name: "return error when group is subgroup",
+			input: SubgroupInput{
+				GroupID:    parent.ID,
+				SubgroupID: parent.ID,
+			},
+			ancestorIDs: []entity.ID{parent.ID},
+			wantErr:     entity.ErrGroupCycle,
+		},
+		{
+			name: "return error when subgroup is unknown",
+			input: SubgroupInput{
+				GroupID:    parent.ID,
+				SubgroupID: child.ID,
+			},
+			subgroupErr: usecase.ErrNotFoundEntity,
+			wantErr:     usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			groups := portmocks.NewGroupGateway(t)
+			groups.On("Get", ctx, parent.ID).Return(parent, nil)
+			if tt.input.SubgroupID == child.ID {
+				groups.On("Get", ctx, child.ID).Return(child, tt.subgroupErr).Times(1)
+			}
+			if tt.subgroupErr == nil {
+				groups.On("ListAncestorIDs", ctx, parent.ID).Return(tt.ancestorIDs, nil).Times(1)
+			}
+			if tt.wantAdd {
+				groups.On("AddSubgroup", ctx, parent.ID, child.ID).Return(nil).Times(1)
+			}
+			it := NewGroupInteractor(portmocks.NewUserGateway(t), groups)
+
+			err := it.AddSubgroup(ctx, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_groupInteractor_AddGroupUser(t *testing.T) {
+	group := &entity.Group{ID: "test_group_id_001", Name: "engineering"}
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	tests := []struct {
+		name    string
+		userErr error
+		wantAdd bool
+		wantErr error
+	}{
+		{
+			name:    "add user",
+			wantAdd: true,
+		},
+		{
+			name:    "return error when user is unknown",
+			userErr: usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			groups := portmocks.NewGroupGateway(t)
+			groups.On("Get", ctx, group.ID).Return(group, nil).Times(1)
+			users := portmocks.NewUserGateway(t)
+			users.On("Get", ctx, user.ID).Return(user, tt.userErr).Times(1)
+			if tt.wantAdd {
+				groups.On("AddUser", ctx, group.ID, user.ID).Return(nil).Times(1)
+			}
+			it := NewGroupInteractor(users, groups)
+
+			err := it.AddGroupUser(ctx, GroupUserInput{
+				GroupID: group.ID,
+				UserID:  user.ID,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	UnlockLoginInput struct {
+		Email entity.Email
+	}
+)
+
+var _ LoginLockoutInteractor = (*loginLockoutInteractor)(nil)
+
+// LoginLockoutInteractor lets administrators unlock accounts before their
+// lockout ends.
+type LoginLockoutInteractor interface {
+	Unlock(ctx context.Context, input UnlockLoginInput) (*entity.User, error)
+}
+
+type loginLockoutInteractor struct {
+	users    port.UserGateway
+	failures port.LoginFailureGateway
+}
+
+func NewLoginLockoutInteractor(
+	users port.UserGateway,
+	failures port.LoginFailureGateway,
+) *loginLockoutInteractor {
+	return &loginLockoutInteractor{
+		users:    users,
+		failures: failures,
+	}
+}
+
+func (it *loginLockoutInteractor) Unlock(ctx context.Context, input UnlockLoginInput) (*entity.User, error) {
+	logger := util.FromContext(ctx)
+
+	users, err := it.users.List(ctx, port.UserListInput{
+		Email: &input.Email,
+	})
+	if err != nil {
+		logger.Error(err, "failed find user")
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	user := users[0]
+	if err := it.failures.Reset(ctx, user.ID); err != nil {
+		logger.Error(err, "failed reset login failures")
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// loginLockout counts the failed logins of known users, and refuses them
+// while the policy locks them out. It is shared by every flow that logs
+// users in, so that lockout applies to all of them alike.
+type loginLockout struct {
+	// failures is optional, nil disables lockout.
+	failures port.LoginFailureGateway
+	policy   entity.LoginLockoutPolicy
+}
+
+// get returns nil when lockout is disabled.
+func (l loginLockout) get(ctx context.Context, userID entity.ID) (*entity.LoginFailures, error) {
+	if l.failures == nil {
+		return nil, nil
+	}
+	return l.failures.Get(ctx, userID)
+}
+
+// recordFailure counts wrong passwords and second factors, and returns
+// err. Other errors are not the user's guesses, so they are not counted.
+func (l loginLockout) recordFailure(ctx context.Context, userID entity.ID, now time.Time, err error) error {
+	if l.failures == nil || !IsLoginFailure(err) {
+		return err
+	}
+	rErr := l.failures.Record(ctx, port.LoginFailureRecordInput{
+		UserID:       userID,
+		FailedAt:     now,
+		ForgetBefore: now.Add(-l.policy.Duration),
+	})
+	if rErr != nil {
+		util.FromContext(ctx).Error(rErr, "failed record login failure")
+		return rErr
+	}
+	return err
+}
+
+func (l loginLockout) resetFailures(ctx context.Context, userID entity.ID, failures *entity.LoginFailures) error {
+	if l.failures == nil || failures == nil || failures.Count == 0 {
+		return nil
+	}
+	return l.failures.Reset(ctx, userID)
+}
+
+// passwordChecker checks the passwords of known users for every flow that
+// takes one, under lockout.
+type passwordChecker struct {
+	userCreds port.UserCredentialGateway
+	lockout   loginLockout
+}
+
+// check returns usecase.ErrInvalidCredential when the password is wrong,
+// or the user is refused by lockout whatever the password. The password
+// is checked either way, so that refusals take as long as for unknown
+// emails. It returns the failures counted so far, for resetFailures once
+// the login succeeds.
+func (c passwordChecker) check(
+	ctx context.Context,
+	userID entity.ID,
+	email entity.Email,
+	password entity.Password,
+	now time.Time,
+) (*entity.LoginFailures, error) {
+	logger := util.FromContext(ctx)
+
+	failures, err := c.lockout.get(ctx, userID)
+	if err != nil {
+		logger.Error(err, "failed get login failures")
+		return nil, err
+	}
+	refused := c.lockout.policy.Refuses(failures, now)
+	err = c.userCreds.Check(ctx, email, password)
+	if errors.Is(err, usecase.ErrNoAuthUser) {
+		err = usecase.ErrInvalidCredential
+	}
+	if err != nil && !IsLoginFailure(err) {
+		logger.Error(err, "failed check user credentials")
+		return nil, err
+	}
+	if refused {
+		logger.Info("login refused by lockout", "userID", userID)
+		return nil, usecase.ErrInvalidCredential
+	}
+	if err != nil {
+		logger.Info("wrong password", "userID", userID)
+		return nil, c.lockout.recordFailure(ctx, userID, now, err)
+	}
+
+	return failures, nil
+}
+
+// IsLoginFailure reports whether err is a wrong password or second factor.
+// Such failures are counted towards lockout, so the transaction must be
+// committed despite them.
+func IsLoginFailure(err error) bool {
+	return errors.Is(err, usecase.ErrInvalidCredential) || errors.Is(err, usecase.ErrInvalidSecondFactor)
+}
+
+// ShouldCommitLogin reports whether a login that failed with err changed
+// lockout state, so that its transaction must be committed. Login
+// failures were counted, and unverified emails are only reported once
+// the counted failures were reset.
+func ShouldCommitLogin(err error) bool {
+	return IsLoginFailure(err) || errors.Is(err, usecase.ErrEmailNotVerified)
+}
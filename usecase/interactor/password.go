@@ -0,0 +1,331 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	ChangePasswordInput struct {
+		UserID          entity.ID
+		CurrentPassword entity.Password
+		NewPassword     entity.Password
+		// CurrentSessionID is the session making the request, if any, which
+		// is kept while the user's other sessions are revoked.
+		CurrentSessionID *entity.ID
+	}
+	RequestPasswordResetInput struct {
+		Email entity.Email
+	}
+	ResetPasswordInput struct {
+		Token       string
+		NewPassword entity.Password
+	}
+)
+
+var _ PasswordInteractor = (*passwordInteractor)(nil)
+
+type PasswordInteractor interface {
+	ChangePassword(ctx context.Context, input ChangePasswordInput) error
+	RequestPasswordReset(ctx context.Context, input RequestPasswordResetInput) error
+	ResetPassword(ctx context.Context, input ResetPasswordInput) error
+}
+
+type passwordInteractor struct {
+	users            port.UserGateway
+	userCreds        port.UserCredentialGateway
+	resetTokens      port.PasswordResetTokenManager
+	mailer           port.Mailer
+	sessions         port.SessionStore
+	currentPasswords passwordChecker
+	newPasswords     newPasswordHasher
+	now              func() time.Time
+	// enqueue runs mail sends in the background.
+	enqueue func(func()) error
+}
+
+func NewPasswordInteractor(
+	users port.UserGateway,
+	userCreds port.UserCredentialGateway,
+	resetTokens port.PasswordResetTokenManager,
+	mailer port.Mailer,
+	sessions port.SessionStore,
+	passwordManager port.PasswordManager,
+	passwordPolicy entity.PasswordPolicy,
+	breachedPasswords port.BreachedPasswordChecker,
+	passwordHistory port.PasswordHistoryGateway,
+	loginFailures port.LoginFailureGateway,
+	lockout entity.LoginLockoutPolicy,
+	jobs *BackgroundJobs,
+) *passwordInteractor {
+	return &passwordInteractor{
+		users:       users,
+		userCreds:   userCreds,
+		resetTokens: resetTokens,
+		mailer:      mailer,
+		sessions:    sessions,
+		currentPasswords: passwordChecker{
+			userCreds: userCreds,
+			lockout: loginLockout{
+				failures: loginFailures,
+				policy:   lockout,
+			},
+		},
+		newPasswords: newPasswordHasher{
+			policy:          passwordPolicy,
+			passwordManager: passwordManager,
+			breaches:        breachedPasswords,
+			history:         passwordHistory,
+		},
+		now:     time.Now,
+		enqueue: jobs.Enqueue,
+	}
+}
+
+// ChangePassword checks the current password like a login, so that wrong
+// ones count towards lockout. Sessions other than the current one are
+// revoked, in case they were stolen.
+func (it *passwordInteractor) ChangePassword(
+	ctx context.Context,
+	input ChangePasswordInput,
+) error {
+	logger := util.FromContext(ctx)
+
+	user, err := it.users.Get(ctx, input.UserID)
+	if err != nil {
+		logger.Error(err, "failed get user")
+		return err
+	}
+	failures, err := it.currentPasswords.check(ctx, user.ID, user.Email, input.CurrentPassword, it.now())
+	if err != nil {
+		logger.Error(err, "failed check current password")
+		return err
+	}
+	if err := it.currentPasswords.lockout.resetFailures(ctx, user.ID, failures); err != nil {
+		logger.Error(err, "failed reset login failures")
+		return err
+	}
+	hashed, err := it.newPasswords.hash(ctx, user, input.NewPassword)
+	if err != nil {
+		logger.Error(err, "failed accept new password")
+		return err
+	}
+	_, err = it.userCreds.Update(ctx, port.UserCredentialCreateUpdateInput{
+		UserID:   user.ID,
+		Password: hashed,
+	})
+	if err != nil {
+		logger.Error(err, "failed update user credentials")
+		return err
+	}
+	err = it.sessions.DeleteByUserID(ctx, port.SessionDeleteByUserIDInput{
+		UserID:   user.ID,
+		ExceptID: input.CurrentSessionID,
+	})
+	if err != nil {
+		logger.Error(err, "failed delete sessions")
+		return err
+	}
+
+	return nil
+}
+
+// RequestPasswordReset sends a reset link when the email belongs to an
+// account. It succeeds either way so callers can not probe for accounts,
+// and failed sends are only logged.
+func (it *passwordInteractor) RequestPasswordReset(
+	ctx context.Context,
+	input RequestPasswordResetInput,
+) error {
+	logger := util.FromContext(ctx)
+
+	users, err := it.users.List(ctx, port.UserListInput{
+		Email: &input.Email,
+	})
+	if err != nil {
+		logger.Error(err, "failed find user")
+		return err
+	}
+	// Credentials are looked up for unknown emails too, so that both make
+	// the same queries.
+	cred, err := it.userCreds.GetByEmail(ctx, input.Email)
+	if err != nil && !errors.Is(err, usecase.ErrNotFoundEntity) {
+		logger.Error(err, "failed get user credentials")
+		return err
+	}
+	if len(users) == 0 {
+		logger.Info("password reset requested for unknown email")
+		return nil
+	}
+	if cred == nil {
+		logger.Info("password reset requested for user without credentials")
+		return nil
+	}
+	user := users[0]
+	// The token is issued and the mail sent in the background, so that
+	// known emails are not answered slower than unknown ones. Neither
+	// touches the request's transaction.
+	jobCtx := context.WithoutCancel(ctx)
+	err = it.enqueue(func() {
+		token, err := it.resetTokens.Issue(jobCtx, port.PasswordResetTokenIssueInput{
+			UserID:   user.ID,
+			Password: cred.Password,
+		})
+		if err != nil {
+			logger.Error(err, "failed issue password reset token")
+			return
+		}
+		err = it.mailer.SendPasswordReset(jobCtx, port.PasswordResetMailInput{
+			To:    user.Email,
+			Name:  user.Name,
+			Token: token,
+		})
+		if err != nil {
+			logger.Error(err, "failed send password reset")
+		}
+	})
+	if err != nil {
+		logger.Warn(err, "failed queue password reset")
+	}
+
+	return nil
+}
+
+// ResetPassword revokes all the user's sessions, since whoever holds them
+// may be why the password was reset.
+func (it *passwordInteractor) ResetPassword(
+	ctx context.Context,
+	input ResetPasswordInput,
+) error {
+	logger := util.FromContext(ctx)
+
+	token, err := it.resetTokens.Parse(ctx, input.Token)
+	if err != nil {
+		logger.Error(err, "failed parse password reset token")
+		return err
+	}
+	user, err := it.users.Get(ctx, token.UserID)
+	if err != nil {
+		logger.Error(err, "failed get user")
+		return err
+	}
+	cred, err := it.userCreds.GetByEmail(ctx, user.Email)
+	if err != nil {
+		logger.Error(err, "failed get user credentials")
+		return err
+	}
+	if err := it.resetTokens.Verify(ctx, input.Token, cred.Password); err != nil {
+		logger.Error(err, "failed verify password reset token")
+		return err
+	}
+	hashed, err := it.newPasswords.hash(ctx, user, input.NewPassword)
+	if err != nil {
+		logger.Error(err, "failed accept new password")
+		return err
+	}
+	_, err = it.userCreds.Update(ctx, port.UserCredentialCreateUpdateInput{
+		UserID:   user.ID,
+		Password: hashed,
+	})
+	if err != nil {
+		logger.Error(err, "failed update user credentials")
+		return err
+	}
+	err = it.sessions.DeleteByUserID(ctx, port.SessionDeleteByUserIDInput{
+		UserID: user.ID,
+	})
+	if err != nil {
+		logger.Error(err, "failed delete sessions")
+		return err
+	}
+
+	return nil
+}
+
+// newPasswordHasher is shared by every flow that sets a password so that
+// they all apply the same checks before hashing.
+type newPasswordHasher struct {
+	policy          entity.PasswordPolicy
+	passwordManager port.PasswordManager
+	// breaches is optional, nil disables breached password screening.
+	breaches port.BreachedPasswordChecker
+	// history is optional, nil disables the reuse check. It is left unset
+	// when creating users since they have no previous passwords.
+	history port.PasswordHistoryGateway
+}
+
+func (h newPasswordHasher) hash(
+	ctx context.Context,
+	user *entity.User,
+	password entity.Password,
+) (entity.HashedPassword, error) {
+	err := h.policy.Validate(password, entity.PasswordPolicySubject{
+		Email: user.Email,
+		Name:  user.Name,
+	})
+	var policyErr *entity.PasswordPolicyError
+	if err != nil && !errors.As(err, &policyErr) {
+		return "", err
+	}
+
+	if h.breaches != nil {
+		breached, err := h.breaches.IsBreached(ctx, password)
+		if err != nil {
+			return "", err
+		}
+		if breached {
+			if policyErr == nil {
+				policyErr = &entity.PasswordPolicyError{}
+			}
+			policyErr.Violations = append(policyErr.Violations, entity.PasswordPolicyViolation{
+				Rule:    entity.PasswordRuleBreachedPassword,
+				Message: "must not be a password exposed in a data breach",
+			})
+		}
+	}
+	if h.history != nil && h.policy.HistorySize > 0 {
+		reused, err := h.isReused(ctx, user.ID, password)
+		if err != nil {
+			return "", err
+		}
+		if reused {
+			if policyErr == nil {
+				policyErr = &entity.PasswordPolicyError{}
+			}
+			policyErr.Violations = append(policyErr.Violations, entity.PasswordPolicyViolation{
+				Rule:    entity.PasswordRuleReusedPassword,
+				Message: fmt.Sprintf("must not be one of the last %d passwords", h.policy.HistorySize),
+			})
+		}
+	}
+	if policyErr != nil {
+		return "", policyErr
+	}
+
+	return h.passwordManager.Hash(ctx, password.String())
+}
+
+func (h newPasswordHasher) isReused(
+	ctx context.Context,
+	userID entity.ID,
+	password entity.Password,
+) (bool, error) {
+	recent, err := h.history.ListRecent(ctx, userID, h.policy.HistorySize)
+	if err != nil {
+		return false, err
+	}
+	for _, hashed := range recent {
+		if err := h.passwordManager.Compare(ctx, hashed, password); err == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
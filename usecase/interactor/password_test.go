@@ -0,0 +1,684 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_passwordInteractor_ChangePassword(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	type mockReturn struct {
+		failures     *entity.LoginFailures
+		credsCheck   error
+		passwordHash *entity.HashedPassword
+		credsUpdate  error
+	}
+	type args struct {
+		ctx   context.Context
+		input ChangePasswordInput
+	}
+	tests := []struct {
+		name       string
+		args       args
+		mockReturn mockReturn
+		wantRecord bool
+		wantReset  bool
+		wantErr    error
+	}{
+		{
+			name: "return no error and revoke sessions when password changed",
+			args: args{
+				ctx: context.Background(),
+				input: ChangePasswordInput{
+					UserID:          user.ID,
+					CurrentPassword: "current_pass",
+					NewPassword:     "new_password",
+				},
+			},
+			mockReturn: mockReturn{
+				failures:     &entity.LoginFailures{},
+				passwordHash: util.ToPointer[entity.HashedPassword]("hashed_new_password"),
+			},
+		},
+		{
+			name: "return no error and keep current session when password changed",
+			args: args{
+				ctx: context.Background(),
+				input: ChangePasswordInput{
+					UserID:           user.ID,
+					CurrentPassword:  "current_pass",
+					NewPassword:      "new_password",
+					CurrentSessionID: util.ToPointer[entity.ID]("test_session_id_001"),
+				},
+			},
+			mockReturn: mockReturn{
+				failures:     &entity.LoginFailures{},
+				passwordHash: util.ToPointer[entity.HashedPassword]("hashed_new_password"),
+			},
+		},
+		{
+			name: "return no error and reset failures when password changed",
+			args: args{
+				ctx: context.Background(),
+				input: ChangePasswordInput{
+					UserID:          user.ID,
+					CurrentPassword: "current_pass",
+					NewPassword:     "new_password",
+				},
+			},
+			mockReturn: mockReturn{
+				failures:     &entity.LoginFailures{Count: 1, LastFailedAt: now.Add(-time.Minute)},
+				passwordHash: util.ToPointer[entity.HashedPassword]("hashed_new_password"),
+			},
+			wantReset: true,
+		},
+		{
+			name: "return error and record failure when current password is wrong",
+			args: args{
+				ctx: context.Background(),
+				input: ChangePasswordInput{
+					UserID:          user.ID,
+					CurrentPassword: "wrong_pass",
+					NewPassword:     "new_password",
+				},
+			},
+			mockReturn: mockReturn{
+				failures:   &entity.LoginFailures{},
+				credsCheck: usecase.ErrInvalidCredential,
+			},
+			wantRecord: true,
+			wantErr:    usecase.ErrInvalidCredential,
+		},
+		{
+			name: "return error when locked even if current password is right",
+			args: args{
+				ctx: context.Background(),
+				input: ChangePasswordInput{
+					UserID:          user.ID,
+					CurrentPassword: "current_pass",
+					NewPassword:     "new_password",
+				},
+			},
+			mockReturn: mockReturn{
+				failures: &entity.LoginFailures{Count: 3, LastFailedAt: now.Add(-time.Minute)},
+			},
+			wantErr: usecase.ErrInvalidCredential,
+		},
+		{
+			name: "return policy error when new password violates policy",
+			args: args{
+				ctx: context.Background(),
+				input: ChangePasswordInput{
+					UserID:          user.ID,
+					CurrentPassword: "current_pass",
+					NewPassword:     "short",
+				},
+			},
+			mockReturn: mockReturn{
+				failures: &entity.LoginFailures{},
+			},
+			wantErr: &entity.PasswordPolicyError{},
+		},
+		{
+			name: "return error when credentials update failed",
+			args: args{
+				ctx: context.Background(),
+				input: ChangePasswordInput{
+					UserID:          user.ID,
+					CurrentPassword: "current_pass",
+					NewPassword:     "new_password",
+				},
+			},
+			mockReturn: mockReturn{
+				failures:     &entity.LoginFailures{},
+				passwordHash: util.ToPointer[entity.HashedPassword]("hashed_new_password"),
+				credsUpdate:  errors.New("failed to update"),
+			},
+			wantErr: errors.New("failed to update"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := portmocks.NewUserGateway(t)
+			users.On("Get", tt.args.ctx, tt.args.input.UserID).Return(user, nil).Times(1)
+			userCreds := portmocks.NewUserCredentialGateway(t)
+			userCreds.
+				On("Check", tt.args.ctx, user.Email, tt.args.input.CurrentPassword).
+				Return(tt.mockReturn.credsCheck).
+				Times(1)
+			failures := portmocks.NewLoginFailureGateway(t)
+			failures.On("Get", tt.args.ctx, user.ID).Return(tt.mockReturn.failures, nil).Times(1)
+			if tt.wantRecord {
+				failures.
+					On("Record", tt.args.ctx, port.LoginFailureRecordInput{
+						UserID:       user.ID,
+						FailedAt:     now,
+						ForgetBefore: now.Add(-15 * time.Minute),
+					}).
+					Return(nil).
+					Times(1)
+			}
+			if tt.wantReset {
+				failures.On("Reset", tt.args.ctx, user.ID).Return(nil).Times(1)
+			}
+			passwordManager := portmocks.NewPasswordManager(t)
+			if tt.mockReturn.passwordHash != nil {
+				passwordManager.
+					On("Hash", tt.args.ctx, tt.args.input.NewPassword.String()).
+					Return(*tt.mockReturn.passwordHash, nil).
+					Times(1)
+				userCreds.
+					On("Update", tt.args.ctx, port.UserCredentialCreateUpdateInput{
+						UserID:   user.ID,
+						Password: *tt.mockReturn.passwordHash,
+					}).
+					Return(nil, tt.mockReturn.credsUpdate).
+					Times(1)
+			}
+			sessions := portmocks.NewSessionStore(t)
+			if tt.mockReturn.passwordHash != nil && tt.mockReturn.credsUpdate == nil {
+				sessions.
+					On("DeleteByUserID", tt.args.ctx, port.SessionDeleteByUserIDInput{
+						UserID:   user.ID,
+						ExceptID: tt.args.input.CurrentSessionID,
+					}).
+					Return(nil).
+					Times(1)
+			}
+			it := &passwordInteractor{
+				users:     users,
+				userCreds: userCreds,
+				sessions:  sessions,
+				currentPasswords: passwordChecker{
+					userCreds: userCreds,
+					lockout: loginLockout{
+						failures: failures,
+						policy: entity.LoginLockoutPolicy{
+							Threshold: 3,
+							Duration:  15 * time.Minute,
+						},
+					},
+				},
+				newPasswords: newPasswordHasher{
+					policy: entity.PasswordPolicy{
+						MinLength: 8,
+					},
+					passwordManager: passwordManager,
+				},
+				now: func() time.Time { return now },
+			}
+			err := it.ChangePassword(tt.args.ctx, tt.args.input)
+			assertInteractorError(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_passwordInteractor_RequestPasswordReset(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	cred := &entity.UserCredential{
+		ID:       "test_cred_id_001",
+		UserID:   user.ID,
+		Email:    user.Email,
+		Password: "hashed_current_password",
+	}
+	tests := []struct {
+		name        string
+		users       entity.Users
+		credErr     error
+		issueErr    error
+		sendErr     error
+		enqueueErr  error
+		wantEnqueue bool
+		wantSend    bool
+	}{
+		{
+			name:        "return no error and send mail in background",
+			users:       entity.Users{user},
+			wantEnqueue: true,
+			wantSend:    true,
+		},
+		{
+			name:        "return no error when mail fails",
+			users:       entity.Users{user},
+			sendErr:     errors.New("test_error"),
+			wantEnqueue: true,
+			wantSend:    true,
+		},
+		{
+			name:        "return no error without mail when token fails",
+			users:       entity.Users{user},
+			issueErr:    errors.New("test_error"),
+			wantEnqueue: true,
+		},
+		{
+			name:       "return no error when background queue is full",
+			users:      entity.Users{user},
+			enqueueErr: ErrBackgroundQueueFull,
+		},
+		{
+			name:    "return no error without mail for unknown email",
+			credErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name:    "return no error without mail for user without credentials",
+			users:   entity.Users{user},
+			credErr: usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			users := portmocks.NewUserGateway(t)
+			users.
+				On("List", ctx, port.UserListInput{Email: &user.Email}).
+				Return(tt.users, nil).
+				Times(1)
+			userCreds := portmocks.NewUserCredentialGateway(t)
+			resetTokens := portmocks.NewPasswordResetTokenManager(t)
+			mailer := portmocks.NewMailer(t)
+			// Unknown emails make the same queries as known ones.
+			if tt.credErr != nil {
+				userCreds.On("GetByEmail", ctx, user.Email).Return(nil, tt.credErr).Times(1)
+			} else {
+				userCreds.On("GetByEmail", ctx, user.Email).Return(cred, nil).Times(1)
+			}
+			notCanceled := mock.MatchedBy(func(ctx context.Context) bool {
+				return ctx.Err() == nil
+			})
+			if tt.wantEnqueue {
+				resetTokens.
+					On("Issue", notCanceled, port.PasswordResetTokenIssueInput{
+						UserID:   user.ID,
+						Password: cred.Password,
+					}).
+					Return("test_token", tt.issueErr).
+					Times(1)
+			}
+			if tt.wantSend {
+				mailer.
+					On("SendPasswordReset", notCanceled, port.PasswordResetMailInput{
+						To:    user.Email,
+						Name:  user.Name,
+						Token: "test_token",
+					}).
+					Return(tt.sendErr).
+					Times(1)
+			}
+			var enqueued []func()
+			it := &passwordInteractor{
+				users:       users,
+				userCreds:   userCreds,
+				resetTokens: resetTokens,
+				mailer:      mailer,
+				enqueue: func(f func()) error {
+					if tt.enqueueErr != nil {
+						return tt.enqueueErr
+					}
+					enqueued = append(enqueued, f)
+					return nil
+				},
+			}
+
+			err := it.RequestPasswordReset(ctx, RequestPasswordResetInput{
+				Email: user.Email,
+			})
+			assert.NoError(t, err)
+			if !tt.wantEnqueue {
+				assert.Empty(t, enqueued)
+				return
+			}
+			// The token is issued and the mail sent after the request is done.
+			assert.Len(t, enqueued, 1)
+			resetTokens.AssertNotCalled(t, "Issue")
+			mailer.AssertNotCalled(t, "SendPasswordReset")
+			cancel()
+			enqueued[0]()
+		})
+	}
+}
+
+func Test_passwordInteractor_ResetPassword(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	cred := &entity.UserCredential{
+		ID:       "test_cred_id_001",
+		UserID:   user.ID,
+		Email:    user.Email,
+		Password: "hashed_current_password",
+	}
+	type mockReturn struct {
+		tokenParse   error
+		tokenVerify  error
+		passwordHash *entity.HashedPassword
+	}
+	type args struct {
+		ctx   context.Context
+		input ResetPasswordInput
+	}
+	tests := []struct {
+		name       string
+		args       args
+		mockReturn mockReturn
+		wantErr    error
+	}{
+		{
+			name: "return no error and revoke sessions when password reset",
+			args: args{
+				ctx: context.Background(),
+				input: ResetPasswordInput{
+					Token:       "test_token",
+					NewPassword: "new_password",
+				},
+			},
+			mockReturn: mockReturn{
+				passwordHash: util.ToPointer[entity.HashedPassword]("hashed_new_password"),
+			},
+		},
+		{
+			name: "return error when token is invalid",
+			args: args{
+				ctx: context.Background(),
+				input: ResetPasswordInput{
+					Token:       "test_token",
+					NewPassword: "new_password",
+				},
+			},
+			mockReturn: mockReturn{
+				tokenParse: usecase.ErrInvalidToken,
+			},
+			wantErr: usecase.ErrInvalidToken,
+		},
+		{
+			name: "return error when token was already used",
+			args: args{
+				ctx: context.Background(),
+				input: ResetPasswordInput{
+					Token:       "test_token",
+					NewPassword: "new_password",
+				},
+			},
+			mockReturn: mockReturn{
+				tokenVerify: usecase.ErrInvalidToken,
+			},
+			wantErr: usecase.ErrInvalidToken,
+		},
+		{
+			name: "return policy error when new password violates policy",
+			args: args{
+				ctx: context.Background(),
+				input: ResetPasswordInput{
+					Token:       "test_token",
+					NewPassword: "short",
+				},
+			},
+			wantErr: &entity.PasswordPolicyError{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetTokens := portmocks.NewPasswordResetTokenManager(t)
+			users := portmocks.NewUserGateway(t)
+			userCreds := portmocks.NewUserCredentialGateway(t)
+			passwordManager := portmocks.NewPasswordManager(t)
+			if tt.mockReturn.tokenParse != nil {
+				resetTokens.On("Parse", tt.args.ctx, tt.args.input.Token).Return(nil, tt.mockReturn.tokenParse).Times(1)
+			} else {
+				resetTokens.
+					On("Parse", tt.args.ctx, tt.args.input.Token).
+					Return(&port.PasswordResetToken{
+						UserID:    user.ID,
+						ExpiresAt: time.Now().Add(time.Hour),
+					}, nil).
+					Times(1)
+				users.On("Get", tt.args.ctx, user.ID).Return(user, nil).Times(1)
+				userCreds.On("GetByEmail", tt.args.ctx, user.Email).Return(cred, nil).Times(1)
+				resetTokens.
+					On("Verify", tt.args.ctx, tt.args.input.Token, cred.Password).
+					Return(tt.mockReturn.tokenVerify).
+					Times(1)
+			}
+			if tt.mockReturn.passwordHash != nil {
+				passwordManager.
+					On("Hash", tt.args.ctx, tt.args.input.NewPassword.String()).
+					Return(*tt.mockReturn.passwordHash, nil).
+					Times(1)
+				userCreds.
+					On("Update", tt.args.ctx, port.UserCredentialCreateUpdateInput{
+						UserID:   user.ID,
+						Password: *tt.mockReturn.passwordHash,
+					}).
+					Return(nil, nil).
+					Times(1)
+			}
+			sessions := portmocks.NewSessionStore(t)
+			if tt.mockReturn.passwordHash != nil {
+				sessions.
+					On("DeleteByUserID", tt.args.ctx, port.SessionDeleteByUserIDInput{
+						UserID: user.ID,
+					}).
+					Return(nil).
+					Times(1)
+			}
+			it := &passwordInteractor{
+				users:       users,
+				userCreds:   userCreds,
+				resetTokens: resetTokens,
+				sessions:    sessions,
+				newPasswords: newPasswordHasher{
+					policy: entity.PasswordPolicy{
+						MinLength: 8,
+					},
+					passwordManager: passwordManager,
+				},
+			}
+			err := it.ResetPassword(tt.args.ctx, tt.args.input)
+			assertInteractorError(t, tt.wantErr, err)
+		})
+	}
+}
+
+func Test_newPasswordHasher_hash(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	type mockReturn struct {
+		breached     *bool
+		breachedErr  error
+		history      []entity.HashedPassword
+		historyErr   error
+		reusedIndex  int
+		passwordHash *entity.HashedPassword
+	}
+	tests := []struct {
+		name           string
+		password       entity.Password
+		mockReturn     mockReturn
+		want           entity.HashedPassword
+		wantViolations []entity.PasswordRule
+		wantErr        error
+	}{
+		{
+			name:     "return hash when password is not breached",
+			password: "new_password",
+			mockReturn: mockReturn{
+				breached:     util.ToPointer(false),
+				history:      []entity.HashedPassword{},
+				passwordHash: util.ToPointer[entity.HashedPassword]("hashed_new_password"),
+			},
+			want: "hashed_new_password",
+		},
+		{
+			name:     "return policy error when password is breached",
+			password: "new_password",
+			mockReturn: mockReturn{
+				breached: util.ToPointer(true),
+				history:  []entity.HashedPassword{},
+			},
+			wantViolations: []entity.PasswordRule{
+				entity.PasswordRuleBreachedPassword,
+			},
+		},
+		{
+			name:     "return every violation when breached password also violates policy",
+			password: "short",
+			mockReturn: mockReturn{
+				breached: util.ToPointer(true),
+				history:  []entity.HashedPassword{},
+			},
+			wantViolations: []entity.PasswordRule{
+				entity.PasswordRuleMinLength,
+				entity.PasswordRuleBreachedPassword,
+			},
+		},
+		{
+			name:     "return hash when password is not one of recent passwords",
+			password: "new_password",
+			mockReturn: mockReturn{
+				breached: util.ToPointer(false),
+				history: []entity.HashedPassword{
+					"hashed_password_001",
+					"hashed_password_002",
+				},
+				reusedIndex:  -1,
+				passwordHash: util.ToPointer[entity.HashedPassword]("hashed_new_password"),
+			},
+			want: "hashed_new_password",
+		},
+		{
+			name:     "return policy error when password is one of recent passwords",
+			password: "new_password",
+			mockReturn: mockReturn{
+				breached: util.ToPointer(false),
+				history: []entity.HashedPassword{
+					"hashed_password_001",
+					"hashed_password_002",
+					"hashed_password_003",
+				},
+				reusedIndex: 1,
+			},
+			wantViolations: []entity.PasswordRule{
+				entity.PasswordRuleReusedPassword,
+			},
+		},
+		{
+			name:     "return error when password history could not be listed",
+			password: "new_password",
+			mockReturn: mockReturn{
+				breached:   util.ToPointer(false),
+				historyErr: errors.New("failed to list history"),
+			},
+			wantErr: errors.New("failed to list history"),
+		},
+		{
+			name:     "return error when breach check failed",
+			password: "new_password",
+			mockReturn: mockReturn{
+				breached:    util.ToPointer(false),
+				breachedErr: errors.New("failed to read corpus"),
+			},
+			wantErr: errors.New("failed to read corpus"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			breaches := portmocks.NewBreachedPasswordChecker(t)
+			if tt.mockReturn.breached != nil {
+				breaches.
+					On("IsBreached", ctx, tt.password).
+					Return(*tt.mockReturn.breached, tt.mockReturn.breachedErr).
+					Times(1)
+			}
+			passwordManager := portmocks.NewPasswordManager(t)
+			if tt.mockReturn.passwordHash != nil {
+				passwordManager.
+					On("Hash", ctx, tt.password.String()).
+					Return(*tt.mockReturn.passwordHash, nil).
+					Times(1)
+			}
+			history := portmocks.NewPasswordHistoryGateway(t)
+			if tt.mockReturn.history != nil || tt.mockReturn.historyErr != nil {
+				history.
+					On("ListRecent", ctx, user.ID, 3).
+					Return(tt.mockReturn.history, tt.mockReturn.historyErr).
+					Times(1)
+			}
+			for i, hashed := range tt.mockReturn.history {
+				if tt.mockReturn.reusedIndex >= 0 && i > tt.mockReturn.reusedIndex {
+					break
+				}
+				var compareErr error
+				if i != tt.mockReturn.reusedIndex {
+					compareErr = errors.New("mismatched")
+				}
+				passwordManager.
+					On("Compare", ctx, hashed, tt.password).
+					Return(compareErr).
+					Times(1)
+			}
+			h := newPasswordHasher{
+				policy: entity.PasswordPolicy{
+					MinLength:   8,
+					HistorySize: 3,
+				},
+				passwordManager: passwordManager,
+				breaches:        breaches,
+				history:         history,
+			}
+			got, err := h.hash(ctx, user, tt.password)
+			if tt.wantViolations != nil {
+				var policyErr *entity.PasswordPolicyError
+				if !assert.ErrorAs(t, err, &policyErr) {
+					return
+				}
+				var rules []entity.PasswordRule
+				for _, v := range policyErr.Violations {
+					rules = append(rules, v.Rule)
+				}
+				assert.Equal(t, tt.wantViolations, rules)
+				return
+			}
+			assertInteractorError(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func assertInteractorError(t *testing.T, want error, got error) {
+	t.Helper()
+	if want == nil {
+		assert.NoError(t, got)
+		return
+	}
+	var policyErr *entity.PasswordPolicyError
+	if errors.As(want, &policyErr) {
+		assert.ErrorAs(t, got, &policyErr)
+		return
+	}
+	assert.ErrorContains(t, got, want.Error())
+}
@@ -0,0 +1,65 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	RateLimitBucket struct {
+		Key   string
+		Limit entity.RateLimit
+	}
+	TakeRateLimitInput struct {
+		Buckets []RateLimitBucket
+	}
+)
+
+var _ RateLimitInteractor = (*rateLimitInteractor)(nil)
+
+type RateLimitInteractor interface {
+	// Take takes a token from every bucket and returns the most
+	// restrictive result. A request is refused when any bucket is empty.
+	Take(ctx context.Context, input TakeRateLimitInput) (*entity.RateLimitResult, error)
+}
+
+type rateLimitInteractor struct {
+	store port.RateLimitStore
+}
+
+func NewRateLimitInteractor(store port.RateLimitStore) *rateLimitInteractor {
+	return &rateLimitInteractor{
+		store: store,
+	}
+}
+
+func (it *rateLimitInteractor) Take(ctx context.Context, input TakeRateLimitInput) (*entity.RateLimitResult, error) {
+	logger := util.FromContext(ctx)
+
+	var result *entity.RateLimitResult
+	for _, bucket := range input.Buckets {
+		r, err := it.store.Take(ctx, bucket.Key, bucket.Limit)
+		if err != nil {
+			logger.Error(err, "failed take rate limit token")
+			return nil, err
+		}
+		if result == nil || moreRestrictive(r, result) {
+			result = r
+		}
+	}
+
+	return result, nil
+}
+
+func moreRestrictive(a *entity.RateLimitResult, b *entity.RateLimitResult) bool {
+	if a.Allowed != b.Allowed {
+		return !a.Allowed
+	}
+	if !a.Allowed {
+		return a.RetryAfter > b.RetryAfter
+	}
+	return a.Remaining < b.Remaining
+}
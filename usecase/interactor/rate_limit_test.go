@@ -0,0 +1,72 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rateLimitInteractor_Take(t *testing.T) {
+	ipLimit := entity.RateLimit{Limit: 20, Period: time.Minute}
+	accountLimit := entity.RateLimit{Limit: 5, Period: time.Minute}
+	input := TakeRateLimitInput{
+		Buckets: []RateLimitBucket{
+			{Key: "ip:192.0.2.1", Limit: ipLimit},
+			{Key: "account:test_001@example.com", Limit: accountLimit},
+		},
+	}
+	tests := []struct {
+		name          string
+		ipResult      *entity.RateLimitResult
+		accountResult *entity.RateLimitResult
+		accountErr    error
+		want          *entity.RateLimitResult
+		wantErr       bool
+	}{
+		{
+			name:          "return result with fewest remaining tokens",
+			ipResult:      &entity.RateLimitResult{Allowed: true, Limit: 20, Remaining: 19},
+			accountResult: &entity.RateLimitResult{Allowed: true, Limit: 5, Remaining: 4},
+			want:          &entity.RateLimitResult{Allowed: true, Limit: 5, Remaining: 4},
+		},
+		{
+			name:          "return refused result",
+			ipResult:      &entity.RateLimitResult{Allowed: true, Limit: 20, Remaining: 0},
+			accountResult: &entity.RateLimitResult{Limit: 5, RetryAfter: time.Second},
+			want:          &entity.RateLimitResult{Limit: 5, RetryAfter: time.Second},
+		},
+		{
+			name:       "return error when store fails",
+			ipResult:   &entity.RateLimitResult{Allowed: true, Limit: 20, Remaining: 19},
+			accountErr: errors.New("test error"),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := portmocks.NewRateLimitStore(t)
+			store.
+				On("Take", ctx, "ip:192.0.2.1", ipLimit).
+				Return(tt.ipResult, nil).
+				Times(1)
+			store.
+				On("Take", ctx, "account:test_001@example.com", accountLimit).
+				Return(tt.accountResult, tt.accountErr).
+				Times(1)
+			it := NewRateLimitInteractor(store)
+			got, err := it.Take(ctx, input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	SaveRoleInput struct {
+		Name        string
+		Permissions []entity.Permission
+	}
+	DeleteRoleInput struct {
+		Name string
+	}
+	ListUserRolesInput struct {
+		UserID entity.ID
+	}
+	AssignRoleInput struct {
+		UserID   entity.ID
+		RoleName string
+	}
+	AssignRoleByEmailInput struct {
+		Email    entity.Email
+		RoleName string
+	}
+	UnassignRoleInput struct {
+		UserID   entity.ID
+		RoleName string
+	}
+)
+
+var _ RoleInteractor = (*roleInteractor)(nil)
+
+type RoleInteractor interface {
+	ListRoles(ctx context.Context) (entity.Roles, error)
+	// SaveRole creates the role or replaces its permissions.
+	SaveRole(ctx context.Context, input SaveRoleInput) (*entity.Role, error)
+	DeleteRole(ctx context.Context, input DeleteRoleInput) error
+	ListUserRoles(ctx context.Context, input ListUserRolesInput) (entity.Roles, error)
+	AssignRole(ctx context.Context, input AssignRoleInput) (*entity.Role, error)
+	// AssignRoleByEmail assigns the role to the user with the email, for
+	// bootstrapping administrators before anyone can call AssignRole.
+	AssignRoleByEmail(ctx context.Context, input AssignRoleByEmailInput) (*entity.User, error)
+	UnassignRole(ctx context.Context, input UnassignRoleInput) error
+}
+
+type roleInteractor struct {
+	users port.UserGateway
+	roles port.RoleGateway
+}
+
+func NewRoleInteractor(
+	users port.UserGateway,
+	roles port.RoleGateway,
+) *roleInteractor {
+	return &roleInteractor{
+		users: users,
+		roles: roles,
+	}
+}
+
+func (it *roleInteractor) ListRoles(ctx context.Context) (entity.Roles, error) {
+	logger := util.FromContext(ctx)
+
+	roles, err := it.roles.List(ctx)
+	if err != nil {
+		logger.Error(err, "failed list roles")
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (it *roleInteractor) SaveRole(ctx context.Context, input SaveRoleInput) (*entity.Role, error) {
+	logger := util.FromContext(ctx)
+
+	var permissions []entity.Permission
+	seen := make(map[entity.Permission]bool)
+	for _, p := range input.Permissions {
+		if !seen[p] {
+			seen[p] = true
+			permissions = append(permissions, p)
+		}
+	}
+	role, err := it.roles.Save(ctx, port.RoleSaveInput{
+		Name:        input.Name,
+		Permissions: permissions,
+	})
+	if err != nil {
+		logger.Error(err, "failed save role")
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (it *roleInteractor) DeleteRole(ctx context.Context, input DeleteRoleInput) error {
+	logger := util.FromContext(ctx)
+
+	if err := it.roles.Delete(ctx, input.Name); err != nil {
+		logger.Error(err, "failed delete role")
+		return err
+	}
+
+	return nil
+}
+
+func (it *roleInteractor) ListUserRoles(ctx context.Context, input ListUserRolesInput) (entity.Roles, error) {
+	logger := util.FromContext(ctx)
+
+	roles, err := it.roles.ListByUserID(ctx, input.UserID)
+	if err != nil {
+		logger.Error(err, "failed list user roles")
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+func (it *roleInteractor) AssignRole(ctx context.Context, input AssignRoleInput) (*entity.Role, error) {
+	logger := util.FromContext(ctx)
+
+	if _, err := it.users.Get(ctx, input.UserID); err != nil {
+		logger.Error(err, "failed get user")
+		return nil, err
+	}
+	role, err := it.roles.Get(ctx, input.RoleName)
+	if err != nil {
+		logger.Error(err, "failed get role")
+		return nil, err
+	}
+	if err := it.roles.Assign(ctx, input.UserID, role.Name); err != nil {
+		logger.Error(err, "failed assign role")
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (it *roleInteractor) AssignRoleByEmail(ctx context.Context, input AssignRoleByEmailInput) (*entity.User, error) {
+	logger := util.FromContext(ctx)
+
+	users, err := it.users.List(ctx, port.UserListInput{
+		Email: &input.Email,
+	})
+	if err != nil {
+		logger.Error(err, "failed find user")
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	user := users[0]
+	if _, err := it.AssignRole(ctx, AssignRoleInput{
+		UserID:   user.ID,
+		RoleName: input.RoleName,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (it *roleInteractor) UnassignRole(ctx context.Context, input UnassignRoleInput) error {
+	logger := util.FromContext(ctx)
+
+	if err := it.roles.Unassign(ctx, input.UserID, input.RoleName); err != nil {
+		logger.Error(err, "failed unassign role")
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_roleInteractor_SaveRole(t *testing.T) {
+	ctx := context.Background()
+	role := &entity.Role{
+		Name:        "support",
+		Permissions: []entity.Permission{entity.PermissionUsersRead, entity.PermissionUsersWrite},
+	}
+	roles := portmocks.NewRoleGateway(t)
+	roles.
+		On("Save", ctx, port.RoleSaveInput{
+			Name:        role.Name,
+			Permissions: role.Permissions,
+		}).
+		Return(role, nil).
+		Times(1)
+	it := NewRoleInteractor(portmocks.NewUserGateway(t), roles)
+
+	got, err := it.SaveRole(ctx, SaveRoleInput{
+		Name: "support",
+		Permissions: []entity.Permission{
+			entity.PermissionUsersRead,
+			entity.PermissionUsersWrite,
+			entity.PermissionUsersRead,
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, role, got)
+}
+
+func Test_roleInteractor_AssignRole(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	role := &entity.Role{
+		Name:        entity.RoleAdmin,
+		Permissions: entity.Permissions,
+	}
+	tests := []struct {
+		name       string
+		userErr    error
+		roleErr    error
+		wantAssign bool
+		wantErr    error
+	}{
+		{
+			name:       "return assigned role",
+			wantAssign: true,
+		},
+		{
+			name:    "return error when user is unknown",
+			userErr: usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name:    "return error when role is unknown",
+			roleErr: usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := portmocks.NewUserGateway(t)
+			users.On("Get", ctx, user.ID).Return(user, tt.userErr).Times(1)
+			roles := portmocks.NewRoleGateway(t)
+			if tt.userErr == nil {
+				roles.On("Get", ctx, role.Name).Return(role, tt.roleErr).Times(1)
+			}
+			if tt.wantAssign {
+				roles.On("Assign", ctx, user.ID, role.Name).Return(nil).Times(1)
+			}
+			it := NewRoleInteractor(users, roles)
+
+			got, err := it.AssignRole(ctx, AssignRoleInput{
+				UserID:   user.ID,
+				RoleName: role.Name,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, role, got)
+		})
+	}
+}
+
+func Test_roleInteractor_AssignRoleByEmail(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	role := &entity.Role{
+		Name:        entity.RoleAdmin,
+		Permissions: entity.Permissions,
+	}
+	tests := []struct {
+		name    string
+		found   entity.Users
+		wantErr error
+	}{
+		{
+			name:  "return user the role is assigned to",
+			found: entity.Users{user},
+		},
+		{
+			name:    "return error when email is unknown",
+			found:   entity.Users{},
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := portmocks.NewUserGateway(t)
+			users.On("List", ctx, port.UserListInput{Email: &user.Email}).Return(tt.found, nil).Times(1)
+			roles := portmocks.NewRoleGateway(t)
+			if tt.wantErr == nil {
+				users.On("Get", ctx, user.ID).Return(user, nil).Times(1)
+				roles.On("Get", ctx, role.Name).Return(role, nil).Times(1)
+				roles.On("Assign", ctx, user.ID, role.Name).Return(nil).Times(1)
+			}
+			it := NewRoleInteractor(users, roles)
+
+			got, err := it.AssignRoleByEmail(ctx, AssignRoleByEmailInput{
+				Email:    user.Email,
+				RoleName: role.Name,
+			})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, user, got)
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	CreateSessionInput struct {
+		Authentication *entity.Authentication
+		UserAgent      string
+		IPAddress      string
+	}
+	CreatedSession struct {
+		Session *entity.Session
+		// Token is sent to the client as the session cookie.
+		Token string
+		// CSRFToken is echoed by the client in requests that change state.
+		CSRFToken string
+	}
+	SessionAuthentication struct {
+		Session        *entity.Session
+		Authentication *entity.Authentication
+	}
+	RevokeSessionInput struct {
+		UserID entity.ID
+		ID     entity.ID
+	}
+	RevokeOtherSessionsInput struct {
+		UserID entity.ID
+		// CurrentID is the session making the request, if any, which is
+		// kept.
+		CurrentID *entity.ID
+	}
+	SessionPolicy struct {
+		// IdleTimeout ends sessions that have not been used for a while.
+		IdleTimeout time.Duration
+		// AbsoluteTimeout ends sessions however active they are.
+		AbsoluteTimeout time.Duration
+	}
+)
+
+var _ SessionInteractor = (*sessionInteractor)(nil)
+
+type SessionInteractor interface {
+	// Create starts a session for a user authenticated otherwise.
+	Create(ctx context.Context, input CreateSessionInput) (*CreatedSession, error)
+	// Authenticate returns usecase.ErrInvalidToken for unknown, idle and
+	// expired sessions.
+	Authenticate(ctx context.Context, token string) (*SessionAuthentication, error)
+	// VerifyCSRFToken returns usecase.ErrInvalidToken for tokens not issued
+	// for the session.
+	VerifyCSRFToken(ctx context.Context, sessionID entity.ID, token string) error
+	Delete(ctx context.Context, id entity.ID) error
+	// List returns the user's active sessions, newest first.
+	List(ctx context.Context, userID entity.ID) (entity.Sessions, error)
+	// Revoke returns usecase.ErrNotFoundEntity for sessions of other users.
+	Revoke(ctx context.Context, input RevokeSessionInput) error
+	RevokeOthers(ctx context.Context, input RevokeOtherSessionsInput) error
+}
+
+type sessionInteractor struct {
+	users      port.UserGateway
+	sessions   port.SessionStore
+	csrfTokens port.CSRFTokenManager
+	policy     SessionPolicy
+	now        func() time.Time
+}
+
+func NewSessionInteractor(
+	users port.UserGateway,
+	sessions port.SessionStore,
+	csrfTokens port.CSRFTokenManager,
+	policy SessionPolicy,
+) *sessionInteractor {
+	return &sessionInteractor{
+		users:      users,
+		sessions:   sessions,
+		csrfTokens: csrfTokens,
+		policy:     policy,
+		now:        time.Now,
+	}
+}
+
+func (it *sessionInteractor) Create(ctx context.Context, input CreateSessionInput) (*CreatedSession, error) {
+	logger := util.FromContext(ctx)
+
+	authentication := input.Authentication
+	session, token, err := it.sessions.Create(ctx, port.SessionCreateInput{
+		UserID:         authentication.User.ID,
+		Level:          authentication.Level,
+		AvailableLevel: authentication.AvailableLevel,
+		Methods:        authentication.Methods,
+		AuthTime:       authentication.AuthTime,
+		UserAgent:      input.UserAgent,
+		IPAddress:      input.IPAddress,
+		ExpiresAt:      it.now().Add(it.policy.AbsoluteTimeout),
+	})
+	if err != nil {
+		logger.Error(err, "failed create session")
+		return nil, err
+	}
+	csrfToken, err := it.csrfTokens.Issue(ctx, session.ID)
+	if err != nil {
+		logger.Error(err, "failed issue csrf token")
+		return nil, err
+	}
+
+	return &CreatedSession{
+		Session:   session,
+		Token:     token,
+		CSRFToken: csrfToken,
+	}, nil
+}
+
+func (it *sessionInteractor) Authenticate(ctx context.Context, token string) (*SessionAuthentication, error) {
+	logger := util.FromContext(ctx)
+
+	session, err := it.sessions.GetByToken(ctx, token)
+	if err != nil {
+		logger.Error(err, "failed get session")
+		return nil, err
+	}
+	now := it.now()
+	if err := it.checkActive(session, now); err != nil {
+		return nil, err
+	}
+	user, err := it.users.Get(ctx, session.UserID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, usecase.ErrNoAuthUser
+		}
+		logger.Error(err, "failed get user")
+		return nil, err
+	}
+	if err := it.sessions.Touch(ctx, session.ID, now); err != nil {
+		logger.Error(err, "failed touch session")
+		return nil, err
+	}
+	session.LastSeenAt = now
+
+	return &SessionAuthentication{
+		Session:        session,
+		Authentication: session.Authentication(user),
+	}, nil
+}
+
+func (it *sessionInteractor) VerifyCSRFToken(ctx context.Context, sessionID entity.ID, token string) error {
+	logger := util.FromContext(ctx)
+
+	if err := it.csrfTokens.Verify(ctx, sessionID, token); err != nil {
+		logger.Error(err, "failed verify csrf token")
+		return err
+	}
+
+	return nil
+}
+
+func (it *sessionInteractor) Delete(ctx context.Context, id entity.ID) error {
+	logger := util.FromContext(ctx)
+
+	if err := it.sessions.Delete(ctx, id); err != nil {
+		logger.Error(err, "failed delete session")
+		return err
+	}
+
+	return nil
+}
+
+func (it *sessionInteractor) List(ctx context.Context, userID entity.ID) (entity.Sessions, error) {
+	logger := util.FromContext(ctx)
+
+	sessions, err := it.sessions.ListByUserID(ctx, userID)
+	if err != nil {
+		logger.Error(err, "failed list sessions")
+		return nil, err
+	}
+	now := it.now()
+	var active entity.Sessions
+	for _, session := range sessions {
+		if it.checkActive(session, now) == nil {
+			active = append(active, session)
+		}
+	}
+
+	return active, nil
+}
+
+func (it *sessionInteractor) Revoke(ctx context.Context, input RevokeSessionInput) error {
+	logger := util.FromContext(ctx)
+
+	session, err := it.sessions.Get(ctx, input.ID)
+	if err != nil {
+		logger.Error(err, "failed get session")
+		return err
+	}
+	if session.UserID != input.UserID {
+		return usecase.ErrNotFoundEntity
+	}
+	if err := it.sessions.Delete(ctx, session.ID); err != nil {
+		logger.Error(err, "failed delete session")
+		return err
+	}
+
+	return nil
+}
+
+func (it *sessionInteractor) RevokeOthers(ctx context.Context, input RevokeOtherSessionsInput) error {
+	logger := util.FromContext(ctx)
+
+	err := it.sessions.DeleteByUserID(ctx, port.SessionDeleteByUserIDInput{
+		UserID:   input.UserID,
+		ExceptID: input.CurrentID,
+	})
+	if err != nil {
+		logger.Error(err, "failed delete sessions")
+		return err
+	}
+
+	return nil
+}
+
+func (it *sessionInteractor) checkActive(session *entity.Session, now time.Time) error {
+	if !now.Before(session.ExpiresAt) {
+		return fmt.Errorf("%w: session expired", usecase.ErrInvalidToken)
+	}
+	if it.policy.IdleTimeout > 0 && now.Sub(session.LastSeenAt) >= it.policy.IdleTimeout {
+		return fmt.Errorf("%w: session idle", usecase.ErrInvalidToken)
+	}
+	return nil
+}
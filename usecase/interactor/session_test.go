@@ -0,0 +1,266 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_sessionInteractor_Create(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	authentication := entity.NewAuthentication(user, now, entity.AuthMethodPassword, entity.AuthMethodOTP)
+	session := &entity.Session{
+		ID:             "test_session_id_001",
+		UserID:         user.ID,
+		Level:          entity.AuthLevelMFA,
+		AvailableLevel: entity.AuthLevelMFA,
+		Methods:        authentication.Methods,
+		AuthTime:       now,
+		UserAgent:      "test_user_agent",
+		IPAddress:      "192.0.2.1",
+		CreatedAt:      now,
+		LastSeenAt:     now,
+		ExpiresAt:      now.Add(12 * time.Hour),
+	}
+
+	ctx := context.Background()
+	sessions := portmocks.NewSessionStore(t)
+	sessions.
+		On("Create", ctx, port.SessionCreateInput{
+			UserID:         user.ID,
+			Level:          entity.AuthLevelMFA,
+			AvailableLevel: entity.AuthLevelMFA,
+			Methods:        authentication.Methods,
+			AuthTime:       now,
+			UserAgent:      "test_user_agent",
+			IPAddress:      "192.0.2.1",
+			ExpiresAt:      now.Add(12 * time.Hour),
+		}).
+		Return(session, "test_token_001", nil).
+		Times(1)
+	csrfTokens := portmocks.NewCSRFTokenManager(t)
+	csrfTokens.
+		On("Issue", ctx, session.ID).
+		Return("test_csrf_token_001", nil).
+		Times(1)
+	it := NewSessionInteractor(portmocks.NewUserGateway(t), sessions, csrfTokens, SessionPolicy{
+		IdleTimeout:     30 * time.Minute,
+		AbsoluteTimeout: 12 * time.Hour,
+	})
+	it.now = func() time.Time { return now }
+
+	got, err := it.Create(ctx, CreateSessionInput{
+		Authentication: authentication,
+		UserAgent:      "test_user_agent",
+		IPAddress:      "192.0.2.1",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &CreatedSession{
+		Session:   session,
+		Token:     "test_token_001",
+		CSRFToken: "test_csrf_token_001",
+	}, got)
+}
+
+func Test_sessionInteractor_Authenticate(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	newSession := func(lastSeenAt, expiresAt time.Time) *entity.Session {
+		return &entity.Session{
+			ID:             "test_session_id_001",
+			UserID:         user.ID,
+			Level:          entity.AuthLevelPassword,
+			AvailableLevel: entity.AuthLevelMFA,
+			Methods:        []string{entity.AuthMethodPassword},
+			AuthTime:       now.Add(-time.Hour),
+			CreatedAt:      now.Add(-time.Hour),
+			LastSeenAt:     lastSeenAt,
+			ExpiresAt:      expiresAt,
+		}
+	}
+	tests := []struct {
+		name      string
+		session   *entity.Session
+		getErr    error
+		users     bool
+		wantTouch bool
+		want      *entity.Authentication
+		wantErr   error
+	}{
+		{
+			name:      "return authentication of active session",
+			session:   newSession(now.Add(-time.Minute), now.Add(time.Hour)),
+			users:     true,
+			wantTouch: true,
+			want: &entity.Authentication{
+				User:           user,
+				Level:          entity.AuthLevelPassword,
+				Methods:        []string{entity.AuthMethodPassword},
+				AuthTime:       now.Add(-time.Hour),
+				AvailableLevel: entity.AuthLevelMFA,
+			},
+		},
+		{
+			name:    "return error when session is unknown",
+			getErr:  usecase.ErrInvalidToken,
+			wantErr: usecase.ErrInvalidToken,
+		},
+		{
+			name:    "return error when session is idle",
+			session: newSession(now.Add(-30*time.Minute), now.Add(time.Hour)),
+			wantErr: usecase.ErrInvalidToken,
+		},
+		{
+			name:    "return error when session is expired",
+			session: newSession(now.Add(-time.Minute), now),
+			wantErr: usecase.ErrInvalidToken,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sessions := portmocks.NewSessionStore(t)
+			sessions.
+				On("GetByToken", ctx, "test_token_001").
+				Return(tt.session, tt.getErr).
+				Times(1)
+			if tt.wantTouch {
+				sessions.On("Touch", ctx, tt.session.ID, now).Return(nil).Times(1)
+			}
+			users := portmocks.NewUserGateway(t)
+			if tt.users {
+				users.On("Get", ctx, user.ID).Return(user, nil).Times(1)
+			}
+			it := NewSessionInteractor(users, sessions, portmocks.NewCSRFTokenManager(t), SessionPolicy{
+				IdleTimeout:     30 * time.Minute,
+				AbsoluteTimeout: 12 * time.Hour,
+			})
+			it.now = func() time.Time { return now }
+
+			got, err := it.Authenticate(ctx, "test_token_001")
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.Authentication)
+			assert.Equal(t, now, got.Session.LastSeenAt)
+		})
+	}
+}
+
+func Test_sessionInteractor_List(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	active := &entity.Session{
+		ID:         "test_session_id_001",
+		UserID:     "test_user_id_001",
+		LastSeenAt: now.Add(-time.Minute),
+		ExpiresAt:  now.Add(time.Hour),
+	}
+	idle := &entity.Session{
+		ID:         "test_session_id_002",
+		UserID:     "test_user_id_001",
+		LastSeenAt: now.Add(-time.Hour),
+		ExpiresAt:  now.Add(time.Hour),
+	}
+	expired := &entity.Session{
+		ID:         "test_session_id_003",
+		UserID:     "test_user_id_001",
+		LastSeenAt: now.Add(-time.Minute),
+		ExpiresAt:  now.Add(-time.Second),
+	}
+
+	ctx := context.Background()
+	sessions := portmocks.NewSessionStore(t)
+	sessions.
+		On("ListByUserID", ctx, entity.ID("test_user_id_001")).
+		Return(entity.Sessions{active, idle, expired}, nil).
+		Times(1)
+	it := NewSessionInteractor(portmocks.NewUserGateway(t), sessions, portmocks.NewCSRFTokenManager(t), SessionPolicy{
+		IdleTimeout:     30 * time.Minute,
+		AbsoluteTimeout: 12 * time.Hour,
+	})
+	it.now = func() time.Time { return now }
+
+	got, err := it.List(ctx, "test_user_id_001")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.Sessions{active}, got)
+}
+
+func Test_sessionInteractor_Revoke(t *testing.T) {
+	session := &entity.Session{
+		ID:     "test_session_id_001",
+		UserID: "test_user_id_001",
+	}
+	tests := []struct {
+		name       string
+		input      RevokeSessionInput
+		getErr     error
+		wantDelete bool
+		wantErr    error
+	}{
+		{
+			name: "delete session of user",
+			input: RevokeSessionInput{
+				UserID: "test_user_id_001",
+				ID:     session.ID,
+			},
+			wantDelete: true,
+		},
+		{
+			name: "return error when session is of another user",
+			input: RevokeSessionInput{
+				UserID: "test_user_id_002",
+				ID:     session.ID,
+			},
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+		{
+			name: "return error when session is unknown",
+			input: RevokeSessionInput{
+				UserID: "test_user_id_001",
+				ID:     session.ID,
+			},
+			getErr:  usecase.ErrNotFoundEntity,
+			wantErr: usecase.ErrNotFoundEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			sessions := portmocks.NewSessionStore(t)
+			if tt.getErr != nil {
+				sessions.On("Get", ctx, tt.input.ID).Return(nil, tt.getErr).Times(1)
+			} else {
+				sessions.On("Get", ctx, tt.input.ID).Return(session, nil).Times(1)
+			}
+			if tt.wantDelete {
+				sessions.On("Delete", ctx, session.ID).Return(nil).Times(1)
+			}
+			it := NewSessionInteractor(portmocks.NewUserGateway(t), sessions, portmocks.NewCSRFTokenManager(t), SessionPolicy{})
+
+			err := it.Revoke(ctx, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
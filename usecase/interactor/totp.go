@@ -0,0 +1,239 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	EnrollTOTPInput struct {
+		UserID entity.ID
+	}
+	EnrollTOTPOutput struct {
+		Secret string
+		URI    string
+		QRCode []byte
+	}
+	ConfirmTOTPInput struct {
+		UserID entity.ID
+		Code   string
+	}
+	DisableTOTPInput struct {
+		UserID entity.ID
+	}
+	RegenerateRecoveryCodesInput struct {
+		UserID entity.ID
+	}
+	TOTPPolicy struct {
+		RecoveryCodeCount int
+	}
+)
+
+var _ TOTPInteractor = (*totpInteractor)(nil)
+
+type TOTPInteractor interface {
+	Enroll(ctx context.Context, input EnrollTOTPInput) (*EnrollTOTPOutput, error)
+	// Confirm enables the enrollment and returns its recovery codes.
+	Confirm(ctx context.Context, input ConfirmTOTPInput) ([]string, error)
+	Disable(ctx context.Context, input DisableTOTPInput) error
+	RegenerateRecoveryCodes(ctx context.Context, input RegenerateRecoveryCodesInput) ([]string, error)
+}
+
+type totpInteractor struct {
+	users         port.UserGateway
+	totps         port.TOTPGateway
+	totpManager   port.TOTPManager
+	recoveryCodes port.RecoveryCodeGateway
+	policy        TOTPPolicy
+}
+
+func NewTOTPInteractor(
+	users port.UserGateway,
+	totps port.TOTPGateway,
+	totpManager port.TOTPManager,
+	recoveryCodes port.RecoveryCodeGateway,
+	policy TOTPPolicy,
+) *totpInteractor {
+	return &totpInteractor{
+		users:         users,
+		totps:         totps,
+		totpManager:   totpManager,
+		recoveryCodes: recoveryCodes,
+		policy:        policy,
+	}
+}
+
+func (it *totpInteractor) Enroll(
+	ctx context.Context,
+	input EnrollTOTPInput,
+) (*EnrollTOTPOutput, error) {
+	logger := util.FromContext(ctx)
+
+	user, err := it.users.Get(ctx, input.UserID)
+	if err != nil {
+		logger.Error(err, "failed get user")
+		return nil, err
+	}
+	current, err := it.totps.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, usecase.ErrNotFoundEntity) {
+		logger.Error(err, "failed get totp")
+		return nil, err
+	}
+	// A confirmed enrollment has to be disabled first, so that a stolen
+	// password alone can not replace the second factor.
+	if current != nil && current.Confirmed {
+		return nil, usecase.ErrAlreadyExistsEntity
+	}
+
+	key, err := it.totpManager.Generate(ctx, user.Email.String())
+	if err != nil {
+		logger.Error(err, "failed generate totp key")
+		return nil, err
+	}
+	err = it.totps.Save(ctx, entity.TOTP{
+		UserID: user.ID,
+		Secret: key.Secret,
+	})
+	if err != nil {
+		logger.Error(err, "failed save totp")
+		return nil, err
+	}
+	qrCode, err := it.totpManager.QRCode(ctx, key.URI)
+	if err != nil {
+		logger.Error(err, "failed generate totp qr code")
+		return nil, err
+	}
+
+	return &EnrollTOTPOutput{
+		Secret: key.EncodedSecret,
+		URI:    key.URI,
+		QRCode: qrCode,
+	}, nil
+}
+
+func (it *totpInteractor) Confirm(
+	ctx context.Context,
+	input ConfirmTOTPInput,
+) ([]string, error) {
+	logger := util.FromContext(ctx)
+
+	totp, err := it.totps.Get(ctx, input.UserID)
+	if err != nil {
+		logger.Error(err, "failed get totp")
+		return nil, err
+	}
+	if totp.Confirmed {
+		return nil, usecase.ErrAlreadyExistsEntity
+	}
+	step, err := it.totpManager.Validate(ctx, totp.Secret, input.Code)
+	if err != nil {
+		logger.Error(err, "failed validate totp code")
+		return nil, err
+	}
+	if err := it.totps.Confirm(ctx, totp.UserID, step); err != nil {
+		logger.Error(err, "failed confirm totp")
+		return nil, err
+	}
+	codes, err := it.recoveryCodes.Generate(ctx, totp.UserID, it.policy.RecoveryCodeCount)
+	if err != nil {
+		logger.Error(err, "failed generate recovery codes")
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (it *totpInteractor) Disable(
+	ctx context.Context,
+	input DisableTOTPInput,
+) error {
+	logger := util.FromContext(ctx)
+
+	if _, err := it.totps.Get(ctx, input.UserID); err != nil {
+		logger.Error(err, "failed get totp")
+		return err
+	}
+	if err := it.totps.Delete(ctx, input.UserID); err != nil {
+		logger.Error(err, "failed delete totp")
+		return err
+	}
+	if err := it.recoveryCodes.DeleteByUserID(ctx, input.UserID); err != nil {
+		logger.Error(err, "failed delete recovery codes")
+		return err
+	}
+
+	return nil
+}
+
+func (it *totpInteractor) RegenerateRecoveryCodes(
+	ctx context.Context,
+	input RegenerateRecoveryCodesInput,
+) ([]string, error) {
+	logger := util.FromContext(ctx)
+
+	totp, err := it.totps.Get(ctx, input.UserID)
+	if err != nil {
+		logger.Error(err, "failed get totp")
+		return nil, err
+	}
+	if !totp.Confirmed {
+		return nil, usecase.ErrNotFoundEntity
+	}
+	codes, err := it.recoveryCodes.Generate(ctx, totp.UserID, it.policy.RecoveryCodeCount)
+	if err != nil {
+		logger.Error(err, "failed generate recovery codes")
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// verifySecondFactor accepts a TOTP code or, failing that, a recovery code
+// from users with a confirmed enrollment. Users without one pass, which is
+// reported by returning false. A nil recoveryCodes refuses recovery codes.
+//
+// A valid TOTP code used again within its time step returns
+// usecase.ErrSecondFactorUsed, which is not a guess, so that it is not
+// counted towards lockout.
+func verifySecondFactor(
+	ctx context.Context,
+	totps port.TOTPGateway,
+	totpManager port.TOTPManager,
+	recoveryCodes port.RecoveryCodeGateway,
+	userID entity.ID,
+	code string,
+) (bool, error) {
+	totp, err := totps.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !totp.Confirmed {
+		return false, nil
+	}
+	if len(code) == 0 {
+		return false, usecase.ErrSecondFactorRequired
+	}
+
+	step, err := totpManager.Validate(ctx, totp.Secret, code)
+	if err == nil {
+		if err := totps.UseStep(ctx, userID, step); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if !errors.Is(err, usecase.ErrInvalidSecondFactor) || recoveryCodes == nil {
+		return false, err
+	}
+	if err := recoveryCodes.Use(ctx, userID, code); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,380 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_totpInteractor_Enroll(t *testing.T) {
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	key := &port.TOTPKey{
+		Secret:        []byte("test_secret"),
+		EncodedSecret: "ORSXG5C7ONSWG4TFOQ",
+		URI:           "otpauth://totp/go-auth-api:test_001@example.com?secret=ORSXG5C7ONSWG4TFOQ",
+	}
+	type mockTOTPGetReturn struct {
+		totp *entity.TOTP
+		err  error
+	}
+	type mockReturn struct {
+		totpGet *mockTOTPGetReturn
+		enroll  bool
+	}
+	tests := []struct {
+		name       string
+		mockReturn mockReturn
+		want       *EnrollTOTPOutput
+		wantErr    error
+	}{
+		{
+			name: "return enrollment",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					err: usecase.ErrNotFoundEntity,
+				},
+				enroll: true,
+			},
+			want: &EnrollTOTPOutput{
+				Secret: key.EncodedSecret,
+				URI:    key.URI,
+				QRCode: []byte("test_png"),
+			},
+		},
+		{
+			name: "return enrollment replacing unconfirmed one",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: &entity.TOTP{
+						UserID: user.ID,
+						Secret: []byte("old_secret"),
+					},
+				},
+				enroll: true,
+			},
+			want: &EnrollTOTPOutput{
+				Secret: key.EncodedSecret,
+				URI:    key.URI,
+				QRCode: []byte("test_png"),
+			},
+		},
+		{
+			name: "return error when already confirmed",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: &entity.TOTP{
+						UserID:    user.ID,
+						Secret:    []byte("old_secret"),
+						Confirmed: true,
+					},
+				},
+			},
+			wantErr: usecase.ErrAlreadyExistsEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			users := portmocks.NewUserGateway(t)
+			users.On("Get", ctx, user.ID).Return(user, nil).Times(1)
+			totps := portmocks.NewTOTPGateway(t)
+			totps.
+				On("Get", ctx, user.ID).
+				Return(tt.mockReturn.totpGet.totp, tt.mockReturn.totpGet.err).
+				Times(1)
+			totpManager := portmocks.NewTOTPManager(t)
+			if tt.mockReturn.enroll {
+				totpManager.On("Generate", ctx, user.Email.String()).Return(key, nil).Times(1)
+				totps.On("Save", ctx, entity.TOTP{UserID: user.ID, Secret: key.Secret}).Return(nil).Times(1)
+				totpManager.On("QRCode", ctx, key.URI).Return([]byte("test_png"), nil).Times(1)
+			}
+			it := &totpInteractor{
+				users:       users,
+				totps:       totps,
+				totpManager: totpManager,
+			}
+			got, err := it.Enroll(ctx, EnrollTOTPInput{UserID: user.ID})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "totpInteractor.Enroll() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "totpInteractor.Enroll() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func Test_totpInteractor_Confirm(t *testing.T) {
+	const userID = entity.ID("test_user_id_001")
+	type mockValidateReturn struct {
+		step int64
+		err  error
+	}
+	type mockReturn struct {
+		totp     *entity.TOTP
+		validate *mockValidateReturn
+	}
+	tests := []struct {
+		name       string
+		mockReturn mockReturn
+		want       []string
+		wantErr    error
+	}{
+		{
+			name: "return recovery codes",
+			mockReturn: mockReturn{
+				totp: &entity.TOTP{
+					UserID: userID,
+					Secret: []byte("test_secret"),
+				},
+				validate: &mockValidateReturn{
+					step: 100,
+				},
+			},
+			want: []string{"aaaaa-bbbbb", "ccccc-ddddd"},
+		},
+		{
+			name: "return error when code is invalid",
+			mockReturn: mockReturn{
+				totp: &entity.TOTP{
+					UserID: userID,
+					Secret: []byte("test_secret"),
+				},
+				validate: &mockValidateReturn{
+					err: usecase.ErrInvalidSecondFactor,
+				},
+			},
+			wantErr: usecase.ErrInvalidSecondFactor,
+		},
+		{
+			name: "return error when already confirmed",
+			mockReturn: mockReturn{
+				totp: &entity.TOTP{
+					UserID:    userID,
+					Secret:    []byte("test_secret"),
+					Confirmed: true,
+				},
+			},
+			wantErr: usecase.ErrAlreadyExistsEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			totps := portmocks.NewTOTPGateway(t)
+			totps.On("Get", ctx, userID).Return(tt.mockReturn.totp, nil).Times(1)
+			totpManager := portmocks.NewTOTPManager(t)
+			recoveryCodes := portmocks.NewRecoveryCodeGateway(t)
+			if tt.mockReturn.validate != nil {
+				totpManager.
+					On("Validate", ctx, tt.mockReturn.totp.Secret, "123456").
+					Return(tt.mockReturn.validate.step, tt.mockReturn.validate.err).
+					Times(1)
+				if tt.mockReturn.validate.err == nil {
+					totps.On("Confirm", ctx, userID, tt.mockReturn.validate.step).Return(nil).Times(1)
+					recoveryCodes.On("Generate", ctx, userID, 2).Return(tt.want, nil).Times(1)
+				}
+			}
+			it := &totpInteractor{
+				totps:         totps,
+				totpManager:   totpManager,
+				recoveryCodes: recoveryCodes,
+				policy: TOTPPolicy{
+					RecoveryCodeCount: 2,
+				},
+			}
+			got, err := it.Confirm(ctx, ConfirmTOTPInput{UserID: userID, Code: "123456"})
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "totpInteractor.Confirm() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "totpInteractor.Confirm() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func Test_verifySecondFactor(t *testing.T) {
+	const userID = entity.ID("test_user_id_001")
+	confirmed := &entity.TOTP{
+		UserID:    userID,
+		Secret:    []byte("test_secret"),
+		Confirmed: true,
+	}
+	type mockTOTPGetReturn struct {
+		totp *entity.TOTP
+		err  error
+	}
+	type mockValidateReturn struct {
+		step int64
+		err  error
+	}
+	type mockReturn struct {
+		totpGet      *mockTOTPGetReturn
+		validate     *mockValidateReturn
+		useStep      error
+		recoveryCode error
+	}
+	tests := []struct {
+		name            string
+		code            string
+		mockReturn      mockReturn
+		noRecoveryCodes bool
+		want            bool
+		wantErr         error
+	}{
+		{
+			name: "return false when user has not enrolled",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					err: usecase.ErrNotFoundEntity,
+				},
+			},
+		},
+		{
+			name: "return false when enrollment is unconfirmed",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: &entity.TOTP{
+						UserID: userID,
+						Secret: []byte("test_secret"),
+					},
+				},
+			},
+		},
+		{
+			name: "return error when code is missing",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: confirmed,
+				},
+			},
+			wantErr: usecase.ErrSecondFactorRequired,
+		},
+		{
+			name: "return true when totp code is valid",
+			code: "123456",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: confirmed,
+				},
+				validate: &mockValidateReturn{
+					step: 100,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "return error when totp code was already used",
+			code: "123456",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: confirmed,
+				},
+				validate: &mockValidateReturn{
+					step: 100,
+				},
+				useStep: usecase.ErrSecondFactorUsed,
+			},
+			wantErr: usecase.ErrSecondFactorUsed,
+		},
+		{
+			name: "return error when recovery codes are refused",
+			code: "aaaaa-bbbbb",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: confirmed,
+				},
+				validate: &mockValidateReturn{
+					err: usecase.ErrInvalidSecondFactor,
+				},
+			},
+			noRecoveryCodes: true,
+			wantErr:         usecase.ErrInvalidSecondFactor,
+		},
+		{
+			name: "return true when recovery code is valid",
+			code: "aaaaa-bbbbb",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: confirmed,
+				},
+				validate: &mockValidateReturn{
+					err: usecase.ErrInvalidSecondFactor,
+				},
+			},
+			want: true,
+		},
+		{
+			name: "return error when recovery code is invalid",
+			code: "aaaaa-bbbbb",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: confirmed,
+				},
+				validate: &mockValidateReturn{
+					err: usecase.ErrInvalidSecondFactor,
+				},
+				recoveryCode: usecase.ErrInvalidSecondFactor,
+			},
+			wantErr: usecase.ErrInvalidSecondFactor,
+		},
+		{
+			name: "return error when validation failed",
+			code: "123456",
+			mockReturn: mockReturn{
+				totpGet: &mockTOTPGetReturn{
+					totp: confirmed,
+				},
+				validate: &mockValidateReturn{
+					err: errors.New("failed to validate"),
+				},
+			},
+			wantErr: errors.New("failed to validate"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			totps := portmocks.NewTOTPGateway(t)
+			totps.
+				On("Get", ctx, userID).
+				Return(tt.mockReturn.totpGet.totp, tt.mockReturn.totpGet.err).
+				Times(1)
+			totpManager := portmocks.NewTOTPManager(t)
+			recoveryCodes := portmocks.NewRecoveryCodeGateway(t)
+			if v := tt.mockReturn.validate; v != nil {
+				totpManager.
+					On("Validate", ctx, confirmed.Secret, tt.code).
+					Return(v.step, v.err).
+					Times(1)
+				if v.err == nil {
+					totps.On("UseStep", ctx, userID, v.step).Return(tt.mockReturn.useStep).Times(1)
+				} else if errors.Is(v.err, usecase.ErrInvalidSecondFactor) && !tt.noRecoveryCodes {
+					recoveryCodes.On("Use", ctx, userID, tt.code).Return(tt.mockReturn.recoveryCode).Times(1)
+				}
+			}
+			var recoveryCodeGateway port.RecoveryCodeGateway = recoveryCodes
+			if tt.noRecoveryCodes {
+				recoveryCodeGateway = nil
+			}
+			got, err := verifySecondFactor(ctx, totps, totpManager, recoveryCodeGateway, userID, tt.code)
+			if tt.wantErr != nil {
+				assert.ErrorContains(t, err, tt.wantErr.Error(), "verifySecondFactor() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "verifySecondFactor() = %v, want %v", got, tt.want)
+		})
+	}
+}
@@ -2,8 +2,10 @@ package interactor
 
 import (
 	"context"
+	"errors"
 
 	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
 	"github.com/mkaiho/go-auth-api/usecase/port"
 	"github.com/mkaiho/go-auth-api/util"
 )
@@ -16,15 +18,24 @@ type (
 		Email *entity.Email
 	}
 	CreateUserInput struct {
-		Name     string
-		Email    entity.Email
-		Password entity.HashedPassword
+		Name  string
+		Email entity.Email
+		// Password is empty for accounts that only log in by email, which
+		// UserPolicy has to allow.
+		Password entity.Password
 	}
 	UpdateUserInput struct {
 		ID    entity.ID
 		Name  string
 		Email entity.Email
 	}
+	UserPolicy struct {
+		AllowPasswordless bool
+		// ConcealExistingEmails mails the owner of an email that is signed
+		// up with again, so that the caller can answer as for a new
+		// account.
+		ConcealExistingEmails bool
+	}
 )
 
 var _ UserInteractor = (*userInteractor)(nil)
@@ -32,22 +43,43 @@ var _ UserInteractor = (*userInteractor)(nil)
 type UserInteractor interface {
 	GetUser(ctx context.Context, input GetUserInput) (*entity.User, error)
 	FindUsers(ctx context.Context, input FindUserInput) (entity.Users, error)
+	// CreateUser returns usecase.ErrAlreadyExistsEntity for emails with an
+	// account, after mailing their owner under
+	// UserPolicy.ConcealExistingEmails.
 	CreateUser(ctx context.Context, input CreateUserInput) (*entity.User, error)
 	UpdateUser(ctx context.Context, input UpdateUserInput) (*entity.User, error)
 }
 
 type userInteractor struct {
-	users     port.UserGateway
-	userCreds port.UserCredentialGateway
+	users              port.UserGateway
+	userCreds          port.UserCredentialGateway
+	verificationTokens port.EmailVerificationTokenManager
+	mailer             port.Mailer
+	newPasswords       newPasswordHasher
+	policy             UserPolicy
 }
 
 func NewUserInteractor(
 	users port.UserGateway,
 	userCreds port.UserCredentialGateway,
+	verificationTokens port.EmailVerificationTokenManager,
+	mailer port.Mailer,
+	passwordManager port.PasswordManager,
+	passwordPolicy entity.PasswordPolicy,
+	breachedPasswords port.BreachedPasswordChecker,
+	policy UserPolicy,
 ) *userInteractor {
 	return &userInteractor{
-		users:     users,
-		userCreds: userCreds,
+		users:              users,
+		userCreds:          userCreds,
+		verificationTokens: verificationTokens,
+		mailer:             mailer,
+		newPasswords: newPasswordHasher{
+			policy:          passwordPolicy,
+			passwordManager: passwordManager,
+			breaches:        breachedPasswords,
+		},
+		policy: policy,
 	}
 }
 
@@ -89,43 +121,103 @@ func (it *userInteractor) CreateUser(
 ) (*entity.User, error) {
 	logger := util.FromContext(ctx)
 
+	passwordless := it.policy.AllowPasswordless && len(input.Password) == 0
+	var password entity.HashedPassword
+	if !passwordless {
+		var err error
+		password, err = it.newPasswords.hash(ctx, &entity.User{
+			Name:  input.Name,
+			Email: input.Email,
+		}, input.Password)
+		if err != nil {
+			logger.Error(err, "failed accept password")
+			return nil, err
+		}
+	}
+
 	user, err := it.users.Create(ctx, port.UserCreateInput{
 		Name:  input.Name,
 		Email: input.Email,
 	})
 	if err != nil {
+		if errors.Is(err, usecase.ErrAlreadyExistsEntity) && it.policy.ConcealExistingEmails {
+			if nErr := it.notifyAccountExists(ctx, input.Email); nErr != nil {
+				logger.Error(nErr, "failed notify existing account")
+				return nil, nErr
+			}
+		}
 		logger.Error(err, "failed create user")
 		return nil, err
 	}
 
-	_, err = it.userCreds.Create(ctx, port.UserCredentialCreateInput{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Password: input.Password,
-	})
+	if !passwordless {
+		_, err = it.userCreds.Create(ctx, port.UserCredentialCreateInput{
+			UserID:   user.ID,
+			Email:    user.Email,
+			Password: password,
+		})
+		if err != nil {
+			logger.Error(err, "failed create user credentials")
+			return nil, err
+		}
+	}
+
+	err = sendEmailVerification(ctx, it.verificationTokens, it.mailer, user)
 	if err != nil {
-		logger.Error(err, "failed create user credentials")
+		logger.Error(err, "failed send email verification")
 		return nil, err
 	}
 
 	return user, nil
 }
 
+func (it *userInteractor) notifyAccountExists(ctx context.Context, email entity.Email) error {
+	users, err := it.users.List(ctx, port.UserListInput{
+		Email: &email,
+	})
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		return nil
+	}
+	return it.mailer.SendAccountExists(ctx, port.AccountExistsMailInput{
+		To:   users[0].Email,
+		Name: users[0].Name,
+	})
+}
+
 func (it *userInteractor) UpdateUser(
 	ctx context.Context,
 	input UpdateUserInput,
 ) (*entity.User, error) {
 	logger := util.FromContext(ctx)
 
+	current, err := it.users.Get(ctx, input.ID)
+	if err != nil {
+		logger.Error(err, "failed get user")
+		return nil, err
+	}
+	emailChanged := current.Email != input.Email
+
 	user, err := it.users.Update(ctx, port.UserUpdateInput{
-		ID:    input.ID,
-		Name:  input.Name,
-		Email: input.Email,
+		ID:            input.ID,
+		Name:          input.Name,
+		Email:         input.Email,
+		EmailVerified: current.EmailVerified && !emailChanged,
 	})
 	if err != nil {
 		logger.Error(err, "failed update user")
 		return nil, err
 	}
 
+	if emailChanged {
+		err = sendEmailVerification(ctx, it.verificationTokens, it.mailer, user)
+		if err != nil {
+			logger.Error(err, "failed send email verification")
+			return nil, err
+		}
+	}
+
 	return user, nil
 }
@@ -0,0 +1,70 @@
+package interactor
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	// ImportUserInput carries a password already hashed by another system.
+	// The hash is stored as is and replaced on the first successful login.
+	ImportUserInput struct {
+		Name          string
+		Email         entity.Email
+		EmailVerified bool
+		Password      entity.HashedPassword
+	}
+)
+
+var _ UserImportInteractor = (*userImportInteractor)(nil)
+
+type UserImportInteractor interface {
+	ImportUser(ctx context.Context, input ImportUserInput) (*entity.User, error)
+}
+
+type userImportInteractor struct {
+	users     port.UserGateway
+	userCreds port.UserCredentialGateway
+}
+
+func NewUserImportInteractor(
+	users port.UserGateway,
+	userCreds port.UserCredentialGateway,
+) *userImportInteractor {
+	return &userImportInteractor{
+		users:     users,
+		userCreds: userCreds,
+	}
+}
+
+func (it *userImportInteractor) ImportUser(
+	ctx context.Context,
+	input ImportUserInput,
+) (*entity.User, error) {
+	logger := util.FromContext(ctx)
+
+	user, err := it.users.Create(ctx, port.UserCreateInput{
+		Name:          input.Name,
+		Email:         input.Email,
+		EmailVerified: input.EmailVerified,
+	})
+	if err != nil {
+		logger.Error(err, "failed create user")
+		return nil, err
+	}
+
+	_, err = it.userCreds.Create(ctx, port.UserCredentialCreateInput{
+		UserID:   user.ID,
+		Email:    user.Email,
+		Password: input.Password,
+	})
+	if err != nil {
+		logger.Error(err, "failed create user credentials")
+		return nil, err
+	}
+
+	return user, nil
+}
@@ -0,0 +1,172 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_userImportInteractor_ImportUser(t *testing.T) {
+	type mockReturn struct {
+		userCreate *struct {
+			user *entity.User
+			err  error
+		}
+		userCredsCreate *struct {
+			creds *entity.UserCredential
+			err   error
+		}
+	}
+	type args struct {
+		ctx   context.Context
+		input ImportUserInput
+	}
+	tests := []struct {
+		name       string
+		args       args
+		mockReturn mockReturn
+		want       *entity.User
+		wantErr    bool
+	}{
+		{
+			name: "return imported user",
+			args: args{
+				ctx: context.Background(),
+				input: ImportUserInput{
+					Name:          "test_user_001",
+					Email:         "test_001@example.com",
+					EmailVerified: true,
+					Password:      "pbkdf2_sha256$1000$somesalt$6vIAkHC3WTrtFDrLB5lDvI5WiTf/uysM1ehQBSEy9iw=",
+				},
+			},
+			mockReturn: mockReturn{
+				userCreate: &struct {
+					user *entity.User
+					err  error
+				}{
+					user: &entity.User{
+						ID:            "test_user_id_001",
+						Name:          "test_user_001",
+						Email:         "test_001@example.com",
+						EmailVerified: true,
+					},
+				},
+				userCredsCreate: &struct {
+					creds *entity.UserCredential
+					err   error
+				}{
+					creds: &entity.UserCredential{
+						ID:       "test_user_cred_id_001",
+						UserID:   "test_user_id_001",
+						Email:    "test_001@example.com",
+						Password: "pbkdf2_sha256$1000$somesalt$6vIAkHC3WTrtFDrLB5lDvI5WiTf/uysM1ehQBSEy9iw=",
+					},
+				},
+			},
+			want: &entity.User{
+				ID:            "test_user_id_001",
+				Name:          "test_user_001",
+				Email:         "test_001@example.com",
+				EmailVerified: true,
+			},
+		},
+		{
+			name: "return error when failed to create user",
+			args: args{
+				ctx: context.Background(),
+				input: ImportUserInput{
+					Name:     "test_user_001",
+					Email:    "test_001@example.com",
+					Password: "$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0",
+				},
+			},
+			mockReturn: mockReturn{
+				userCreate: &struct {
+					user *entity.User
+					err  error
+				}{
+					err: errors.New("failed to create user"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "return error when failed to create user credentials",
+			args: args{
+				ctx: context.Background(),
+				input: ImportUserInput{
+					Name:     "test_user_001",
+					Email:    "test_001@example.com",
+					Password: "$P$9IQRaTwmfeRo7ud9Fh4E2PdI0S3r.L0",
+				},
+			},
+			mockReturn: mockReturn{
+				userCreate: &struct {
+					user *entity.User
+					err  error
+				}{
+					user: &entity.User{
+						ID:    "test_user_id_001",
+						Name:  "test_user_001",
+						Email: "test_001@example.com",
+					},
+				},
+				userCredsCreate: &struct {
+					creds *entity.UserCredential
+					err   error
+				}{
+					err: errors.New("failed to create user credentials"),
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := portmocks.NewUserGateway(t)
+			if tt.mockReturn.userCreate != nil {
+				users.
+					On("Create", tt.args.ctx, port.UserCreateInput{
+						Name:          tt.args.input.Name,
+						Email:         tt.args.input.Email,
+						EmailVerified: tt.args.input.EmailVerified,
+					}).
+					Return(
+						tt.mockReturn.userCreate.user,
+						tt.mockReturn.userCreate.err,
+					).
+					Times(1)
+			}
+			userCreds := portmocks.NewUserCredentialGateway(t)
+			if tt.mockReturn.userCredsCreate != nil {
+				userCreds.
+					On("Create", tt.args.ctx, port.UserCredentialCreateInput{
+						UserID:   tt.mockReturn.userCreate.user.ID,
+						Email:    tt.mockReturn.userCreate.user.Email,
+						Password: tt.args.input.Password,
+					}).
+					Return(
+						tt.mockReturn.userCredsCreate.creds,
+						tt.mockReturn.userCredsCreate.err,
+					).
+					Times(1)
+			}
+
+			it := &userImportInteractor{
+				users:     users,
+				userCreds: userCreds,
+			}
+			got, err := it.ImportUser(tt.args.ctx, tt.args.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("userImportInteractor.ImportUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.Equal(t, tt.want, got, "userImportInteractor.ImportUser() = %v, want %v", got, tt.want)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/mkaiho/go-auth-api/entity"
 	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
 	"github.com/mkaiho/go-auth-api/usecase/port"
 	"github.com/stretchr/testify/assert"
 )
@@ -111,9 +112,23 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 		creds *entity.UserCredential
 		err   error
 	}
+	type mockTokenIssueReturn struct {
+		token string
+		err   error
+	}
+	type mockMailerSendReturn struct {
+		err error
+	}
+	type mockPasswordHashReturn struct {
+		hashed entity.HashedPassword
+		err    error
+	}
 	type mockReturn struct {
+		passwordHash    *mockPasswordHashReturn
 		userCreate      *mockUserCreateReturn
 		userCredsCreate *mockUserCredsCreateReturn
+		tokenIssue      *mockTokenIssueReturn
+		mailerSend      *mockMailerSendReturn
 	}
 
 	type args struct {
@@ -123,6 +138,7 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 	tests := []struct {
 		name       string
 		args       args
+		policy     UserPolicy
 		mockReturn mockReturn
 		want       *entity.User
 		wantErr    bool
@@ -138,6 +154,10 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 				},
 			},
 			mockReturn: mockReturn{
+				passwordHash: &mockPasswordHashReturn{
+					hashed: "test_hashed_pass",
+					err:    nil,
+				},
 				userCreate: &mockUserCreateReturn{
 					user: &entity.User{
 						ID:    "test_user_id_001",
@@ -151,10 +171,67 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 						ID:       "test_user_creds_001",
 						UserID:   "test_user_id_001",
 						Email:    "test_001@example.com",
-						Password: "test_pass",
+						Password: "test_hashed_pass",
+					},
+					err: nil,
+				},
+				tokenIssue: &mockTokenIssueReturn{
+					token: "test_token",
+					err:   nil,
+				},
+				mailerSend: &mockMailerSendReturn{
+					err: nil,
+				},
+			},
+			want: &entity.User{
+				ID:    "test_user_id_001",
+				Name:  "test_user_001",
+				Email: "test_001@example.com",
+			},
+			wantErr: false,
+		},
+		{
+			name: "return error when password violates policy",
+			args: args{
+				ctx: context.Background(),
+				input: CreateUserInput{
+					Name:     "test_user_001",
+					Email:    "test_001@example.com",
+					Password: "short",
+				},
+			},
+			mockReturn: mockReturn{},
+			want:       nil,
+			wantErr:    true,
+		},
+		{
+			name: "return created user without credentials when passwordless is allowed",
+			args: args{
+				ctx: context.Background(),
+				input: CreateUserInput{
+					Name:  "test_user_001",
+					Email: "test_001@example.com",
+				},
+			},
+			policy: UserPolicy{
+				AllowPasswordless: true,
+			},
+			mockReturn: mockReturn{
+				userCreate: &mockUserCreateReturn{
+					user: &entity.User{
+						ID:    "test_user_id_001",
+						Name:  "test_user_001",
+						Email: "test_001@example.com",
 					},
 					err: nil,
 				},
+				tokenIssue: &mockTokenIssueReturn{
+					token: "test_token",
+					err:   nil,
+				},
+				mailerSend: &mockMailerSendReturn{
+					err: nil,
+				},
 			},
 			want: &entity.User{
 				ID:    "test_user_id_001",
@@ -163,6 +240,19 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "return error when password is missing and passwordless is not allowed",
+			args: args{
+				ctx: context.Background(),
+				input: CreateUserInput{
+					Name:  "test_user_001",
+					Email: "test_001@example.com",
+				},
+			},
+			mockReturn: mockReturn{},
+			want:       nil,
+			wantErr:    true,
+		},
 		{
 			name: "return error when user creation failed",
 			args: args{
@@ -174,6 +264,10 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 				},
 			},
 			mockReturn: mockReturn{
+				passwordHash: &mockPasswordHashReturn{
+					hashed: "test_hashed_pass",
+					err:    nil,
+				},
 				userCreate: &mockUserCreateReturn{
 					user: nil,
 					err:  errors.New("failed to create user"),
@@ -193,6 +287,10 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 				},
 			},
 			mockReturn: mockReturn{
+				passwordHash: &mockPasswordHashReturn{
+					hashed: "test_hashed_pass",
+					err:    nil,
+				},
 				userCreate: &mockUserCreateReturn{
 					user: &entity.User{
 						ID:    "test_user_id_001",
@@ -209,9 +307,62 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "return error when sending email verification failed",
+			args: args{
+				ctx: context.Background(),
+				input: CreateUserInput{
+					Name:     "test_user_001",
+					Email:    "test_001@example.com",
+					Password: "test_pass",
+				},
+			},
+			mockReturn: mockReturn{
+				passwordHash: &mockPasswordHashReturn{
+					hashed: "test_hashed_pass",
+					err:    nil,
+				},
+				userCreate: &mockUserCreateReturn{
+					user: &entity.User{
+						ID:    "test_user_id_001",
+						Name:  "test_user_001",
+						Email: "test_001@example.com",
+					},
+					err: nil,
+				},
+				userCredsCreate: &mockUserCredsCreateReturn{
+					creds: &entity.UserCredential{
+						ID:       "test_user_creds_001",
+						UserID:   "test_user_id_001",
+						Email:    "test_001@example.com",
+						Password: "test_hashed_pass",
+					},
+					err: nil,
+				},
+				tokenIssue: &mockTokenIssueReturn{
+					token: "test_token",
+					err:   nil,
+				},
+				mailerSend: &mockMailerSendReturn{
+					err: errors.New("failed to send mail"),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			passwordManager := portmocks.NewPasswordManager(t)
+			if tt.mockReturn.passwordHash != nil {
+				passwordManager.
+					On("Hash", tt.args.ctx, tt.args.input.Password.String()).
+					Return(
+						tt.mockReturn.passwordHash.hashed,
+						tt.mockReturn.passwordHash.err,
+					).
+					Times(1)
+			}
 			users := portmocks.NewUserGateway(t)
 			if tt.mockReturn.userCreate != nil {
 				users.
@@ -231,7 +382,7 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 					On("Create", tt.args.ctx, port.UserCredentialCreateInput{
 						UserID:   tt.mockReturn.userCreate.user.ID,
 						Email:    tt.mockReturn.userCreate.user.Email,
-						Password: tt.args.input.Password,
+						Password: tt.mockReturn.passwordHash.hashed,
 					}).
 					Return(
 						tt.mockReturn.userCredsCreate.creds,
@@ -240,9 +391,43 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 					Times(1)
 			}
 
+			verificationTokens := portmocks.NewEmailVerificationTokenManager(t)
+			if tt.mockReturn.tokenIssue != nil {
+				verificationTokens.
+					On("Issue", tt.args.ctx, port.EmailVerificationTokenIssueInput{
+						UserID: tt.mockReturn.userCreate.user.ID,
+						Email:  tt.mockReturn.userCreate.user.Email,
+					}).
+					Return(
+						tt.mockReturn.tokenIssue.token,
+						tt.mockReturn.tokenIssue.err,
+					).
+					Times(1)
+			}
+			mailer := portmocks.NewMailer(t)
+			if tt.mockReturn.mailerSend != nil {
+				mailer.
+					On("SendEmailVerification", tt.args.ctx, port.EmailVerificationMailInput{
+						To:    tt.mockReturn.userCreate.user.Email,
+						Name:  tt.mockReturn.userCreate.user.Name,
+						Token: tt.mockReturn.tokenIssue.token,
+					}).
+					Return(tt.mockReturn.mailerSend.err).
+					Times(1)
+			}
+
 			it := &userInteractor{
-				users:     users,
-				userCreds: userCreds,
+				users:              users,
+				userCreds:          userCreds,
+				verificationTokens: verificationTokens,
+				mailer:             mailer,
+				newPasswords: newPasswordHasher{
+					policy: entity.PasswordPolicy{
+						MinLength: 8,
+					},
+					passwordManager: passwordManager,
+				},
+				policy: tt.policy,
 			}
 			got, err := it.CreateUser(tt.args.ctx, tt.args.input)
 			if (err != nil) != tt.wantErr {
@@ -253,3 +438,78 @@ func Test_userInteractor_CreateUser(t *testing.T) {
 		})
 	}
 }
+
+func Test_userInteractor_CreateUser_existingEmail(t *testing.T) {
+	existing := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	input := CreateUserInput{
+		Name:     "test_user_002",
+		Email:    "test_001@example.com",
+		Password: "test_pass",
+	}
+	tests := []struct {
+		name       string
+		policy     UserPolicy
+		wantNotify bool
+	}{
+		{
+			name: "return error without notifying owner",
+		},
+		{
+			name:       "return error after notifying owner when emails are concealed",
+			policy:     UserPolicy{ConcealExistingEmails: true},
+			wantNotify: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			passwordManager := portmocks.NewPasswordManager(t)
+			passwordManager.
+				On("Hash", ctx, input.Password.String()).
+				Return(entity.HashedPassword("test_hashed_pass"), nil).
+				Times(1)
+			users := portmocks.NewUserGateway(t)
+			users.
+				On("Create", ctx, port.UserCreateInput{
+					Name:  input.Name,
+					Email: input.Email,
+				}).
+				Return(nil, usecase.ErrAlreadyExistsEntity).
+				Times(1)
+			mailer := portmocks.NewMailer(t)
+			if tt.wantNotify {
+				users.
+					On("List", ctx, port.UserListInput{Email: &input.Email}).
+					Return(entity.Users{existing}, nil).
+					Times(1)
+				mailer.
+					On("SendAccountExists", ctx, port.AccountExistsMailInput{
+						To:   existing.Email,
+						Name: existing.Name,
+					}).
+					Return(nil).
+					Times(1)
+			}
+			it := &userInteractor{
+				users:              users,
+				userCreds:          portmocks.NewUserCredentialGateway(t),
+				verificationTokens: portmocks.NewEmailVerificationTokenManager(t),
+				mailer:             mailer,
+				newPasswords: newPasswordHasher{
+					policy: entity.PasswordPolicy{
+						MinLength: 8,
+					},
+					passwordManager: passwordManager,
+				},
+				policy: tt.policy,
+			}
+			got, err := it.CreateUser(ctx, input)
+			assert.ErrorIs(t, err, usecase.ErrAlreadyExistsEntity)
+			assert.Nil(t, got)
+		})
+	}
+}
@@ -0,0 +1,269 @@
+package interactor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/mkaiho/go-auth-api/util"
+)
+
+type (
+	BeginWebAuthnRegistrationInput struct {
+		UserID entity.ID
+	}
+	WebAuthnRegistrationOptions struct {
+		ChallengeID  entity.ID
+		Challenge    []byte
+		RelyingParty port.WebAuthnRelyingParty
+		User         *entity.User
+		// ExcludeCredentials are the user's registered credential IDs, so
+		// that an authenticator is not registered twice.
+		ExcludeCredentials [][]byte
+	}
+	FinishWebAuthnRegistrationInput struct {
+		UserID            entity.ID
+		ChallengeID       entity.ID
+		ClientDataJSON    []byte
+		AttestationObject []byte
+	}
+	// WebAuthnLoginOptions list no credentials, so the authenticator offers
+	// its discoverable ones.
+	WebAuthnLoginOptions struct {
+		ChallengeID  entity.ID
+		Challenge    []byte
+		RelyingParty port.WebAuthnRelyingParty
+	}
+	WebAuthnAssertionInput struct {
+		ChallengeID       entity.ID
+		CredentialID      []byte
+		ClientDataJSON    []byte
+		AuthenticatorData []byte
+		Signature         []byte
+	}
+	WebAuthnPolicy struct {
+		RequireVerifiedEmail bool
+	}
+)
+
+var _ WebAuthnInteractor = (*webAuthnInteractor)(nil)
+
+type WebAuthnInteractor interface {
+	BeginRegistration(ctx context.Context, input BeginWebAuthnRegistrationInput) (*WebAuthnRegistrationOptions, error)
+	FinishRegistration(ctx context.Context, input FinishWebAuthnRegistrationInput) (*entity.WebAuthnCredential, error)
+	// BeginLogin takes no account, so that its options can not tell which
+	// accounts exist or have passkeys.
+	BeginLogin(ctx context.Context) (*WebAuthnLoginOptions, error)
+	// FinishLogin authenticates the owner of the asserted passkey, in place
+	// of a password and second factor.
+	FinishLogin(ctx context.Context, input WebAuthnAssertionInput) (*entity.Authentication, error)
+}
+
+type webAuthnInteractor struct {
+	users       port.UserGateway
+	credentials port.WebAuthnCredentialGateway
+	challenges  port.WebAuthnChallengeGateway
+	verifier    port.WebAuthnVerifier
+	policy      WebAuthnPolicy
+	now         func() time.Time
+}
+
+func NewWebAuthnInteractor(
+	users port.UserGateway,
+	credentials port.WebAuthnCredentialGateway,
+	challenges port.WebAuthnChallengeGateway,
+	verifier port.WebAuthnVerifier,
+	policy WebAuthnPolicy,
+) *webAuthnInteractor {
+	return &webAuthnInteractor{
+		users:       users,
+		credentials: credentials,
+		challenges:  challenges,
+		verifier:    verifier,
+		policy:      policy,
+		now:         time.Now,
+	}
+}
+
+func (it *webAuthnInteractor) BeginRegistration(
+	ctx context.Context,
+	input BeginWebAuthnRegistrationInput,
+) (*WebAuthnRegistrationOptions, error) {
+	logger := util.FromContext(ctx)
+
+	user, err := it.users.Get(ctx, input.UserID)
+	if err != nil {
+		logger.Error(err, "failed get user")
+		return nil, err
+	}
+	credentials, err := it.credentials.ListByUserID(ctx, user.ID)
+	if err != nil {
+		logger.Error(err, "failed list webauthn credentials")
+		return nil, err
+	}
+	challenge, err := it.challenges.Create(ctx, port.WebAuthnChallengeCreateInput{
+		UserID:   &user.ID,
+		Ceremony: entity.WebAuthnCeremonyRegistration,
+	})
+	if err != nil {
+		logger.Error(err, "failed create webauthn challenge")
+		return nil, err
+	}
+
+	return &WebAuthnRegistrationOptions{
+		ChallengeID:        challenge.ID,
+		Challenge:          challenge.Challenge,
+		RelyingParty:       it.verifier.RelyingParty(ctx),
+		User:               user,
+		ExcludeCredentials: credentialIDs(credentials),
+	}, nil
+}
+
+func (it *webAuthnInteractor) FinishRegistration(
+	ctx context.Context,
+	input FinishWebAuthnRegistrationInput,
+) (*entity.WebAuthnCredential, error) {
+	logger := util.FromContext(ctx)
+
+	challenge, err := it.challenges.Consume(ctx, input.ChallengeID, entity.WebAuthnCeremonyRegistration)
+	if err != nil {
+		logger.Error(err, "failed consume webauthn challenge")
+		return nil, err
+	}
+	if challenge.UserID == nil || *challenge.UserID != input.UserID {
+		return nil, usecase.ErrInvalidToken
+	}
+	registration, err := it.verifier.VerifyRegistration(ctx, port.WebAuthnRegistrationInput{
+		Challenge:         challenge.Challenge,
+		ClientDataJSON:    input.ClientDataJSON,
+		AttestationObject: input.AttestationObject,
+	})
+	if err != nil {
+		logger.Error(err, "failed verify webauthn registration")
+		return nil, err
+	}
+	_, err = it.credentials.GetByCredentialID(ctx, registration.CredentialID)
+	if err == nil {
+		return nil, usecase.ErrAlreadyExistsEntity
+	}
+	if !errors.Is(err, usecase.ErrNotFoundEntity) {
+		logger.Error(err, "failed get webauthn credential")
+		return nil, err
+	}
+	credential, err := it.credentials.Create(ctx, port.WebAuthnCredentialCreateInput{
+		UserID:       input.UserID,
+		CredentialID: registration.CredentialID,
+		PublicKey:    registration.PublicKey,
+		SignCount:    registration.SignCount,
+	})
+	if err != nil {
+		logger.Error(err, "failed create webauthn credential")
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+func (it *webAuthnInteractor) BeginLogin(ctx context.Context) (*WebAuthnLoginOptions, error) {
+	logger := util.FromContext(ctx)
+
+	challenge, err := it.challenges.Create(ctx, port.WebAuthnChallengeCreateInput{
+		Ceremony: entity.WebAuthnCeremonyAuthentication,
+	})
+	if err != nil {
+		logger.Error(err, "failed create webauthn challenge")
+		return nil, err
+	}
+
+	return &WebAuthnLoginOptions{
+		ChallengeID:  challenge.ID,
+		Challenge:    challenge.Challenge,
+		RelyingParty: it.verifier.RelyingParty(ctx),
+	}, nil
+}
+
+func (it *webAuthnInteractor) FinishLogin(
+	ctx context.Context,
+	input WebAuthnAssertionInput,
+) (*entity.Authentication, error) {
+	logger := util.FromContext(ctx)
+
+	// A passkey replaces both factors, so the authenticator has to have
+	// verified the user with a PIN or biometric.
+	credential, err := verifyWebAuthnAssertion(ctx, it.credentials, it.challenges, it.verifier, input, true)
+	if err != nil {
+		logger.Error(err, "failed verify webauthn assertion")
+		return nil, err
+	}
+	user, err := it.users.Get(ctx, credential.UserID)
+	if err != nil {
+		logger.Error(err, "failed get user")
+		return nil, err
+	}
+	if it.policy.RequireVerifiedEmail && !user.EmailVerified {
+		return nil, usecase.ErrEmailNotVerified
+	}
+
+	// The passkey is something the user has, unlocked by something they
+	// know or are, so it is multi-factor on its own.
+	return &entity.Authentication{
+		User:           user,
+		Level:          entity.AuthLevelMFA,
+		Methods:        []string{entity.AuthMethodHardwareKey, entity.AuthMethodMFA},
+		AuthTime:       it.now(),
+		AvailableLevel: entity.AuthLevelMFA,
+	}, nil
+}
+
+// verifyWebAuthnAssertion consumes the challenge, verifies the assertion
+// with the stored credential and records its new sign count. It returns
+// usecase.ErrInvalidCredential for unknown credentials.
+func verifyWebAuthnAssertion(
+	ctx context.Context,
+	credentials port.WebAuthnCredentialGateway,
+	challenges port.WebAuthnChallengeGateway,
+	verifier port.WebAuthnVerifier,
+	input WebAuthnAssertionInput,
+	requireUserVerification bool,
+) (*entity.WebAuthnCredential, error) {
+	challenge, err := challenges.Consume(ctx, input.ChallengeID, entity.WebAuthnCeremonyAuthentication)
+	if err != nil {
+		return nil, err
+	}
+	credential, err := credentials.GetByCredentialID(ctx, input.CredentialID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrNotFoundEntity) {
+			return nil, usecase.ErrInvalidCredential
+		}
+		return nil, err
+	}
+	assertion, err := verifier.VerifyAssertion(ctx, port.WebAuthnAssertionInput{
+		Challenge:               challenge.Challenge,
+		PublicKey:               credential.PublicKey,
+		SignCount:               credential.SignCount,
+		ClientDataJSON:          input.ClientDataJSON,
+		AuthenticatorData:       input.AuthenticatorData,
+		Signature:               input.Signature,
+		RequireUserVerification: requireUserVerification,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := credentials.UpdateSignCount(ctx, credential.ID, assertion.SignCount); err != nil {
+		return nil, err
+	}
+	credential.SignCount = assertion.SignCount
+
+	return credential, nil
+}
+
+func credentialIDs(credentials entity.WebAuthnCredentials) [][]byte {
+	ids := make([][]byte, 0, len(credentials))
+	for _, credential := range credentials {
+		ids = append(ids, credential.CredentialID)
+	}
+	return ids
+}
@@ -0,0 +1,306 @@
+package interactor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+	portmocks "github.com/mkaiho/go-auth-api/mocks/usecase/port"
+	"github.com/mkaiho/go-auth-api/usecase"
+	"github.com/mkaiho/go-auth-api/usecase/port"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_webAuthnInteractor_FinishRegistration(t *testing.T) {
+	const userID = entity.ID("test_user_id_001")
+	otherUserID := entity.ID("test_user_id_002")
+	input := FinishWebAuthnRegistrationInput{
+		UserID:            userID,
+		ChallengeID:       "test_challenge_id_001",
+		ClientDataJSON:    []byte("test_client_data"),
+		AttestationObject: []byte("test_attestation"),
+	}
+	registration := &port.WebAuthnRegistration{
+		CredentialID: []byte("test_credential_id"),
+		PublicKey:    []byte("test_public_key"),
+		SignCount:    1,
+	}
+	credential := &entity.WebAuthnCredential{
+		ID:           "test_id_001",
+		UserID:       userID,
+		CredentialID: registration.CredentialID,
+		PublicKey:    registration.PublicKey,
+		SignCount:    registration.SignCount,
+	}
+	type mockReturn struct {
+		challengeUserID *entity.ID
+		verifyErr       error
+		existing        *entity.WebAuthnCredential
+		create          bool
+	}
+	tests := []struct {
+		name       string
+		mockReturn mockReturn
+		want       *entity.WebAuthnCredential
+		wantErr    error
+	}{
+		{
+			name: "return created credential",
+			mockReturn: mockReturn{
+				challengeUserID: &input.UserID,
+				create:          true,
+			},
+			want: credential,
+		},
+		{
+			name: "return error when challenge was issued to another user",
+			mockReturn: mockReturn{
+				challengeUserID: &otherUserID,
+			},
+			wantErr: usecase.ErrInvalidToken,
+		},
+		{
+			name: "return error when attestation is invalid",
+			mockReturn: mockReturn{
+				challengeUserID: &input.UserID,
+				verifyErr:       usecase.ErrInvalidCredential,
+			},
+			wantErr: usecase.ErrInvalidCredential,
+		},
+		{
+			name: "return error when credential is already registered",
+			mockReturn: mockReturn{
+				challengeUserID: &input.UserID,
+				existing:        credential,
+			},
+			wantErr: usecase.ErrAlreadyExistsEntity,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			challenges := portmocks.NewWebAuthnChallengeGateway(t)
+			challenges.
+				On("Consume", ctx, input.ChallengeID, entity.WebAuthnCeremonyRegistration).
+				Return(&entity.WebAuthnChallenge{
+					ID:        input.ChallengeID,
+					UserID:    tt.mockReturn.challengeUserID,
+					Ceremony:  entity.WebAuthnCeremonyRegistration,
+					Challenge: []byte("test_challenge"),
+				}, nil).
+				Times(1)
+			verifier := portmocks.NewWebAuthnVerifier(t)
+			credentials := portmocks.NewWebAuthnCredentialGateway(t)
+			if *tt.mockReturn.challengeUserID == input.UserID {
+				var result *port.WebAuthnRegistration
+				if tt.mockReturn.verifyErr == nil {
+					result = registration
+				}
+				verifier.
+					On("VerifyRegistration", ctx, port.WebAuthnRegistrationInput{
+						Challenge:         []byte("test_challenge"),
+						ClientDataJSON:    input.ClientDataJSON,
+						AttestationObject: input.AttestationObject,
+					}).
+					Return(result, tt.mockReturn.verifyErr).
+					Times(1)
+			}
+			if tt.mockReturn.existing != nil {
+				credentials.
+					On("GetByCredentialID", ctx, registration.CredentialID).
+					Return(tt.mockReturn.existing, nil).
+					Times(1)
+			}
+			if tt.mockReturn.create {
+				credentials.
+					On("GetByCredentialID", ctx, registration.CredentialID).
+					Return(nil, usecase.ErrNotFoundEntity).
+					Times(1)
+				credentials.
+					On("Create", ctx, port.WebAuthnCredentialCreateInput{
+						UserID:       userID,
+						CredentialID: registration.CredentialID,
+						PublicKey:    registration.PublicKey,
+						SignCount:    registration.SignCount,
+					}).
+					Return(credential, nil).
+					Times(1)
+			}
+			it := &webAuthnInteractor{
+				credentials: credentials,
+				challenges:  challenges,
+				verifier:    verifier,
+			}
+			got, err := it.FinishRegistration(ctx, input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "webAuthnInteractor.FinishRegistration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "webAuthnInteractor.FinishRegistration() = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func Test_webAuthnInteractor_BeginLogin(t *testing.T) {
+	ctx := context.Background()
+	challenge := &entity.WebAuthnChallenge{
+		ID:        "test_challenge_id_001",
+		Challenge: []byte("test_challenge"),
+		Ceremony:  entity.WebAuthnCeremonyAuthentication,
+	}
+	relyingParty := port.WebAuthnRelyingParty{
+		ID:   "example.com",
+		Name: "test_relying_party",
+	}
+	// No users or credentials are looked up, so that the options are the
+	// same whoever asks.
+	users := portmocks.NewUserGateway(t)
+	credentials := portmocks.NewWebAuthnCredentialGateway(t)
+	challenges := portmocks.NewWebAuthnChallengeGateway(t)
+	challenges.
+		On("Create", ctx, port.WebAuthnChallengeCreateInput{
+			Ceremony: entity.WebAuthnCeremonyAuthentication,
+		}).
+		Return(challenge, nil).
+		Times(1)
+	verifier := portmocks.NewWebAuthnVerifier(t)
+	verifier.On("RelyingParty", ctx).Return(relyingParty).Times(1)
+	it := NewWebAuthnInteractor(users, credentials, challenges, verifier, WebAuthnPolicy{})
+
+	got, err := it.BeginLogin(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &WebAuthnLoginOptions{
+		ChallengeID:  challenge.ID,
+		Challenge:    challenge.Challenge,
+		RelyingParty: relyingParty,
+	}, got)
+}
+
+func Test_webAuthnInteractor_FinishLogin(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	user := &entity.User{
+		ID:    "test_user_id_001",
+		Name:  "test_user_001",
+		Email: "test_001@example.com",
+	}
+	input := WebAuthnAssertionInput{
+		ChallengeID:       "test_challenge_id_001",
+		CredentialID:      []byte("test_credential_id"),
+		ClientDataJSON:    []byte("test_client_data"),
+		AuthenticatorData: []byte("test_authenticator_data"),
+		Signature:         []byte("test_signature"),
+	}
+	type mockReturn struct {
+		credentialErr error
+		verifyErr     error
+	}
+	tests := []struct {
+		name       string
+		policy     WebAuthnPolicy
+		mockReturn mockReturn
+		want       *entity.Authentication
+		wantErr    error
+	}{
+		{
+			name: "return mfa authentication by passkey",
+			want: &entity.Authentication{
+				User:           user,
+				Level:          entity.AuthLevelMFA,
+				Methods:        []string{entity.AuthMethodHardwareKey, entity.AuthMethodMFA},
+				AuthTime:       now,
+				AvailableLevel: entity.AuthLevelMFA,
+			},
+		},
+		{
+			name: "return error when credential is unknown",
+			mockReturn: mockReturn{
+				credentialErr: usecase.ErrNotFoundEntity,
+			},
+			wantErr: usecase.ErrInvalidCredential,
+		},
+		{
+			name: "return error when assertion is invalid",
+			mockReturn: mockReturn{
+				verifyErr: usecase.ErrInvalidCredential,
+			},
+			wantErr: usecase.ErrInvalidCredential,
+		},
+		{
+			name: "return error when email is not verified",
+			policy: WebAuthnPolicy{
+				RequireVerifiedEmail: true,
+			},
+			wantErr: usecase.ErrEmailNotVerified,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			credential := &entity.WebAuthnCredential{
+				ID:           "test_id_001",
+				UserID:       user.ID,
+				CredentialID: input.CredentialID,
+				PublicKey:    []byte("test_public_key"),
+				SignCount:    4,
+			}
+			challenges := portmocks.NewWebAuthnChallengeGateway(t)
+			challenges.
+				On("Consume", ctx, input.ChallengeID, entity.WebAuthnCeremonyAuthentication).
+				Return(&entity.WebAuthnChallenge{
+					ID:        input.ChallengeID,
+					Ceremony:  entity.WebAuthnCeremonyAuthentication,
+					Challenge: []byte("test_challenge"),
+				}, nil).
+				Times(1)
+			credentials := portmocks.NewWebAuthnCredentialGateway(t)
+			verifier := portmocks.NewWebAuthnVerifier(t)
+			users := portmocks.NewUserGateway(t)
+			if tt.mockReturn.credentialErr != nil {
+				credentials.
+					On("GetByCredentialID", ctx, input.CredentialID).
+					Return(nil, tt.mockReturn.credentialErr).
+					Times(1)
+			} else {
+				credentials.
+					On("GetByCredentialID", ctx, input.CredentialID).
+					Return(credential, nil).
+					Times(1)
+				var assertion *port.WebAuthnAssertion
+				if tt.mockReturn.verifyErr == nil {
+					assertion = &port.WebAuthnAssertion{SignCount: 5}
+					credentials.On("UpdateSignCount", ctx, credential.ID, uint32(5)).Return(nil).Times(1)
+					users.On("Get", ctx, user.ID).Return(user, nil).Times(1)
+				}
+				verifier.
+					On("VerifyAssertion", ctx, port.WebAuthnAssertionInput{
+						Challenge:               []byte("test_challenge"),
+						PublicKey:               credential.PublicKey,
+						SignCount:               4,
+						ClientDataJSON:          input.ClientDataJSON,
+						AuthenticatorData:       input.AuthenticatorData,
+						Signature:               input.Signature,
+						RequireUserVerification: true,
+					}).
+					Return(assertion, tt.mockReturn.verifyErr).
+					Times(1)
+			}
+			it := &webAuthnInteractor{
+				users:       users,
+				credentials: credentials,
+				challenges:  challenges,
+				verifier:    verifier,
+				policy:      tt.policy,
+				now:         func() time.Time { return now },
+			}
+			got, err := it.FinishLogin(ctx, input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "webAuthnInteractor.FinishLogin() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "webAuthnInteractor.FinishLogin() = %v, want %v", got, tt.want)
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+// AccessPolicySource provides the access policy in force.
+type AccessPolicySource interface {
+	Get(ctx context.Context) (*entity.AccessPolicy, error)
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type BreachedPasswordChecker interface {
+	IsBreached(ctx context.Context, password entity.Password) (bool, error)
+}
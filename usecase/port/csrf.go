@@ -0,0 +1,15 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+// CSRFTokenManager issues the tokens cookie-authenticated requests echo in
+// a header. A token is only accepted with the session it was issued for.
+type CSRFTokenManager interface {
+	Issue(ctx context.Context, sessionID entity.ID) (string, error)
+	// Verify returns usecase.ErrInvalidToken for tokens of other sessions.
+	Verify(ctx context.Context, sessionID entity.ID, token string) error
+}
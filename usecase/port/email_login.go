@@ -0,0 +1,43 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	EmailLoginCode struct {
+		ID        entity.ID
+		Code      string
+		ExpiresAt time.Time
+	}
+	EmailLoginTokenIssueInput struct {
+		ID        entity.ID
+		ExpiresAt time.Time
+	}
+	EmailLoginToken struct {
+		ID        entity.ID
+		ExpiresAt time.Time
+	}
+)
+
+type EmailLoginCodeGateway interface {
+	// Issue replaces the user's pending code with a new one. Only a hash of
+	// the code is stored.
+	Issue(ctx context.Context, userID entity.ID) (*EmailLoginCode, error)
+	// Verify uses the code and returns its user. A wrong code counts
+	// against the attempt limit and returns usecase.ErrInvalidCredential.
+	// Unknown, expired and exhausted codes return usecase.ErrInvalidToken.
+	Verify(ctx context.Context, id entity.ID, code string) (entity.ID, error)
+	// Consume uses the code without comparing it, for magic links whose
+	// signature already proves the email was received.
+	Consume(ctx context.Context, id entity.ID) (entity.ID, error)
+}
+
+// EmailLoginTokenManager signs the magic links sent along with codes.
+type EmailLoginTokenManager interface {
+	Issue(ctx context.Context, input EmailLoginTokenIssueInput) (string, error)
+	Parse(ctx context.Context, token string) (*EmailLoginToken, error)
+}
@@ -0,0 +1,25 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	EmailVerificationTokenIssueInput struct {
+		UserID entity.ID
+		Email  entity.Email
+	}
+	EmailVerificationToken struct {
+		UserID    entity.ID
+		Email     entity.Email
+		ExpiresAt time.Time
+	}
+)
+
+type EmailVerificationTokenManager interface {
+	Issue(ctx context.Context, input EmailVerificationTokenIssueInput) (string, error)
+	Parse(ctx context.Context, token string) (*EmailVerificationToken, error)
+}
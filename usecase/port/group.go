@@ -0,0 +1,51 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	GroupCreateInput struct {
+		Name        string
+		Description string
+	}
+	GroupUpdateInput struct {
+		ID          entity.ID
+		Name        string
+		Description string
+	}
+)
+
+type GroupGateway interface {
+	List(ctx context.Context) (entity.Groups, error)
+	// Get returns usecase.ErrNotFoundEntity for unknown groups.
+	Get(ctx context.Context, id entity.ID) (*entity.Group, error)
+	// Create returns usecase.ErrAlreadyExistsEntity when the name is taken.
+	Create(ctx context.Context, input GroupCreateInput) (*entity.Group, error)
+	// Update returns usecase.ErrNotFoundEntity for unknown groups, and
+	// usecase.ErrAlreadyExistsEntity when another group has the name.
+	Update(ctx context.Context, input GroupUpdateInput) (*entity.Group, error)
+	// Delete removes the group's memberships too, and returns
+	// usecase.ErrNotFoundEntity for unknown groups.
+	Delete(ctx context.Context, id entity.ID) error
+	ListMembers(ctx context.Context, id entity.ID) (*entity.GroupMembers, error)
+	// ListByUserID returns the groups the user is a member of, directly or
+	// through subgroups.
+	ListByUserID(ctx context.Context, userID entity.ID) (entity.Groups, error)
+	// ListAncestorIDs returns the group and the groups it is nested in,
+	// directly or not. Nesting is locked until the end of the transaction,
+	// so that the result holds while a subgroup is added.
+	ListAncestorIDs(ctx context.Context, id entity.ID) ([]entity.ID, error)
+	// AddUser does nothing when the user is already a member.
+	AddUser(ctx context.Context, id entity.ID, userID entity.ID) error
+	// RemoveUser returns usecase.ErrNotFoundEntity when the user is not a
+	// member.
+	RemoveUser(ctx context.Context, id entity.ID, userID entity.ID) error
+	// AddSubgroup does nothing when the subgroup is already nested.
+	AddSubgroup(ctx context.Context, id entity.ID, subgroupID entity.ID) error
+	// RemoveSubgroup returns usecase.ErrNotFoundEntity when the subgroup is
+	// not nested.
+	RemoveSubgroup(ctx context.Context, id entity.ID, subgroupID entity.ID) error
+}
@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+)
+
+// DataKey is a key generated for encrypting one piece of data. Only
+// EncryptedKey may be stored; Plaintext should be discarded after use.
+type DataKey struct {
+	Plaintext    []byte
+	EncryptedKey []byte
+}
+
+type KeyManagementService interface {
+	GenerateDataKey(ctx context.Context) (*DataKey, error)
+	DecryptDataKey(ctx context.Context, encryptedKey []byte) ([]byte, error)
+	Sign(ctx context.Context, message []byte) ([]byte, error)
+	Verify(ctx context.Context, message []byte, signature []byte) error
+}
@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	LoginFailureRecordInput struct {
+		UserID   entity.ID
+		FailedAt time.Time
+		// ForgetBefore restarts the count when the last failure was
+		// before it.
+		ForgetBefore time.Time
+	}
+)
+
+// LoginFailureGateway counts failed logins per user. Failures are recorded
+// in the caller's transaction, which must be committed even though the
+// login failed.
+type LoginFailureGateway interface {
+	// Get returns zero failures for users without any.
+	Get(ctx context.Context, userID entity.ID) (*entity.LoginFailures, error)
+	Record(ctx context.Context, input LoginFailureRecordInput) error
+	Reset(ctx context.Context, userID entity.ID) error
+}
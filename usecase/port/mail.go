@@ -0,0 +1,39 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	EmailVerificationMailInput struct {
+		To    entity.Email
+		Name  string
+		Token string
+	}
+	PasswordResetMailInput struct {
+		To    entity.Email
+		Name  string
+		Token string
+	}
+	EmailLoginMailInput struct {
+		To    entity.Email
+		Name  string
+		Code  string
+		Token string
+	}
+	AccountExistsMailInput struct {
+		To   entity.Email
+		Name string
+	}
+)
+
+type Mailer interface {
+	SendEmailVerification(ctx context.Context, input EmailVerificationMailInput) error
+	SendPasswordReset(ctx context.Context, input PasswordResetMailInput) error
+	SendEmailLogin(ctx context.Context, input EmailLoginMailInput) error
+	// SendAccountExists tells the owner of an email that someone tried to
+	// sign up with it.
+	SendAccountExists(ctx context.Context, input AccountExistsMailInput) error
+}
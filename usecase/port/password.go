@@ -9,4 +9,7 @@ import (
 type PasswordManager interface {
 	Hash(ctx context.Context, value string) (entity.HashedPassword, error)
 	Compare(ctx context.Context, hashedPassword entity.HashedPassword, password entity.Password) error
+	// NeedsRehash reports whether hashedPassword was produced by another
+	// algorithm or weaker parameters than new passwords are hashed with.
+	NeedsRehash(ctx context.Context, hashedPassword entity.HashedPassword) bool
 }
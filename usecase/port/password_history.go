@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type PasswordHistoryGateway interface {
+	// ListRecent returns up to limit of the passwords most recently set for
+	// the user, including the current one, most recent first.
+	ListRecent(ctx context.Context, userID entity.ID, limit int) ([]entity.HashedPassword, error)
+}
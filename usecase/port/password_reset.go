@@ -0,0 +1,27 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	PasswordResetTokenIssueInput struct {
+		UserID   entity.ID
+		Password entity.HashedPassword
+	}
+	PasswordResetToken struct {
+		UserID    entity.ID
+		ExpiresAt time.Time
+	}
+)
+
+type PasswordResetTokenManager interface {
+	Issue(ctx context.Context, input PasswordResetTokenIssueInput) (string, error)
+	Parse(ctx context.Context, token string) (*PasswordResetToken, error)
+	// Verify checks the token was issued while password was current, so
+	// that a token can not be used again once the password changed.
+	Verify(ctx context.Context, token string, password entity.HashedPassword) error
+}
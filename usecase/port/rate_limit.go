@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+// RateLimitStore keeps a token bucket per key.
+type RateLimitStore interface {
+	// Take takes a token from the key's bucket, refilled according to
+	// limit. The bucket is left as it is when no token is available.
+	Take(ctx context.Context, key string, limit entity.RateLimit) (*entity.RateLimitResult, error)
+}
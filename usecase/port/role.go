@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	RoleSaveInput struct {
+		Name        string
+		Permissions []entity.Permission
+	}
+)
+
+type RoleGateway interface {
+	List(ctx context.Context) (entity.Roles, error)
+	// Get returns usecase.ErrNotFoundEntity for unknown roles.
+	Get(ctx context.Context, name string) (*entity.Role, error)
+	// Save creates the role or replaces its permissions.
+	Save(ctx context.Context, input RoleSaveInput) (*entity.Role, error)
+	// Delete removes the role from its users too, and returns
+	// usecase.ErrNotFoundEntity for unknown roles.
+	Delete(ctx context.Context, name string) error
+	ListByUserID(ctx context.Context, userID entity.ID) (entity.Roles, error)
+	// Assign does nothing when the user already has the role.
+	Assign(ctx context.Context, userID entity.ID, name string) error
+	// Unassign returns usecase.ErrNotFoundEntity when the user does not
+	// have the role.
+	Unassign(ctx context.Context, userID entity.ID, name string) error
+}
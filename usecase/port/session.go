@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	SessionCreateInput struct {
+		UserID         entity.ID
+		Level          entity.AuthLevel
+		AvailableLevel entity.AuthLevel
+		Methods        []string
+		AuthTime       time.Time
+		UserAgent      string
+		IPAddress      string
+		ExpiresAt      time.Time
+	}
+	SessionDeleteByUserIDInput struct {
+		UserID entity.ID
+		// ExceptID keeps one session, such as the one making the request.
+		ExceptID *entity.ID
+	}
+)
+
+// SessionStore keeps sessions by a random token. Only a hash of the token
+// is stored.
+type SessionStore interface {
+	// Create returns the session along with its token.
+	Create(ctx context.Context, input SessionCreateInput) (*entity.Session, string, error)
+	Get(ctx context.Context, id entity.ID) (*entity.Session, error)
+	// GetByToken returns usecase.ErrInvalidToken for unknown tokens.
+	GetByToken(ctx context.Context, token string) (*entity.Session, error)
+	ListByUserID(ctx context.Context, userID entity.ID) (entity.Sessions, error)
+	Touch(ctx context.Context, id entity.ID, lastSeenAt time.Time) error
+	Delete(ctx context.Context, id entity.ID) error
+	DeleteByUserID(ctx context.Context, input SessionDeleteByUserIDInput) error
+}
@@ -0,0 +1,47 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	TOTPKey struct {
+		Secret []byte
+		// EncodedSecret is the form users type into an authenticator app
+		// when they can not scan URI.
+		EncodedSecret string
+		URI           string
+	}
+)
+
+type TOTPManager interface {
+	Generate(ctx context.Context, account string) (*TOTPKey, error)
+	QRCode(ctx context.Context, uri string) ([]byte, error)
+	// Validate returns the time step code was generated for, or
+	// usecase.ErrInvalidSecondFactor.
+	Validate(ctx context.Context, secret []byte, code string) (int64, error)
+}
+
+type TOTPGateway interface {
+	Get(ctx context.Context, userID entity.ID) (*entity.TOTP, error)
+	// Save replaces any enrollment of the user with an unconfirmed one.
+	Save(ctx context.Context, totp entity.TOTP) error
+	Confirm(ctx context.Context, userID entity.ID, step int64) error
+	// UseStep records step as the last used one. It returns
+	// usecase.ErrSecondFactorUsed when step is not after the last used
+	// step.
+	UseStep(ctx context.Context, userID entity.ID, step int64) error
+	Delete(ctx context.Context, userID entity.ID) error
+}
+
+type RecoveryCodeGateway interface {
+	// Generate replaces the user's recovery codes with count new ones. Only
+	// hashes are stored, so the returned codes can not be shown again.
+	Generate(ctx context.Context, userID entity.ID, count int) ([]string, error)
+	// Use consumes code, or returns usecase.ErrInvalidSecondFactor when it
+	// is not an unused code of the user.
+	Use(ctx context.Context, userID entity.ID, code string) error
+	DeleteByUserID(ctx context.Context, userID entity.ID) error
+}
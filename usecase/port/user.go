@@ -11,13 +11,15 @@ type (
 		Email *entity.Email
 	}
 	UserCreateInput struct {
-		Name  string
-		Email entity.Email
+		Name          string
+		Email         entity.Email
+		EmailVerified bool
 	}
 	UserUpdateInput struct {
-		ID    entity.ID
-		Name  string
-		Email entity.Email
+		ID            entity.ID
+		Name          string
+		Email         entity.Email
+		EmailVerified bool
 	}
 )
 
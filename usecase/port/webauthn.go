@@ -0,0 +1,74 @@
+package port
+
+import (
+	"context"
+
+	"github.com/mkaiho/go-auth-api/entity"
+)
+
+type (
+	WebAuthnRelyingParty struct {
+		ID   string
+		Name string
+		// Algorithms are the accepted COSE algorithm identifiers.
+		Algorithms []int
+	}
+	WebAuthnRegistrationInput struct {
+		Challenge               []byte
+		ClientDataJSON          []byte
+		AttestationObject       []byte
+		RequireUserVerification bool
+	}
+	WebAuthnRegistration struct {
+		CredentialID []byte
+		PublicKey    []byte
+		SignCount    uint32
+	}
+	WebAuthnAssertionInput struct {
+		Challenge               []byte
+		PublicKey               []byte
+		SignCount               uint32
+		ClientDataJSON          []byte
+		AuthenticatorData       []byte
+		Signature               []byte
+		RequireUserVerification bool
+	}
+	WebAuthnAssertion struct {
+		SignCount uint32
+	}
+	WebAuthnCredentialCreateInput struct {
+		UserID       entity.ID
+		CredentialID []byte
+		PublicKey    []byte
+		SignCount    uint32
+	}
+	WebAuthnChallengeCreateInput struct {
+		UserID   *entity.ID
+		Ceremony entity.WebAuthnCeremony
+	}
+)
+
+type WebAuthnVerifier interface {
+	RelyingParty(ctx context.Context) WebAuthnRelyingParty
+	// VerifyRegistration and VerifyAssertion return
+	// usecase.ErrInvalidCredential when the response does not verify.
+	VerifyRegistration(ctx context.Context, input WebAuthnRegistrationInput) (*WebAuthnRegistration, error)
+	VerifyAssertion(ctx context.Context, input WebAuthnAssertionInput) (*WebAuthnAssertion, error)
+}
+
+type WebAuthnCredentialGateway interface {
+	ListByUserID(ctx context.Context, userID entity.ID) (entity.WebAuthnCredentials, error)
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*entity.WebAuthnCredential, error)
+	Create(ctx context.Context, input WebAuthnCredentialCreateInput) (*entity.WebAuthnCredential, error)
+	UpdateSignCount(ctx context.Context, id entity.ID, signCount uint32) error
+}
+
+type WebAuthnChallengeGateway interface {
+	// Create issues a random challenge that expires after a configured
+	// period.
+	Create(ctx context.Context, input WebAuthnChallengeCreateInput) (*entity.WebAuthnChallenge, error)
+	// Consume deletes the challenge and returns it, or returns
+	// usecase.ErrInvalidToken when it does not exist, has expired or was
+	// issued for another ceremony.
+	Consume(ctx context.Context, id entity.ID, ceremony entity.WebAuthnCeremony) (*entity.WebAuthnChallenge, error)
+}